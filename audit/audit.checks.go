@@ -0,0 +1,401 @@
+package audit
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mkenney/go-chrome/coverage"
+)
+
+/*
+Default is the set of audits run by Run when no audits are given.
+*/
+var Default = []*Audit{
+	RenderBlockingResources,
+	TotalByteWeight,
+	UnusedJavaScript,
+	UnusedCSS,
+	OversizedImages,
+	ImageAlt,
+	ColorContrast,
+	IsOnHTTPS,
+	ErrorsInConsole,
+}
+
+/*
+Thresholds of the audits. Resources and images that waste fewer bytes are not
+reported.
+*/
+var (
+	// The number of render-blocking resources at which the audit scores 0.
+	RenderBlockingLimit = 5
+
+	// The total transfer size at which the byte weight audit starts to lose
+	// score, and the size at which it scores 0.
+	ByteWeightGood = 1600 * 1024
+	ByteWeightPoor = 5000 * 1024
+
+	// The transfer size of a resource reported as a large transfer.
+	LargeTransferSize = 250 * 1024
+
+	// The unused bytes of a script or style sheet that are reported.
+	UnusedJavaScriptSize = 20 * 1024
+	UnusedCSSSize        = 10 * 1024
+
+	// The bytes of an image that are reported as wasted by its display size.
+	OversizedImageSize = 4 * 1024
+
+	// The wasted bytes at which the unused code and oversized image audits
+	// score 0.
+	WastedBytesPoor = 500 * 1024
+)
+
+/*
+RenderBlockingResources reports scripts and style sheets that delay the first
+render.
+*/
+var RenderBlockingResources = &Audit{
+	ID:          "render-blocking-resources",
+	Title:       "Eliminate render-blocking resources",
+	Description: "Synchronous scripts and style sheets in the document head block the first paint. Inline critical resources, defer scripts and load other style sheets asynchronously.",
+	Weight:      3,
+	Check: func(page *Page) (float64, string, []*Item) {
+		details := []*Item{}
+		for _, resource := range page.Resources {
+			if resource.RenderBlocking {
+				details = append(details, &Item{URL: resource.URL, Bytes: resource.TransferSize})
+			}
+		}
+		score := 1 - float64(len(details))/float64(RenderBlockingLimit)
+		return score, plural(len(details), "resource"), details
+	},
+}
+
+/*
+TotalByteWeight reports the total transfer size of the page and its large
+transfers.
+*/
+var TotalByteWeight = &Audit{
+	ID:          "total-byte-weight",
+	Title:       "Avoid enormous network payloads",
+	Description: "Large network payloads cost users money and are highly correlated with long load times.",
+	Weight:      2,
+	Check: func(page *Page) (float64, string, []*Item) {
+		total := 0
+		details := []*Item{}
+		for _, resource := range page.Resources {
+			total += resource.TransferSize
+			if resource.TransferSize >= LargeTransferSize {
+				details = append(details, &Item{URL: resource.URL, Bytes: resource.TransferSize})
+			}
+		}
+		sort.SliceStable(details, func(a, b int) bool {
+			return details[a].Bytes > details[b].Bytes
+		})
+		score := 1 - float64(total-ByteWeightGood)/float64(ByteWeightPoor-ByteWeightGood)
+		return score, "Total size was " + formatBytes(total), details
+	},
+}
+
+/*
+UnusedJavaScript reports scripts with code that didn't run.
+*/
+var UnusedJavaScript = &Audit{
+	ID:          "unused-javascript",
+	Title:       "Reduce unused JavaScript",
+	Description: "Scripts that are loaded but not executed slow down the page. Split code and defer it until it's needed.",
+	Weight:      2,
+	Check: func(page *Page) (float64, string, []*Item) {
+		return unusedCode(page, coverage.JavaScript, UnusedJavaScriptSize)
+	},
+}
+
+/*
+UnusedCSS reports style sheets with rules that were never used.
+*/
+var UnusedCSS = &Audit{
+	ID:          "unused-css-rules",
+	Title:       "Reduce unused CSS",
+	Description: "Rules that are loaded but never match delay rendering. Remove dead rules and defer the styles of content below the fold.",
+	Weight:      2,
+	Check: func(page *Page) (float64, string, []*Item) {
+		return unusedCode(page, coverage.CSS, UnusedCSSSize)
+	},
+}
+
+/*
+OversizedImages reports images that are larger than their displayed size.
+*/
+var OversizedImages = &Audit{
+	ID:          "uses-responsive-images",
+	Title:       "Properly size images",
+	Description: "Images larger than their displayed size waste bytes. Serve responsive images sized for the device.",
+	Weight:      2,
+	Check: func(page *Page) (float64, string, []*Item) {
+		dpr := page.DevicePixelRatio
+		if dpr <= 0 {
+			dpr = 1
+		}
+		// An image displayed several times wastes bytes by its largest use.
+		largest := map[string]*Image{}
+		urls := []string{}
+		displayed := func(image *Image) float64 {
+			width := math.Min(image.DisplayWidth*dpr, float64(image.NaturalWidth))
+			height := math.Min(image.DisplayHeight*dpr, float64(image.NaturalHeight))
+			return width * height
+		}
+		for _, image := range page.Images {
+			if "" == image.URL || image.NaturalWidth <= 0 || image.NaturalHeight <= 0 {
+				continue
+			}
+			if existing, ok := largest[image.URL]; !ok {
+				largest[image.URL] = image
+				urls = append(urls, image.URL)
+			} else if displayed(image) > displayed(existing) {
+				largest[image.URL] = image
+			}
+		}
+
+		details := []*Item{}
+		wasted := 0
+		for _, src := range urls {
+			image := largest[src]
+			resource := page.Resource(src)
+			if nil == resource || displayed(image) <= 0 {
+				continue
+			}
+			natural := float64(image.NaturalWidth * image.NaturalHeight)
+			bytes := int(float64(resource.TransferSize) * (1 - displayed(image)/natural))
+			if bytes < OversizedImageSize {
+				continue
+			}
+			wasted += bytes
+			details = append(details, &Item{
+				URL:         src,
+				Selector:    image.Selector,
+				Value:       fmt.Sprintf("%dx%d displayed at %gx%g", image.NaturalWidth, image.NaturalHeight, image.DisplayWidth, image.DisplayHeight),
+				Bytes:       resource.TransferSize,
+				WastedBytes: bytes,
+			})
+		}
+		sortWasted(details)
+		return wastedScore(wasted), savings(wasted), details
+	},
+}
+
+/*
+ImageAlt reports images without alternative text.
+*/
+var ImageAlt = &Audit{
+	ID:          "image-alt",
+	Title:       "Image elements have alt attributes",
+	Description: "Screen readers describe images by their alt text. Use an empty alt attribute for decorative images.",
+	Weight:      3,
+	Check: func(page *Page) (float64, string, []*Item) {
+		details := []*Item{}
+		for _, image := range page.Images {
+			if !image.HasAlt && !image.Presentational {
+				details = append(details, &Item{URL: image.URL, Selector: image.Selector})
+			}
+		}
+		return passed(details), plural(len(details), "image"), details
+	},
+}
+
+/*
+ColorContrast reports text with a contrast ratio below the WCAG AA minimum,
+4.5:1 for normal text and 3:1 for large text. Text over a gradient is checked
+against each stop, text over an unknown background is not checked.
+*/
+var ColorContrast = &Audit{
+	ID:          "color-contrast",
+	Title:       "Background and foreground colors have a sufficient contrast ratio",
+	Description: "Low contrast text is difficult or impossible for many users to read.",
+	Weight:      3,
+	Check: func(page *Page) (float64, string, []*Item) {
+		details := []*Item{}
+		for _, text := range page.Text {
+			color, ok := ParseColor(text.Color)
+			if !ok || 0 == color.A {
+				continue
+			}
+			ratio := 21.0
+			for _, value := range text.Backgrounds {
+				if background, ok := ParseColor(value); ok {
+					ratio = math.Min(ratio, ContrastRatio(color, background))
+				}
+			}
+			if ratio < requiredContrast(text) {
+				details = append(details, &Item{
+					Selector: text.Selector,
+					Text:     text.Text,
+					Value:    fmt.Sprintf("%.2f:1, expected %g:1", ratio, requiredContrast(text)),
+				})
+			}
+		}
+		return passed(details), plural(len(details), "element"), details
+	},
+}
+
+/*
+requiredContrast returns the minimum contrast ratio of text. Large text is at
+least 18pt, or 14pt and bold.
+*/
+func requiredContrast(text *Text) float64 {
+	if text.FontSize >= 24 || text.FontSize >= 18.66 && text.FontWeight >= 700 {
+		return 3
+	}
+	return 4.5
+}
+
+/*
+IsOnHTTPS reports a page that isn't served over HTTPS and insecure resources
+loaded by a secure page. Loopback hosts are secure contexts.
+*/
+var IsOnHTTPS = &Audit{
+	ID:          "is-on-https",
+	Title:       "Uses HTTPS and doesn't load mixed content",
+	Description: "Pages and their resources should be served over HTTPS. Browsers block or flag mixed content, insecure resources loaded by a secure page.",
+	Weight:      3,
+	Check: func(page *Page) (float64, string, []*Item) {
+		details := []*Item{}
+		if !secureURL(page.URL) {
+			details = append(details, &Item{URL: page.URL, Value: "insecure page"})
+		} else {
+			for _, resource := range page.Resources {
+				if !secureURL(resource.URL) {
+					details = append(details, &Item{URL: resource.URL, Value: "mixed content"})
+				}
+			}
+		}
+		if 0 == len(details) && "insecure" == page.SecurityState {
+			details = append(details, &Item{URL: page.URL, Value: "security state insecure"})
+		}
+		return passed(details), plural(len(details), "insecure request"), details
+	},
+}
+
+/*
+secureURL reports whether a URL is delivered securely.
+*/
+func secureURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if nil != err {
+		return false
+	}
+	switch parsed.Scheme {
+	case "http", "ws":
+		host := parsed.Hostname()
+		return "localhost" == host || strings.HasSuffix(host, ".localhost") || "::1" == host || strings.HasPrefix(host, "127.")
+	}
+	return true
+}
+
+/*
+ErrorsInConsole reports errors logged to the console, uncaught exceptions and
+browser errors such as failed requests.
+*/
+var ErrorsInConsole = &Audit{
+	ID:          "errors-in-console",
+	Title:       "No browser errors logged to the console",
+	Description: "Errors logged to the console indicate unresolved problems, such as failed requests or uncaught exceptions.",
+	Weight:      1,
+	Check: func(page *Page) (float64, string, []*Item) {
+		details := []*Item{}
+		for _, message := range page.Console {
+			if "error" != message.Level {
+				continue
+			}
+			item := &Item{URL: message.URL, Text: message.Text, Value: message.Source}
+			if message.Line > 0 {
+				item.URL = fmt.Sprintf("%s:%d", message.URL, message.Line)
+			}
+			details = append(details, item)
+		}
+		return passed(details), plural(len(details), "error"), details
+	},
+}
+
+/*
+unusedCode reports the files of a type with at least min unused bytes. The
+unused bytes of a file are its transfer size scaled by the unused share of its
+source.
+*/
+func unusedCode(page *Page, typ coverage.Type, min int) (float64, string, []*Item) {
+	details := []*Item{}
+	if nil == page.Coverage {
+		return 1, savings(0), details
+	}
+	wasted := 0
+	for _, file := range page.Coverage.Files {
+		unused, length := file.Unused()
+		if typ != file.Type || 0 == length {
+			continue
+		}
+		size := length
+		if resource := page.Resource(file.URL); nil != resource && resource.TransferSize > 0 {
+			size = resource.TransferSize
+		}
+		bytes := size * unused / length
+		if bytes < min {
+			continue
+		}
+		wasted += bytes
+		details = append(details, &Item{URL: file.URL, Bytes: size, WastedBytes: bytes})
+	}
+	sortWasted(details)
+	return wastedScore(wasted), savings(wasted), details
+}
+
+/*
+sortWasted sorts items by wasted bytes, largest first.
+*/
+func sortWasted(items []*Item) {
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].WastedBytes > items[b].WastedBytes
+	})
+}
+
+/*
+wastedScore scores the bytes that could be saved.
+*/
+func wastedScore(wasted int) float64 {
+	return 1 - float64(wasted)/float64(WastedBytesPoor)
+}
+
+/*
+passed scores an audit that passes only without failing items.
+*/
+func passed(details []*Item) float64 {
+	if 0 == len(details) {
+		return 1
+	}
+	return 0
+}
+
+/*
+plural formats a count of things, e.g. "1 image" or "3 images".
+*/
+func plural(count int, noun string) string {
+	if 1 == count {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+/*
+savings formats the bytes that could be saved.
+*/
+func savings(bytes int) string {
+	return "Potential savings of " + formatBytes(bytes)
+}
+
+/*
+formatBytes formats a size in KiB.
+*/
+func formatBytes(bytes int) string {
+	return fmt.Sprintf("%.1f KiB", float64(bytes)/1024)
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/coverage"
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+testPage returns a page that fails each default audit.
+*/
+func testPage() *Page {
+	// A script whose second half is a function that was never called.
+	half := strings.Repeat("x();", 8*1024)
+	script := "function a() {" + half + "}\nfunction b() {" + half + "}\na();\n"
+	unused := strings.Index(script, "function b")
+	// A style sheet with an unused rule.
+	sheet := "a {}\n.unused {" + strings.Repeat(" ", 12*1024) + "}\n"
+
+	report := &coverage.Report{}
+	report.Add(coverage.FromScriptCoverage(&profiler.ScriptCoverage{
+		URL: "https://example.com/app.js",
+		Functions: []*profiler.FunctionCoverage{
+			{Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: len(script), Count: 1}}},
+			{FunctionName: "b", Ranges: []*profiler.CoverageRange{{StartOffset: unused, EndOffset: len(script) - 5, Count: 0}}},
+		},
+	}, script))
+	report.Add(coverage.FromRuleUsage("https://example.com/app.css", sheet, []*css.RuleUsage{
+		{StartOffset: 0, EndOffset: 4, Used: true},
+		{StartOffset: 5, EndOffset: float64(len(sheet) - 1), Used: false},
+	}))
+
+	return &Page{
+		URL:              "https://example.com/",
+		DevicePixelRatio: 2,
+		Resources: []*Resource{
+			{URL: "https://example.com/", Type: "Document", TransferSize: 2000 * 1024},
+			{URL: "https://example.com/app.js", Type: "Script", TransferSize: 80 * 1024, RenderBlocking: true},
+			{URL: "https://example.com/app.css", Type: "Stylesheet", TransferSize: 10 * 1024, RenderBlocking: true},
+			{URL: "https://example.com/hero.jpg", Type: "Image", TransferSize: 400 * 1024},
+			{URL: "http://example.com/icon.png", Type: "Image", TransferSize: 1024},
+		},
+		Coverage: report,
+		Images: []*Image{
+			{URL: "https://example.com/hero.jpg", Selector: "img.hero", HasAlt: true, NaturalWidth: 2000, NaturalHeight: 1000, DisplayWidth: 200, DisplayHeight: 100},
+			{URL: "https://example.com/hero.jpg", Selector: "img.thumb", HasAlt: true, NaturalWidth: 2000, NaturalHeight: 1000, DisplayWidth: 100, DisplayHeight: 50},
+			{URL: "http://example.com/icon.png", Selector: "img#icon", NaturalWidth: 16, NaturalHeight: 16, DisplayWidth: 16, DisplayHeight: 16},
+			{URL: "https://example.com/spacer.gif", Selector: "img.spacer", Presentational: true},
+		},
+		Text: []*Text{
+			{Selector: "p.muted", Text: "Muted", Color: "rgb(170, 170, 170)", Backgrounds: []string{"rgb(255, 255, 255)"}, FontSize: 16, FontWeight: 400},
+			{Selector: "h1", Text: "Title", Color: "rgb(119, 119, 119)", Backgrounds: []string{"rgb(255, 255, 255)"}, FontSize: 32, FontWeight: 700},
+			{Selector: "p.gradient", Text: "Gradient", Color: "rgb(0, 0, 0)", Backgrounds: []string{"rgb(255, 255, 255)", "rgb(40, 40, 40)"}, FontSize: 16, FontWeight: 400},
+			{Selector: "p.image", Text: "Image", Color: "rgb(255, 255, 255)", FontSize: 16, FontWeight: 400},
+		},
+		Console: []*Message{
+			{Source: "exception", Level: "error", Text: "Uncaught TypeError: x is not a function", URL: "https://example.com/app.js", Line: 3},
+			{Source: "console-api", Level: "warning", Text: "deprecated"},
+		},
+	}
+}
+
+func TestChecks(t *testing.T) {
+	report := Run(testPage())
+	// 32784 of the 65573 code units of the script never ran.
+	for id, expected := range map[string]struct {
+		score    float64
+		selector string
+		url      string
+		wasted   int
+	}{
+		"render-blocking-resources": {0.6, "", "https://example.com/app.js", 0},
+		"total-byte-weight":         {0.74, "", "https://example.com/", 0},
+		"unused-javascript":         {0.92, "", "https://example.com/app.js", 80 * 1024 * 32784 / 65573},
+		"unused-css-rules":          {1, "", "", 0},
+		"uses-responsive-images":    {0.23, "img.hero", "https://example.com/hero.jpg", 384 * 1024},
+		"image-alt":                 {0, "img#icon", "http://example.com/icon.png", 0},
+		"color-contrast":            {0, "p.muted", "", 0},
+		"is-on-https":               {0, "", "http://example.com/icon.png", 0},
+		"errors-in-console":         {0, "", "https://example.com/app.js:3", 0},
+	} {
+		result := report.Audit(id)
+		if nil == result {
+			t.Errorf("%s: expected a result", id)
+			continue
+		}
+		if expected.score != result.Score {
+			t.Errorf("%s: expected score %g, received %g (%s)", id, expected.score, result.Score, result.DisplayValue)
+		}
+		if "" == expected.url && "" == expected.selector {
+			continue
+		}
+		if 0 == len(result.Details) {
+			t.Errorf("%s: expected details", id)
+			continue
+		}
+		item := result.Details[0]
+		if expected.url != item.URL || expected.selector != item.Selector || expected.wasted != item.WastedBytes {
+			t.Errorf("%s: unexpected item %+v", id, item)
+		}
+	}
+
+	// Large text and text over an unknown background pass, text over a
+	// gradient fails on its darkest stop.
+	if contrast := report.Audit("color-contrast"); 2 != len(contrast.Details) || "p.gradient" != contrast.Details[1].Selector {
+		t.Errorf("Unexpected contrast failures %+v", contrast.Details)
+	}
+	if images := report.Audit("uses-responsive-images"); 1 != len(images.Details) {
+		t.Errorf("Expected an image displayed twice to be reported once, received %+v", images.Details)
+	}
+	if errors := report.Audit("errors-in-console"); 1 != len(errors.Details) || "1 error" != errors.DisplayValue {
+		t.Errorf("Expected warnings to be ignored, received %+v", errors.Details)
+	}
+}
+
+func TestUnusedCSS(t *testing.T) {
+	page := testPage()
+	page.Resource("https://example.com/app.css").TransferSize = 0
+	result := Run(page, UnusedCSS).Audits[0]
+	if 1 != len(result.Details) || result.Details[0].WastedBytes < UnusedCSSSize {
+		t.Errorf("Expected the source length to be used without a transfer size, received %+v", result.Details)
+	}
+}
+
+func TestIsOnHTTPS(t *testing.T) {
+	for pageURL, expected := range map[string]bool{
+		"http://example.com/":       false,
+		"http://localhost:8080/":    true,
+		"http://127.0.0.1/":         true,
+		"http://[::1]/":             true,
+		"https://example.com/":      true,
+		"data:text/html,<p>hi</p>":  true,
+		"http://app.localhost/path": true,
+	} {
+		result := Run(&Page{URL: pageURL}, IsOnHTTPS).Audits[0]
+		if expected != result.Passed {
+			t.Errorf("%s: expected passed %v, received %+v", pageURL, expected, result)
+		}
+	}
+	result := Run(&Page{URL: "https://example.com/", SecurityState: "insecure"}, IsOnHTTPS).Audits[0]
+	if result.Passed {
+		t.Errorf("Expected an insecure security state to fail")
+	}
+}
@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Color is an sRGB color. Channels are between 0 and 255, the alpha between 0
+and 1.
+*/
+type Color struct {
+	R, G, B, A float64
+}
+
+/*
+ParseColor parses a CSS color in the hex, rgb() or rgba() notation, or the
+transparent keyword. Computed styles and CSS.getBackgroundColors report colors
+in the rgb() and rgba() notations.
+*/
+func ParseColor(value string) (Color, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if "transparent" == value {
+		return Color{}, true
+	}
+	if strings.HasPrefix(value, "#") {
+		return parseHexColor(value[1:])
+	}
+
+	open, end := strings.IndexByte(value, '('), len(value)-1
+	if open < 0 || ')' != value[end] {
+		return Color{}, false
+	}
+	if name := value[:open]; "rgb" != name && "rgba" != name {
+		return Color{}, false
+	}
+	fields := strings.Fields(strings.NewReplacer(",", " ", "/", " ").Replace(value[open+1 : end]))
+	if len(fields) < 3 || len(fields) > 4 {
+		return Color{}, false
+	}
+	channels := []float64{0, 0, 0, 1}
+	for a, field := range fields {
+		scale := 255.0
+		if 3 == a {
+			scale = 1
+		}
+		percent := strings.HasSuffix(field, "%")
+		number, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		if nil != err {
+			return Color{}, false
+		}
+		if percent {
+			number = number / 100 * scale
+		}
+		channels[a] = math.Max(0, math.Min(scale, number))
+	}
+	return Color{channels[0], channels[1], channels[2], channels[3]}, true
+}
+
+/*
+parseHexColor parses the digits of a #rgb, #rgba, #rrggbb or #rrggbbaa color.
+*/
+func parseHexColor(digits string) (Color, bool) {
+	if 3 == len(digits) || 4 == len(digits) {
+		expanded := ""
+		for _, digit := range digits {
+			expanded += string(digit) + string(digit)
+		}
+		digits = expanded
+	}
+	if 6 == len(digits) {
+		digits += "ff"
+	}
+	if 8 != len(digits) {
+		return Color{}, false
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if nil != err {
+		return Color{}, false
+	}
+	return Color{
+		R: float64(value >> 24 & 0xff),
+		G: float64(value >> 16 & 0xff),
+		B: float64(value >> 8 & 0xff),
+		A: float64(value&0xff) / 255,
+	}, true
+}
+
+/*
+Over composites the color over an opaque background.
+*/
+func (color Color) Over(background Color) Color {
+	blend := func(fg, bg float64) float64 {
+		return fg*color.A + bg*(1-color.A)
+	}
+	return Color{blend(color.R, background.R), blend(color.G, background.G), blend(color.B, background.B), 1}
+}
+
+/*
+Luminance returns the relative luminance of the color as defined by WCAG 2.
+*/
+func (color Color) Luminance() float64 {
+	linear := func(channel float64) float64 {
+		channel /= 255
+		if channel <= 0.03928 {
+			return channel / 12.92
+		}
+		return math.Pow((channel+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(color.R) + 0.7152*linear(color.G) + 0.0722*linear(color.B)
+}
+
+/*
+ContrastRatio returns the WCAG 2 contrast ratio of text over a background,
+between 1 and 21. Translucent backgrounds are composited over white, the
+default canvas color, and translucent text over the background.
+*/
+func ContrastRatio(text, background Color) float64 {
+	white := Color{255, 255, 255, 1}
+	background = background.Over(white)
+	text = text.Over(background)
+	lighter, darker := text.Luminance(), background.Luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+	return (lighter + 0.05) / (darker + 0.05)
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for value, expected := range map[string]Color{
+		"rgb(255, 128, 0)":        {255, 128, 0, 1},
+		"rgba(0, 0, 0, 0.5)":      {0, 0, 0, 0.5},
+		"rgb(100% 0% 50% / 25%)":  {255, 0, 127.5, 0.25},
+		"#fff":                    {255, 255, 255, 1},
+		"#00000080":               {0, 0, 0, 128.0 / 255},
+		"transparent":             {},
+		" RGBA(10, 20, 30, 1.5) ": {10, 20, 30, 1},
+	} {
+		color, ok := ParseColor(value)
+		if !ok || expected != color {
+			t.Errorf("%q: expected %v, received %v %v", value, expected, color, ok)
+		}
+	}
+	for _, value := range []string{"", "red", "rgb(1, 2)", "hsl(0, 0%, 0%)", "#12345", "rgb(a, b, c)"} {
+		if _, ok := ParseColor(value); ok {
+			t.Errorf("%q: expected an invalid color", value)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, white := Color{0, 0, 0, 1}, Color{255, 255, 255, 1}
+	for _, test := range []struct {
+		text, background Color
+		expected         float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{Color{119, 119, 119, 1}, white, 4.48},
+		// Translucent backgrounds are composited over white.
+		{black, Color{0, 0, 0, 0}, 21},
+		{Color{0, 0, 0, 0.5}, white, 3.98},
+	} {
+		if ratio := ContrastRatio(test.text, test.background); math.Abs(test.expected-ratio) > 0.01 {
+			t.Errorf("%v over %v: expected %g, received %g", test.text, test.background, test.expected, ratio)
+		}
+	}
+}
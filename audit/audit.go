@@ -0,0 +1,336 @@
+/*
+Package audit checks a loaded page for common performance, accessibility,
+security and quality issues and produces a scored report in the style of
+Lighthouse. The page is collected from the browser, see Tab.StartAudit, and
+each audit scores it between 0 and 1. The report score is the weighted mean of
+the audit scores between 0 and 100.
+
+	report := audit.Run(page)
+	err := report.WriteJSON(file)
+*/
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/coverage"
+)
+
+/*
+Page is the data an audit checks.
+*/
+type Page struct {
+	// The page URL.
+	URL string
+
+	// The security state reported by the Security domain, e.g. "secure",
+	// "neutral" or "insecure".
+	SecurityState string
+
+	// The ratio of device pixels to CSS pixels.
+	DevicePixelRatio float64
+
+	// The resources loaded by the page in request order.
+	Resources []*Resource
+
+	// Optional. The JavaScript and CSS coverage of the page.
+	Coverage *coverage.Report
+
+	// The images of the page.
+	Images []*Image
+
+	// The elements that contain text.
+	Text []*Text
+
+	// The errors and warnings logged by the page and the browser.
+	Console []*Message
+}
+
+/*
+Resource returns the resource loaded from a URL, or nil.
+*/
+func (page *Page) Resource(url string) *Resource {
+	for _, resource := range page.Resources {
+		if url == resource.URL {
+			return resource
+		}
+	}
+	return nil
+}
+
+/*
+Resource is a network resource loaded by the page.
+*/
+type Resource struct {
+	// The resource URL.
+	URL string
+
+	// The resource type, e.g. "Document", "Script", "Stylesheet" or "Image".
+	Type string
+
+	// The MIME type of the response.
+	MimeType string
+
+	// The HTTP status code.
+	Status int
+
+	// The number of bytes received, including headers.
+	TransferSize int
+
+	// The resource blocks the first render of the page.
+	RenderBlocking bool
+}
+
+/*
+Image is an img element.
+*/
+type Image struct {
+	// The current source URL.
+	URL string
+
+	// A CSS selector that identifies the element.
+	Selector string
+
+	// The element has an alt attribute and its value.
+	HasAlt bool
+	Alt    string
+
+	// The element is hidden from assistive technology with role="none" or
+	// role="presentation".
+	Presentational bool
+
+	// The intrinsic size in pixels.
+	NaturalWidth  int
+	NaturalHeight int
+
+	// The displayed size in CSS pixels.
+	DisplayWidth  float64
+	DisplayHeight float64
+}
+
+/*
+Text is an element that contains text.
+*/
+type Text struct {
+	// A CSS selector that identifies the element.
+	Selector string
+
+	// The beginning of the text.
+	Text string
+
+	// The computed text color, e.g. "rgb(0, 0, 0)".
+	Color string
+
+	// The colors behind the text, e.g. the stops of a gradient. Empty if the
+	// background is unknown, e.g. an image.
+	Backgrounds []string
+
+	// The computed font size in CSS pixels.
+	FontSize float64
+
+	// The computed font weight, e.g. 400 or 700.
+	FontWeight int
+}
+
+/*
+Message is a console message or a browser log entry.
+*/
+type Message struct {
+	// The message source, e.g. "console-api", "exception", "network" or
+	// "security".
+	Source string
+
+	// The message level, e.g. "error" or "warning".
+	Level string
+
+	// The message text.
+	Text string
+
+	// The location of the message, if known (1-based).
+	URL  string
+	Line int
+}
+
+/*
+Audit is a check of a page.
+*/
+type Audit struct {
+	// The audit ID, e.g. "image-alt".
+	ID string
+
+	// A short description of a passing page.
+	Title string
+
+	// What the audit checks and why it matters.
+	Description string
+
+	// The weight of the audit in the report score.
+	Weight float64
+
+	// Check scores the page between 0 and 1, and returns a summary for
+	// display and the items that failed the audit.
+	Check func(page *Page) (score float64, display string, details []*Item)
+}
+
+/*
+PassScore is the score at which an audit passes.
+*/
+const PassScore = 0.9
+
+/*
+Result is the result of an audit.
+*/
+type Result struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"`
+
+	// The score between 0 and 1.
+	Score float64 `json:"score"`
+
+	// The score is at least PassScore.
+	Passed bool `json:"passed"`
+
+	// A summary for display, e.g. "3 resources".
+	DisplayValue string `json:"displayValue,omitempty"`
+
+	// The items that failed the audit.
+	Details []*Item `json:"details,omitempty"`
+}
+
+/*
+Item is a resource or element that failed an audit.
+*/
+type Item struct {
+	URL      string `json:"url,omitempty"`
+	Selector string `json:"selector,omitempty"`
+	Text     string `json:"text,omitempty"`
+
+	// An audit specific value, e.g. a contrast ratio.
+	Value string `json:"value,omitempty"`
+
+	// The size of the resource and the bytes that could be saved.
+	Bytes       int `json:"bytes,omitempty"`
+	WastedBytes int `json:"wastedBytes,omitempty"`
+}
+
+/*
+Report is the result of auditing a page.
+*/
+type Report struct {
+	// The page URL.
+	URL string `json:"url"`
+
+	// The time the page was audited.
+	FetchTime time.Time `json:"fetchTime"`
+
+	// The weighted mean of the audit scores between 0 and 100.
+	Score float64 `json:"score"`
+
+	// The audit results in the order they ran.
+	Audits []*Result `json:"audits"`
+}
+
+/*
+Run audits a page. If no audits are given the Default audits run.
+*/
+func Run(page *Page, audits ...*Audit) *Report {
+	if 0 == len(audits) {
+		audits = Default
+	}
+	report := &Report{
+		URL:       page.URL,
+		FetchTime: time.Now(),
+		Audits:    []*Result{},
+	}
+	var score, weight float64
+	for _, audit := range audits {
+		result := &Result{
+			ID:          audit.ID,
+			Title:       audit.Title,
+			Description: audit.Description,
+			Weight:      audit.Weight,
+		}
+		result.Score, result.DisplayValue, result.Details = audit.Check(page)
+		result.Score = math.Floor(clamp(result.Score)*100+0.5) / 100
+		result.Passed = result.Score >= PassScore
+		report.Audits = append(report.Audits, result)
+		score += result.Score * audit.Weight
+		weight += audit.Weight
+	}
+	if weight > 0 {
+		report.Score = math.Floor(100*score/weight + 0.5)
+	}
+	return report
+}
+
+/*
+Audit returns the result of an audit by ID, or nil.
+*/
+func (report *Report) Audit(id string) *Result {
+	for _, result := range report.Audits {
+		if id == result.ID {
+			return result
+		}
+	}
+	return nil
+}
+
+/*
+Failed returns the results of the audits that didn't pass.
+*/
+func (report *Report) Failed() []*Result {
+	failed := []*Result{}
+	for _, result := range report.Audits {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+/*
+WriteJSON writes the report to w as JSON.
+*/
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); nil != err {
+		return errs.Wrap(err, codes.AuditWriteFailed, "could not write the audit report")
+	}
+	return nil
+}
+
+/*
+String implements Stringer.
+*/
+func (report *Report) String() string {
+	lines := []string{fmt.Sprintf("%s: score %g, %d of %d audits failed", report.URL, report.Score, len(report.Failed()), len(report.Audits))}
+	for _, result := range report.Audits {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		line := fmt.Sprintf("  [%s] %s: %s", status, result.ID, result.Title)
+		if "" != result.DisplayValue {
+			line += " (" + result.DisplayValue + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+/*
+clamp limits a score to [0, 1].
+*/
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+/*
+testAudit returns an audit with a fixed score.
+*/
+func testAudit(id string, weight, score float64, details ...*Item) *Audit {
+	return &Audit{
+		ID:     id,
+		Title:  id,
+		Weight: weight,
+		Check: func(page *Page) (float64, string, []*Item) {
+			return score, "display " + id, details
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	report := Run(&Page{URL: "https://example.com/"},
+		testAudit("a", 3, 1),
+		testAudit("b", 1, 0.333, &Item{URL: "https://example.com/b.js"}),
+		testAudit("c", 0, -1),
+	)
+	if "https://example.com/" != report.URL || 3 != len(report.Audits) {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	// (3 * 1 + 1 * 0.33) / 4
+	if 83 != report.Score {
+		t.Errorf("Expected score 83, received %g", report.Score)
+	}
+	if b := report.Audit("b"); nil == b || 0.33 != b.Score || b.Passed || 1 != len(b.Details) {
+		t.Errorf("Unexpected result %+v", b)
+	}
+	if c := report.Audit("c"); 0 != c.Score {
+		t.Errorf("Expected scores to be clamped, received %g", c.Score)
+	}
+	if failed := report.Failed(); 2 != len(failed) || "b" != failed[0].ID {
+		t.Errorf("Expected b and c to fail, received %+v", failed)
+	}
+	if nil != report.Audit("d") {
+		t.Errorf("Expected nil, received a result")
+	}
+	if !strings.Contains(report.String(), "[FAIL] b: b (display b)") {
+		t.Errorf("Unexpected summary %s", report)
+	}
+}
+
+func TestRunDefault(t *testing.T) {
+	report := Run(&Page{URL: "https://example.com/"})
+	if len(Default) != len(report.Audits) || 100 != report.Score {
+		t.Errorf("Expected an empty page to pass the default audits, received %s", report)
+	}
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	report := Run(&Page{URL: "https://example.com/"}, testAudit("a", 1, 0.5, &Item{URL: "https://example.com/a.js", WastedBytes: 10}))
+	buf := &bytes.Buffer{}
+	if err := report.WriteJSON(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	decoded := &struct {
+		Score  float64 `json:"score"`
+		Audits []struct {
+			ID      string  `json:"id"`
+			Score   float64 `json:"score"`
+			Passed  bool    `json:"passed"`
+			Details []struct {
+				URL         string `json:"url"`
+				WastedBytes int    `json:"wastedBytes"`
+			} `json:"details"`
+		} `json:"audits"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), decoded); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 50 != decoded.Score || 1 != len(decoded.Audits) || "a" != decoded.Audits[0].ID || 10 != decoded.Audits[0].Details[0].WastedBytes {
+		t.Errorf("Unexpected report %s", buf.String())
+	}
+}
@@ -97,6 +97,196 @@ const (
 	WebsocketPanic
 )
 
+////////////////////////////////////////////////////////////////////////////
+// Input errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// InputKeyUnknown - 7000: The key is not defined in the keyboard layout.
+	InputKeyUnknown std.Code = iota + 7000
+	// InputDispatchFailed - 7001: An input event could not be dispatched.
+	InputDispatchFailed
+	// InputElementNotVisible - 7002: The element position could not be determined.
+	InputElementNotVisible
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Runtime errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// RuntimeEvaluateFailed - 8000: JavaScript evaluation failed.
+	RuntimeEvaluateFailed std.Code = iota + 8000
+	// RuntimeDecodeFailed - 8001: A JavaScript value could not be decoded.
+	RuntimeDecodeFailed
+	// RuntimeArgumentInvalid - 8002: A call argument could not be serialized.
+	RuntimeArgumentInvalid
+	// RuntimeBindingFailed - 8003: A Go function could not be exposed to the page.
+	RuntimeBindingFailed
+	// RuntimeObjectInvalid - 8004: A remote object was released or its context was destroyed.
+	RuntimeObjectInvalid
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Capture errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// CaptureFailed - 9000: The page could not be captured.
+	CaptureFailed std.Code = iota + 9000
+	// CaptureOptionsInvalid - 9001: The capture options are invalid.
+	CaptureOptionsInvalid
+	// CaptureDecodeFailed - 9002: The captured data could not be decoded.
+	CaptureDecodeFailed
+	// CaptureEncodeFailed - 9003: The captured data could not be encoded.
+	CaptureEncodeFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Stream errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// StreamReadFailed - 10000: A protocol stream could not be read.
+	StreamReadFailed std.Code = iota + 10000
+	// StreamDecodeFailed - 10001: A protocol stream chunk could not be decoded.
+	StreamDecodeFailed
+	// StreamClosed - 10002: The protocol stream is closed.
+	StreamClosed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Emulation errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// EmulationFailed - 11000: The emulation override could not be applied.
+	EmulationFailed std.Code = iota + 11000
+	// EmulationDeviceInvalid - 11001: The device descriptor is invalid.
+	EmulationDeviceInvalid
+	// EmulationConditionsInvalid - 11002: The throttling conditions are invalid.
+	EmulationConditionsInvalid
+	// EmulationVirtualTimeInvalid - 11003: The virtual time has already passed.
+	EmulationVirtualTimeInvalid
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Rendering errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// RenderingFailed - 12000: A BeginFrame could not be issued.
+	RenderingFailed std.Code = iota + 12000
+	// RenderingClockInvalid - 12001: The frame clock settings are invalid.
+	RenderingClockInvalid
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Visual comparison errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// VisualImageInvalid - 13000: An image could not be decoded.
+	VisualImageInvalid std.Code = iota + 13000
+	// VisualOptionsInvalid - 13001: The comparison options are invalid.
+	VisualOptionsInvalid
+	// VisualBaselineMissing - 13002: The baseline image does not exist.
+	VisualBaselineMissing
+	// VisualBaselineFailed - 13003: A baseline or diff image could not be read or written.
+	VisualBaselineFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Tracing errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// TracingFailed - 14000: Tracing could not be started or stopped.
+	TracingFailed std.Code = iota + 14000
+	// TracingWriteFailed - 14001: The trace could not be written.
+	TracingWriteFailed
+	// TracingDataInvalid - 14002: Trace data could not be parsed.
+	TracingDataInvalid
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Profile errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// ProfileInvalid - 15000: The profile is invalid.
+	ProfileInvalid std.Code = iota + 15000
+	// ProfileWriteFailed - 15001: The profile could not be written.
+	ProfileWriteFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Coverage errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// CoverageFailed - 16000: Coverage could not be started, taken or stopped.
+	CoverageFailed std.Code = iota + 16000
+	// CoverageSourceMapInvalid - 16001: A source map could not be loaded or decoded.
+	CoverageSourceMapInvalid
+	// CoverageWriteFailed - 16002: The coverage report could not be written.
+	CoverageWriteFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Heap snapshot errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// HeapSnapshotFailed - 17000: The heap snapshot could not be taken.
+	HeapSnapshotFailed std.Code = iota + 17000
+	// HeapSnapshotWriteFailed - 17001: The heap snapshot could not be written.
+	HeapSnapshotWriteFailed
+	// HeapSnapshotInvalid - 17002: The heap snapshot could not be parsed.
+	HeapSnapshotInvalid
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Memory leak errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// LeakDetected - 18000: Memory grew across the iterations of a scenario.
+	LeakDetected std.Code = iota + 18000
+	// LeakDetectionFailed - 18001: The scenario or a memory measurement failed.
+	LeakDetectionFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Metrics errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// MetricsFailed - 19000: Performance metrics could not be sampled.
+	MetricsFailed std.Code = iota + 19000
+	// MetricsWriteFailed - 19001: The metrics could not be written.
+	MetricsWriteFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Audit errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// AuditFailed - 20000: The page could not be audited.
+	AuditFailed std.Code = iota + 20000
+	// AuditWriteFailed - 20001: The audit report could not be written.
+	AuditWriteFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Source map errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// SourceMapInvalid - 21000: A source map could not be decoded.
+	SourceMapInvalid std.Code = iota + 21000
+	// SourceMapLoadFailed - 21001: A source map could not be loaded.
+	SourceMapLoadFailed
+)
+
+////////////////////////////////////////////////////////////////////////////
+// Debugger errors
+////////////////////////////////////////////////////////////////////////////
+const (
+	// DebuggerFailed - 22000: A debugger command failed.
+	DebuggerFailed std.Code = iota + 22000
+	// DebuggerNotPaused - 22001: The debugger is not paused.
+	DebuggerNotPaused
+	// DebuggerBreakpointInvalid - 22002: A breakpoint location could not be resolved.
+	DebuggerBreakpointInvalid
+)
+
 func init() {
 	errs.Codes[Unspecified] = errs.ErrCode{Int: "The error code was unspecified", Ext: "An unknown error occurred", HTTP: 500}
 	errs.Codes[Unknown] = errs.ErrCode{Int: "An unspecified error occurred", Ext: "An unknown error occurred", HTTP: 500}
@@ -126,4 +316,67 @@ func init() {
 	errs.Codes[WebsocketConnectFailed] = errs.ErrCode{Int: "Websocket connection failed", Ext: "An unknown error occurred", HTTP: 500}
 	errs.Codes[WebsocketNotConnected] = errs.ErrCode{Int: "Websocket not connected", Ext: "An unknown error occurred", HTTP: 500}
 	errs.Codes[WebsocketPanic] = errs.ErrCode{Int: "A panic occurred while reading from a websocket", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[InputKeyUnknown] = errs.ErrCode{Int: "The key is not defined in the keyboard layout", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[InputDispatchFailed] = errs.ErrCode{Int: "An input event could not be dispatched", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[InputElementNotVisible] = errs.ErrCode{Int: "The element position could not be determined", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[RuntimeEvaluateFailed] = errs.ErrCode{Int: "JavaScript evaluation failed", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[RuntimeDecodeFailed] = errs.ErrCode{Int: "A JavaScript value could not be decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[RuntimeArgumentInvalid] = errs.ErrCode{Int: "A call argument could not be serialized", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[RuntimeBindingFailed] = errs.ErrCode{Int: "A Go function could not be exposed to the page", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[RuntimeObjectInvalid] = errs.ErrCode{Int: "A remote object was released or its context was destroyed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[CaptureFailed] = errs.ErrCode{Int: "The page could not be captured", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[CaptureOptionsInvalid] = errs.ErrCode{Int: "The capture options are invalid", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[CaptureDecodeFailed] = errs.ErrCode{Int: "The captured data could not be decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[CaptureEncodeFailed] = errs.ErrCode{Int: "The captured data could not be encoded", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[StreamReadFailed] = errs.ErrCode{Int: "A protocol stream could not be read", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[StreamDecodeFailed] = errs.ErrCode{Int: "A protocol stream chunk could not be decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[StreamClosed] = errs.ErrCode{Int: "The protocol stream is closed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[EmulationFailed] = errs.ErrCode{Int: "The emulation override could not be applied", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[EmulationDeviceInvalid] = errs.ErrCode{Int: "The device descriptor is invalid", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[EmulationConditionsInvalid] = errs.ErrCode{Int: "The throttling conditions are invalid", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[EmulationVirtualTimeInvalid] = errs.ErrCode{Int: "The virtual time has already passed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[RenderingFailed] = errs.ErrCode{Int: "A BeginFrame could not be issued", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[RenderingClockInvalid] = errs.ErrCode{Int: "The frame clock settings are invalid", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[VisualImageInvalid] = errs.ErrCode{Int: "An image could not be decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[VisualOptionsInvalid] = errs.ErrCode{Int: "The comparison options are invalid", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[VisualBaselineMissing] = errs.ErrCode{Int: "The baseline image does not exist", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[VisualBaselineFailed] = errs.ErrCode{Int: "A baseline or diff image could not be read or written", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[TracingFailed] = errs.ErrCode{Int: "Tracing could not be started or stopped", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[TracingWriteFailed] = errs.ErrCode{Int: "The trace could not be written", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[TracingDataInvalid] = errs.ErrCode{Int: "Trace data could not be parsed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[ProfileInvalid] = errs.ErrCode{Int: "The profile is invalid", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[ProfileWriteFailed] = errs.ErrCode{Int: "The profile could not be written", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[CoverageFailed] = errs.ErrCode{Int: "Coverage could not be started, taken or stopped", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[CoverageSourceMapInvalid] = errs.ErrCode{Int: "A source map could not be loaded or decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[CoverageWriteFailed] = errs.ErrCode{Int: "The coverage report could not be written", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[HeapSnapshotFailed] = errs.ErrCode{Int: "The heap snapshot could not be taken", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[HeapSnapshotWriteFailed] = errs.ErrCode{Int: "The heap snapshot could not be written", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[HeapSnapshotInvalid] = errs.ErrCode{Int: "The heap snapshot could not be parsed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[LeakDetected] = errs.ErrCode{Int: "Memory grew across the iterations of a scenario", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[LeakDetectionFailed] = errs.ErrCode{Int: "The scenario or a memory measurement failed", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[MetricsFailed] = errs.ErrCode{Int: "Performance metrics could not be sampled", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[MetricsWriteFailed] = errs.ErrCode{Int: "The metrics could not be written", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[AuditFailed] = errs.ErrCode{Int: "The page could not be audited", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[AuditWriteFailed] = errs.ErrCode{Int: "The audit report could not be written", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[SourceMapInvalid] = errs.ErrCode{Int: "A source map could not be decoded", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[SourceMapLoadFailed] = errs.ErrCode{Int: "A source map could not be loaded", Ext: "An unknown error occurred", HTTP: 500}
+
+	errs.Codes[DebuggerFailed] = errs.ErrCode{Int: "A debugger command failed", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[DebuggerNotPaused] = errs.ErrCode{Int: "The debugger is not paused", Ext: "An unknown error occurred", HTTP: 500}
+	errs.Codes[DebuggerBreakpointInvalid] = errs.ErrCode{Int: "A breakpoint location could not be resolved", Ext: "An unknown error occurred", HTTP: 500}
 }
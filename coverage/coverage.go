@@ -0,0 +1,431 @@
+/*
+Package coverage converts the JavaScript and CSS coverage recorded by the
+Profiler and CSS domains to line coverage, and writes lcov, Istanbul JSON and
+HTML reports. Coverage recorded over several takes or navigations is merged by
+URL, and source maps are applied to report the coverage of the original
+sources.
+
+	report := &coverage.Report{}
+	report.Add(coverage.FromScriptCoverage(script, source))
+	...
+	err := report.WriteLcov(file)
+*/
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+Type is the type of a covered file.
+*/
+type Type string
+
+/*
+Covered file types.
+*/
+const (
+	JavaScript Type = "js"
+	CSS        Type = "css"
+)
+
+/*
+File is the line coverage of a script or style sheet.
+*/
+type File struct {
+	// The file URL.
+	URL string
+
+	// The file type.
+	Type Type
+
+	// Optional. The file source.
+	Source string
+
+	// Optional. The URL of the source map of the file, relative to URL.
+	SourceMapURL string
+
+	// The executable lines.
+	Lines []*Line
+
+	// The functions of a script.
+	Functions []*Function
+
+	// The execution count of each UTF-16 code unit of the source, -1 for code
+	// that is not executable.
+	counts []int
+
+	// The offset of the first code unit of each line.
+	lineStarts []int
+}
+
+/*
+Line is the coverage of a line.
+*/
+type Line struct {
+	// The 1-based line number.
+	Number int
+
+	// The execution count.
+	Count int
+}
+
+/*
+Function is the coverage of a function.
+*/
+type Function struct {
+	// The function name, empty for anonymous functions.
+	Name string
+
+	// The 1-based line and column of the function start.
+	Line   int
+	Column int
+
+	// The number of calls.
+	Count int
+
+	// The UTF-16 offset of the function start.
+	offset int
+}
+
+/*
+span is a covered source range in UTF-16 code units.
+*/
+type span struct {
+	start int
+	end   int
+	count int
+}
+
+/*
+FromScriptCoverage returns the coverage of a script.
+*/
+func FromScriptCoverage(script *profiler.ScriptCoverage, source string) *File {
+	file := newFile(script.URL, JavaScript, source)
+	spans := []*span{}
+	for _, function := range script.Functions {
+		if 0 == len(function.Ranges) {
+			continue
+		}
+		for _, r := range function.Ranges {
+			spans = append(spans, &span{r.StartOffset, r.EndOffset, r.Count})
+		}
+
+		// The top level code of the script is reported as an anonymous
+		// function spanning the script.
+		r := function.Ranges[0]
+		if "" == function.FunctionName && 0 == r.StartOffset && r.EndOffset >= len(file.counts) {
+			continue
+		}
+		file.Functions = append(file.Functions, &Function{
+			Name:   function.FunctionName,
+			Count:  r.Count,
+			offset: r.StartOffset,
+		})
+	}
+	file.paint(spans)
+	file.update()
+	return file
+}
+
+/*
+FromRuleUsage returns the coverage of a style sheet. Each rule is a covered
+region that is executed once if it was used.
+*/
+func FromRuleUsage(url, text string, rules []*css.RuleUsage) *File {
+	file := newFile(url, CSS, text)
+	spans := []*span{}
+	for _, rule := range rules {
+		count := 0
+		if rule.Used {
+			count = 1
+		}
+		spans = append(spans, &span{int(rule.StartOffset), int(rule.EndOffset), count})
+	}
+	file.paint(spans)
+	file.update()
+	return file
+}
+
+/*
+newFile returns a file with no executable code.
+*/
+func newFile(url string, typ Type, source string) *File {
+	file := &File{
+		URL:        url,
+		Type:       typ,
+		Source:     source,
+		lineStarts: []int{0},
+	}
+	units := 0
+	for _, r := range source {
+		if n := utf16.RuneLen(r); n > 0 {
+			units += n
+		} else {
+			units++
+		}
+		if '\n' == r {
+			file.lineStarts = append(file.lineStarts, units)
+		}
+	}
+	file.counts = make([]int, units)
+	for i := range file.counts {
+		file.counts[i] = -1
+	}
+	return file
+}
+
+/*
+paint sets the execution count of the covered ranges. Ranges are nested, the
+innermost range determines the count.
+*/
+func (file *File) paint(spans []*span) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+	for _, s := range spans {
+		start, end := s.start, s.end
+		if start < 0 {
+			start = 0
+		}
+		if end > len(file.counts) {
+			end = len(file.counts)
+		}
+		for i := start; i < end; i++ {
+			file.counts[i] = s.count
+		}
+	}
+}
+
+/*
+update computes the line coverage and function positions from the execution
+counts. A line is executable if it contains executable code that is not
+whitespace, and is covered as often as the first code on the line. Blocks that
+start in the middle of a line, such as the body of an if statement, don't
+change the coverage of the line.
+*/
+func (file *File) update() {
+	file.Lines = []*Line{}
+	line, unit := -1, 0
+	for _, r := range file.Source {
+		n := utf16.RuneLen(r)
+		if n < 1 {
+			n = 1
+		}
+		if !unicode.IsSpace(r) && file.counts[unit] >= 0 {
+			number := file.line(unit) + 1
+			if number != line {
+				file.Lines = append(file.Lines, &Line{Number: number, Count: file.counts[unit]})
+				line = number
+			}
+		}
+		unit += n
+	}
+	for _, function := range file.Functions {
+		line := file.line(function.offset)
+		function.Line = line + 1
+		function.Column = function.offset - file.lineStarts[line] + 1
+	}
+}
+
+/*
+line returns the 0-based line of a UTF-16 offset.
+*/
+func (file *File) line(offset int) int {
+	return sort.Search(len(file.lineStarts), func(i int) bool {
+		return file.lineStarts[i] > offset
+	}) - 1
+}
+
+/*
+Summary returns the number of covered and executable lines.
+*/
+func (file *File) Summary() (covered, total int) {
+	for _, line := range file.Lines {
+		if line.Count > 0 {
+			covered++
+		}
+	}
+	return covered, len(file.Lines)
+}
+
+/*
+FunctionSummary returns the number of called and total functions.
+*/
+func (file *File) FunctionSummary() (called, total int) {
+	for _, function := range file.Functions {
+		if function.Count > 0 {
+			called++
+		}
+	}
+	return called, len(file.Functions)
+}
+
+/*
+Unused returns the number of UTF-16 code units of the source that never ran,
+such as uncalled functions, untaken branches and unused CSS rules, and the
+length of the source. Files without execution counts, such as the original
+sources of a source map, report no unused code.
+*/
+func (file *File) Unused() (unused, length int) {
+	for _, count := range file.counts {
+		if 0 == count {
+			unused++
+		}
+	}
+	return unused, len(file.counts)
+}
+
+/*
+merge adds the coverage of another take of the same file. Coverage of a file
+whose source changed replaces the previous coverage.
+*/
+func (file *File) merge(other *File) {
+	if file.Source != other.Source && "" != file.Source && "" != other.Source {
+		*file = *other
+		return
+	}
+	if "" == file.SourceMapURL {
+		file.SourceMapURL = other.SourceMapURL
+	}
+
+	if nil != file.counts && len(file.counts) == len(other.counts) {
+		for i, count := range other.counts {
+			if count < 0 {
+				continue
+			}
+			if file.counts[i] < 0 {
+				file.counts[i] = 0
+			}
+			file.counts[i] += count
+		}
+		file.mergeFunctions(other)
+		file.update()
+		return
+	}
+
+	// Files without execution counts, such as the original sources of a
+	// source map, are merged by line.
+	file.counts = nil
+	lines := map[int]*Line{}
+	for _, line := range file.Lines {
+		lines[line.Number] = line
+	}
+	for _, line := range other.Lines {
+		if existing, ok := lines[line.Number]; ok {
+			existing.Count += line.Count
+		} else {
+			copied := *line
+			lines[line.Number] = &copied
+			file.Lines = append(file.Lines, &copied)
+		}
+	}
+	sort.Slice(file.Lines, func(i, j int) bool {
+		return file.Lines[i].Number < file.Lines[j].Number
+	})
+	file.mergeFunctions(other)
+}
+
+/*
+mergeFunctions adds the call counts of the functions of another take.
+*/
+func (file *File) mergeFunctions(other *File) {
+	type key struct {
+		name   string
+		line   int
+		column int
+		offset int
+	}
+	functions := map[key]*Function{}
+	for _, function := range file.Functions {
+		functions[key{function.Name, function.Line, function.Column, function.offset}] = function
+	}
+	for _, function := range other.Functions {
+		if existing, ok := functions[key{function.Name, function.Line, function.Column, function.offset}]; ok {
+			existing.Count += function.Count
+			continue
+		}
+		copied := *function
+		file.Functions = append(file.Functions, &copied)
+	}
+	sort.SliceStable(file.Functions, func(i, j int) bool {
+		if file.Functions[i].Line != file.Functions[j].Line {
+			return file.Functions[i].Line < file.Functions[j].Line
+		}
+		return file.Functions[i].Column < file.Functions[j].Column
+	})
+}
+
+/*
+Report is the coverage of a set of files.
+*/
+type Report struct {
+	// The covered files, sorted by URL.
+	Files []*File
+}
+
+/*
+Add adds the coverage of a file. Coverage of a file that is already in the
+report is merged.
+*/
+func (report *Report) Add(file *File) {
+	if existing := report.File(file.URL); nil != existing {
+		existing.merge(file)
+		return
+	}
+	report.Files = append(report.Files, file)
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].URL < report.Files[j].URL
+	})
+}
+
+/*
+File returns the coverage of a URL, or nil.
+*/
+func (report *Report) File(url string) *File {
+	for _, file := range report.Files {
+		if url == file.URL {
+			return file
+		}
+	}
+	return nil
+}
+
+/*
+Summary returns the number of covered and executable lines of all files.
+*/
+func (report *Report) Summary() (covered, total int) {
+	for _, file := range report.Files {
+		c, t := file.Summary()
+		covered += c
+		total += t
+	}
+	return covered, total
+}
+
+/*
+String implements Stringer.
+*/
+func (report *Report) String() string {
+	covered, total := report.Summary()
+	return fmt.Sprintf("%d files, %d of %d lines covered (%s)", len(report.Files), covered, total, percent(covered, total))
+}
+
+/*
+percent formats a coverage ratio.
+*/
+func percent(covered, total int) string {
+	if 0 == total {
+		return "100.0%"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(covered)/float64(total))
+}
@@ -0,0 +1,151 @@
+package coverage
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+htmlReport is the HTML report template. The report is a single file with
+inline styles.
+*/
+var htmlReport = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Coverage report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.2em 0.8em; text-align: left; }
+.summary td, .summary th { border-bottom: 1px solid #ddd; }
+.bar { display: inline-block; width: 100px; height: 0.8em; background: #f2b8b8; }
+.bar span { display: block; height: 100%; background: #8fd18f; }
+.source { font-family: monospace; font-size: 13px; white-space: pre; margin-bottom: 2em; }
+.source td { padding: 0 0.8em; }
+.number, .count { color: #999; text-align: right; user-select: none; }
+.covered { background: #e3f7e3; }
+.uncovered { background: #fbe1e1; }
+</style>
+</head>
+<body>
+<h1>Coverage report</h1>
+<p>{{.Covered}} of {{.Total}} lines covered ({{.Percent}})</p>
+<table class="summary">
+<tr><th>File</th><th>Type</th><th>Lines</th><th></th><th>Functions</th></tr>
+{{range $i, $file := .Files}}<tr>
+<td><a href="#file-{{$i}}">{{$file.URL}}</a></td>
+<td>{{$file.Type}}</td>
+<td>{{$file.Covered}}/{{$file.Total}} ({{$file.Percent}})</td>
+<td><span class="bar"><span style="width: {{$file.Width}}%"></span></span></td>
+<td>{{$file.Called}}/{{$file.Functions}}</td>
+</tr>
+{{end}}</table>
+{{range $i, $file := .Files}}
+<h2 id="file-{{$i}}">{{$file.URL}}</h2>
+<table class="source">
+{{range $file.Lines}}<tr class="{{.Class}}"><td class="number">{{.Number}}</td><td class="count">{{.Count}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+/*
+htmlData is the data rendered by the HTML report template.
+*/
+type htmlData struct {
+	Covered int
+	Total   int
+	Percent string
+	Files   []*htmlFile
+}
+
+/*
+htmlFile is a file in the HTML report.
+*/
+type htmlFile struct {
+	URL       string
+	Type      Type
+	Covered   int
+	Total     int
+	Percent   string
+	Width     int
+	Called    int
+	Functions int
+	Lines     []*htmlLine
+}
+
+/*
+htmlLine is a source line in the HTML report.
+*/
+type htmlLine struct {
+	Number int
+	Count  string
+	Class  string
+	Text   string
+}
+
+/*
+WriteHTML writes the report as a self-contained HTML page that lists the
+coverage of each file and highlights covered and uncovered lines.
+*/
+func (report *Report) WriteHTML(w io.Writer) error {
+	data := &htmlData{}
+	data.Covered, data.Total = report.Summary()
+	data.Percent = percent(data.Covered, data.Total)
+	for _, file := range report.Files {
+		f := &htmlFile{URL: file.URL, Type: file.Type}
+		f.Covered, f.Total = file.Summary()
+		f.Percent = percent(f.Covered, f.Total)
+		f.Width = 100
+		if f.Total > 0 {
+			f.Width = 100 * f.Covered / f.Total
+		}
+		f.Called, f.Functions = file.FunctionSummary()
+		f.Lines = file.htmlLines()
+		data.Files = append(data.Files, f)
+	}
+	if err := htmlReport.Execute(w, data); nil != err {
+		return errs.Wrap(err, codes.CoverageWriteFailed, "could not write the HTML report")
+	}
+	return nil
+}
+
+/*
+htmlLines returns the source lines of a file with their coverage, or only the
+executable lines if the source is not available.
+*/
+func (file *File) htmlLines() []*htmlLine {
+	lines := map[int]*Line{}
+	for _, line := range file.Lines {
+		lines[line.Number] = line
+	}
+	html := func(number int, text string) *htmlLine {
+		line := &htmlLine{Number: number, Text: text}
+		if covered, ok := lines[number]; ok {
+			line.Class, line.Count = "uncovered", "0"
+			if covered.Count > 0 {
+				line.Class, line.Count = "covered", strconv.Itoa(covered.Count)
+			}
+		}
+		return line
+	}
+
+	result := []*htmlLine{}
+	if "" == file.Source {
+		for _, line := range file.Lines {
+			result = append(result, html(line.Number, ""))
+		}
+		return result
+	}
+	for i, text := range strings.Split(file.Source, "\n") {
+		result = append(result, html(i+1, strings.TrimRight(text, "\r")))
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/css"
+)
+
+func TestWriteHTML(t *testing.T) {
+	report := &Report{}
+	report.Add(FromScriptCoverage(scriptCoverage(2), testScript))
+	report.Add(FromRuleUsage("https://example.com/<a>.css", "a {}", []*css.RuleUsage{{EndOffset: 4, Used: true}}))
+	report.Add(&File{URL: "https://example.com/src/b.js", Lines: []*Line{{Number: 5}}})
+	buf := &bytes.Buffer{}
+	if err := report.WriteHTML(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	html := buf.String()
+	for _, expected := range []string{
+		"5 of 9 lines covered (55.6%)",
+		`<a href="#file-1">https://example.com/app.js</a>`,
+		`<tr class="covered"><td class="number">1</td><td class="count">2</td><td>function used() {</td></tr>`,
+		`<tr class="uncovered"><td class="number">5</td><td class="count">0</td><td>  if (x) { y(); }</td></tr>`,
+		`<tr class=""><td class="number">8</td><td class="count"></td><td></td></tr>`,
+		"https://example.com/&lt;a&gt;.css",
+		`<tr class="uncovered"><td class="number">5</td><td class="count">0</td><td></td></tr>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected the report to contain %s", expected)
+		}
+	}
+	if strings.Contains(html, "<a>.css") {
+		t.Errorf("Expected URLs to be escaped")
+	}
+}
@@ -0,0 +1,120 @@
+package coverage
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+istanbulFile is the Istanbul coverage of a file. Each executable line is a
+statement.
+
+https://github.com/istanbuljs/istanbuljs/blob/master/docs/raw-output.md
+*/
+type istanbulFile struct {
+	Path         string                       `json:"path"`
+	StatementMap map[string]*istanbulLocation `json:"statementMap"`
+	FnMap        map[string]*istanbulFunction `json:"fnMap"`
+	BranchMap    map[string]interface{}       `json:"branchMap"`
+	S            map[string]int               `json:"s"`
+	F            map[string]int               `json:"f"`
+	B            map[string][]int             `json:"b"`
+}
+
+/*
+istanbulLocation is a source range.
+*/
+type istanbulLocation struct {
+	Start *istanbulPosition `json:"start"`
+	End   *istanbulPosition `json:"end"`
+}
+
+/*
+istanbulPosition is a 1-based line and a 0-based column.
+*/
+type istanbulPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+/*
+istanbulFunction is a function declaration.
+*/
+type istanbulFunction struct {
+	Name string            `json:"name"`
+	Decl *istanbulLocation `json:"decl"`
+	Loc  *istanbulLocation `json:"loc"`
+	Line int               `json:"line"`
+}
+
+/*
+WriteIstanbul writes the report in the Istanbul JSON format read by nyc and
+istanbul reporters, keyed by file URL.
+*/
+func (report *Report) WriteIstanbul(w io.Writer) error {
+	files := map[string]*istanbulFile{}
+	for _, file := range report.Files {
+		istanbul := &istanbulFile{
+			Path:         file.URL,
+			StatementMap: map[string]*istanbulLocation{},
+			FnMap:        map[string]*istanbulFunction{},
+			BranchMap:    map[string]interface{}{},
+			S:            map[string]int{},
+			F:            map[string]int{},
+			B:            map[string][]int{},
+		}
+		lengths := file.lineLengths()
+		for i, line := range file.Lines {
+			key := strconv.Itoa(i)
+			end := 0
+			if line.Number <= len(lengths) {
+				end = lengths[line.Number-1]
+			}
+			istanbul.StatementMap[key] = &istanbulLocation{
+				Start: &istanbulPosition{Line: line.Number},
+				End:   &istanbulPosition{Line: line.Number, Column: end},
+			}
+			istanbul.S[key] = line.Count
+		}
+		names := file.functionNames()
+		for i, function := range file.Functions {
+			key := strconv.Itoa(i)
+			position := &istanbulPosition{Line: function.Line, Column: function.Column - 1}
+			location := &istanbulLocation{Start: position, End: position}
+			istanbul.FnMap[key] = &istanbulFunction{
+				Name: names[i],
+				Decl: location,
+				Loc:  location,
+				Line: function.Line,
+			}
+			istanbul.F[key] = function.Count
+		}
+		files[file.URL] = istanbul
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(files); nil != err {
+		return errs.Wrap(err, codes.CoverageWriteFailed, "could not write the Istanbul report")
+	}
+	return nil
+}
+
+/*
+lineLengths returns the length of each source line in characters.
+*/
+func (file *File) lineLengths() []int {
+	lengths := []int{0}
+	for _, r := range file.Source {
+		if '\n' == r {
+			lengths = append(lengths, 0)
+		} else if '\r' != r {
+			lengths[len(lengths)-1]++
+		}
+	}
+	return lengths
+}
@@ -0,0 +1,34 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteIstanbul(t *testing.T) {
+	report := &Report{}
+	report.Add(FromScriptCoverage(scriptCoverage(2), testScript))
+	buf := &bytes.Buffer{}
+	if err := report.WriteIstanbul(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	files := map[string]*istanbulFile{}
+	if err := json.Unmarshal(buf.Bytes(), &files); nil != err {
+		t.Fatalf("Expected Istanbul JSON, received error: %v", err)
+	}
+	file := files["https://example.com/app.js"]
+	if nil == file || "https://example.com/app.js" != file.Path {
+		t.Fatalf("Expected the file to be keyed by URL, received %s", buf.String())
+	}
+	if 7 != len(file.S) || 2 != file.S["0"] || 0 != file.S["3"] || 1 != file.S["6"] {
+		t.Errorf("Unexpected statement counts %v", file.S)
+	}
+	if 17 != file.StatementMap["0"].End.Column || 1 != file.StatementMap["0"].Start.Line {
+		t.Errorf("Expected statement 0 to span line 1, received %+v", file.StatementMap["0"].End)
+	}
+	if 2 != len(file.F) || "unused" != file.FnMap["1"].Name || 4 != file.FnMap["1"].Line || 0 != file.F["1"] {
+		t.Errorf("Unexpected functions %v", file.F)
+	}
+}
@@ -0,0 +1,64 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+WriteLcov writes the report in the lcov tracefile format read by genhtml and
+most coverage services.
+
+http://ltp.sourceforge.net/coverage/lcov/geninfo.1.php
+*/
+func (report *Report) WriteLcov(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	for _, file := range report.Files {
+		fmt.Fprintf(buf, "TN:\nSF:%s\n", file.URL)
+		names := file.functionNames()
+		for i, function := range file.Functions {
+			fmt.Fprintf(buf, "FN:%d,%s\n", function.Line, names[i])
+		}
+		for i, function := range file.Functions {
+			fmt.Fprintf(buf, "FNDA:%d,%s\n", function.Count, names[i])
+		}
+		called, functions := file.FunctionSummary()
+		fmt.Fprintf(buf, "FNF:%d\nFNH:%d\n", functions, called)
+		for _, line := range file.Lines {
+			fmt.Fprintf(buf, "DA:%d,%d\n", line.Number, line.Count)
+		}
+		covered, lines := file.Summary()
+		fmt.Fprintf(buf, "LF:%d\nLH:%d\nend_of_record\n", lines, covered)
+	}
+	if err := buf.Flush(); nil != err {
+		return errs.Wrap(err, codes.CoverageWriteFailed, "could not write the lcov report")
+	}
+	return nil
+}
+
+/*
+functionNames returns unique names for the functions of a file. Anonymous and
+duplicate names are qualified with the function position.
+*/
+func (file *File) functionNames() []string {
+	seen := map[string]int{}
+	for _, function := range file.Functions {
+		seen[function.Name]++
+	}
+	names := make([]string, len(file.Functions))
+	for i, function := range file.Functions {
+		switch {
+		case "" == function.Name:
+			names[i] = fmt.Sprintf("(anonymous_%d:%d)", function.Line, function.Column)
+		case seen[function.Name] > 1:
+			names[i] = fmt.Sprintf("%s_%d:%d", function.Name, function.Line, function.Column)
+		default:
+			names[i] = function.Name
+		}
+	}
+	return names
+}
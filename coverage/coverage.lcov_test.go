@@ -0,0 +1,52 @@
+package coverage
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteLcov(t *testing.T) {
+	report := &Report{}
+	report.Add(FromScriptCoverage(scriptCoverage(2), testScript))
+	buf := &bytes.Buffer{}
+	if err := report.WriteLcov(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	expected := `TN:
+SF:https://example.com/app.js
+FN:1,used
+FN:4,unused
+FNDA:2,used
+FNDA:0,unused
+FNF:2
+FNH:1
+DA:1,2
+DA:2,2
+DA:3,2
+DA:4,0
+DA:5,0
+DA:6,0
+DA:7,1
+LF:7
+LH:4
+end_of_record
+`
+	if expected != buf.String() {
+		t.Errorf("Unexpected lcov report:\n%s", buf.String())
+	}
+}
+
+func TestFunctionNames(t *testing.T) {
+	file := &File{Functions: []*Function{
+		{Name: "f", Line: 1, Column: 1},
+		{Line: 2, Column: 5},
+		{Name: "g", Line: 3, Column: 1},
+		{Name: "g", Line: 4, Column: 1},
+	}}
+	names := file.functionNames()
+	for i, expected := range []string{"f", "(anonymous_2:5)", "g_3:1", "g_4:1"} {
+		if expected != names[i] {
+			t.Errorf("Expected '%s', received '%s'", expected, names[i])
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/sourcemap"
+)
+
+/*
+ApplySourceMaps replaces the coverage of files that reference a source map
+with the coverage of their original sources. Source maps in data URLs are
+decoded, other source maps are loaded with fetch. Files whose source map can't
+be loaded or decoded are kept and the first error is returned.
+*/
+func (report *Report) ApplySourceMaps(fetch func(url string) ([]byte, error)) error {
+	var firstErr error
+	files := report.Files
+	report.Files = nil
+	for _, file := range files {
+		if "" == file.SourceMapURL || nil == file.counts {
+			report.Add(file)
+			continue
+		}
+		originals, err := file.applySourceMap(fetch)
+		if nil != err {
+			if nil == firstErr {
+				firstErr = err
+			}
+			report.Add(file)
+			continue
+		}
+		for _, original := range originals {
+			report.Add(original)
+		}
+	}
+	return firstErr
+}
+
+/*
+applySourceMap loads the source map of a file and returns the coverage of the
+original sources.
+*/
+func (file *File) applySourceMap(fetch func(url string) ([]byte, error)) ([]*File, error) {
+	sm, err := sourcemap.Load(file.URL, file.SourceMapURL, fetch)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.CoverageSourceMapInvalid, fmt.Sprintf("could not apply the source map of %s", file.URL))
+	}
+	originals := make([]*File, len(sm.Sources))
+	for i, source := range sm.Sources {
+		originals[i] = &File{
+			URL:   source,
+			Type:  file.Type,
+			Lines: []*Line{},
+		}
+		if nil != sm.SourcesContent[i] {
+			originals[i].Source = *sm.SourcesContent[i]
+		}
+	}
+
+	// Each mapped segment covers the generated code up to the next segment.
+	// An original line is covered as often as the segment it starts with.
+	type lineStart struct {
+		column int
+		count  int
+	}
+	starts := make([]map[int]*lineStart, len(sm.Sources))
+	for i := range starts {
+		starts[i] = map[int]*lineStart{}
+	}
+	for number := 0; number < sm.Lines() && number < len(file.lineStarts); number++ {
+		segments := sm.Segments(number)
+		lineEnd := len(file.counts)
+		if number+1 < len(file.lineStarts) {
+			lineEnd = file.lineStarts[number+1]
+		}
+		for i, seg := range segments {
+			if seg.Source < 0 {
+				continue
+			}
+			end := lineEnd
+			if i+1 < len(segments) && file.lineStarts[number]+segments[i+1].Column < end {
+				end = file.lineStarts[number] + segments[i+1].Column
+			}
+			count := -1
+			for offset := file.lineStarts[number] + seg.Column; offset < end && count < 0; offset++ {
+				count = file.counts[offset]
+			}
+			if count < 0 {
+				continue
+			}
+			if start, ok := starts[seg.Source][seg.SourceLine]; !ok || seg.SourceColumn < start.column {
+				starts[seg.Source][seg.SourceLine] = &lineStart{seg.SourceColumn, count}
+			}
+		}
+	}
+	for i, original := range originals {
+		for line, start := range starts[i] {
+			original.Lines = append(original.Lines, &Line{Number: line + 1, Count: start.count})
+		}
+		sort.Slice(original.Lines, func(a, b int) bool {
+			return original.Lines[a].Number < original.Lines[b].Number
+		})
+	}
+
+	for _, function := range file.Functions {
+		line := file.line(function.offset)
+		pos, ok := sm.Original(line, function.offset-file.lineStarts[line])
+		if !ok {
+			continue
+		}
+		name := function.Name
+		if "" == name {
+			name = pos.Name
+		}
+		index := sm.SourceIndex(pos.Source)
+		originals[index].Functions = append(originals[index].Functions, &Function{
+			Name:   name,
+			Line:   pos.Line + 1,
+			Column: pos.Column + 1,
+			Count:  function.Count,
+		})
+	}
+
+	mapped := []*File{}
+	for _, original := range originals {
+		if len(original.Lines) > 0 || len(original.Functions) > 0 {
+			mapped = append(mapped, original)
+		}
+	}
+	return mapped, nil
+}
@@ -0,0 +1,83 @@
+package coverage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+testBundle is a bundle of a.js and b.js with its source map. A is called, B is
+not.
+*/
+const (
+	testBundle    = "A;B;\n"
+	testSourceMap = `{"version":3,"sourceRoot":"src","sources":["a.js","b.js"],"sourcesContent":["A;\n",null],"names":[],"mappings":"AAAA,ECIE"}`
+)
+
+/*
+bundleCoverage returns the coverage of testBundle.
+*/
+func bundleCoverage(sourceMapURL string) *File {
+	file := FromScriptCoverage(&profiler.ScriptCoverage{
+		URL: "https://example.com/dist/bundle.js",
+		Functions: []*profiler.FunctionCoverage{
+			{Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: 5, Count: 1}}},
+			{FunctionName: "B", Ranges: []*profiler.CoverageRange{{StartOffset: 2, EndOffset: 4, Count: 0}}},
+		},
+	}, testBundle)
+	file.SourceMapURL = sourceMapURL
+	return file
+}
+
+func TestApplySourceMaps(t *testing.T) {
+	report := &Report{}
+	report.Add(bundleCoverage("data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(testSourceMap))))
+	if err := report.ApplySourceMaps(nil); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 2 != len(report.Files) {
+		t.Fatalf("Expected the bundle to be replaced by 2 sources, received %v", report.Files)
+	}
+	a, b := report.File("https://example.com/dist/src/a.js"), report.File("https://example.com/dist/src/b.js")
+	if nil == a || nil == b {
+		t.Fatalf("Expected sources relative to the source root, received %s and %s", report.Files[0].URL, report.Files[1].URL)
+	}
+	if "A;\n" != a.Source || 1 != len(a.Lines) || 1 != a.Lines[0].Count {
+		t.Errorf("Expected a.js line 1 to be covered, received %+v", a.Lines)
+	}
+	if 1 != len(b.Lines) || 5 != b.Lines[0].Number || 0 != b.Lines[0].Count {
+		t.Errorf("Expected b.js line 5 not to be covered, received %+v", b.Lines)
+	}
+	if 1 != len(b.Functions) || "B" != b.Functions[0].Name || 5 != b.Functions[0].Line || 3 != b.Functions[0].Column {
+		t.Errorf("Expected function B at 5:3, received %+v", b.Functions)
+	}
+}
+
+func TestApplySourceMapsFetch(t *testing.T) {
+	report := &Report{}
+	report.Add(bundleCoverage("bundle.js.map"))
+	fetched := ""
+	if err := report.ApplySourceMaps(func(url string) ([]byte, error) {
+		fetched = url
+		return []byte(testSourceMap), nil
+	}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/dist/bundle.js.map" != fetched || 2 != len(report.Files) {
+		t.Errorf("Expected the source map to be fetched, received %s", fetched)
+	}
+
+	report = &Report{}
+	report.Add(bundleCoverage("bundle.js.map"))
+	if err := report.ApplySourceMaps(func(url string) ([]byte, error) {
+		return nil, fmt.Errorf("not found")
+	}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 1 != len(report.Files) || "https://example.com/dist/bundle.js" != report.Files[0].URL {
+		t.Errorf("Expected the bundle to be kept")
+	}
+}
@@ -0,0 +1,144 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+var testScript = "function used() {\n  return 1;\n}\nfunction unused() {\n  if (x) { y(); }\n}\nused();\n"
+
+/*
+scriptCoverage returns the coverage of testScript after calling used() count
+times.
+*/
+func scriptCoverage(count int) *profiler.ScriptCoverage {
+	unused := strings.Index(testScript, "function unused")
+	block := strings.Index(testScript, "{ y(); }")
+	return &profiler.ScriptCoverage{
+		ScriptID: "1",
+		URL:      "https://example.com/app.js",
+		Functions: []*profiler.FunctionCoverage{
+			{Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: len(testScript), Count: 1}}},
+			{FunctionName: "used", Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: unused - 1, Count: count}}},
+			{FunctionName: "unused", IsBlockCoverage: true, Ranges: []*profiler.CoverageRange{
+				{StartOffset: unused, EndOffset: len(testScript) - 9, Count: 0},
+				{StartOffset: block, EndOffset: block + 8, Count: 0},
+			}},
+		},
+	}
+}
+
+/*
+lineCounts returns the line counts of a file by line number.
+*/
+func lineCounts(file *File) map[int]int {
+	counts := map[int]int{}
+	for _, line := range file.Lines {
+		counts[line.Number] = line.Count
+	}
+	return counts
+}
+
+func TestFromScriptCoverage(t *testing.T) {
+	file := FromScriptCoverage(scriptCoverage(2), testScript)
+	expected := map[int]int{1: 2, 2: 2, 3: 2, 4: 0, 5: 0, 6: 0, 7: 1}
+	counts := lineCounts(file)
+	if len(expected) != len(counts) {
+		t.Errorf("Expected %d lines, received %v", len(expected), counts)
+	}
+	for line, count := range expected {
+		if count != counts[line] {
+			t.Errorf("Line %d: expected %d, received %d", line, count, counts[line])
+		}
+	}
+	if 2 != len(file.Functions) || "unused" != file.Functions[1].Name || 4 != file.Functions[1].Line || 1 != file.Functions[1].Column {
+		t.Errorf("Unexpected functions %+v", file.Functions)
+	}
+	if covered, total := file.Summary(); 4 != covered || 7 != total {
+		t.Errorf("Expected 4 of 7 lines covered, received %d of %d", covered, total)
+	}
+	if called, total := file.FunctionSummary(); 1 != called || 2 != total {
+		t.Errorf("Expected 1 of 2 functions called, received %d of %d", called, total)
+	}
+}
+
+func TestFromScriptCoverageUTF16(t *testing.T) {
+	// The emoji is two UTF-16 code units.
+	source := "var s = '😀';\nf();\n"
+	file := FromScriptCoverage(&profiler.ScriptCoverage{
+		URL: "https://example.com/emoji.js",
+		Functions: []*profiler.FunctionCoverage{
+			{Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: 20, Count: 1}}},
+			{FunctionName: "f", Ranges: []*profiler.CoverageRange{{StartOffset: 14, EndOffset: 18, Count: 0}}},
+		},
+	}, source)
+	if counts := lineCounts(file); 1 != counts[1] || 0 != counts[2] {
+		t.Errorf("Unexpected line counts %v", counts)
+	}
+	if 2 != file.Functions[0].Line || 1 != file.Functions[0].Column {
+		t.Errorf("Expected f at 2:1, received %d:%d", file.Functions[0].Line, file.Functions[0].Column)
+	}
+}
+
+func TestFromRuleUsage(t *testing.T) {
+	text := "body {\n  margin: 0;\n}\n.unused {\n  color: red;\n}\n"
+	unused := strings.Index(text, ".unused")
+	file := FromRuleUsage("https://example.com/app.css", text, []*css.RuleUsage{
+		{StyleSheetID: "1", StartOffset: 0, EndOffset: float64(unused - 1), Used: true},
+		{StyleSheetID: "1", StartOffset: float64(unused), EndOffset: float64(len(text) - 1), Used: false},
+	})
+	if CSS != file.Type {
+		t.Errorf("Expected a CSS file, received %s", file.Type)
+	}
+	if covered, total := file.Summary(); 3 != covered || 6 != total {
+		t.Errorf("Expected 3 of 6 lines covered, received %d of %d", covered, total)
+	}
+}
+
+func TestFileUnused(t *testing.T) {
+	file := FromScriptCoverage(scriptCoverage(1), testScript)
+	unused, length := file.Unused()
+	expected := len(testScript) - 9 - strings.Index(testScript, "function unused")
+	if expected != unused || len(testScript) != length {
+		t.Errorf("Expected %d of %d unused, received %d of %d", expected, len(testScript), unused, length)
+	}
+	file.merge(&File{URL: file.URL, Lines: []*Line{{Number: 1, Count: 1}}})
+	if unused, length := file.Unused(); 0 != unused || 0 != length {
+		t.Errorf("Expected no unused code without counts, received %d of %d", unused, length)
+	}
+}
+
+func TestReportAdd(t *testing.T) {
+	report := &Report{}
+	report.Add(FromScriptCoverage(scriptCoverage(1), testScript))
+	report.Add(FromScriptCoverage(scriptCoverage(2), testScript))
+	report.Add(FromRuleUsage("https://example.com/a.css", "a {}", []*css.RuleUsage{{EndOffset: 4, Used: true}}))
+
+	if 2 != len(report.Files) || "https://example.com/a.css" != report.Files[0].URL {
+		t.Fatalf("Expected 2 files sorted by URL, received %v", report.Files)
+	}
+	file := report.File("https://example.com/app.js")
+	if counts := lineCounts(file); 3 != counts[1] || 2 != counts[7] || 0 != counts[4] {
+		t.Errorf("Expected the counts to be summed, received %v", counts)
+	}
+	if 3 != file.Functions[0].Count {
+		t.Errorf("Expected the call counts to be summed, received %d", file.Functions[0].Count)
+	}
+	if covered, total := report.Summary(); 5 != covered || 8 != total {
+		t.Errorf("Expected 5 of 8 lines covered, received %d of %d", covered, total)
+	}
+
+	report.Add(FromScriptCoverage(&profiler.ScriptCoverage{
+		URL:       "https://example.com/app.js",
+		Functions: []*profiler.FunctionCoverage{{Ranges: []*profiler.CoverageRange{{EndOffset: 4, Count: 1}}}},
+	}, "f();"))
+	if 1 != len(report.File("https://example.com/app.js").Lines) {
+		t.Errorf("Expected a changed source to replace the coverage")
+	}
+	if "" == report.String() {
+		t.Errorf("Expected a summary")
+	}
+}
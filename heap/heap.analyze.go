@@ -0,0 +1,290 @@
+package heap
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+Order is a sort order for constructor statistics.
+*/
+type Order int
+
+/*
+Constructor statistics sort orders, largest first.
+*/
+const (
+	ByRetainedSize Order = iota
+	BySelfSize
+	ByCount
+)
+
+/*
+Constructor is the statistics of the reachable nodes with a class name.
+*/
+type Constructor struct {
+	// The class name, see Node.ClassName.
+	Name string
+
+	// The number of nodes.
+	Count int
+
+	// The total size of the nodes in bytes.
+	SelfSize int
+
+	// The total retained size of the nodes in bytes. Nodes dominated by
+	// another node of the same class are counted once.
+	RetainedSize int
+
+	// The nodes.
+	Nodes []*Node
+}
+
+/*
+Path is a chain of references.
+*/
+type Path []*Edge
+
+/*
+String implements Stringer, e.g. "Window.cache -> Array[2] -> Item @42".
+*/
+func (path Path) String() string {
+	if 0 == len(path) {
+		return ""
+	}
+	steps := []string{}
+	for _, edge := range path {
+		if "synthetic" == edge.From.Type {
+			continue
+		}
+		step := edge.From.ClassName()
+		switch edge.Type {
+		case EdgeElement, EdgeHidden:
+			step += "[" + edge.Name + "]"
+		default:
+			step += "." + edge.Name
+		}
+		steps = append(steps, step)
+	}
+	return strings.Join(append(steps, path[len(path)-1].To.String()), " -> ")
+}
+
+/*
+retains reports whether an edge keeps the referenced node alive.
+*/
+func retains(edge *Edge) bool {
+	return EdgeWeak != edge.Type
+}
+
+/*
+analyze computes the distances, dominators and retained sizes of the nodes.
+*/
+func (snapshot *Snapshot) analyze() {
+	snapshot.distances()
+	order := snapshot.postorder()
+	snapshot.dominators(order)
+
+	// A dominator is an ancestor in the depth-first tree, it follows the
+	// nodes it dominates in postorder.
+	for _, node := range order {
+		node.RetainedSize += node.SelfSize
+		if nil != node.Dominator {
+			node.Dominator.RetainedSize += node.RetainedSize
+		}
+	}
+}
+
+/*
+distances computes the shortest retaining path to each reachable node with a
+breadth-first search from the root.
+*/
+func (snapshot *Snapshot) distances() {
+	root := snapshot.Root()
+	root.Distance = 0
+	queue := []*Node{root}
+	for a := 0; a < len(queue); a++ {
+		node := queue[a]
+		for _, edge := range node.Edges {
+			if retains(edge) && edge.To.Distance < 0 {
+				edge.To.Distance = node.Distance + 1
+				edge.To.retainer = edge
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+}
+
+/*
+postorder returns the reachable nodes in depth-first postorder, the root is
+last.
+*/
+func (snapshot *Snapshot) postorder() []*Node {
+	type frame struct {
+		node *Node
+		edge int
+	}
+	order := make([]*Node, 0, len(snapshot.Nodes))
+	visited := make([]bool, len(snapshot.Nodes))
+	visited[0] = true
+	stack := []*frame{{node: snapshot.Root()}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.edge < len(top.node.Edges) {
+			edge := top.node.Edges[top.edge]
+			top.edge++
+			if retains(edge) && !visited[edge.To.index] {
+				visited[edge.To.index] = true
+				stack = append(stack, &frame{node: edge.To})
+			}
+			continue
+		}
+		order = append(order, top.node)
+		stack = stack[:len(stack)-1]
+	}
+	return order
+}
+
+/*
+dominators computes the immediate dominators of the reachable nodes with the
+iterative algorithm of Cooper, Harvey and Kennedy, "A Simple, Fast Dominance
+Algorithm".
+*/
+func (snapshot *Snapshot) dominators(order []*Node) {
+	post := make([]int, len(snapshot.Nodes))
+	for a := range post {
+		post[a] = -1
+	}
+	for a, node := range order {
+		post[node.index] = a
+	}
+	root := len(order) - 1
+	idom := make([]int, len(order))
+	for a := range idom {
+		idom[a] = -1
+	}
+	idom[root] = root
+
+	intersect := func(a, b int) int {
+		for a != b {
+			for a < b {
+				a = idom[a]
+			}
+			for b < a {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+	for changed := true; changed; {
+		changed = false
+		for a := root - 1; a >= 0; a-- {
+			dominator := -1
+			for _, edge := range order[a].Retainers {
+				from := post[edge.From.index]
+				if !retains(edge) || from < 0 || idom[from] < 0 {
+					continue
+				}
+				if dominator < 0 {
+					dominator = from
+				} else {
+					dominator = intersect(from, dominator)
+				}
+			}
+			if dominator != idom[a] {
+				idom[a] = dominator
+				changed = true
+			}
+		}
+	}
+	for a := 0; a < root; a++ {
+		order[a].Dominator = order[idom[a]]
+	}
+}
+
+/*
+RetainingPath returns the shortest chain of references from the root to the
+node, nil for unreachable nodes.
+*/
+func (node *Node) RetainingPath() Path {
+	if !node.Reachable() {
+		return nil
+	}
+	path := make(Path, node.Distance)
+	for edge := node.retainer; nil != edge; edge = edge.From.retainer {
+		path[edge.From.Distance] = edge
+	}
+	return path
+}
+
+/*
+Constructors returns the statistics of the reachable nodes grouped by class
+name, largest first.
+*/
+func (snapshot *Snapshot) Constructors(order Order) []*Constructor {
+	byName := map[string]*Constructor{}
+	constructors := []*Constructor{}
+	children := map[*Node][]*Node{}
+	for _, node := range snapshot.Nodes {
+		if nil == node.Dominator {
+			continue
+		}
+		children[node.Dominator] = append(children[node.Dominator], node)
+		if "synthetic" == node.Type {
+			continue
+		}
+		name := node.ClassName()
+		constructor, ok := byName[name]
+		if !ok {
+			constructor = &Constructor{Name: name}
+			byName[name] = constructor
+			constructors = append(constructors, constructor)
+		}
+		constructor.Count++
+		constructor.SelfSize += node.SelfSize
+		constructor.Nodes = append(constructor.Nodes, node)
+	}
+
+	// Walk the dominator tree and count the retained size of the outermost
+	// node of each class.
+	type frame struct {
+		node  *Node
+		child int
+	}
+	active := map[string]int{}
+	stack := []*frame{{node: snapshot.Root()}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.child < len(children[top.node]) {
+			child := children[top.node][top.child]
+			top.child++
+			if constructor, ok := byName[child.ClassName()]; ok && 0 == active[constructor.Name] {
+				constructor.RetainedSize += child.RetainedSize
+			}
+			active[child.ClassName()]++
+			stack = append(stack, &frame{node: child})
+			continue
+		}
+		active[top.node.ClassName()]--
+		stack = stack[:len(stack)-1]
+	}
+
+	sort.SliceStable(constructors, func(a, b int) bool {
+		x, y := constructors[a], constructors[b]
+		switch order {
+		case BySelfSize:
+			if x.SelfSize != y.SelfSize {
+				return x.SelfSize > y.SelfSize
+			}
+		case ByCount:
+			if x.Count != y.Count {
+				return x.Count > y.Count
+			}
+		default:
+			if x.RetainedSize != y.RetainedSize {
+				return x.RetainedSize > y.RetainedSize
+			}
+		}
+		return x.Name < y.Name
+	})
+	return constructors
+}
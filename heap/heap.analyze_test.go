@@ -0,0 +1,86 @@
+package heap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	snapshot, err := Parse(strings.NewReader(testSnapshot(testNodes()...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	root := snapshot.Root()
+	for id, expected := range map[uint64]struct {
+		dominator uint64
+		retained  int
+		distance  int
+	}{
+		3:  {1, 50, 1},
+		5:  {1, 20, 1},
+		7:  {1, 90, 2},
+		9:  {3, 40, 2},
+		11: {0, 0, -1},
+		13: {7, 60, 3},
+	} {
+		node := snapshot.Node(id)
+		dominator := uint64(0)
+		if nil != node.Dominator {
+			dominator = node.Dominator.ID
+		}
+		if expected.dominator != dominator || expected.retained != node.RetainedSize || expected.distance != node.Distance {
+			t.Errorf("%d: expected %+v, received dominator %d, retained size %d, distance %d", id, expected, dominator, node.RetainedSize, node.Distance)
+		}
+	}
+	if 160 != root.RetainedSize || 160 != snapshot.Size() || nil != root.Dominator {
+		t.Errorf("Expected the root to retain all reachable nodes, received %d", root.RetainedSize)
+	}
+	if snapshot.Node(11).Reachable() || nil != snapshot.Node(11).RetainingPath() {
+		t.Errorf("Expected nodes retained by weak references to be unreachable")
+	}
+}
+
+func TestRetainingPath(t *testing.T) {
+	snapshot, err := Parse(strings.NewReader(testSnapshot(testNodes()...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	path := snapshot.Node(13).RetainingPath()
+	if 3 != len(path) || "a" != path[0].Name || "c" != path[1].Name || "0" != path[2].Name {
+		t.Fatalf("Unexpected path %s", path)
+	}
+	if "Foo.c -> Bar[0] -> (array) @13" != path.String() {
+		t.Errorf("Unexpected path '%s'", path)
+	}
+	if 0 != len(snapshot.Root().RetainingPath()) {
+		t.Errorf("Expected an empty path to the root")
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	snapshot, err := Parse(strings.NewReader(testSnapshot(testNodes()...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	constructors := snapshot.Constructors(ByRetainedSize)
+	if 3 != len(constructors) {
+		t.Fatalf("Expected 3 constructors, received %d", len(constructors))
+	}
+	bar, foo, array := constructors[0], constructors[1], constructors[2]
+	if "Bar" != bar.Name || 90 != bar.RetainedSize {
+		t.Errorf("Unexpected constructor %+v", bar)
+	}
+	if "Foo" != foo.Name || 3 != foo.Count || 70 != foo.SelfSize || 70 != foo.RetainedSize || 3 != len(foo.Nodes) {
+		t.Errorf("Expected nested objects to be retained once, received %+v", foo)
+	}
+	if "(array)" != array.Name || 60 != array.RetainedSize {
+		t.Errorf("Unexpected constructor %+v", array)
+	}
+
+	if constructors = snapshot.Constructors(ByCount); "Foo" != constructors[0].Name || "(array)" != constructors[1].Name {
+		t.Errorf("Expected constructors to be sorted by count, then name")
+	}
+	if constructors = snapshot.Constructors(BySelfSize); "Foo" != constructors[0].Name || "(array)" != constructors[1].Name {
+		t.Errorf("Expected constructors to be sorted by self size")
+	}
+}
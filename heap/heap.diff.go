@@ -0,0 +1,96 @@
+package heap
+
+import (
+	"sort"
+)
+
+/*
+ConstructorDiff is the change in the reachable nodes of a class between two
+snapshots.
+*/
+type ConstructorDiff struct {
+	// The class name, see Node.ClassName.
+	Name string
+
+	// The number of nodes allocated since the first snapshot.
+	Added int
+
+	// The number of nodes collected since the first snapshot.
+	Removed int
+
+	// The change in the number of nodes.
+	CountDelta int
+
+	// The total size of the added nodes in bytes.
+	AddedSize int
+
+	// The total size of the removed nodes in bytes.
+	RemovedSize int
+
+	// The change in size in bytes.
+	SizeDelta int
+
+	// The added nodes, from the second snapshot.
+	AddedNodes []*Node
+}
+
+/*
+Diff compares two snapshots taken from the same page by heap object ID and
+returns the classes whose nodes changed, largest growth first. Objects that are
+retained by the second snapshot but were not in the first are the candidates
+for a leak.
+*/
+func Diff(before, after *Snapshot) []*ConstructorDiff {
+	byName := map[string]*ConstructorDiff{}
+	diffs := []*ConstructorDiff{}
+	get := func(node *Node) *ConstructorDiff {
+		diff, ok := byName[node.ClassName()]
+		if !ok {
+			diff = &ConstructorDiff{Name: node.ClassName()}
+			byName[diff.Name] = diff
+			diffs = append(diffs, diff)
+		}
+		return diff
+	}
+
+	for _, node := range after.Nodes {
+		if !counted(node) || counted(before.Node(node.ID)) {
+			continue
+		}
+		diff := get(node)
+		diff.Added++
+		diff.AddedSize += node.SelfSize
+		diff.AddedNodes = append(diff.AddedNodes, node)
+	}
+	for _, node := range before.Nodes {
+		if !counted(node) || counted(after.Node(node.ID)) {
+			continue
+		}
+		diff := get(node)
+		diff.Removed++
+		diff.RemovedSize += node.SelfSize
+	}
+	for _, diff := range diffs {
+		diff.CountDelta = diff.Added - diff.Removed
+		diff.SizeDelta = diff.AddedSize - diff.RemovedSize
+	}
+
+	sort.SliceStable(diffs, func(a, b int) bool {
+		x, y := diffs[a], diffs[b]
+		if x.SizeDelta != y.SizeDelta {
+			return x.SizeDelta > y.SizeDelta
+		}
+		if x.CountDelta != y.CountDelta {
+			return x.CountDelta > y.CountDelta
+		}
+		return x.Name < y.Name
+	})
+	return diffs
+}
+
+/*
+counted reports whether a node is counted by the statistics.
+*/
+func counted(node *Node) bool {
+	return nil != node && node.Reachable() && "synthetic" != node.Type
+}
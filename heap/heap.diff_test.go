@@ -0,0 +1,42 @@
+package heap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before, err := Parse(strings.NewReader(testSnapshot(testNodes()...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	// The array is collected, two Foo objects are allocated and the closure
+	// becomes reachable.
+	nodes := testNodes()
+	nodes[0].edges = append(nodes[0].edges, testEdge{"property", "f", 7}, testEdge{"property", "g", 8}, testEdge{"property", "e", 5})
+	nodes[3].edges = nil
+	nodes = append(nodes,
+		testNode{typ: "object", name: "Foo", id: 15, size: 5},
+		testNode{typ: "object", name: "Foo", id: 17, size: 5},
+	)
+	after, err := Parse(strings.NewReader(testSnapshot(nodes...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	diffs := Diff(before, after)
+	if 3 != len(diffs) {
+		t.Fatalf("Expected 3 changed classes, received %d", len(diffs))
+	}
+	closure, foo, array := diffs[0], diffs[1], diffs[2]
+	if "(closure)" != closure.Name || 1 != closure.Added || 50 != closure.SizeDelta {
+		t.Errorf("Unexpected diff %+v", closure)
+	}
+	if "Foo" != foo.Name || 2 != foo.Added || 2 != foo.CountDelta || 10 != foo.AddedSize || 2 != len(foo.AddedNodes) || 15 != foo.AddedNodes[0].ID {
+		t.Errorf("Unexpected diff %+v", foo)
+	}
+	if "(array)" != array.Name || 1 != array.Removed || -1 != array.CountDelta || -60 != array.SizeDelta {
+		t.Errorf("Unexpected diff %+v", array)
+	}
+}
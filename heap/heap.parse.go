@@ -0,0 +1,362 @@
+/*
+Package heap analyzes V8 heap snapshots taken by Tab.WriteHeapSnapshot or saved
+from the DevTools memory panel. It builds the node and edge graph of the
+snapshot and computes dominators, retained sizes, retaining paths, statistics
+by constructor and the difference between two snapshots.
+
+	before, err := tab.HeapSnapshot(ctx, nil)
+	...
+	after, err := tab.HeapSnapshot(ctx, nil)
+	...
+	for _, diff := range heap.Diff(before, after) {
+		fmt.Printf("%s: +%d objects, %+d bytes\n", diff.Name, diff.Added, diff.SizeDelta)
+	}
+*/
+package heap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+Edge types that don't hold a reference as a property or element name.
+*/
+const (
+	EdgeElement = "element"
+	EdgeHidden  = "hidden"
+	EdgeWeak    = "weak"
+)
+
+/*
+Snapshot is a parsed heap snapshot.
+*/
+type Snapshot struct {
+	// The snapshot nodes. The first node is the synthetic root.
+	Nodes []*Node
+
+	byID map[uint64]*Node
+}
+
+/*
+Node is a heap object.
+*/
+type Node struct {
+	// The node type, e.g. "object", "closure", "string" or "native".
+	Type string
+
+	// The constructor name of objects, the value of strings or the name of
+	// other nodes.
+	Name string
+
+	// The heap object ID. IDs are stable across snapshots taken from the same
+	// page.
+	ID uint64
+
+	// The size of the object in bytes.
+	SelfSize int
+
+	// The size of the object and the objects it dominates in bytes, the memory
+	// that would be freed if the object was collected. 0 for unreachable
+	// nodes.
+	RetainedSize int
+
+	// The length of the shortest retaining path from the root, -1 for
+	// unreachable nodes.
+	Distance int
+
+	// The immediate dominator of the node, nil for the root and unreachable
+	// nodes.
+	Dominator *Node
+
+	// The references held by the node.
+	Edges []*Edge
+
+	// The references to the node.
+	Retainers []*Edge
+
+	detachedness int
+	index        int
+	retainer     *Edge
+}
+
+/*
+Edge is a reference from one node to another.
+*/
+type Edge struct {
+	// The edge type, e.g. "property", "element", "context", "internal" or
+	// "weak".
+	Type string
+
+	// The property name, the element index or the variable name of the
+	// reference.
+	Name string
+
+	// The node holding the reference.
+	From *Node
+
+	// The referenced node.
+	To *Node
+}
+
+/*
+snapshotFile is the .heapsnapshot format. Nodes and edges are flat arrays of
+integer fields described by the meta data.
+*/
+type snapshotFile struct {
+	Snapshot struct {
+		Meta struct {
+			NodeFields []string          `json:"node_fields"`
+			NodeTypes  []json.RawMessage `json:"node_types"`
+			EdgeFields []string          `json:"edge_fields"`
+			EdgeTypes  []json.RawMessage `json:"edge_types"`
+		} `json:"meta"`
+	} `json:"snapshot"`
+	Nodes   []int    `json:"nodes"`
+	Edges   []int    `json:"edges"`
+	Strings []string `json:"strings"`
+}
+
+/*
+fields maps field names to their offset in a flat record.
+*/
+type fields map[string]int
+
+/*
+newFields returns the offsets of the fields and checks that the required
+fields are present.
+*/
+func newFields(names []string, required ...string) (fields, error) {
+	f := fields{}
+	for i, name := range names {
+		f[name] = i
+	}
+	for _, name := range required {
+		if _, ok := f[name]; !ok {
+			return nil, errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("missing field '%s'", name))
+		}
+	}
+	return f, nil
+}
+
+/*
+Parse reads a heap snapshot in the .heapsnapshot format and computes its
+dominators and retained sizes.
+*/
+func Parse(r io.Reader) (*Snapshot, error) {
+	file := &snapshotFile{}
+	if err := json.NewDecoder(r).Decode(file); nil != err {
+		return nil, errs.Wrap(err, codes.HeapSnapshotInvalid, "could not decode the heap snapshot")
+	}
+	snapshot, err := file.build()
+	if nil != err {
+		return nil, err
+	}
+	snapshot.analyze()
+	return snapshot, nil
+}
+
+/*
+build builds the node graph.
+*/
+func (file *snapshotFile) build() (*Snapshot, error) {
+	meta := file.Snapshot.Meta
+	nodeFields, err := newFields(meta.NodeFields, "type", "name", "id", "self_size", "edge_count")
+	if nil != err {
+		return nil, err
+	}
+	edgeFields, err := newFields(meta.EdgeFields, "type", "name_or_index", "to_node")
+	if nil != err {
+		return nil, err
+	}
+	nodeTypes, err := enumTypes(meta.NodeTypes, nodeFields["type"])
+	if nil != err {
+		return nil, err
+	}
+	edgeTypes, err := enumTypes(meta.EdgeTypes, edgeFields["type"])
+	if nil != err {
+		return nil, err
+	}
+
+	nodeSize, edgeSize := len(meta.NodeFields), len(meta.EdgeFields)
+	if 0 != len(file.Nodes)%nodeSize || 0 != len(file.Edges)%edgeSize {
+		return nil, errs.New(codes.HeapSnapshotInvalid, "truncated node or edge data")
+	}
+	if 0 == len(file.Nodes) {
+		return nil, errs.New(codes.HeapSnapshotInvalid, "the snapshot has no nodes")
+	}
+	str := func(index int) (string, error) {
+		if index < 0 || index >= len(file.Strings) {
+			return "", errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("string %d out of range", index))
+		}
+		return file.Strings[index], nil
+	}
+	enum := func(types []string, index int) (string, error) {
+		if index < 0 || index >= len(types) {
+			return "", errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("type %d out of range", index))
+		}
+		return types[index], nil
+	}
+
+	snapshot := &Snapshot{
+		Nodes: make([]*Node, len(file.Nodes)/nodeSize),
+		byID:  make(map[uint64]*Node, len(file.Nodes)/nodeSize),
+	}
+	nodes := make([]Node, len(snapshot.Nodes))
+	edges := make([]Edge, len(file.Edges)/edgeSize)
+	edgePtrs := make([]*Edge, len(edges))
+	for a := range edges {
+		edgePtrs[a] = &edges[a]
+	}
+
+	edge := 0
+	for a := range nodes {
+		node, record := &nodes[a], file.Nodes[a*nodeSize:(a+1)*nodeSize]
+		node.index = a
+		node.Distance = -1
+		if node.Type, err = enum(nodeTypes, record[nodeFields["type"]]); nil != err {
+			return nil, err
+		}
+		if node.Name, err = str(record[nodeFields["name"]]); nil != err {
+			return nil, err
+		}
+		node.ID = uint64(record[nodeFields["id"]])
+		node.SelfSize = record[nodeFields["self_size"]]
+		if i, ok := nodeFields["detachedness"]; ok {
+			node.detachedness = record[i]
+		}
+		count := record[nodeFields["edge_count"]]
+		if count < 0 || edge+count > len(edges) {
+			return nil, errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("node %d has too many edges", node.ID))
+		}
+		node.Edges = edgePtrs[edge : edge+count : edge+count]
+		for _, e := range node.Edges {
+			e.From = node
+		}
+		edge += count
+		snapshot.Nodes[a] = node
+		snapshot.byID[node.ID] = node
+	}
+	if edge != len(edges) {
+		return nil, errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("%d edges have no node", len(edges)-edge))
+	}
+
+	retainers := make([]int, len(nodes)+1)
+	for a := range edges {
+		e, record := &edges[a], file.Edges[a*edgeSize:(a+1)*edgeSize]
+		if e.Type, err = enum(edgeTypes, record[edgeFields["type"]]); nil != err {
+			return nil, err
+		}
+		to := record[edgeFields["to_node"]]
+		if 0 != to%nodeSize || to < 0 || to/nodeSize >= len(nodes) {
+			return nil, errs.New(codes.HeapSnapshotInvalid, fmt.Sprintf("edge to invalid node offset %d", to))
+		}
+		nameOrIndex := record[edgeFields["name_or_index"]]
+		if EdgeElement == e.Type || EdgeHidden == e.Type {
+			e.Name = strconv.Itoa(nameOrIndex)
+		} else if e.Name, err = str(nameOrIndex); nil != err {
+			return nil, err
+		}
+		e.To = snapshot.Nodes[to/nodeSize]
+		retainers[e.To.index+1]++
+	}
+
+	// Retainers share one backing array, offsets are accumulated counts.
+	for a := 1; a < len(retainers); a++ {
+		retainers[a] += retainers[a-1]
+	}
+	retainerPtrs := make([]*Edge, len(edges))
+	for _, node := range snapshot.Nodes {
+		node.Retainers = retainerPtrs[retainers[node.index]:retainers[node.index]:retainers[node.index+1]]
+	}
+	for _, node := range snapshot.Nodes {
+		for _, e := range node.Edges {
+			e.To.Retainers = append(e.To.Retainers, e)
+		}
+	}
+	return snapshot, nil
+}
+
+/*
+enumTypes decodes the names of an enum field. The types of other fields are
+strings such as "number" or "node".
+*/
+func enumTypes(types []json.RawMessage, field int) ([]string, error) {
+	if field >= len(types) {
+		return nil, errs.New(codes.HeapSnapshotInvalid, "missing type names")
+	}
+	names := []string{}
+	if err := json.Unmarshal(types[field], &names); nil != err {
+		return nil, errs.Wrap(err, codes.HeapSnapshotInvalid, "invalid type names")
+	}
+	return names, nil
+}
+
+/*
+Root returns the synthetic root node.
+*/
+func (snapshot *Snapshot) Root() *Node {
+	return snapshot.Nodes[0]
+}
+
+/*
+Node returns the node with a heap object ID, or nil.
+*/
+func (snapshot *Snapshot) Node(id uint64) *Node {
+	return snapshot.byID[id]
+}
+
+/*
+Size returns the total size of the reachable nodes in bytes.
+*/
+func (snapshot *Snapshot) Size() int {
+	return snapshot.Root().RetainedSize
+}
+
+/*
+ClassName returns the name nodes are grouped by: the constructor name of
+objects and the type of other nodes in parentheses, as in the DevTools memory
+panel.
+*/
+func (node *Node) ClassName() string {
+	switch node.Type {
+	case "object", "native":
+		return node.Name
+	case "hidden":
+		return "(system)"
+	case "code":
+		return "(compiled code)"
+	case "concatenated string", "sliced string":
+		return "(string)"
+	}
+	return "(" + node.Type + ")"
+}
+
+/*
+Detached reports whether the node is a DOM node that is no longer attached to
+a document.
+*/
+func (node *Node) Detached() bool {
+	return 2 == node.detachedness || strings.HasPrefix(node.Name, "Detached ")
+}
+
+/*
+Reachable reports whether the node is reachable from the root without
+following weak references.
+*/
+func (node *Node) Reachable() bool {
+	return node.Distance >= 0
+}
+
+/*
+String implements Stringer.
+*/
+func (node *Node) String() string {
+	return fmt.Sprintf("%s @%d", node.ClassName(), node.ID)
+}
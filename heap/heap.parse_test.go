@@ -0,0 +1,153 @@
+package heap
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+testNode is a node of a test snapshot.
+*/
+type testNode struct {
+	typ, name string
+	id, size  int
+	edges     []testEdge
+}
+
+/*
+testEdge is an edge of a test snapshot, to is the index of the referenced
+node.
+*/
+type testEdge struct {
+	typ, name string
+	to        int
+}
+
+var (
+	testNodeTypes = []string{"hidden", "array", "string", "object", "code", "closure", "regexp", "number", "native", "synthetic"}
+	testEdgeTypes = []string{"context", "element", "property", "internal", "hidden", "shortcut", "weak"}
+)
+
+/*
+testSnapshot encodes nodes in the .heapsnapshot format.
+*/
+func testSnapshot(nodes ...testNode) string {
+	indexOf := func(list []string, value string) int {
+		for a, v := range list {
+			if v == value {
+				return a
+			}
+		}
+		panic(value)
+	}
+	strs := []string{""}
+	str := func(value string) int {
+		strs = append(strs, value)
+		return len(strs) - 1
+	}
+	nodeData, edgeData := []int{}, []int{}
+	for _, node := range nodes {
+		nodeData = append(nodeData, indexOf(testNodeTypes, node.typ), str(node.name), node.id, node.size, len(node.edges), 0)
+		for _, edge := range node.edges {
+			name, err := strconv.Atoi(edge.name)
+			if EdgeElement != edge.typ && EdgeHidden != edge.typ || nil != err {
+				name = str(edge.name)
+			}
+			edgeData = append(edgeData, indexOf(testEdgeTypes, edge.typ), name, edge.to*6)
+		}
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"snapshot": map[string]interface{}{
+			"meta": map[string]interface{}{
+				"node_fields": []string{"type", "name", "id", "self_size", "edge_count", "detachedness"},
+				"node_types":  []interface{}{testNodeTypes, "string", "number", "number", "number", "number"},
+				"edge_fields": []string{"type", "name_or_index", "to_node"},
+				"edge_types":  []interface{}{testEdgeTypes, "string_or_number", "node"},
+			},
+			"node_count": len(nodes),
+		},
+		"nodes":   nodeData,
+		"edges":   edgeData,
+		"strings": strs,
+	})
+	return string(data)
+}
+
+/*
+testNodes is a small heap:
+
+	root -a-> A -c-> C -[0]-> F
+	root -b-> B -c-> C
+	          A -d-> D -weak-> E
+*/
+func testNodes() []testNode {
+	return []testNode{
+		{typ: "synthetic", id: 1, edges: []testEdge{{"property", "a", 1}, {"property", "b", 2}}},
+		{typ: "object", name: "Foo", id: 3, size: 10, edges: []testEdge{{"property", "c", 3}, {"property", "d", 4}}},
+		{typ: "object", name: "Foo", id: 5, size: 20, edges: []testEdge{{"property", "c", 3}}},
+		{typ: "object", name: "Bar", id: 7, size: 30, edges: []testEdge{{"element", "0", 6}}},
+		{typ: "object", name: "Foo", id: 9, size: 40, edges: []testEdge{{"weak", "e", 5}}},
+		{typ: "closure", name: "e", id: 11, size: 50},
+		{typ: "array", id: 13, size: 60},
+	}
+}
+
+func TestParse(t *testing.T) {
+	snapshot, err := Parse(strings.NewReader(testSnapshot(testNodes()...)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 7 != len(snapshot.Nodes) || "synthetic" != snapshot.Root().Type {
+		t.Fatalf("Expected 7 nodes, received %d", len(snapshot.Nodes))
+	}
+	c := snapshot.Node(7)
+	if nil == c || "Bar" != c.Name || 30 != c.SelfSize || 2 != len(c.Retainers) {
+		t.Fatalf("Unexpected node %+v", c)
+	}
+	if edge := c.Edges[0]; "element" != edge.Type || "0" != edge.Name || c != edge.From || 13 != edge.To.ID {
+		t.Errorf("Unexpected edge %+v", edge)
+	}
+	if "c" != c.Retainers[0].Name || 3 != c.Retainers[0].From.ID {
+		t.Errorf("Unexpected retainer %+v", c.Retainers[0])
+	}
+	if "(closure)" != snapshot.Node(11).ClassName() || "Foo" != snapshot.Node(3).ClassName() {
+		t.Errorf("Unexpected class names")
+	}
+	if nil != snapshot.Node(2) {
+		t.Errorf("Expected nil, received a node")
+	}
+}
+
+func TestParseDetached(t *testing.T) {
+	snapshot, err := Parse(strings.NewReader(testSnapshot(
+		testNode{typ: "synthetic", id: 1, edges: []testEdge{{"property", "a", 1}}},
+		testNode{typ: "native", name: "Detached HTMLDivElement", id: 3, size: 10},
+	)))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !snapshot.Node(3).Detached() || snapshot.Root().Detached() {
+		t.Errorf("Expected detached DOM nodes to be detected")
+	}
+}
+
+func TestParseError(t *testing.T) {
+	valid := testSnapshot(testNodes()...)
+	for name, data := range map[string]string{
+		"json":    "{",
+		"fields":  strings.Replace(valid, `"self_size"`, `"size"`, 1),
+		"to_node": testSnapshot(testNode{typ: "synthetic", id: 1, edges: []testEdge{{"property", "a", 1}}}),
+		"empty":   `{"snapshot":{"meta":{"node_fields":["type","name","id","self_size","edge_count"],"node_types":[[]],"edge_fields":["type","name_or_index","to_node"],"edge_types":[[]]}}}`,
+	} {
+		if _, err := Parse(strings.NewReader(data)); nil == err {
+			t.Errorf("%s: expected error, received nil", name)
+		} else if codes.HeapSnapshotInvalid != err.(*errs.Err).Code() {
+			t.Errorf("%s: expected code %d, received %d", name, codes.HeapSnapshotInvalid, err.(*errs.Err).Code())
+		}
+	}
+}
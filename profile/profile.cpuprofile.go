@@ -0,0 +1,38 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+WriteCPUProfile writes the profile to w in the .cpuprofile format, which can be
+loaded in the DevTools performance panel.
+*/
+func WriteCPUProfile(w io.Writer, profile *profiler.Profile) error {
+	if _, err := newTree(profile); nil != err {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(profile); nil != err {
+		return errs.Wrap(err, codes.ProfileWriteFailed, "could not write the profile")
+	}
+	return nil
+}
+
+/*
+ReadCPUProfile reads a profile in the .cpuprofile format.
+*/
+func ReadCPUProfile(r io.Reader) (*profiler.Profile, error) {
+	profile := &profiler.Profile{}
+	if err := json.NewDecoder(r).Decode(profile); nil != err {
+		return nil, errs.Wrap(err, codes.ProfileInvalid, "could not decode the profile")
+	}
+	if _, err := newTree(profile); nil != err {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,32 @@
+package profile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCPUProfile(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteCPUProfile(buf, testProfile()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"timeDeltas":[100,1000,1000,2000]`) {
+		t.Errorf("Unexpected profile %s", buf.String())
+	}
+
+	profile, err := ReadCPUProfile(buf)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 4 != len(profile.Nodes) || "main" != profile.Nodes[1].CallFrame.FunctionName || 5600 != profile.EndTime {
+		t.Errorf("Expected the profile to be read back, received %+v", profile)
+	}
+
+	if _, err := ReadCPUProfile(strings.NewReader(`{"nodes":[]}`)); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if err := WriteCPUProfile(buf, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Package profile converts JavaScript CPU profiles recorded with the Profiler
+domain to the pprof format, for analysis with `go tool pprof` and flame graph
+tools, and to the .cpuprofile format loaded by the DevTools performance panel.
+
+	result := <-tab.Profiler().Stop()
+	...
+	file, err := os.Create("page.pb.gz")
+	...
+	err = profile.WritePprof(file, result.Profile)
+*/
+package profile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+Pprof returns the profile encoded as an uncompressed pprof protocol buffer.
+Each profile node is a location in the function of its call frame, samples
+record the number of samples and the CPU time of each call stack.
+
+https://github.com/google/pprof/blob/master/proto/profile.proto
+*/
+func Pprof(profile *profiler.Profile) ([]byte, error) {
+	tree, err := newTree(profile)
+	if nil != err {
+		return nil, err
+	}
+	builder := &pprofBuilder{
+		functions: map[functionKey]uint64{},
+		locations: map[locationKey]uint64{},
+		strings:   map[string]int64{"": 0},
+		table:     []string{""},
+	}
+	return builder.build(tree), nil
+}
+
+/*
+WritePprof writes the profile to w as a gzip compressed pprof protocol buffer.
+*/
+func WritePprof(w io.Writer, profile *profiler.Profile) error {
+	data, err := Pprof(profile)
+	if nil != err {
+		return err
+	}
+	writer := gzip.NewWriter(w)
+	if _, err := writer.Write(data); nil != err {
+		return errs.Wrap(err, codes.ProfileWriteFailed, "could not write the profile")
+	}
+	if err := writer.Close(); nil != err {
+		return errs.Wrap(err, codes.ProfileWriteFailed, "could not write the profile")
+	}
+	return nil
+}
+
+/*
+functionKey identifies a function.
+*/
+type functionKey struct {
+	name   string
+	url    string
+	line   int
+	column int
+}
+
+/*
+locationKey identifies a location in a function.
+*/
+type locationKey struct {
+	function uint64
+	line     int
+	column   int
+}
+
+/*
+pprofBuilder accumulates the functions, locations and string table of a pprof
+profile.
+*/
+type pprofBuilder struct {
+	functionData []byte
+	functions    map[functionKey]uint64
+	locationData []byte
+	locations    map[locationKey]uint64
+	strings      map[string]int64
+	table        []string
+}
+
+/*
+build encodes the profile.
+*/
+func (builder *pprofBuilder) build(tree *tree) []byte {
+	samples := &protobuf{}
+	for _, node := range tree.sampled {
+		stack := []uint64{}
+		for id, ok := node.ID, true; ok && !tree.root(id); id, ok = tree.parents[id] {
+			stack = append(stack, builder.location(tree.nodes[id]))
+		}
+		samples.message(2, func(sample *protobuf) {
+			sample.packed(1, stack)
+			sample.packed(2, []uint64{uint64(tree.counts[node.ID]), uint64(tree.nanos[node.ID])})
+		})
+	}
+
+	// Strings are interned while encoding, so the string table is encoded
+	// last.
+	profile := &protobuf{}
+	sampleTypes := [][2]string{{"samples", "count"}, {"cpu", "nanoseconds"}}
+	for _, sampleType := range sampleTypes {
+		builder.valueType(profile, 1, sampleType[0], sampleType[1])
+	}
+	profile.data = append(profile.data, samples.data...)
+	profile.data = append(profile.data, builder.locationData...)
+	profile.data = append(profile.data, builder.functionData...)
+	builder.valueType(profile, 11, "cpu", "nanoseconds")
+	for _, s := range builder.table {
+		profile.bytes(6, []byte(s))
+	}
+	profile.int64(10, tree.duration)
+	profile.int64(12, tree.period)
+	return profile.data
+}
+
+/*
+valueType encodes a pprof ValueType.
+*/
+func (builder *pprofBuilder) valueType(buf *protobuf, field int, typ, unit string) {
+	typeIndex, unitIndex := builder.string(typ), builder.string(unit)
+	buf.message(field, func(valueType *protobuf) {
+		valueType.int64(1, typeIndex)
+		valueType.int64(2, unitIndex)
+	})
+}
+
+/*
+string returns the string table index of a string.
+*/
+func (builder *pprofBuilder) string(s string) int64 {
+	if index, ok := builder.strings[s]; ok {
+		return index
+	}
+	index := int64(len(builder.table))
+	builder.strings[s] = index
+	builder.table = append(builder.table, s)
+	return index
+}
+
+/*
+function returns the ID of the function of a profile node.
+*/
+func (builder *pprofBuilder) function(node *profiler.ProfileNode) uint64 {
+	frame := node.CallFrame
+	key := functionKey{frame.FunctionName, frame.URL, frame.LineNumber, frame.ColumnNumber}
+	if id, ok := builder.functions[key]; ok {
+		return id
+	}
+	id := uint64(len(builder.functions) + 1)
+	builder.functions[key] = id
+
+	name := frame.FunctionName
+	if "" == name {
+		name = "(anonymous)"
+	}
+	systemName := name
+	if "" != frame.URL {
+		systemName = fmt.Sprintf("%s %s:%d:%d", name, frame.URL, frame.LineNumber+1, frame.ColumnNumber+1)
+	}
+	nameIndex, systemNameIndex, filenameIndex := builder.string(name), builder.string(systemName), builder.string(frame.URL)
+	buf := &protobuf{data: builder.functionData}
+	buf.message(5, func(function *protobuf) {
+		function.uint64(1, id)
+		function.int64(2, nameIndex)
+		function.int64(3, systemNameIndex)
+		function.int64(4, filenameIndex)
+		function.int64(5, int64(frame.LineNumber+1))
+	})
+	builder.functionData = buf.data
+	return id
+}
+
+/*
+location returns the ID of the location of a profile node. Lines and columns
+are 1-based.
+*/
+func (builder *pprofBuilder) location(node *profiler.ProfileNode) uint64 {
+	function := builder.function(node)
+	key := locationKey{function, node.CallFrame.LineNumber, node.CallFrame.ColumnNumber}
+	if id, ok := builder.locations[key]; ok {
+		return id
+	}
+	id := uint64(len(builder.locations) + 1)
+	builder.locations[key] = id
+
+	buf := &protobuf{data: builder.locationData}
+	buf.message(4, func(location *protobuf) {
+		location.uint64(1, id)
+		location.message(4, func(line *protobuf) {
+			line.uint64(1, function)
+			line.int64(2, int64(key.line+1))
+			line.int64(3, int64(key.column+1))
+		})
+	})
+	builder.locationData = buf.data
+	return id
+}
+
+/*
+protobuf encodes protocol buffer messages. Zero scalar values are omitted.
+*/
+type protobuf struct {
+	data []byte
+}
+
+/*
+varint appends a base 128 varint.
+*/
+func (buf *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		buf.data = append(buf.data, byte(x)|0x80)
+		x >>= 7
+	}
+	buf.data = append(buf.data, byte(x))
+}
+
+/*
+key appends a field key.
+*/
+func (buf *protobuf) key(field, wireType int) {
+	buf.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+/*
+uint64 appends a varint field.
+*/
+func (buf *protobuf) uint64(field int, x uint64) {
+	if 0 == x {
+		return
+	}
+	buf.key(field, 0)
+	buf.varint(x)
+}
+
+/*
+int64 appends a varint field.
+*/
+func (buf *protobuf) int64(field int, x int64) {
+	buf.uint64(field, uint64(x))
+}
+
+/*
+bytes appends a length delimited field.
+*/
+func (buf *protobuf) bytes(field int, data []byte) {
+	buf.key(field, 2)
+	buf.varint(uint64(len(data)))
+	buf.data = append(buf.data, data...)
+}
+
+/*
+packed appends a packed repeated varint field.
+*/
+func (buf *protobuf) packed(field int, values []uint64) {
+	packed := &protobuf{}
+	for _, x := range values {
+		packed.varint(x)
+	}
+	buf.bytes(field, packed.data)
+}
+
+/*
+message appends an embedded message.
+*/
+func (buf *protobuf) message(field int, encode func(*protobuf)) {
+	message := &protobuf{}
+	encode(message)
+	buf.bytes(field, message.data)
+}
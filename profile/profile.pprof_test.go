@@ -0,0 +1,211 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/profiler"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+field is a decoded protocol buffer field.
+*/
+type field struct {
+	num   int
+	value uint64
+	data  []byte
+}
+
+/*
+varint decodes a varint from the start of data.
+*/
+func varint(t *testing.T, data *[]byte) uint64 {
+	var x uint64
+	for shift := uint(0); ; shift += 7 {
+		if 0 == len(*data) {
+			t.Fatalf("Unexpected end of message")
+		}
+		b := (*data)[0]
+		*data = (*data)[1:]
+		x |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return x
+		}
+	}
+}
+
+/*
+decode decodes the fields of a protocol buffer message.
+*/
+func decode(t *testing.T, data []byte) []*field {
+	fields := []*field{}
+	for len(data) > 0 {
+		key := varint(t, &data)
+		f := &field{num: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			f.value = varint(t, &data)
+		case 2:
+			n := varint(t, &data)
+			f.data, data = data[:n], data[n:]
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+/*
+packed decodes a packed repeated varint field.
+*/
+func packed(t *testing.T, data []byte) []uint64 {
+	values := []uint64{}
+	for len(data) > 0 {
+		values = append(values, varint(t, &data))
+	}
+	return values
+}
+
+/*
+testProfile is a profile of compute() called from main() in app.js.
+*/
+func testProfile() *profiler.Profile {
+	return &profiler.Profile{
+		Nodes: []*profiler.ProfileNode{
+			{ID: 1, CallFrame: &runtime.CallFrame{FunctionName: "(root)"}, Children: []int{2, 3}},
+			{ID: 2, CallFrame: &runtime.CallFrame{FunctionName: "main", URL: "https://example.com/app.js", ScriptID: "7"}, Children: []int{4}},
+			{ID: 3, CallFrame: &runtime.CallFrame{FunctionName: "(program)"}},
+			{ID: 4, CallFrame: &runtime.CallFrame{URL: "https://example.com/app.js", ScriptID: "7", LineNumber: 9, ColumnNumber: 4}},
+		},
+		StartTime:  1000,
+		EndTime:    5600,
+		Samples:    []int{4, 4, 2, 3},
+		TimeDeltas: []int{100, 1000, 1000, 2000},
+	}
+}
+
+func TestPprof(t *testing.T) {
+	data, err := Pprof(testProfile())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	strings := []string{}
+	samples := [][]uint64{}
+	stacks := [][]uint64{}
+	functions := map[uint64]uint64{}
+	var duration, period uint64
+	for _, f := range decode(t, data) {
+		switch f.num {
+		case 2:
+			for _, s := range decode(t, f.data) {
+				if 1 == s.num {
+					stacks = append(stacks, packed(t, s.data))
+				} else if 2 == s.num {
+					samples = append(samples, packed(t, s.data))
+				}
+			}
+		case 5:
+			var id, name uint64
+			for _, s := range decode(t, f.data) {
+				if 1 == s.num {
+					id = s.value
+				} else if 2 == s.num {
+					name = s.value
+				}
+			}
+			functions[id] = name
+		case 6:
+			strings = append(strings, string(f.data))
+		case 10:
+			duration = f.value
+		case 12:
+			period = f.value
+		}
+	}
+
+	if 0 == len(strings) || "" != strings[0] {
+		t.Fatalf("Expected the string table to start with an empty string, received %v", strings)
+	}
+	names := map[string]bool{}
+	for id, index := range functions {
+		names[strings[index]] = true
+		if 0 == id {
+			t.Errorf("Expected function IDs to be non-zero")
+		}
+	}
+	if 3 != len(functions) || !names["main"] || !names["(anonymous)"] || !names["(program)"] || names["(root)"] {
+		t.Errorf("Unexpected functions %v", names)
+	}
+	if 3 != len(samples) {
+		t.Fatalf("Expected 3 call stacks, received %d", len(samples))
+	}
+	// Nodes 2, 3 and 4 in node order; the anonymous function is called from
+	// main so its stack has two locations, leaf first.
+	expected := [][]uint64{{1, 2000000}, {1, 500000}, {2, 2000000}}
+	for i, values := range samples {
+		if expected[i][0] != values[0] || expected[i][1] != values[1] {
+			t.Errorf("Sample %d: expected %v, received %v", i, expected[i], values)
+		}
+	}
+	if 2 != len(stacks[2]) || stacks[0][0] != stacks[2][1] {
+		t.Errorf("Expected the anonymous function to be called from main, received %v", stacks)
+	}
+	if 4600000 != duration || 1150000 != period {
+		t.Errorf("Unexpected duration %d and period %d", duration, period)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WritePprof(buf, testProfile()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	reader, err := gzip.NewReader(buf)
+	if nil != err {
+		t.Fatalf("Expected gzip data, received error: %v", err)
+	}
+	if compressed, _ := ioutil.ReadAll(reader); !bytes.Equal(data, compressed) {
+		t.Errorf("Expected the compressed profile to match")
+	}
+}
+
+func TestPprofHitCounts(t *testing.T) {
+	profile := testProfile()
+	profile.Samples, profile.TimeDeltas = nil, nil
+	profile.Nodes[1].HitCount = 1
+	profile.Nodes[3].HitCount = 3
+	tree, err := newTree(profile)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 2 != len(tree.sampled) || 3 != tree.counts[4] || 3*1150000 != tree.nanos[4] {
+		t.Errorf("Expected samples to be derived from hit counts, received %v %v", tree.counts, tree.nanos)
+	}
+}
+
+func TestPprofInvalid(t *testing.T) {
+	for name, modify := range map[string]func(*profiler.Profile){
+		"no nodes":       func(p *profiler.Profile) { p.Nodes = nil },
+		"unknown child":  func(p *profiler.Profile) { p.Nodes[0].Children = append(p.Nodes[0].Children, 9) },
+		"two parents":    func(p *profiler.Profile) { p.Nodes[2].Children = []int{4} },
+		"unknown sample": func(p *profiler.Profile) { p.Samples[0] = 9 },
+		"time deltas":    func(p *profiler.Profile) { p.TimeDeltas = p.TimeDeltas[1:] },
+		"no call frame":  func(p *profiler.Profile) { p.Nodes[1].CallFrame = nil },
+		"negative time":  func(p *profiler.Profile) { p.EndTime = 0 },
+		"cycle": func(p *profiler.Profile) {
+			p.Nodes = append(p.Nodes,
+				&profiler.ProfileNode{ID: 5, CallFrame: &runtime.CallFrame{}, Children: []int{6}},
+				&profiler.ProfileNode{ID: 6, CallFrame: &runtime.CallFrame{}, Children: []int{5}},
+			)
+		},
+	} {
+		profile := testProfile()
+		modify(profile)
+		if _, err := Pprof(profile); nil == err {
+			t.Errorf("%s: expected error, received nil", name)
+		}
+	}
+}
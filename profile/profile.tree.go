@@ -0,0 +1,145 @@
+package profile
+
+import (
+	"fmt"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/profiler"
+)
+
+/*
+tree is a validated profile with the samples and CPU time of each node.
+*/
+type tree struct {
+	counts   map[int]int64
+	duration int64
+	nanos    map[int]int64
+	nodes    map[int]*profiler.ProfileNode
+	parents  map[int]int
+	period   int64
+	rootID   int
+	sampled  []*profiler.ProfileNode
+}
+
+/*
+newTree validates a profile and attributes its samples to the profile nodes.
+Each sample lasts until the next sample, the last sample lasts until the end of
+the profile. Profiles without samples are attributed by node hit counts.
+*/
+func newTree(profile *profiler.Profile) (*tree, error) {
+	if nil == profile || 0 == len(profile.Nodes) {
+		return nil, errs.New(codes.ProfileInvalid, "the profile has no nodes")
+	}
+	if profile.EndTime < profile.StartTime {
+		return nil, errs.New(codes.ProfileInvalid, "the profile ends before it starts")
+	}
+	if len(profile.TimeDeltas) > 0 && len(profile.TimeDeltas) != len(profile.Samples) {
+		return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("%d time deltas for %d samples", len(profile.TimeDeltas), len(profile.Samples)))
+	}
+
+	tree := &tree{
+		counts:   map[int]int64{},
+		duration: int64(profile.EndTime-profile.StartTime) * 1000,
+		nanos:    map[int]int64{},
+		nodes:    map[int]*profiler.ProfileNode{},
+		parents:  map[int]int{},
+	}
+	for _, node := range profile.Nodes {
+		if nil == node || nil == node.CallFrame {
+			return nil, errs.New(codes.ProfileInvalid, "a profile node has no call frame")
+		}
+		if _, ok := tree.nodes[node.ID]; ok {
+			return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("duplicate node %d", node.ID))
+		}
+		tree.nodes[node.ID] = node
+	}
+	tree.rootID = profile.Nodes[0].ID
+	for _, node := range profile.Nodes {
+		for _, child := range node.Children {
+			if _, ok := tree.nodes[child]; !ok {
+				return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("node %d has unknown child %d", node.ID, child))
+			}
+			if _, ok := tree.parents[child]; ok || child == tree.rootID {
+				return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("node %d has several parents", child))
+			}
+			tree.parents[child] = node.ID
+		}
+	}
+	// The parents of a node are walked to build its stack, each walk must
+	// end without returning to a node.
+	acyclic := map[int]bool{}
+	for _, node := range profile.Nodes {
+		walked := map[int]bool{}
+		for id := node.ID; !acyclic[id]; {
+			if walked[id] {
+				return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("node %d is its own ancestor", id))
+			}
+			walked[id] = true
+			parent, ok := tree.parents[id]
+			if !ok {
+				break
+			}
+			id = parent
+		}
+		for id := range walked {
+			acyclic[id] = true
+		}
+	}
+
+	if len(profile.Samples) > 0 {
+		timestamps := make([]int, len(profile.Samples))
+		timestamp := profile.StartTime
+		for i, id := range profile.Samples {
+			if _, ok := tree.nodes[id]; !ok {
+				return nil, errs.New(codes.ProfileInvalid, fmt.Sprintf("sample %d has unknown node %d", i, id))
+			}
+			if len(profile.TimeDeltas) > 0 {
+				timestamp += profile.TimeDeltas[i]
+			} else {
+				timestamp = profile.StartTime + (profile.EndTime-profile.StartTime)*i/len(profile.Samples)
+			}
+			timestamps[i] = timestamp
+		}
+		for i, id := range profile.Samples {
+			end := profile.EndTime
+			if i+1 < len(timestamps) {
+				end = timestamps[i+1]
+			}
+			tree.counts[id]++
+			if end > timestamps[i] {
+				tree.nanos[id] += int64(end-timestamps[i]) * 1000
+			}
+		}
+		tree.period = tree.duration / int64(len(profile.Samples))
+	} else {
+		hits := 0
+		for _, node := range profile.Nodes {
+			hits += node.HitCount
+		}
+		if hits > 0 {
+			tree.period = tree.duration / int64(hits)
+		}
+		for _, node := range profile.Nodes {
+			if node.HitCount > 0 {
+				tree.counts[node.ID] = int64(node.HitCount)
+				tree.nanos[node.ID] = int64(node.HitCount) * tree.period
+			}
+		}
+	}
+
+	for _, node := range profile.Nodes {
+		if tree.counts[node.ID] > 0 {
+			tree.sampled = append(tree.sampled, node)
+		}
+	}
+	return tree, nil
+}
+
+/*
+root reports whether a node is the synthetic root node, which is omitted from
+call stacks.
+*/
+func (tree *tree) root(id int) bool {
+	return id == tree.rootID && "(root)" == tree.nodes[id].CallFrame.FunctionName
+}
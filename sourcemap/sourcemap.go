@@ -0,0 +1,300 @@
+/*
+Package sourcemap decodes version 3 source maps, including index maps and
+source maps in data URLs, and maps positions in a generated script to its
+original sources and back. Lines and columns are 0-based, as in the DevTools
+protocol.
+
+	sm, err := sourcemap.Load(script.URL, script.SourceMapURL, fetch)
+	...
+	if pos, ok := sm.Original(line, column); ok {
+		fmt.Printf("%s:%d:%d\n", pos.Source, pos.Line+1, pos.Column+1)
+	}
+
+https://sourcemaps.info/spec.html
+*/
+package sourcemap
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+Map is a decoded source map.
+*/
+type Map struct {
+	// The URL the source URLs are resolved against.
+	URL string
+
+	// Optional. The name of the generated file.
+	File string
+
+	// The resolved URLs of the original sources.
+	Sources []string
+
+	// The content of each original source, nil if it isn't embedded.
+	SourcesContent []*string
+
+	// The symbol names referenced by the mappings.
+	Names []string
+
+	// The segments of each generated line, sorted by column.
+	lines [][]*Segment
+}
+
+/*
+Segment maps a generated column to an original source position.
+*/
+type Segment struct {
+	// The generated column.
+	Column int
+
+	// The index of the original source, -1 if the generated code isn't
+	// mapped.
+	Source int
+
+	// The original position.
+	SourceLine   int
+	SourceColumn int
+
+	// The index of the original name, -1 if the segment has no name.
+	Name int
+}
+
+/*
+Position is a position in an original source.
+*/
+type Position struct {
+	// The source URL.
+	Source string
+
+	// The 0-based line and column.
+	Line   int
+	Column int
+
+	// Optional. The original name of the symbol at the position.
+	Name string
+}
+
+/*
+sourceMap is the JSON encoding of a source map.
+*/
+type sourceMap struct {
+	Version        int        `json:"version"`
+	File           string     `json:"file"`
+	SourceRoot     string     `json:"sourceRoot"`
+	Sources        []string   `json:"sources"`
+	SourcesContent []*string  `json:"sourcesContent"`
+	Names          []string   `json:"names"`
+	Mappings       string     `json:"mappings"`
+	Sections       []*section `json:"sections"`
+}
+
+/*
+Parse decodes a source map or an index map. Source URLs are resolved against
+mapURL.
+*/
+func Parse(mapURL string, data []byte) (*Map, error) {
+	sm := &sourceMap{}
+	if err := json.Unmarshal(data, sm); nil != err {
+		return nil, errs.Wrap(err, codes.SourceMapInvalid, "could not decode the source map")
+	}
+	if 3 != sm.Version {
+		return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("unsupported source map version %d", sm.Version))
+	}
+	if nil != sm.Sections {
+		return parseIndex(mapURL, sm)
+	}
+	lines, err := decodeMappings(sm.Mappings, len(sm.Sources), len(sm.Names))
+	if nil != err {
+		return nil, errs.Wrap(err, codes.SourceMapInvalid, "invalid source map mappings")
+	}
+
+	sourceRoot := sm.SourceRoot
+	if "" != sourceRoot && !strings.HasSuffix(sourceRoot, "/") {
+		sourceRoot += "/"
+	}
+	m := &Map{
+		URL:            mapURL,
+		File:           sm.File,
+		Sources:        make([]string, len(sm.Sources)),
+		SourcesContent: make([]*string, len(sm.Sources)),
+		Names:          sm.Names,
+		lines:          lines,
+	}
+	for a, source := range sm.Sources {
+		m.Sources[a] = resolveURL(mapURL, sourceRoot+source)
+		if a < len(sm.SourcesContent) {
+			m.SourcesContent[a] = sm.SourcesContent[a]
+		}
+	}
+	return m, nil
+}
+
+/*
+Load loads the source map of a script. sourceMapURL is resolved against the
+script URL. Source maps in data URLs are decoded and their sources are
+resolved against the script URL, other source maps are loaded with fetch.
+*/
+func Load(scriptURL, sourceMapURL string, fetch func(url string) ([]byte, error)) (*Map, error) {
+	mapURL := resolveURL(scriptURL, sourceMapURL)
+	var data []byte
+	var err error
+	if strings.HasPrefix(mapURL, "data:") {
+		data, err = decodeDataURL(mapURL)
+		mapURL = scriptURL
+	} else if nil != fetch {
+		data, err = fetch(mapURL)
+	} else {
+		return nil, errs.New(codes.SourceMapLoadFailed, fmt.Sprintf("no source map loader for %s", mapURL))
+	}
+	if nil != err {
+		return nil, errs.Wrap(err, codes.SourceMapLoadFailed, fmt.Sprintf("could not load the source map of %s", scriptURL))
+	}
+	return Parse(mapURL, data)
+}
+
+/*
+Lines returns the number of generated lines with mappings.
+*/
+func (m *Map) Lines() int {
+	return len(m.lines)
+}
+
+/*
+Segments returns the segments of a generated line sorted by column.
+*/
+func (m *Map) Segments(line int) []*Segment {
+	if line < 0 || line >= len(m.lines) {
+		return nil
+	}
+	return m.lines[line]
+}
+
+/*
+Original returns the original position of a generated position, the position
+of the last segment that starts at or before the column.
+*/
+func (m *Map) Original(line, column int) (*Position, bool) {
+	var seg *Segment
+	for _, s := range m.Segments(line) {
+		if s.Column > column {
+			break
+		}
+		seg = s
+	}
+	if nil == seg || seg.Source < 0 {
+		return nil, false
+	}
+	pos := &Position{
+		Source: m.Sources[seg.Source],
+		Line:   seg.SourceLine,
+		Column: seg.SourceColumn,
+	}
+	if seg.Name >= 0 {
+		pos.Name = m.Names[seg.Name]
+	}
+	return pos, true
+}
+
+/*
+Generated returns the generated position of an original position: the first
+generated code mapped to the line at or after the column, or the last code
+mapped to the line before the column.
+*/
+func (m *Map) Generated(source string, line, column int) (genLine, genColumn int, ok bool) {
+	index := m.SourceIndex(source)
+	if index < 0 {
+		return 0, 0, false
+	}
+	var best, fallback *Segment
+	bestLine, fallbackLine := 0, 0
+	for number, segments := range m.lines {
+		for _, seg := range segments {
+			if index != seg.Source || line != seg.SourceLine {
+				continue
+			}
+			if seg.SourceColumn >= column {
+				if nil == best || seg.SourceColumn < best.SourceColumn {
+					best, bestLine = seg, number
+				}
+			} else if nil == fallback || seg.SourceColumn > fallback.SourceColumn {
+				fallback, fallbackLine = seg, number
+			}
+		}
+	}
+	if nil != best {
+		return bestLine, best.Column, true
+	}
+	if nil != fallback {
+		return fallbackLine, fallback.Column, true
+	}
+	return 0, 0, false
+}
+
+/*
+SourceIndex returns the index of a source by URL or by a path suffix such as
+"src/app.ts", or -1.
+*/
+func (m *Map) SourceIndex(source string) int {
+	for a, src := range m.Sources {
+		if source == src {
+			return a
+		}
+	}
+	for a, src := range m.Sources {
+		if MatchURL(src, source) {
+			return a
+		}
+	}
+	return -1
+}
+
+/*
+MatchURL reports whether a URL is name or ends with the path name, e.g.
+"https://example.com/src/app.ts" matches "app.ts" and "src/app.ts" but not
+"p.ts".
+*/
+func MatchURL(src, name string) bool {
+	name = strings.TrimPrefix(name, "./")
+	if "" == name {
+		return false
+	}
+	return src == name || strings.HasSuffix(src, "/"+strings.TrimPrefix(name, "/"))
+}
+
+/*
+decodeDataURL returns the content of a data URL.
+*/
+func decodeDataURL(dataURL string) ([]byte, error) {
+	comma := strings.Index(dataURL, ",")
+	if comma < 0 {
+		return nil, errs.New(codes.SourceMapLoadFailed, "invalid data URL")
+	}
+	if strings.HasSuffix(dataURL[:comma], ";base64") {
+		return base64.StdEncoding.DecodeString(dataURL[comma+1:])
+	}
+	data, err := url.PathUnescape(dataURL[comma+1:])
+	return []byte(data), err
+}
+
+/*
+resolveURL resolves a URL reference against a base URL.
+*/
+func resolveURL(base, ref string) string {
+	refURL, err := url.Parse(ref)
+	if nil != err {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if nil != err {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
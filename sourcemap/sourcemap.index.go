@@ -0,0 +1,104 @@
+package sourcemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+section is a section of an index map, a source map for the generated code
+starting at the offset.
+*/
+type section struct {
+	Offset struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"offset"`
+	URL string          `json:"url"`
+	Map json.RawMessage `json:"map"`
+}
+
+/*
+parseIndex flattens the sections of an index map into a single map. Sections
+must be ordered by offset and embed their map, sections that reference a map
+by URL aren't supported.
+*/
+func parseIndex(mapURL string, sm *sourceMap) (*Map, error) {
+	m := &Map{
+		URL:            mapURL,
+		File:           sm.File,
+		Sources:        []string{},
+		SourcesContent: []*string{},
+		Names:          []string{},
+		lines:          [][]*Segment{},
+	}
+	line, column := 0, 0
+	for a, sec := range sm.Sections {
+		if sec.Offset.Line < line || (sec.Offset.Line == line && sec.Offset.Column < column) {
+			return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("section %d overlaps the previous section", a))
+		}
+		line, column = sec.Offset.Line, sec.Offset.Column
+		if "" != sec.URL || 0 == len(sec.Map) {
+			return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("section %d doesn't embed its source map", a))
+		}
+		sectionMap, err := Parse(mapURL, sec.Map)
+		if nil != err {
+			return nil, errs.Wrap(err, codes.SourceMapInvalid, fmt.Sprintf("invalid source map in section %d", a))
+		}
+		m.merge(sectionMap, line, column)
+	}
+	return m, nil
+}
+
+/*
+merge adds the mappings of a section map starting at an offset. Sources
+shared with previous sections are merged.
+*/
+func (m *Map) merge(sectionMap *Map, line, column int) {
+	sources := make([]int, len(sectionMap.Sources))
+	for a, source := range sectionMap.Sources {
+		sources[a] = -1
+		for b, src := range m.Sources {
+			if source == src {
+				sources[a] = b
+				break
+			}
+		}
+		if sources[a] < 0 {
+			sources[a] = len(m.Sources)
+			m.Sources = append(m.Sources, source)
+			m.SourcesContent = append(m.SourcesContent, sectionMap.SourcesContent[a])
+		} else if nil == m.SourcesContent[sources[a]] {
+			m.SourcesContent[sources[a]] = sectionMap.SourcesContent[a]
+		}
+	}
+	names := len(m.Names)
+	m.Names = append(m.Names, sectionMap.Names...)
+
+	for number, segments := range sectionMap.lines {
+		target := line + number
+		for len(m.lines) <= target {
+			m.lines = append(m.lines, []*Segment{})
+		}
+		for _, seg := range segments {
+			merged := *seg
+			if 0 == number {
+				merged.Column += column
+			}
+			if merged.Source >= 0 {
+				merged.Source = sources[merged.Source]
+			}
+			if merged.Name >= 0 {
+				merged.Name += names
+			}
+			m.lines[target] = append(m.lines[target], &merged)
+		}
+		sort.SliceStable(m.lines[target], func(i, j int) bool {
+			return m.lines[target][i].Column < m.lines[target][j].Column
+		})
+	}
+}
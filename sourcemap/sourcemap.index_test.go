@@ -0,0 +1,61 @@
+package sourcemap
+
+import (
+	"testing"
+)
+
+/*
+testIndexMap has two sections that share a source. The second section starts
+at column 10 of the second line.
+*/
+const testIndexMap = `{
+	"version": 3,
+	"file": "bundle.js",
+	"sections": [
+		{"offset": {"line": 0, "column": 0}, "map": {"version": 3, "sources": ["a.ts", "shared.ts"], "names": ["a"], "mappings": "AAAAA;ACAA"}},
+		{"offset": {"line": 1, "column": 10}, "map": {"version": 3, "sources": ["shared.ts"], "sourcesContent": ["export {}"], "names": ["b"], "mappings": "AAEAA"}}
+	]
+}`
+
+func TestParseIndex(t *testing.T) {
+	sm, err := Parse("https://example.com/bundle.js.map", []byte(testIndexMap))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "bundle.js" != sm.File || 2 != len(sm.Sources) || 2 != len(sm.Names) || 2 != sm.Lines() {
+		t.Fatalf("Unexpected index map %+v", sm)
+	}
+	if nil == sm.SourcesContent[1] || "export {}" != *sm.SourcesContent[1] {
+		t.Errorf("Expected the content of the shared source, received %v", sm.SourcesContent)
+	}
+
+	for _, test := range []struct {
+		line, column int
+		expected     Position
+	}{
+		{0, 0, Position{Source: "https://example.com/a.ts", Name: "a"}},
+		{1, 5, Position{Source: "https://example.com/shared.ts"}},
+		{1, 12, Position{Source: "https://example.com/shared.ts", Line: 2, Name: "b"}},
+	} {
+		pos, ok := sm.Original(test.line, test.column)
+		if !ok || test.expected != *pos {
+			t.Errorf("%d:%d: expected %+v, received %+v", test.line, test.column, test.expected, pos)
+		}
+	}
+	if line, column, ok := sm.Generated("shared.ts", 2, 0); !ok || 1 != line || 10 != column {
+		t.Errorf("Expected 1:10, received %d:%d %v", line, column, ok)
+	}
+
+	for _, data := range []string{
+		`{"version": 3, "sections": [{"offset": {"line": 0, "column": 0}, "url": "a.js.map"}]}`,
+		`{"version": 3, "sections": [{"offset": {"line": 0, "column": 0}, "map": {"version": 2}}]}`,
+		`{"version": 3, "sections": [
+			{"offset": {"line": 1, "column": 0}, "map": {"version": 3, "sources": [], "mappings": ""}},
+			{"offset": {"line": 0, "column": 5}, "map": {"version": 3, "sources": [], "mappings": ""}}
+		]}`,
+	} {
+		if _, err := Parse("https://example.com/bundle.js.map", []byte(data)); nil == err {
+			t.Errorf("%s: expected error, received nil", data)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package sourcemap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+decodeMappings decodes the base64 VLQ mappings of a source map into the
+segments of each generated line.
+*/
+func decodeMappings(mappings string, sources, names int) ([][]*Segment, error) {
+	lines := [][]*Segment{}
+	var source, sourceLine, sourceColumn, name int
+	for _, group := range strings.Split(mappings, ";") {
+		segments := []*Segment{}
+		column := 0
+		for _, field := range strings.Split(group, ",") {
+			if "" == field {
+				continue
+			}
+			values, err := decodeVLQ(field)
+			if nil != err {
+				return nil, err
+			}
+			if 1 != len(values) && 4 != len(values) && 5 != len(values) {
+				return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("invalid segment '%s'", field))
+			}
+			column += values[0]
+			seg := &Segment{Column: column, Source: -1, Name: -1}
+			if len(values) >= 4 {
+				source += values[1]
+				sourceLine += values[2]
+				sourceColumn += values[3]
+				if source < 0 || source >= sources {
+					return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("invalid source %d", source))
+				}
+				seg.Source, seg.SourceLine, seg.SourceColumn = source, sourceLine, sourceColumn
+			}
+			if 5 == len(values) {
+				name += values[4]
+				if name < 0 || name >= names {
+					return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("invalid name %d", name))
+				}
+				seg.Name = name
+			}
+			segments = append(segments, seg)
+		}
+		sort.SliceStable(segments, func(i, j int) bool {
+			return segments[i].Column < segments[j].Column
+		})
+		lines = append(lines, segments)
+	}
+	return lines, nil
+}
+
+/*
+decodeVLQ decodes a base64 VLQ field.
+*/
+func decodeVLQ(field string) ([]int, error) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	values := []int{}
+	value, shift := 0, uint(0)
+	for _, c := range field {
+		digit := strings.IndexRune(alphabet, c)
+		if digit < 0 {
+			return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("invalid base64 character '%c'", c))
+		}
+		value += (digit & 31) << shift
+		if 0 != digit&32 {
+			shift += 5
+			continue
+		}
+		if 1 == value&1 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+		value, shift = 0, 0
+	}
+	if 0 != shift {
+		return nil, errs.New(codes.SourceMapInvalid, fmt.Sprintf("truncated field '%s'", field))
+	}
+	return values, nil
+}
@@ -0,0 +1,57 @@
+package sourcemap
+
+import (
+	"fmt"
+	"testing"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+func TestDecodeVLQ(t *testing.T) {
+	for field, expected := range map[string][]int{
+		"AAAA": {0, 0, 0, 0},
+		"ECIE": {2, 1, 4, 2},
+		"D":    {-1},
+		"gB":   {16},
+	} {
+		values, err := decodeVLQ(field)
+		if nil != err || fmt.Sprint(expected) != fmt.Sprint(values) {
+			t.Errorf("%s: expected %v, received %v (%v)", field, expected, values, err)
+		}
+	}
+	for _, field := range []string{"g", "!"} {
+		if _, err := decodeVLQ(field); nil == err {
+			t.Errorf("%s: expected error, received nil", field)
+		}
+	}
+}
+
+func TestDecodeMappings(t *testing.T) {
+	// Columns are relative to the previous segment, the second segment
+	// starts before the first one.
+	lines, err := decodeMappings("IAAAA,DAAA;;C", 1, 1)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 3 != len(lines) || 2 != len(lines[0]) || 0 != len(lines[1]) || 1 != len(lines[2]) {
+		t.Fatalf("Unexpected lines %v", lines)
+	}
+	if first := lines[0][0]; 3 != first.Column || 0 != first.Source || -1 != first.Name {
+		t.Errorf("Unexpected segment %+v", first)
+	}
+	if second := lines[0][1]; 4 != second.Column || 0 != second.Name {
+		t.Errorf("Unexpected segment %+v", second)
+	}
+	if unmapped := lines[2][0]; 1 != unmapped.Column || -1 != unmapped.Source {
+		t.Errorf("Expected an unmapped segment, received %+v", unmapped)
+	}
+
+	for _, mappings := range []string{"AC", "ACAA", "AAAAC", "AAAA,g"} {
+		if _, err := decodeMappings(mappings, 1, 0); nil == err {
+			t.Errorf("%s: expected error, received nil", mappings)
+		} else if codes.SourceMapInvalid != err.(*errs.Err).Code() {
+			t.Errorf("%s: expected code %d, received %d", mappings, codes.SourceMapInvalid, err.(*errs.Err).Code())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package sourcemap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+testMap maps the first line of a script to src/a.ts and the second line to
+src/b.ts. The first segment is named "main".
+*/
+const testMap = `{
+	"version": 3,
+	"file": "app.js",
+	"sourceRoot": "src",
+	"sources": ["a.ts", "b.ts"],
+	"sourcesContent": ["const a = 1"],
+	"names": ["main"],
+	"mappings": "AAAAA,IAAI;ACCA"
+}`
+
+func TestParse(t *testing.T) {
+	sm, err := Parse("https://example.com/js/app.js.map", []byte(testMap))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "app.js" != sm.File || 2 != len(sm.Sources) || "https://example.com/js/src/b.ts" != sm.Sources[1] {
+		t.Errorf("Unexpected source map %+v", sm)
+	}
+	if 2 != len(sm.SourcesContent) || nil == sm.SourcesContent[0] || nil != sm.SourcesContent[1] {
+		t.Errorf("Expected the content of the first source only, received %v", sm.SourcesContent)
+	}
+	if 2 != sm.Lines() || 2 != len(sm.Segments(0)) || nil != sm.Segments(2) {
+		t.Errorf("Unexpected segments %v", sm.lines)
+	}
+
+	for _, data := range []string{
+		`{`,
+		`{"version": 2, "sources": [], "mappings": ""}`,
+		`{"version": 3, "sources": [], "mappings": "AAAA"}`,
+	} {
+		_, err := Parse("https://example.com/app.js.map", []byte(data))
+		if nil == err {
+			t.Errorf("%s: expected error, received nil", data)
+		} else if codes.SourceMapInvalid != err.(*errs.Err).Code() {
+			t.Errorf("%s: expected code %d, received %d", data, codes.SourceMapInvalid, err.(*errs.Err).Code())
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	requested := ""
+	fetch := func(url string) ([]byte, error) {
+		requested = url
+		return []byte(testMap), nil
+	}
+	sm, err := Load("https://example.com/js/app.js", "app.js.map", fetch)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/js/app.js.map" != requested || "https://example.com/js/app.js.map" != sm.URL {
+		t.Errorf("Expected the source map URL to be resolved against the script, received %s", requested)
+	}
+
+	// Sources of inline source maps are resolved against the script.
+	dataURL := "data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(testMap))
+	sm, err = Load("https://example.com/js/app.js", dataURL, nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/js/src/a.ts" != sm.Sources[0] {
+		t.Errorf("Unexpected sources %v", sm.Sources)
+	}
+	if _, err := Load("https://example.com/app.js", "data:application/json,%7B%22version%22:3,%22sources%22:[],%22mappings%22:%22%22%7D", nil); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+
+	for _, test := range []struct {
+		sourceMapURL string
+		fetch        func(string) ([]byte, error)
+	}{
+		{"app.js.map", nil},
+		{"app.js.map", func(string) ([]byte, error) { return nil, fmt.Errorf("404 Not Found") }},
+		{"data:application/json;base64,!", nil},
+		{"data:application/json", nil},
+	} {
+		_, err := Load("https://example.com/app.js", test.sourceMapURL, test.fetch)
+		if nil == err {
+			t.Errorf("%s: expected error, received nil", test.sourceMapURL)
+		} else if codes.SourceMapLoadFailed != err.(*errs.Err).Code() {
+			t.Errorf("%s: expected code %d, received %d", test.sourceMapURL, codes.SourceMapLoadFailed, err.(*errs.Err).Code())
+		}
+	}
+}
+
+func TestOriginal(t *testing.T) {
+	sm, err := Parse("https://example.com/app.js.map", []byte(testMap))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, test := range []struct {
+		line, column int
+		expected     *Position
+	}{
+		{0, 0, &Position{Source: "https://example.com/src/a.ts", Name: "main"}},
+		{0, 3, &Position{Source: "https://example.com/src/a.ts", Name: "main"}},
+		{0, 4, &Position{Source: "https://example.com/src/a.ts", Column: 4}},
+		{1, 10, &Position{Source: "https://example.com/src/b.ts", Line: 1, Column: 4}},
+		{2, 0, nil},
+		{-1, 0, nil},
+	} {
+		pos, ok := sm.Original(test.line, test.column)
+		if (nil != test.expected) != ok || (ok && *test.expected != *pos) {
+			t.Errorf("%d:%d: expected %+v, received %+v", test.line, test.column, test.expected, pos)
+		}
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	sm, err := Parse("https://example.com/app.js.map", []byte(testMap))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, test := range []struct {
+		source             string
+		line, column       int
+		genLine, genColumn int
+		ok                 bool
+	}{
+		{"https://example.com/src/a.ts", 0, 2, 0, 4, true},
+		{"a.ts", 0, 0, 0, 0, true},
+		// Past the last mapping of the line.
+		{"src/a.ts", 0, 10, 0, 4, true},
+		{"b.ts", 1, 0, 1, 0, true},
+		{"b.ts", 0, 0, 0, 0, false},
+		{"c.ts", 0, 0, 0, 0, false},
+	} {
+		genLine, genColumn, ok := sm.Generated(test.source, test.line, test.column)
+		if test.ok != ok || test.genLine != genLine || test.genColumn != genColumn {
+			t.Errorf("%s:%d:%d: expected %d:%d %v, received %d:%d %v", test.source, test.line, test.column, test.genLine, test.genColumn, test.ok, genLine, genColumn, ok)
+		}
+	}
+}
+
+func TestMatchURL(t *testing.T) {
+	for _, test := range []struct {
+		src, name string
+		expected  bool
+	}{
+		{"https://example.com/src/app.ts", "https://example.com/src/app.ts", true},
+		{"https://example.com/src/app.ts", "app.ts", true},
+		{"https://example.com/src/app.ts", "./src/app.ts", true},
+		{"https://example.com/src/app.ts", "/src/app.ts", true},
+		{"https://example.com/src/app.ts", "p.ts", false},
+		{"https://example.com/src/app.ts", "", false},
+		{"webpack:///src/app.ts", "src/app.ts", true},
+	} {
+		if test.expected != MatchURL(test.src, test.name) {
+			t.Errorf("%s %s: expected %v", test.src, test.name, test.expected)
+		}
+	}
+}
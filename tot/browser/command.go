@@ -90,6 +90,55 @@ type GetWindowForTargetResult struct {
 	Err error `json:"-"`
 }
 
+/*
+GrantPermissionsParams represents Browser.grantPermissions parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Browser/#method-grantPermissions
+*/
+type GrantPermissionsParams struct {
+	// Optional. Origin the permission applies to, all origins if not
+	// specified.
+	Origin string `json:"origin,omitempty"`
+
+	// The permissions to grant.
+	Permissions []PermissionType `json:"permissions"`
+
+	// Optional. BrowserContext to override permissions. When omitted, default
+	// browser context is used.
+	BrowserContextID target.BrowserContextID `json:"browserContextId,omitempty"`
+}
+
+/*
+GrantPermissionsResult represents the result of calls to Browser.grantPermissions.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Browser/#method-grantPermissions
+*/
+type GrantPermissionsResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
+/*
+ResetPermissionsParams represents Browser.resetPermissions parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Browser/#method-resetPermissions
+*/
+type ResetPermissionsParams struct {
+	// Optional. BrowserContext to reset permissions. When omitted, default
+	// browser context is used.
+	BrowserContextID target.BrowserContextID `json:"browserContextId,omitempty"`
+}
+
+/*
+ResetPermissionsResult represents the result of calls to Browser.resetPermissions.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Browser/#method-resetPermissions
+*/
+type ResetPermissionsResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 SetWindowBoundsParams represents Browser.setWindowBounds parameters.
 
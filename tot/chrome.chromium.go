@@ -34,6 +34,23 @@ func New(
 	}
 }
 
+/*
+DeterministicFlags are the Chromium flags Launch sets when deterministic
+rendering is selected by setting the "deterministic-mode" or
+"enable-begin-frame-control" flag. In this mode the compositor only produces a
+frame when one is requested with HeadlessExperimental.beginFrame and every
+rendering stage runs before the frame is drawn, see Tab.BeginFrames.
+*/
+var DeterministicFlags = []string{
+	"deterministic-mode",
+	"enable-begin-frame-control",
+	"run-all-compositor-stages-before-draw",
+	"disable-new-content-rendering-timeout",
+	"disable-threaded-animation",
+	"disable-threaded-scrolling",
+	"disable-checker-imaging",
+}
+
 /*
 Chrome implements Chromium.
 */
@@ -92,6 +109,13 @@ func (chrome *Chrome) Address() string {
 	return value.(string)
 }
 
+/*
+Deterministic returns true if deterministic rendering is selected.
+*/
+func (chrome *Chrome) Deterministic() bool {
+	return chrome.Flags().Has("deterministic-mode") || chrome.Flags().Has("enable-begin-frame-control")
+}
+
 /*
 Flags implements Chromium.
 */
@@ -189,6 +213,11 @@ they aren't included in the Flags definition:
 	user-data-dir = os.TempDir() + chrome.Workdir()
 	chrome.workdir = "headless-chrome"
 	chrome.output = "/dev/stdout"
+
+If either the "deterministic-mode" or "enable-begin-frame-control" flag is set
+all of the DeterministicFlags are set:
+
+	flags := chrome.Flags{"headless": nil, "deterministic-mode": nil}
 */
 func (chrome *Chrome) Launch() error {
 	var err error
@@ -201,6 +230,11 @@ func (chrome *Chrome) Launch() error {
 	if !chrome.Flags().Has("user-data-dir") {
 		chrome.Flags().Set("user-data-dir", os.TempDir())
 	}
+	if chrome.Deterministic() {
+		for _, flag := range DeterministicFlags {
+			chrome.Flags().Set(flag, nil)
+		}
+	}
 
 	if err = os.MkdirAll(chrome.Workdir(), 0700); err != nil {
 		return errs.Wrap(err, codes.ChromeInvalidWorkdir, fmt.Sprintf("cannot create working directory '%s'", chrome.Workdir()))
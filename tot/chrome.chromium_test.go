@@ -80,6 +80,30 @@ func TestChromiumLaunch(t *testing.T) {
 	}
 }
 
+func TestChromiumLaunchDeterministic(t *testing.T) {
+	flags := &Flags{"deterministic-mode": nil}
+	workdir := filepath.Join(os.TempDir(), "go-chrome-deterministic")
+	defer os.RemoveAll(workdir)
+	chrome := New(
+		flags,
+		filepath.Join(workdir, "missing-binary"),
+		workdir,
+		filepath.Join(workdir, "stdout"),
+		filepath.Join(workdir, "stderr"),
+	)
+	if !chrome.Deterministic() {
+		t.Errorf("Expected deterministic rendering to be selected")
+	}
+	if err := chrome.Launch(); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	for _, flag := range DeterministicFlags {
+		if !flags.Has(flag) {
+			t.Errorf("Expected the '%s' flag to be set", flag)
+		}
+	}
+}
+
 func TestChromiumQuery(t *testing.T) {
 	chrome := New(
 		&Flags{
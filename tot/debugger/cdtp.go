@@ -99,7 +99,8 @@ type Scope struct {
 	//	- ScopeType.Block
 	//	- ScopeType.Script
 	//	- ScopeType.Eval
-	//	- ScopeType.Module.
+	//	- ScopeType.Module
+	//	- ScopeType.WasmExpressionStack
 	Type ScopeTypeEnum `json:"type"`
 
 	// Object representing the scope. For global and with scopes it represents
@@ -0,0 +1,142 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type reasonEnum struct {
+	XHR              ReasonEnum
+	DOM              ReasonEnum
+	EventListener    ReasonEnum
+	Exception        ReasonEnum
+	Assert           ReasonEnum
+	DebugCommand     ReasonEnum
+	PromiseRejection ReasonEnum
+	OOM              ReasonEnum
+	Other            ReasonEnum
+	Ambiguous        ReasonEnum
+	Instrumentation  ReasonEnum
+	CSPViolation     ReasonEnum
+	Step             ReasonEnum
+}
+
+/*
+Reason provides named acces to the ReasonEnum values.
+*/
+var Reason = reasonEnum{
+	XHR:              reasonXHR,
+	DOM:              reasonDOM,
+	EventListener:    reasonEventListener,
+	Exception:        reasonException,
+	Assert:           reasonAssert,
+	DebugCommand:     reasonDebugCommand,
+	PromiseRejection: reasonPromiseRejection,
+	OOM:              reasonOOM,
+	Other:            reasonOther,
+	Ambiguous:        reasonAmbiguous,
+	Instrumentation:  reasonInstrumentation,
+	CSPViolation:     reasonCSPViolation,
+	Step:             reasonStep,
+}
+
+/*
+ReasonEnum represents the reason the debugger paused. Allowed values:
+	- Reason.XHR              "XHR"
+	- Reason.DOM              "DOM"
+	- Reason.EventListener    "EventListener"
+	- Reason.Exception        "exception"
+	- Reason.Assert           "assert"
+	- Reason.DebugCommand     "debugCommand"
+	- Reason.PromiseRejection "promiseRejection"
+	- Reason.OOM              "OOM"
+	- Reason.Other            "other"
+	- Reason.Ambiguous        "ambiguous"
+	- Reason.Instrumentation  "instrumentation"
+	- Reason.CSPViolation     "CSPViolation"
+	- Reason.Step             "step"
+
+https://chromedevtools.github.io/devtools-protocol/tot/Debugger/#event-paused
+*/
+type ReasonEnum int
+
+/*
+String implements Stringer
+*/
+func (enum ReasonEnum) String() string {
+	return _reasonEnums[enum]
+}
+
+/*
+MarshalJSON implements json.Marshaler
+*/
+func (enum ReasonEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(enum.String())
+}
+
+/*
+UnmarshalJSON implements json.Unmarshaler
+*/
+func (enum *ReasonEnum) UnmarshalJSON(bytes []byte) error {
+	var err error
+	var val string
+
+	err = json.Unmarshal(bytes, &val)
+	if nil != err {
+		return err
+	}
+
+	for k, v := range _reasonEnums {
+		if v == val {
+			*enum = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a valid reason value", bytes)
+}
+
+const (
+	// reasonXHR represents the "XHR" value.
+	reasonXHR ReasonEnum = iota + 1
+	// reasonDOM represents the "DOM" value.
+	reasonDOM
+	// reasonEventListener represents the "EventListener" value.
+	reasonEventListener
+	// reasonException represents the "exception" value.
+	reasonException
+	// reasonAssert represents the "assert" value.
+	reasonAssert
+	// reasonDebugCommand represents the "debugCommand" value.
+	reasonDebugCommand
+	// reasonPromiseRejection represents the "promiseRejection" value.
+	reasonPromiseRejection
+	// reasonOOM represents the "OOM" value.
+	reasonOOM
+	// reasonOther represents the "other" value.
+	reasonOther
+	// reasonAmbiguous represents the "ambiguous" value.
+	reasonAmbiguous
+	// reasonInstrumentation represents the "instrumentation" value.
+	reasonInstrumentation
+	// reasonCSPViolation represents the "CSPViolation" value.
+	reasonCSPViolation
+	// reasonStep represents the "step" value.
+	reasonStep
+)
+
+var _reasonEnums = map[ReasonEnum]string{
+	reasonXHR:              "XHR",
+	reasonDOM:              "DOM",
+	reasonEventListener:    "EventListener",
+	reasonException:        "exception",
+	reasonAssert:           "assert",
+	reasonDebugCommand:     "debugCommand",
+	reasonPromiseRejection: "promiseRejection",
+	reasonOOM:              "OOM",
+	reasonOther:            "other",
+	reasonAmbiguous:        "ambiguous",
+	reasonInstrumentation:  "instrumentation",
+	reasonCSPViolation:     "CSPViolation",
+	reasonStep:             "step",
+}
@@ -0,0 +1,212 @@
+package debugger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumReason(t *testing.T) {
+	var enum ReasonEnum
+	var err error
+	var result []byte
+
+	err = json.Unmarshal([]byte(`""`), &enum)
+	if nil == err {
+		t.Errorf("Expected error, got nil")
+	}
+
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `""` != string(result) {
+		t.Errorf("Expected empty JSON string, got '%s'", result)
+	}
+
+	enum = Reason.XHR
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"XHR"` != string(result) {
+		t.Errorf("Expected '\"XHR\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"XHR"`), &enum)
+	if Reason.XHR != enum {
+		t.Errorf("Expcected %d, got %d", Reason.XHR, enum)
+	}
+
+	enum = Reason.DOM
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"DOM"` != string(result) {
+		t.Errorf("Expected '\"DOM\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"DOM"`), &enum)
+	if Reason.DOM != enum {
+		t.Errorf("Expcected %d, got %d", Reason.DOM, enum)
+	}
+
+	enum = Reason.EventListener
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"EventListener"` != string(result) {
+		t.Errorf("Expected '\"EventListener\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"EventListener"`), &enum)
+	if Reason.EventListener != enum {
+		t.Errorf("Expcected %d, got %d", Reason.EventListener, enum)
+	}
+
+	enum = Reason.Exception
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"exception"` != string(result) {
+		t.Errorf("Expected '\"exception\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"exception"`), &enum)
+	if Reason.Exception != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Exception, enum)
+	}
+}
+
+func TestEnumReason2(t *testing.T) {
+	var enum ReasonEnum
+	var err error
+	var result []byte
+
+	enum = Reason.Assert
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"assert"` != string(result) {
+		t.Errorf("Expected '\"assert\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"assert"`), &enum)
+	if Reason.Assert != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Assert, enum)
+	}
+
+	enum = Reason.DebugCommand
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"debugCommand"` != string(result) {
+		t.Errorf("Expected '\"debugCommand\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"debugCommand"`), &enum)
+	if Reason.DebugCommand != enum {
+		t.Errorf("Expcected %d, got %d", Reason.DebugCommand, enum)
+	}
+
+	enum = Reason.PromiseRejection
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"promiseRejection"` != string(result) {
+		t.Errorf("Expected '\"promiseRejection\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"promiseRejection"`), &enum)
+	if Reason.PromiseRejection != enum {
+		t.Errorf("Expcected %d, got %d", Reason.PromiseRejection, enum)
+	}
+
+	enum = Reason.OOM
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"OOM"` != string(result) {
+		t.Errorf("Expected '\"OOM\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"OOM"`), &enum)
+	if Reason.OOM != enum {
+		t.Errorf("Expcected %d, got %d", Reason.OOM, enum)
+	}
+}
+
+func TestEnumReason3(t *testing.T) {
+	var enum ReasonEnum
+	var err error
+	var result []byte
+
+	enum = Reason.Other
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"other"` != string(result) {
+		t.Errorf("Expected '\"other\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"other"`), &enum)
+	if Reason.Other != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Other, enum)
+	}
+
+	enum = Reason.Ambiguous
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"ambiguous"` != string(result) {
+		t.Errorf("Expected '\"ambiguous\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"ambiguous"`), &enum)
+	if Reason.Ambiguous != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Ambiguous, enum)
+	}
+
+	enum = Reason.Instrumentation
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"instrumentation"` != string(result) {
+		t.Errorf("Expected '\"instrumentation\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"instrumentation"`), &enum)
+	if Reason.Instrumentation != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Instrumentation, enum)
+	}
+
+	enum = Reason.CSPViolation
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"CSPViolation"` != string(result) {
+		t.Errorf("Expected '\"CSPViolation\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"CSPViolation"`), &enum)
+	if Reason.CSPViolation != enum {
+		t.Errorf("Expcected %d, got %d", Reason.CSPViolation, enum)
+	}
+}
+
+func TestEnumReason4(t *testing.T) {
+	var enum ReasonEnum
+	var err error
+	var result []byte
+
+	enum = Reason.Step
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"step"` != string(result) {
+		t.Errorf("Expected '\"step\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"step"`), &enum)
+	if Reason.Step != enum {
+		t.Errorf("Expcected %d, got %d", Reason.Step, enum)
+	}
+}
@@ -6,43 +6,46 @@ import (
 )
 
 type scopeTypeEnum struct {
-	Global  ScopeTypeEnum
-	Local   ScopeTypeEnum
-	With    ScopeTypeEnum
-	Closure ScopeTypeEnum
-	Catch   ScopeTypeEnum
-	Block   ScopeTypeEnum
-	Script  ScopeTypeEnum
-	Eval    ScopeTypeEnum
-	Module  ScopeTypeEnum
+	Global              ScopeTypeEnum
+	Local               ScopeTypeEnum
+	With                ScopeTypeEnum
+	Closure             ScopeTypeEnum
+	Catch               ScopeTypeEnum
+	Block               ScopeTypeEnum
+	Script              ScopeTypeEnum
+	Eval                ScopeTypeEnum
+	Module              ScopeTypeEnum
+	WasmExpressionStack ScopeTypeEnum
 }
 
 /*
 ScopeType provides named acces to the ScopeTypeEnum values.
 */
 var ScopeType = scopeTypeEnum{
-	Global:  ScopeTypeGlobal,
-	Local:   ScopeTypeLocal,
-	With:    ScopeTypeWith,
-	Closure: ScopeTypeClosure,
-	Catch:   ScopeTypeCatch,
-	Block:   ScopeTypeBlock,
-	Script:  ScopeTypeScript,
-	Eval:    ScopeTypeEval,
-	Module:  ScopeTypeModule,
+	Global:              ScopeTypeGlobal,
+	Local:               ScopeTypeLocal,
+	With:                ScopeTypeWith,
+	Closure:             ScopeTypeClosure,
+	Catch:               ScopeTypeCatch,
+	Block:               ScopeTypeBlock,
+	Script:              ScopeTypeScript,
+	Eval:                ScopeTypeEval,
+	Module:              ScopeTypeModule,
+	WasmExpressionStack: ScopeTypeWasmExpressionStack,
 }
 
 /*
 ScopeTypeEnum represents the scope type. Allowed values:
-	- ScopeType.Global  "global"
-	- ScopeType.Local   "local"
-	- ScopeType.With    "with"
-	- ScopeType.Closure "closure"
-	- ScopeType.Catch   "catch"
-	- ScopeType.Block   "block"
-	- ScopeType.Script  "script"
-	- ScopeType.Eval    "eval"
-	- ScopeType.Module  "module"
+	- ScopeType.Global              "global"
+	- ScopeType.Local               "local"
+	- ScopeType.With                "with"
+	- ScopeType.Closure             "closure"
+	- ScopeType.Catch               "catch"
+	- ScopeType.Block               "block"
+	- ScopeType.Script              "script"
+	- ScopeType.Eval                "eval"
+	- ScopeType.Module              "module"
+	- ScopeType.WasmExpressionStack "wasm-expression-stack"
 
 https://chromedevtools.github.io/devtools-protocol/tot/Debugger/#type-Scope
 */
@@ -103,16 +106,19 @@ const (
 	ScopeTypeEval
 	// ScopeTypeModule represents the "module" value.
 	ScopeTypeModule
+	// ScopeTypeWasmExpressionStack represents the "wasm-expression-stack" value.
+	ScopeTypeWasmExpressionStack
 )
 
 var _scopeTypeEnums = map[ScopeTypeEnum]string{
-	ScopeTypeGlobal:  "global",
-	ScopeTypeLocal:   "local",
-	ScopeTypeWith:    "with",
-	ScopeTypeClosure: "closure",
-	ScopeTypeCatch:   "catch",
-	ScopeTypeBlock:   "block",
-	ScopeTypeScript:  "script",
-	ScopeTypeEval:    "eval",
-	ScopeTypeModule:  "module",
+	ScopeTypeGlobal:              "global",
+	ScopeTypeLocal:               "local",
+	ScopeTypeWith:                "with",
+	ScopeTypeClosure:             "closure",
+	ScopeTypeCatch:               "catch",
+	ScopeTypeBlock:               "block",
+	ScopeTypeScript:              "script",
+	ScopeTypeEval:                "eval",
+	ScopeTypeModule:              "module",
+	ScopeTypeWasmExpressionStack: "wasm-expression-stack",
 }
@@ -150,4 +150,17 @@ func TestEnumScopeType3(t *testing.T) {
 	if ScopeType.Module != enum {
 		t.Errorf("Expcected %d, got %d", ScopeType.Module, enum)
 	}
+
+	enum = ScopeType.WasmExpressionStack
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"wasm-expression-stack"` != string(result) {
+		t.Errorf("Expected '\"wasm-expression-stack\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"wasm-expression-stack"`), &enum)
+	if ScopeType.WasmExpressionStack != enum {
+		t.Errorf("Expcected %d, got %d", ScopeType.WasmExpressionStack, enum)
+	}
 }
@@ -1,6 +1,10 @@
 package debugger
 
-import "github.com/mkenney/go-chrome/tot/runtime"
+import (
+	"encoding/json"
+
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
 
 /*
 BreakpointResolvedEvent represents Debugger.breakpointResolved event data.
@@ -27,15 +31,28 @@ type PausedEvent struct {
 	// Call stack the virtual machine stopped on.
 	CallFrames []*CallFrame `json:"callFrames"`
 
-	// Pause reason. Allowed values: XHR, DOM, EventListener, exception, assert,
-	// debugCommand, promiseRejection, OOM, other, ambiguous.
-	Reason string `json:"reason"`
-
-	// Optional. Object containing break-specific auxiliary properties.
-	Data map[string]string `json:"data,omitempty"`
+	// Pause reason. Allowed values:
+	//	- Reason.XHR
+	//	- Reason.DOM
+	//	- Reason.EventListener
+	//	- Reason.Exception
+	//	- Reason.Assert
+	//	- Reason.DebugCommand
+	//	- Reason.PromiseRejection
+	//	- Reason.OOM
+	//	- Reason.Other
+	//	- Reason.Ambiguous
+	//	- Reason.Instrumentation
+	//	- Reason.CSPViolation
+	//	- Reason.Step
+	Reason ReasonEnum `json:"reason"`
+
+	// Optional. Object containing break-specific auxiliary properties, e.g.
+	// the exception object of exception pauses.
+	Data json.RawMessage `json:"data,omitempty"`
 
 	// Optional. Hit breakpoints IDs.
-	HitBreakpoints []string `json:"hitBreakpoints,omitempty"`
+	HitBreakpoints []BreakpointID `json:"hitBreakpoints,omitempty"`
 
 	// Optional. Async stack trace, if any.
 	AsyncStackTrace *runtime.StackTrace `json:"asyncStackTrace,omitempty"`
@@ -94,7 +111,7 @@ type ScriptFailedToParseEvent struct {
 	Hash string `json:"hash"`
 
 	// Optional. Embedder-specific auxiliary data.
-	ExecutionContextAuxData map[string]string `json:"executionContextAuxData,omitempty"`
+	ExecutionContextAuxData map[string]interface{} `json:"executionContextAuxData,omitempty"`
 
 	// Optional. URL of source map associated with script (if any).
 	SourceMapURL string `json:"sourceMapURL,omitempty"`
@@ -148,7 +165,7 @@ type ScriptParsedEvent struct {
 	Hash string `json:"hash"`
 
 	// Optional. Embedder-specific auxiliary data.
-	ExecutionContextAuxData map[string]string `json:"executionContextAuxData,omitempty"`
+	ExecutionContextAuxData map[string]interface{} `json:"executionContextAuxData,omitempty"`
 
 	// Optional. True, if this script is generated as a result of the live edit
 	// operation. EXPERIMENTAL.
@@ -18,13 +18,13 @@ https://chromedevtools.github.io/devtools-protocol/tot/DeviceOrientation/#method
 */
 type SetOverrideParams struct {
 	// Mock alpha.
-	Alpha int64 `json:"alpha"`
+	Alpha float64 `json:"alpha"`
 
 	// Mock beta.
-	Beta int64 `json:"beta"`
+	Beta float64 `json:"beta"`
 
 	// Mock gamma.
-	Gamma int64 `json:"gamma"`
+	Gamma float64 `json:"gamma"`
 }
 
 /*
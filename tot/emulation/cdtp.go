@@ -21,16 +21,3 @@ type ScreenOrientation struct {
 	// Orientation angle.
 	Angle int `json:"angle"`
 }
-
-/*
-VirtualTimePolicy is the time policy
-	- advance: If the scheduler runs out of immediate work, the virtual time base may fast forward
-	  to allow the next delayed task (if any) to run
-	- pause: The virtual time base may not advance
-	- pauseIfNetworkFetchesPending: The virtual time base may not advance if there are any pending
-	  resource fetches.
-EXPERIMENTAL
-
-https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#type-VirtualTimePolicy
-*/
-type VirtualTimePolicy string
@@ -57,7 +57,7 @@ https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setCPUT
 */
 type SetCPUThrottlingRateParams struct {
 	// Throttling rate as a slowdown factor (1 is no throttle, 2 is 2x slowdown, etc).
-	Rate int64 `json:"rate"`
+	Rate float64 `json:"rate"`
 }
 
 /*
@@ -211,13 +211,13 @@ https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setGeol
 */
 type SetGeolocationOverrideParams struct {
 	// Optional. Mock latitude.
-	Latitude int `json:"latitude,omitempty"`
+	Latitude float64 `json:"latitude,omitempty"`
 
 	// Optional. Mock longitude.
-	Longitude int `json:"longitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
 
 	// Optional. Mock accuracy.
-	Accuracy int `json:"accuracy,omitempty"`
+	Accuracy float64 `json:"accuracy,omitempty"`
 }
 
 /*
@@ -230,6 +230,27 @@ type SetGeolocationOverrideResult struct {
 	Err error `json:"-"`
 }
 
+/*
+SetLocaleOverrideParams represents Emulation.setLocaleOverride parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setLocaleOverride
+*/
+type SetLocaleOverrideParams struct {
+	// Optional. ICU style C locale (e.g. "en_US"). If not specified or empty,
+	// disables the override and restores default host system locale.
+	Locale string `json:"locale,omitempty"`
+}
+
+/*
+SetLocaleOverrideResult represents the result of calls to Emulation.setLocaleOverride.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setLocaleOverride
+*/
+type SetLocaleOverrideResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 SetNavigatorOverridesParams represents Emulation.setNavigatorOverrides parameters.
 
@@ -291,6 +312,27 @@ type SetScriptExecutionDisabledResult struct {
 	Err error `json:"-"`
 }
 
+/*
+SetTimezoneOverrideParams represents Emulation.setTimezoneOverride parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setTimezoneOverride
+*/
+type SetTimezoneOverrideParams struct {
+	// The timezone identifier. If empty, disables the override and restores
+	// default host system timezone.
+	TimezoneID string `json:"timezoneId"`
+}
+
+/*
+SetTimezoneOverrideResult represents the result of calls to Emulation.setTimezoneOverride.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setTimezoneOverride
+*/
+type SetTimezoneOverrideResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 SetTouchEmulationEnabledParams represents Emulation.setTouchEmulationEnabled parameters.
 
@@ -315,18 +357,47 @@ type SetTouchEmulationEnabledResult struct {
 	Err error `json:"-"`
 }
 
+/*
+SetUserAgentOverrideParams represents Emulation.setUserAgentOverride parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setUserAgentOverride
+*/
+type SetUserAgentOverrideParams struct {
+	// User agent to use.
+	UserAgent string `json:"userAgent"`
+
+	// Optional. Browser language to emulate.
+	AcceptLanguage string `json:"acceptLanguage,omitempty"`
+
+	// Optional. The platform navigator.platform should return.
+	Platform string `json:"platform,omitempty"`
+}
+
+/*
+SetUserAgentOverrideResult represents the result of calls to Emulation.setUserAgentOverride.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setUserAgentOverride
+*/
+type SetUserAgentOverrideResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 SetVirtualTimePolicyParams represents Emulation.setVirtualTimePolicy parameters.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setVirtualTimePolicy
 */
 type SetVirtualTimePolicyParams struct {
-	// desc.
-	Policy VirtualTimePolicy `json:"policy"`
+	// The virtual time policy. Allowed values:
+	//	- VirtualTimePolicy.Advance
+	//	- VirtualTimePolicy.Pause
+	//	- VirtualTimePolicy.PauseIfNetworkFetchesPending
+	Policy VirtualTimePolicyEnum `json:"policy"`
 
 	// Optional. If set, after this many virtual milliseconds have elapsed
 	// virtual time will be paused and a virtualTimeBudgetExpired event is sent.
-	Budget int `json:"budget,omitempty"`
+	Budget float64 `json:"budget,omitempty"`
 
 	// Optional. If set this specifies the maximum number of tasks that can be
 	// run before virtual is forced forwards to prevent deadlock.
@@ -0,0 +1,87 @@
+package emulation
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type virtualTimePolicyEnum struct {
+	Advance                      VirtualTimePolicyEnum
+	Pause                        VirtualTimePolicyEnum
+	PauseIfNetworkFetchesPending VirtualTimePolicyEnum
+}
+
+/*
+VirtualTimePolicy provides named acces to the VirtualTimePolicyEnum values.
+*/
+var VirtualTimePolicy = virtualTimePolicyEnum{
+	Advance:                      virtualTimePolicyAdvance,
+	Pause:                        virtualTimePolicyPause,
+	PauseIfNetworkFetchesPending: virtualTimePolicyPauseIfNetworkFetchesPending,
+}
+
+/*
+VirtualTimePolicyEnum represents the virtual time policy. Allowed values:
+	- VirtualTimePolicy.Advance                      "advance"
+	- VirtualTimePolicy.Pause                        "pause"
+	- VirtualTimePolicy.PauseIfNetworkFetchesPending "pauseIfNetworkFetchesPending"
+
+advance: If the scheduler runs out of immediate work, the virtual time base may
+fast forward to allow the next delayed task (if any) to run. pause: The virtual
+time base may not advance. pauseIfNetworkFetchesPending: The virtual time base
+may not advance if there are any pending resource fetches. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#type-VirtualTimePolicy
+*/
+type VirtualTimePolicyEnum int
+
+/*
+String implements Stringer
+*/
+func (enum VirtualTimePolicyEnum) String() string {
+	return _virtualTimePolicyEnums[enum]
+}
+
+/*
+MarshalJSON implements json.Marshaler
+*/
+func (enum VirtualTimePolicyEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(enum.String())
+}
+
+/*
+UnmarshalJSON implements json.Unmarshaler
+*/
+func (enum *VirtualTimePolicyEnum) UnmarshalJSON(bytes []byte) error {
+	var err error
+	var val string
+
+	err = json.Unmarshal(bytes, &val)
+	if nil != err {
+		return err
+	}
+
+	for k, v := range _virtualTimePolicyEnums {
+		if v == val {
+			*enum = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a valid virtualTimePolicy value", bytes)
+}
+
+const (
+	// virtualTimePolicyAdvance represents the "advance" value.
+	virtualTimePolicyAdvance VirtualTimePolicyEnum = iota + 1
+	// virtualTimePolicyPause represents the "pause" value.
+	virtualTimePolicyPause
+	// virtualTimePolicyPauseIfNetworkFetchesPending represents the "pauseIfNetworkFetchesPending" value.
+	virtualTimePolicyPauseIfNetworkFetchesPending
+)
+
+var _virtualTimePolicyEnums = map[VirtualTimePolicyEnum]string{
+	virtualTimePolicyAdvance:                      "advance",
+	virtualTimePolicyPause:                        "pause",
+	virtualTimePolicyPauseIfNetworkFetchesPending: "pauseIfNetworkFetchesPending",
+}
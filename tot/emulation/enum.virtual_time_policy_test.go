@@ -0,0 +1,64 @@
+package emulation
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumVirtualTimePolicy(t *testing.T) {
+	var enum VirtualTimePolicyEnum
+	var err error
+	var result []byte
+
+	err = json.Unmarshal([]byte(`""`), &enum)
+	if nil == err {
+		t.Errorf("Expected error, got nil")
+	}
+
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `""` != string(result) {
+		t.Errorf("Expected empty JSON string, got '%s'", result)
+	}
+
+	enum = VirtualTimePolicy.Advance
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"advance"` != string(result) {
+		t.Errorf("Expected '\"advance\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"advance"`), &enum)
+	if VirtualTimePolicy.Advance != enum {
+		t.Errorf("Expcected %d, got %d", VirtualTimePolicy.Advance, enum)
+	}
+
+	enum = VirtualTimePolicy.Pause
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"pause"` != string(result) {
+		t.Errorf("Expected '\"pause\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"pause"`), &enum)
+	if VirtualTimePolicy.Pause != enum {
+		t.Errorf("Expcected %d, got %d", VirtualTimePolicy.Pause, enum)
+	}
+
+	enum = VirtualTimePolicy.PauseIfNetworkFetchesPending
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"pauseIfNetworkFetchesPending"` != string(result) {
+		t.Errorf("Expected '\"pauseIfNetworkFetchesPending\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"pauseIfNetworkFetchesPending"`), &enum)
+	if VirtualTimePolicy.PauseIfNetworkFetchesPending != enum {
+		t.Errorf("Expcected %d, got %d", VirtualTimePolicy.PauseIfNetworkFetchesPending, enum)
+	}
+}
@@ -8,7 +8,7 @@ https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#event-virtualT
 type VirtualTimeAdvancedEvent struct {
 	// The amount of virtual time that has elapsed in milliseconds since virtual
 	// time was first enabled.
-	VirtualTimeElapsed int `json:"virtualTimeElapsed"`
+	VirtualTimeElapsed float64 `json:"virtualTimeElapsed"`
 
 	// Error information related to this event
 	Err error `json:"-"`
@@ -32,7 +32,7 @@ https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#event-virtualT
 type VirtualTimePausedEvent struct {
 	// The amount of virtual time that has elapsed in milliseconds since virtual
 	// time was first enabled.
-	VirtualTimeElapsed int `json:"virtualTimeElapsed"`
+	VirtualTimeElapsed float64 `json:"virtualTimeElapsed"`
 
 	// Error information related to this event
 	Err error `json:"-"`
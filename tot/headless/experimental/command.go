@@ -1,7 +1,5 @@
 package experimental
 
-import "github.com/mkenney/go-chrome/tot/runtime"
-
 /*
 BeginFrameParams represents HeadlessExperimental.beginFrame parameters.
 
@@ -10,11 +8,11 @@ https://chromedevtools.github.io/devtools-protocol/tot/HeadlessExperimental/#met
 type BeginFrameParams struct {
 	// Optional. Timestamp of this BeginFrame (milliseconds since epoch). If not
 	// set, the current time will be used.
-	FrameTime runtime.Timestamp `json:"frameTime,omitempty"`
+	FrameTime float64 `json:"frameTime,omitempty"`
 
 	// Optional. Deadline of this BeginFrame (milliseconds since epoch). If not
 	// set, the deadline will be calculated from the frameTime and interval.
-	Deadline runtime.Timestamp `json:"deadline,omitempty"`
+	Deadline float64 `json:"deadline,omitempty"`
 
 	// Optional. The interval between BeginFrames that is reported to the
 	// compositor, in milliseconds. Defaults to a 60 frames/second interval,
@@ -110,6 +110,11 @@ type DispatchMouseEventParams struct {
 	//	- ButtonEvent.Right
 	Button ButtonEventEnum `json:"button,omitempty"`
 
+	// Optional. A number indicating which buttons are pressed on the mouse
+	// when a mouse event is triggered. Left=1, Right=2, Middle=4, Back=8,
+	// Forward=16, None=0.
+	Buttons int `json:"buttons,omitempty"`
+
 	// Optional. Number of times the mouse button was clicked (default: 0).
 	ClickCount int `json:"clickCount,omitempty"`
 
@@ -222,6 +227,26 @@ type EmulateTouchFromMouseEventResult struct {
 	Err error `json:"-"`
 }
 
+/*
+InsertTextParams represents Input.insertText parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Input/#method-insertText
+*/
+type InsertTextParams struct {
+	// The text to insert.
+	Text string `json:"text"`
+}
+
+/*
+InsertTextResult represents the result of calls to Input.insertText.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Input/#method-insertText
+*/
+type InsertTextResult struct {
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 SetIgnoreEventsParams represents Input.setIgnoreInputEvents parameters.
 
@@ -255,7 +280,7 @@ type SynthesizePinchGestureParams struct {
 	Y float64 `json:"y"`
 
 	// Relative scale factor after zooming (>1.0 zooms in, <1.0 zooms out).
-	ScaleFactor int `json:"scaleFactor"`
+	ScaleFactor float64 `json:"scaleFactor"`
 
 	// Optional. Relative pointer speed in pixels per second (default: 800).
 	RelativeSpeed int `json:"relativeSpeed,omitempty"`
@@ -1,22 +1,20 @@
 package memory
 
-/*
-GetDOMCountersParams represents Memory.getDOMCounters parameters.
-
-https://chromedevtools.github.io/devtools-protocol/tot/Memory/#method-getDOMCounters
-*/
-type GetDOMCountersParams struct {
-	Documents        int `json:"documents"`
-	Nodes            int `json:"nodes"`
-	JsEventListeners int `json:"jsEventListeners"`
-}
-
 /*
 GetDOMCountersResult represents the result of calls to Memory.getDOMCounters.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Memory/#method-getDOMCounters
 */
 type GetDOMCountersResult struct {
+	// The number of documents.
+	Documents int `json:"documents"`
+
+	// The number of DOM nodes.
+	Nodes int `json:"nodes"`
+
+	// The number of JavaScript event listeners.
+	JSEventListeners int `json:"jsEventListeners"`
+
 	// Error information related to executing this method
 	Err error `json:"-"`
 }
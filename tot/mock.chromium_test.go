@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"testing"
 
 	errs "github.com/bdlm/errors"
 	"github.com/bdlm/log"
@@ -330,3 +331,21 @@ func (chrome *MockChrome) NewTab(uri string) (*Tab, error) {
 
 	return tab, nil
 }
+
+/*
+newMockTab returns a tab backed by a MockSocket for testing tab helpers.
+*/
+func newMockTab(t *testing.T, uri string) (*Tab, *MockSocket) {
+	browser := NewMock(
+		&Flags{},
+		"", //"path/to/chrome",
+		"", //"path/to/stderr",
+		"", //"path/to/stdout",
+		"", //"path/to/workdir",
+	)
+	tab, err := browser.NewTab(uri)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	return tab, tab.Socket().(*MockSocket)
+}
@@ -1,15 +1,24 @@
 package chrome
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"math/rand"
 	"net/url"
+	"sync"
+	"time"
 
+	cdtpio "github.com/mkenney/go-chrome/tot/io"
 	"github.com/mkenney/go-chrome/tot/socket"
 )
 
 func NewMockSocket(url *url.URL) *MockSocket {
 	mockSocket := &MockSocket{
-		url:   url,
-		errCh: make(chan error, 3),
+		url:         url,
+		errCh:       make(chan error, 3),
+		eventCounts: map[string]int{},
+		handlers:    map[string][]socket.EventHandler{},
+		responders:  map[string]MockResponder{},
 	}
 
 	mockSocket.accessibility = &socket.AccessibilityProtocol{Socket: mockSocket}
@@ -54,6 +63,47 @@ func NewMockSocket(url *url.URL) *MockSocket {
 	return mockSocket
 }
 
+/*
+MockResponder generates the result of a mock command. A non-nil error is
+returned to the caller as a socket error.
+*/
+type MockResponder func(params interface{}) (interface{}, *socket.Error)
+
+/*
+mockEvent builds an event response.
+*/
+func mockEvent(name string, params interface{}) *socket.Response {
+	data, _ := json.Marshal(params)
+	return &socket.Response{
+		Method: name,
+		Params: data,
+	}
+}
+
+/*
+mockResponse builds a command response using the provided responder.
+*/
+func mockResponse(command socket.Commander, responder MockResponder) *socket.Response {
+	response := &socket.Response{
+		ID:     command.ID(),
+		Result: []byte(`{}`),
+	}
+	if nil == responder {
+		return response
+	}
+	result, err := responder(command.Params())
+	if nil != err {
+		response.Error = err
+		return response
+	}
+	// Chrome responds with an empty object to commands without a result.
+	if nil == result {
+		return response
+	}
+	response.Result, _ = json.Marshal(result)
+	return response
+}
+
 /*
 Socket is a Socketer implementation.
 */
@@ -62,6 +112,29 @@ type MockSocket struct {
 	commandID int
 	errCh     chan error
 
+	// commands is a list of every command sent to the socket.
+	commands []socket.Commander
+
+	// handlers is a map of registered event handlers by event name.
+	handlers map[string][]socket.EventHandler
+
+	// responders is a map of command response generators by method name.
+	responders map[string]MockResponder
+
+	// eventSeq is the sequence number of the last numbered event.
+	eventSeq int
+
+	// eventCounts is the number of numbered events of each method.
+	eventCounts map[string]int
+
+	// concurrent delivers events to handlers in goroutines, like the socket.
+	concurrent bool
+
+	// handling tracks the handlers running in goroutines.
+	handling sync.WaitGroup
+
+	mux sync.Mutex
+
 	// Protocol interfaces for the API.
 	accessibility        *socket.AccessibilityProtocol
 	animation            *socket.AnimationProtocol
@@ -109,6 +182,48 @@ AddEventHandler is a Socketer implementation.
 func (socket *MockSocket) AddEventHandler(
 	handler socket.EventHandler,
 ) {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	socket.handlers[handler.Name()] = append(socket.handlers[handler.Name()], handler)
+}
+
+/*
+Commands returns the commands sent to the socket for the specified method, or
+all commands if method is empty.
+*/
+func (socket *MockSocket) Commands(method string) []socket.Commander {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	commands := socket.commands[:0:0]
+	for _, command := range socket.commands {
+		if "" == method || method == command.Method() {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+/*
+Concurrent delivers events to each handler in a goroutine after a random delay,
+like the socket that runs handlers concurrently, so handlers may run in any
+order. Use Wait to wait for the handlers to return.
+*/
+func (socket *MockSocket) Concurrent() {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	socket.concurrent = true
+}
+
+/*
+countEvents returns a copy of the number of numbered events of each method. The
+caller must hold the lock.
+*/
+func (socket *MockSocket) countEvents() map[string]int {
+	counts := make(map[string]int, len(socket.eventCounts))
+	for method, count := range socket.eventCounts {
+		counts[method] = count
+	}
+	return counts
 }
 
 /*
@@ -123,6 +238,47 @@ func (socket *MockSocket) Errors() chan error {
 	return socket.errCh
 }
 
+/*
+Event returns an event numbered as if it was read from the socket, without
+delivering it. Command responses count the events numbered before them.
+*/
+func (socket *MockSocket) Event(name string, params interface{}) *socket.Response {
+	event := mockEvent(name, params)
+	socket.number(event)
+	return event
+}
+
+/*
+Fire numbers an event and delivers it to all handlers registered for it.
+*/
+func (socket *MockSocket) Fire(name string, params interface{}) {
+	socket.FireEvent(socket.Event(name, params))
+}
+
+/*
+FireEvent delivers an event response to all handlers registered for it. The
+event is not numbered.
+*/
+func (socket *MockSocket) FireEvent(event *socket.Response) {
+	socket.mux.Lock()
+	handlers := append(socket.handlers[event.Method][:0:0], socket.handlers[event.Method]...)
+	concurrent := socket.concurrent
+	socket.mux.Unlock()
+	for _, handler := range handlers {
+		if !concurrent {
+			handler.Handle(event)
+			continue
+		}
+		handler := handler
+		socket.handling.Add(1)
+		go func() {
+			defer socket.handling.Done()
+			time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+			handler.Handle(event)
+		}()
+	}
+}
+
 /*
 Listen starts the socket read loop and delivers messages to handleResponse() and
 handleEvent() as appropriate.
@@ -132,6 +288,18 @@ Listen is a Socketer implementation.
 func (socket *MockSocket) Listen() {
 }
 
+/*
+number numbers an event as the next event read from the socket.
+*/
+func (socket *MockSocket) number(event *socket.Response) {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	socket.eventSeq++
+	socket.eventCounts[event.Method]++
+	event.Sequence = socket.eventSeq
+	event.EventCounts = socket.countEvents()
+}
+
 /*
 NextCommandID generates and returns the next command ID.
 */
@@ -146,13 +314,85 @@ RemoveEventHandler is a Socketer implementation.
 func (socket *MockSocket) RemoveEventHandler(
 	handler socket.EventHandler,
 ) error {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	handlers := socket.handlers[handler.Name()]
+	for k, h := range handlers {
+		if h == handler {
+			socket.handlers[handler.Name()] = append(handlers[:k], handlers[k+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
+/*
+Respond registers a response generator for a command method. Commands without
+a responder receive an empty result.
+*/
+func (socket *MockSocket) Respond(method string, responder MockResponder) {
+	socket.mux.Lock()
+	defer socket.mux.Unlock()
+	socket.responders[method] = responder
+}
+
+/*
+RespondStream responds to IO.read with the given chunks of a stream, base64
+encoding every other chunk. Reads past the last chunk fail.
+*/
+func (mockSocket *MockSocket) RespondStream(chunks ...string) {
+	read := 0
+	mockSocket.Respond("IO.read", func(params interface{}) (interface{}, *socket.Error) {
+		if read >= len(chunks) {
+			return nil, &socket.Error{Code: 1, Message: "invalid stream"}
+		}
+		result := &cdtpio.ReadResult{
+			Data: chunks[read],
+			EOF:  read == len(chunks)-1,
+		}
+		if 1 == read%2 {
+			result.Base64Encoded = true
+			result.Data = base64.StdEncoding.EncodeToString([]byte(chunks[read]))
+		}
+		read++
+		return result, nil
+	})
+}
+
+/*
+RespondEvents responds to a command with an empty result after the given
+events, e.g. the data events of a command that reports its result with events.
+The events are numbered in the order they are given and delivered in reverse
+order, handlers must restore the order from the event numbers.
+*/
+func (mockSocket *MockSocket) RespondEvents(method string, events ...*socket.Response) {
+	mockSocket.Respond(method, func(params interface{}) (interface{}, *socket.Error) {
+		numbered := make([]*socket.Response, len(events))
+		for a, event := range events {
+			numberedEvent := *event
+			mockSocket.number(&numberedEvent)
+			numbered[a] = &numberedEvent
+		}
+		for a := len(numbered) - 1; a >= 0; a-- {
+			mockSocket.FireEvent(numbered[a])
+		}
+		return nil, nil
+	})
+}
+
 /*
 SendCommand is a Socketer implementation.
 */
 func (socket *MockSocket) SendCommand(command socket.Commander) chan *socket.Response {
+	socket.mux.Lock()
+	socket.commands = append(socket.commands, command)
+	responder := socket.responders[command.Method()]
+	socket.mux.Unlock()
+	response := mockResponse(command, responder)
+	socket.mux.Lock()
+	response.EventCounts = socket.countEvents()
+	socket.mux.Unlock()
+	go command.Respond(response)
 	return command.Response()
 }
 
@@ -162,6 +402,13 @@ Stop is a Socketer implementation.
 func (socket *MockSocket) Stop() {
 }
 
+/*
+Wait waits for the handlers running in goroutines to return.
+*/
+func (socket *MockSocket) Wait() {
+	socket.handling.Wait()
+}
+
 /*
 URL returns the URL of the websocket connection.
 */
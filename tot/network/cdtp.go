@@ -6,6 +6,7 @@ https://chromedevtools.github.io/devtools-protocol/tot/Network/
 package network
 
 import (
+	"github.com/mkenney/go-chrome/tot/io"
 	"github.com/mkenney/go-chrome/tot/page"
 	"github.com/mkenney/go-chrome/tot/runtime"
 	"github.com/mkenney/go-chrome/tot/security"
@@ -37,14 +38,14 @@ TimeSinceEpoch represents UTC time in seconds, counted from January 1, 1970.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-TimeSinceEpoch
 */
-type TimeSinceEpoch int64
+type TimeSinceEpoch float64
 
 /*
 MonotonicTime is the monotonically increasing time in seconds since an arbitrary point in the past.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-MonotonicTime
 */
-type MonotonicTime int
+type MonotonicTime float64
 
 /*
 Headers contains request / response headers as keys / values of JSON object.
@@ -61,52 +62,52 @@ https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-ResourceTim
 type ResourceTiming struct {
 	// Timing's requestTime is a baseline in seconds, while the other numbers
 	// are ticks in milliseconds relatively to this requestTime.
-	RequestTime int `json:"requestTime"`
+	RequestTime float64 `json:"requestTime"`
 
 	// Started resolving proxy.
-	ProxyStart int `json:"proxyStart"`
+	ProxyStart float64 `json:"proxyStart"`
 
 	// Finished resolving proxy.
-	ProxyEnd int `json:"proxyEnd"`
+	ProxyEnd float64 `json:"proxyEnd"`
 
 	// Started DNS address resolve.
-	DNSStart int `json:"dnsStart"`
+	DNSStart float64 `json:"dnsStart"`
 
 	// Finished DNS address resolve.
-	DNSEnd int `json:"dnsEnd"`
+	DNSEnd float64 `json:"dnsEnd"`
 
 	// Started connecting to the remote host.
-	ConnectStart int `json:"connectStart"`
+	ConnectStart float64 `json:"connectStart"`
 
 	// Connected to the remote host.
-	ConnectEnd int `json:"connectEnd"`
+	ConnectEnd float64 `json:"connectEnd"`
 
 	// Started SSL handshake.
-	SSLStart int `json:"sslStart"`
+	SSLStart float64 `json:"sslStart"`
 
 	// Finished SSL handshake.
-	SSLEnd int `json:"sslEnd"`
+	SSLEnd float64 `json:"sslEnd"`
 
 	// Started running ServiceWorker. EXPERIMENTAL.
-	WorkerStart int `json:"workerStart"`
+	WorkerStart float64 `json:"workerStart"`
 
 	// Finished Starting ServiceWorker. EXPERIMENTAL.
-	WorkerReady int `json:"workerReady"`
+	WorkerReady float64 `json:"workerReady"`
 
 	// Started sending request.
-	SendStart int `json:"sendStart"`
+	SendStart float64 `json:"sendStart"`
 
 	// Finished sending request.
-	SendEnd int `json:"sendEnd"`
+	SendEnd float64 `json:"sendEnd"`
 
 	// Time the server started pushing request. EXPERIMENTAL.
-	PushStart int `json:"pushStart"`
+	PushStart float64 `json:"pushStart"`
 
 	// Time the server finished pushing request. EXPERIMENTAL.
-	PushEnd int `json:"pushEnd"`
+	PushEnd float64 `json:"pushEnd"`
 
 	// Finished receiving response headers.
-	ReceiveHeadersEnd int `json:"receiveHeadersEnd"`
+	ReceiveHeadersEnd float64 `json:"receiveHeadersEnd"`
 }
 
 /*
@@ -283,7 +284,7 @@ type Response struct {
 	FromServiceWorker bool `json:"fromServiceWorker,omitempty"`
 
 	// Total number of bytes received for this request so far.
-	EncodedDataLength int `json:"encodedDataLength"`
+	EncodedDataLength float64 `json:"encodedDataLength"`
 
 	// Optional. Timing information for the given request.
 	Timing *ResourceTiming `json:"timing,omitempty"`
@@ -496,3 +497,44 @@ type RequestPattern struct {
 	//	- InterceptionStage.HeadersReceived
 	InterceptionStage InterceptionStageEnum `json:"interceptionStage,omitempty"`
 }
+
+/*
+LoadNetworkResourcePageResult is an object providing the result of a network
+resource load. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-LoadNetworkResourcePageResult
+*/
+type LoadNetworkResourcePageResult struct {
+	// Whether the resource was loaded.
+	Success bool `json:"success"`
+
+	// Optional. The network error code if the load failed.
+	NetError int `json:"netError,omitempty"`
+
+	// Optional. The name of the network error if the load failed.
+	NetErrorName string `json:"netErrorName,omitempty"`
+
+	// Optional. The HTTP status code of the response.
+	HTTPStatusCode int `json:"httpStatusCode,omitempty"`
+
+	// Optional. If successful, one of the following two fields holds the
+	// result. A stream handle to read the resource with IO.read.
+	Stream io.StreamHandle `json:"stream,omitempty"`
+
+	// Optional. Response headers.
+	Headers Headers `json:"headers,omitempty"`
+}
+
+/*
+LoadNetworkResourceOptions are the options for the request of a network
+resource load. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-LoadNetworkResourceOptions
+*/
+type LoadNetworkResourceOptions struct {
+	// Whether the request ignores the cache.
+	DisableCache bool `json:"disableCache"`
+
+	// Whether the request includes the credentials of the frame.
+	IncludeCredentials bool `json:"includeCredentials"`
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/page"
 )
 
 /*
@@ -347,6 +348,37 @@ type GetResponseBodyForInterceptionResult struct {
 	Err error `json:"-"`
 }
 
+/*
+LoadNetworkResourceParams represents Network.loadNetworkResource parameters.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Network/#method-loadNetworkResource
+*/
+type LoadNetworkResourceParams struct {
+	// Optional. Frame ID for frame requests. Required for requests of frames
+	// that aren't the main frame.
+	FrameID page.FrameID `json:"frameId,omitempty"`
+
+	// URL of the resource to load.
+	URL string `json:"url"`
+
+	// Options for the request.
+	Options *LoadNetworkResourceOptions `json:"options"`
+}
+
+/*
+LoadNetworkResourceResult represents the result of calls to
+Network.loadNetworkResource.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Network/#method-loadNetworkResource
+*/
+type LoadNetworkResourceResult struct {
+	// The result of the load.
+	Resource *LoadNetworkResourcePageResult `json:"resource"`
+
+	// Error information related to executing this method
+	Err error `json:"-"`
+}
+
 /*
 ReplayXHRParams represents Network.replayXHR parameters.
 
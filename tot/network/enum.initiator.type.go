@@ -6,28 +6,37 @@ import (
 )
 
 type initiatorTypeEnum struct {
-	Parser  InitiatorTypeEnum
-	Script  InitiatorTypeEnum
-	Preload InitiatorTypeEnum
-	Other   InitiatorTypeEnum
+	Parser         InitiatorTypeEnum
+	Script         InitiatorTypeEnum
+	Preload        InitiatorTypeEnum
+	Other          InitiatorTypeEnum
+	SignedExchange InitiatorTypeEnum
+	Preflight      InitiatorTypeEnum
+	FedCM          InitiatorTypeEnum
 }
 
 /*
 InitiatorType provides named acces to the InitiatorTypeEnum values.
 */
 var InitiatorType = initiatorTypeEnum{
-	Parser:  initiatorTypeParser,
-	Script:  initiatorTypeScript,
-	Preload: initiatorTypePreload,
-	Other:   initiatorTypeOther,
+	Parser:         initiatorTypeParser,
+	Script:         initiatorTypeScript,
+	Preload:        initiatorTypePreload,
+	Other:          initiatorTypeOther,
+	SignedExchange: initiatorTypeSignedExchange,
+	Preflight:      initiatorTypePreflight,
+	FedCM:          initiatorTypeFedCM,
 }
 
 /*
 InitiatorTypeEnum is the type of this initiator. Allowed values:
-	- InitiatorType.Parser  "parser"
-	- InitiatorType.Script  "script"
-	- InitiatorType.Preload "preload"
-	- InitiatorType.Other   "other"
+	- InitiatorType.Parser         "parser"
+	- InitiatorType.Script         "script"
+	- InitiatorType.Preload        "preload"
+	- InitiatorType.Other          "other"
+	- InitiatorType.SignedExchange "SignedExchange"
+	- InitiatorType.Preflight      "preflight"
+	- InitiatorType.FedCM          "FedCM"
 
 https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-InitiatorType
 */
@@ -78,11 +87,20 @@ const (
 	initiatorTypePreload
 	// initiatorTypeOther represents the "other" value.
 	initiatorTypeOther
+	// initiatorTypeSignedExchange represents the "SignedExchange" value.
+	initiatorTypeSignedExchange
+	// initiatorTypePreflight represents the "preflight" value.
+	initiatorTypePreflight
+	// initiatorTypeFedCM represents the "FedCM" value.
+	initiatorTypeFedCM
 )
 
 var _initiatorTypeEnums = map[InitiatorTypeEnum]string{
-	initiatorTypeParser:  "parser",
-	initiatorTypeScript:  "script",
-	initiatorTypePreload: "preload",
-	initiatorTypeOther:   "other",
+	initiatorTypeParser:         "parser",
+	initiatorTypeScript:         "script",
+	initiatorTypePreload:        "preload",
+	initiatorTypeOther:          "other",
+	initiatorTypeSignedExchange: "SignedExchange",
+	initiatorTypePreflight:      "preflight",
+	initiatorTypeFedCM:          "FedCM",
 }
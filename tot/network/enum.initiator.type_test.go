@@ -80,4 +80,43 @@ func TestEnumInitiatorType2(t *testing.T) {
 	if InitiatorType.Other != enum {
 		t.Errorf("Expcected %d, got %d", InitiatorType.Other, enum)
 	}
+
+	enum = InitiatorType.SignedExchange
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"SignedExchange"` != string(result) {
+		t.Errorf("Expected '\"SignedExchange\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"SignedExchange"`), &enum)
+	if InitiatorType.SignedExchange != enum {
+		t.Errorf("Expcected %d, got %d", InitiatorType.SignedExchange, enum)
+	}
+
+	enum = InitiatorType.Preflight
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"preflight"` != string(result) {
+		t.Errorf("Expected '\"preflight\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"preflight"`), &enum)
+	if InitiatorType.Preflight != enum {
+		t.Errorf("Expcected %d, got %d", InitiatorType.Preflight, enum)
+	}
+
+	enum = InitiatorType.FedCM
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"FedCM"` != string(result) {
+		t.Errorf("Expected '\"FedCM\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"FedCM"`), &enum)
+	if InitiatorType.FedCM != enum {
+		t.Errorf("Expcected %d, got %d", InitiatorType.FedCM, enum)
+	}
 }
@@ -47,7 +47,7 @@ This is a duplicate of DOM.Rect to avoid an invalid import cycle
 
 https://chromedevtools.github.io/devtools-protocol/tot/Network/#type-TimeSinceEpoch
 */
-type TimeSinceEpoch int
+type TimeSinceEpoch float64
 
 /*
 AppManifestError defines an error that occurs while parsing an app manifest.
@@ -185,16 +185,16 @@ https://chromedevtools.github.io/devtools-protocol/tot/Page/#type-LayoutViewport
 */
 type LayoutViewport struct {
 	// Horizontal offset relative to the document (CSS pixels).
-	PageX int `json:"pageX"`
+	PageX float64 `json:"pageX"`
 
 	// Vertical offset relative to the document (CSS pixels).
-	PageY int `json:"pageY"`
+	PageY float64 `json:"pageY"`
 
 	// Width (CSS pixels), excludes scrollbar if present.
-	ClientWidth int `json:"clientWidth"`
+	ClientWidth float64 `json:"clientWidth"`
 
 	// Height (CSS pixels), excludes scrollbar if present.
-	ClientHeight int `json:"clientHeight"`
+	ClientHeight float64 `json:"clientHeight"`
 }
 
 /*
@@ -226,22 +226,22 @@ https://chromedevtools.github.io/devtools-protocol/tot/Page/#type-ScreencastFram
 */
 type ScreencastFrameMetadata struct {
 	// Top offset in DIP.
-	OffsetTop int `json:"offsetTop"`
+	OffsetTop float64 `json:"offsetTop"`
 
 	// Page scale factor.
-	PageScaleFactor int `json:"pageScaleFactor"`
+	PageScaleFactor float64 `json:"pageScaleFactor"`
 
 	// Device screen width in DIP.
-	DeviceWidth int `json:"deviceWidth"`
+	DeviceWidth float64 `json:"deviceWidth"`
 
 	// Device screen height in DIP.
-	DeviceHeight int `json:"deviceHeight"`
+	DeviceHeight float64 `json:"deviceHeight"`
 
 	// Position of horizontal scroll in CSS pixels.
-	ScrollOffsetX int `json:"scrollOffsetX"`
+	ScrollOffsetX float64 `json:"scrollOffsetX"`
 
 	// Position of vertical scroll in CSS pixels.
-	ScrollOffsetY int `json:"scrollOffsetY"`
+	ScrollOffsetY float64 `json:"scrollOffsetY"`
 
 	// Optional. Frame swap timestamp.
 	Timestamp TimeSinceEpoch `json:"timestamp,omitempty"`
@@ -268,19 +268,19 @@ https://chromedevtools.github.io/devtools-protocol/tot/Page/#type-Viewport
 */
 type Viewport struct {
 	// Required. X offset in CSS pixels.
-	X int `json:"x"`
+	X float64 `json:"x"`
 
 	// Required. Y offset in CSS pixels.
-	Y int `json:"y"`
+	Y float64 `json:"y"`
 
 	// Required. Rectangle width in CSS pixels
-	Width int `json:"width"`
+	Width float64 `json:"width"`
 
 	// Required. Rectangle height in CSS pixels
-	Height int `json:"height"`
+	Height float64 `json:"height"`
 
 	// Required. Page scale factor.
-	Scale int `json:"scale"`
+	Scale float64 `json:"scale"`
 }
 
 /*
@@ -290,23 +290,23 @@ https://chromedevtools.github.io/devtools-protocol/tot/Page/#type-VisualViewport
 */
 type VisualViewport struct {
 	// Horizontal offset relative to the layout viewport (CSS pixels).
-	OffsetX int `json:"offsetX"`
+	OffsetX float64 `json:"offsetX"`
 
 	// Vertical offset relative to the layout viewport (CSS pixels).
-	OffsetY int `json:"offsetY"`
+	OffsetY float64 `json:"offsetY"`
 
 	// Horizontal offset relative to the document (CSS pixels).
-	PageX int `json:"pageX"`
+	PageX float64 `json:"pageX"`
 
 	// Vertical offset relative to the document (CSS pixels).
-	PageY int `json:"pageY"`
+	PageY float64 `json:"pageY"`
 
 	// Width (CSS pixels), excludes scrollbar if present.
-	ClientWidth int `json:"clientWidth"`
+	ClientWidth float64 `json:"clientWidth"`
 
 	// Height (CSS pixels), excludes scrollbar if present.
-	ClientHeight int `json:"clientHeight"`
+	ClientHeight float64 `json:"clientHeight"`
 
 	// Scale relative to the ideal viewport (size at width=device-width).
-	Scale int `json:"scale"`
+	Scale float64 `json:"scale"`
 }
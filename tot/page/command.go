@@ -2,6 +2,7 @@ package page
 
 import (
 	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/io"
 	"github.com/mkenney/go-chrome/tot/runtime"
 )
 
@@ -81,6 +82,10 @@ type CaptureScreenshotParams struct {
 	// Optional. Capture the screenshot from the surface, rather than the view.
 	// Defaults to true. EXPERIMENTAL.
 	FromSurface bool `json:"fromSurface,omitempty"`
+
+	// Optional. Capture the screenshot beyond the viewport. Defaults to false.
+	// EXPERIMENTAL.
+	CaptureBeyondViewport bool `json:"captureBeyondViewport,omitempty"`
 }
 
 /*
@@ -200,6 +205,16 @@ type GetLayoutMetricsResult struct {
 	// Size of scrollable area. Rect is a local implementation of DOM.Rect
 	ContentSize *Rect `json:"contentSize"`
 
+	// Metrics relating to the layout viewport in CSS pixels. Newer versions
+	// of Chrome report the layout metrics in device pixels.
+	CSSLayoutViewport *LayoutViewport `json:"cssLayoutViewport"`
+
+	// Metrics relating to the visual viewport in CSS pixels.
+	CSSVisualViewport *VisualViewport `json:"cssVisualViewport"`
+
+	// Size of scrollable area in CSS pixels.
+	CSSContentSize *Rect `json:"cssContentSize"`
+
 	// Error information related to executing this method
 	Err error `json:"-"`
 }
@@ -365,17 +380,21 @@ type PrintToPDFParams struct {
 	// Optional. Paper height in inches. Defaults to 11 inches.
 	PaperHeight float64 `json:"paperHeight,omitempty"`
 
-	// Optional. Top margin in inches. Defaults to 1cm (~0.4 inches).
-	MarginTop float64 `json:"marginTop,omitempty"`
+	// Optional. Top margin in inches. Defaults to 1cm (~0.4 inches). A
+	// pointer so that a zero margin is sent rather than omitted.
+	MarginTop *float64 `json:"marginTop,omitempty"`
 
-	// Optional. Bottom margin in inches. Defaults to 1cm (~0.4 inches).
-	MarginBottom float64 `json:"marginBottom,omitempty"`
+	// Optional. Bottom margin in inches. Defaults to 1cm (~0.4 inches). A
+	// pointer so that a zero margin is sent rather than omitted.
+	MarginBottom *float64 `json:"marginBottom,omitempty"`
 
-	// Optional. Left margin in inches. Defaults to 1cm (~0.4 inches).
-	MarginLeft float64 `json:"marginLeft,omitempty"`
+	// Optional. Left margin in inches. Defaults to 1cm (~0.4 inches). A
+	// pointer so that a zero margin is sent rather than omitted.
+	MarginLeft *float64 `json:"marginLeft,omitempty"`
 
-	// Optional. Right margin in inches. Defaults to 1cm (~0.4 inches).
-	MarginRight float64 `json:"marginRight,omitempty"`
+	// Optional. Right margin in inches. Defaults to 1cm (~0.4 inches). A
+	// pointer so that a zero margin is sent rather than omitted.
+	MarginRight *float64 `json:"marginRight,omitempty"`
 
 	// Optional. Paper ranges to print, e.g., '1-5, 8, 11-13'. Defaults to the
 	// empty string, which means print all pages.
@@ -384,6 +403,29 @@ type PrintToPDFParams struct {
 	// Optional. Whether to silently ignore invalid but successfully parsed page
 	// ranges, such as '3-2'. Defaults to false.
 	IgnoreInvalidPageRanges bool `json:"ignoreInvalidPageRanges,omitempty"`
+
+	// Optional. HTML template for the print header. Should be valid HTML markup
+	// with the following classes used to inject printing values into them:
+	//	- date: formatted print date
+	//	- title: document title
+	//	- url: document location
+	//	- pageNumber: current page number
+	//	- totalPages: total pages in the document
+	// For example, <span class=title></span> would generate a span containing
+	// the title.
+	HeaderTemplate string `json:"headerTemplate,omitempty"`
+
+	// Optional. HTML template for the print footer. Should use the same format
+	// as the HeaderTemplate.
+	FooterTemplate string `json:"footerTemplate,omitempty"`
+
+	// Optional. Whether or not to prefer page size as defined by css. Defaults
+	// to false, in which case the content will be scaled to fit the paper
+	// size.
+	PreferCSSPageSize bool `json:"preferCSSPageSize,omitempty"`
+
+	// Optional. Return as stream. EXPERIMENTAL.
+	TransferMode TransferModeEnum `json:"transferMode,omitempty"`
 }
 
 /*
@@ -392,9 +434,14 @@ PrintToPDFResult represents the result of calls to Page.printToPDF.
 https://chromedevtools.github.io/devtools-protocol/tot/Page/#method-printToPDF
 */
 type PrintToPDFResult struct {
-	// Base64-encoded pdf data.
+	// Base64-encoded pdf data. Empty if TransferMode.ReturnAsStream is
+	// specified.
 	Data string `json:"data"`
 
+	// Optional. A handle of the stream that holds resulting PDF data.
+	// EXPERIMENTAL.
+	Stream io.StreamHandle `json:"stream,omitempty"`
+
 	// Error information related to executing this method
 	Err error `json:"-"`
 }
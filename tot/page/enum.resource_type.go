@@ -6,56 +6,74 @@ import (
 )
 
 type resourceTypeEnum struct {
-	Document    ResourceTypeEnum
-	Stylesheet  ResourceTypeEnum
-	Image       ResourceTypeEnum
-	Media       ResourceTypeEnum
-	Font        ResourceTypeEnum
-	Script      ResourceTypeEnum
-	TextTrack   ResourceTypeEnum
-	XHR         ResourceTypeEnum
-	Fetch       ResourceTypeEnum
-	EventSource ResourceTypeEnum
-	WebSocket   ResourceTypeEnum
-	Manifest    ResourceTypeEnum
-	Other       ResourceTypeEnum
+	Document           ResourceTypeEnum
+	Stylesheet         ResourceTypeEnum
+	Image              ResourceTypeEnum
+	Media              ResourceTypeEnum
+	Font               ResourceTypeEnum
+	Script             ResourceTypeEnum
+	TextTrack          ResourceTypeEnum
+	XHR                ResourceTypeEnum
+	Fetch              ResourceTypeEnum
+	EventSource        ResourceTypeEnum
+	WebSocket          ResourceTypeEnum
+	Manifest           ResourceTypeEnum
+	Other              ResourceTypeEnum
+	Prefetch           ResourceTypeEnum
+	SignedExchange     ResourceTypeEnum
+	Ping               ResourceTypeEnum
+	CSPViolationReport ResourceTypeEnum
+	Preflight          ResourceTypeEnum
+	FedCM              ResourceTypeEnum
 }
 
 /*
 ResourceType provides named acces to the ResourceTypeEnum values.
 */
 var ResourceType = resourceTypeEnum{
-	Document:    resourceTypeDocument,
-	Stylesheet:  resourceTypeStylesheet,
-	Image:       resourceTypeImage,
-	Media:       resourceTypeMedia,
-	Font:        resourceTypeFont,
-	Script:      resourceTypeScript,
-	TextTrack:   resourceTypeTextTrack,
-	XHR:         resourceTypeXHR,
-	Fetch:       resourceTypeFetch,
-	EventSource: resourceTypeEventSource,
-	WebSocket:   resourceTypeWebSocket,
-	Manifest:    resourceTypeManifest,
-	Other:       resourceTypeOther,
+	Document:           resourceTypeDocument,
+	Stylesheet:         resourceTypeStylesheet,
+	Image:              resourceTypeImage,
+	Media:              resourceTypeMedia,
+	Font:               resourceTypeFont,
+	Script:             resourceTypeScript,
+	TextTrack:          resourceTypeTextTrack,
+	XHR:                resourceTypeXHR,
+	Fetch:              resourceTypeFetch,
+	EventSource:        resourceTypeEventSource,
+	WebSocket:          resourceTypeWebSocket,
+	Manifest:           resourceTypeManifest,
+	Other:              resourceTypeOther,
+	Prefetch:           resourceTypePrefetch,
+	SignedExchange:     resourceTypeSignedExchange,
+	Ping:               resourceTypePing,
+	CSPViolationReport: resourceTypeCSPViolationReport,
+	Preflight:          resourceTypePreflight,
+	FedCM:              resourceTypeFedCM,
 }
 
 /*
 ResourceTypeEnum represents the resource type as it was perceived by the
 rendering engine. Allowed Values:
-	- ResourceType.Document    "Document"
-	- ResourceType.Stylesheet  "Stylesheet"
-	- ResourceType.Image       "Image"
-	- ResourceType.Media       "Media"
-	- ResourceType.Font        "Font"
-	- ResourceType.Script      "Script"
-	- ResourceType.TextTrack   "TextTrack"
-	- ResourceType.XHR         "XHR"
-	- ResourceType.Fetch       "Fetch"
-	- ResourceType.EventSource "EventSource"
-	- ResourceType.WebSocket   "WebSocket"
-	- ResourceType.Manifest    "Manifest"
-	- ResourceType.Other       "Other"
+	- ResourceType.Document           "Document"
+	- ResourceType.Stylesheet         "Stylesheet"
+	- ResourceType.Image              "Image"
+	- ResourceType.Media              "Media"
+	- ResourceType.Font               "Font"
+	- ResourceType.Script             "Script"
+	- ResourceType.TextTrack          "TextTrack"
+	- ResourceType.XHR                "XHR"
+	- ResourceType.Fetch              "Fetch"
+	- ResourceType.EventSource        "EventSource"
+	- ResourceType.WebSocket          "WebSocket"
+	- ResourceType.Manifest           "Manifest"
+	- ResourceType.Other              "Other"
+	- ResourceType.Prefetch           "Prefetch"
+	- ResourceType.SignedExchange     "SignedExchange"
+	- ResourceType.Ping               "Ping"
+	- ResourceType.CSPViolationReport "CSPViolationReport"
+	- ResourceType.Preflight          "Preflight"
+	- ResourceType.FedCM              "FedCM"
 
 https://chromedevtools.github.io/devtools-protocol/tot/Page/#type-ResourceType
 */
@@ -124,20 +142,38 @@ const (
 	resourceTypeManifest
 	// resourceTypeOther represents the "Other" value.
 	resourceTypeOther
+	// resourceTypePrefetch represents the "Prefetch" value.
+	resourceTypePrefetch
+	// resourceTypeSignedExchange represents the "SignedExchange" value.
+	resourceTypeSignedExchange
+	// resourceTypePing represents the "Ping" value.
+	resourceTypePing
+	// resourceTypeCSPViolationReport represents the "CSPViolationReport" value.
+	resourceTypeCSPViolationReport
+	// resourceTypePreflight represents the "Preflight" value.
+	resourceTypePreflight
+	// resourceTypeFedCM represents the "FedCM" value.
+	resourceTypeFedCM
 )
 
 var _resourceTypeEnums = map[ResourceTypeEnum]string{
-	resourceTypeDocument:    "Document",
-	resourceTypeStylesheet:  "Stylesheet",
-	resourceTypeImage:       "Image",
-	resourceTypeMedia:       "Media",
-	resourceTypeFont:        "Font",
-	resourceTypeScript:      "Script",
-	resourceTypeTextTrack:   "TextTrack",
-	resourceTypeXHR:         "XHR",
-	resourceTypeFetch:       "Fetch",
-	resourceTypeEventSource: "EventSource",
-	resourceTypeWebSocket:   "WebSocket",
-	resourceTypeManifest:    "Manifest",
-	resourceTypeOther:       "Other",
+	resourceTypeDocument:           "Document",
+	resourceTypeStylesheet:         "Stylesheet",
+	resourceTypeImage:              "Image",
+	resourceTypeMedia:              "Media",
+	resourceTypeFont:               "Font",
+	resourceTypeScript:             "Script",
+	resourceTypeTextTrack:          "TextTrack",
+	resourceTypeXHR:                "XHR",
+	resourceTypeFetch:              "Fetch",
+	resourceTypeEventSource:        "EventSource",
+	resourceTypeWebSocket:          "WebSocket",
+	resourceTypeManifest:           "Manifest",
+	resourceTypeOther:              "Other",
+	resourceTypePrefetch:           "Prefetch",
+	resourceTypeSignedExchange:     "SignedExchange",
+	resourceTypePing:               "Ping",
+	resourceTypeCSPViolationReport: "CSPViolationReport",
+	resourceTypePreflight:          "Preflight",
+	resourceTypeFedCM:              "FedCM",
 }
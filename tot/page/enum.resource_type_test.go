@@ -209,4 +209,88 @@ func TestEnumResourceType4(t *testing.T) {
 	if ResourceType.Other != enum {
 		t.Errorf("Expcected %d, got %d", ResourceType.Other, enum)
 	}
+
+	enum = ResourceType.Prefetch
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"Prefetch"` != string(result) {
+		t.Errorf("Expected '\"Prefetch\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"Prefetch"`), &enum)
+	if ResourceType.Prefetch != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.Prefetch, enum)
+	}
+
+	enum = ResourceType.SignedExchange
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"SignedExchange"` != string(result) {
+		t.Errorf("Expected '\"SignedExchange\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"SignedExchange"`), &enum)
+	if ResourceType.SignedExchange != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.SignedExchange, enum)
+	}
+}
+
+func TestEnumResourceType5(t *testing.T) {
+	var enum ResourceTypeEnum
+	var err error
+	var result []byte
+
+	enum = ResourceType.Ping
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"Ping"` != string(result) {
+		t.Errorf("Expected '\"Ping\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"Ping"`), &enum)
+	if ResourceType.Ping != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.Ping, enum)
+	}
+
+	enum = ResourceType.CSPViolationReport
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"CSPViolationReport"` != string(result) {
+		t.Errorf("Expected '\"CSPViolationReport\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"CSPViolationReport"`), &enum)
+	if ResourceType.CSPViolationReport != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.CSPViolationReport, enum)
+	}
+
+	enum = ResourceType.Preflight
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"Preflight"` != string(result) {
+		t.Errorf("Expected '\"Preflight\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"Preflight"`), &enum)
+	if ResourceType.Preflight != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.Preflight, enum)
+	}
+
+	enum = ResourceType.FedCM
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"FedCM"` != string(result) {
+		t.Errorf("Expected '\"FedCM\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"FedCM"`), &enum)
+	if ResourceType.FedCM != enum {
+		t.Errorf("Expcected %d, got %d", ResourceType.FedCM, enum)
+	}
 }
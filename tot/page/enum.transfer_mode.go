@@ -0,0 +1,78 @@
+package page
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type transferModeEnum struct {
+	ReturnAsBase64 TransferModeEnum
+	ReturnAsStream TransferModeEnum
+}
+
+/*
+TransferMode provides named acces to the TransferModeEnum values.
+*/
+var TransferMode = transferModeEnum{
+	ReturnAsBase64: transferModeReturnAsBase64,
+	ReturnAsStream: transferModeReturnAsStream,
+}
+
+/*
+TransferModeEnum is optional. Whether to return the PDF as base64-encoded data
+or as a stream (defaults to `ReturnAsBase64`). Allowed values:
+	- TransferMode.ReturnAsBase64 "ReturnAsBase64"
+	- TransferMode.ReturnAsStream "ReturnAsStream"
+
+https://chromedevtools.github.io/devtools-protocol/tot/Page/#method-printToPDF
+*/
+type TransferModeEnum int
+
+/*
+String implements Stringer
+*/
+func (enum TransferModeEnum) String() string {
+	return _transferModeEnums[enum]
+}
+
+/*
+MarshalJSON implements json.Marshaler
+*/
+func (enum TransferModeEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(enum.String())
+}
+
+/*
+UnmarshalJSON implements json.Unmarshaler
+*/
+func (enum *TransferModeEnum) UnmarshalJSON(bytes []byte) error {
+	var err error
+	var val string
+
+	err = json.Unmarshal(bytes, &val)
+	if nil != err {
+		return err
+	}
+
+	for k, v := range _transferModeEnums {
+		if v == val {
+			*enum = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a valid type value", bytes)
+}
+
+const (
+	// transferModeReturnAsBase64 represents the "ReturnAsBase64" value.
+	transferModeReturnAsBase64 TransferModeEnum = iota + 1
+	// transferModeReturnAsStream represents the "ReturnAsStream" value.
+	transferModeReturnAsStream
+)
+
+var _transferModeEnums = map[TransferModeEnum]string{
+	TransferModeEnum(0):        "",
+	transferModeReturnAsBase64: "ReturnAsBase64",
+	transferModeReturnAsStream: "ReturnAsStream",
+}
@@ -5,6 +5,10 @@ https://chromedevtools.github.io/devtools-protocol/tot/Runtime/
 */
 package runtime
 
+import (
+	"encoding/json"
+)
+
 /*
 ScriptID is a unique script identifier.
 
@@ -59,12 +63,14 @@ type RemoteObject struct {
 	ClassName string `json:"className,omitempty"`
 
 	// Optional. Remote object value in case of primitive values or JSON values
-	// (if it was requested).
-	Value interface{} `json:"value,omitempty"`
+	// (if it was requested). The value is kept as raw JSON so that it can be
+	// decoded into the caller's type.
+	Value json.RawMessage `json:"value,omitempty"`
 
 	// Optional. Primitive value which can not be JSON-stringified does not have
-	// value, but gets this property.
-	UnserializableValue UnserializableValueEnum `json:"unserializableValue,omitempty"`
+	// value, but gets this property. One of the UnserializableValue values or a
+	// BigInt literal such as "123n".
+	UnserializableValue string `json:"unserializableValue,omitempty"`
 
 	// Optional. String representation of the object.
 	Description string `json:"description,omitempty"`
@@ -261,11 +267,12 @@ unserializable primitive value or neither of (for undefined) them should be spec
 https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#type-CallArgument
 */
 type CallArgument struct {
-	// Optional. Primitive value or serializable javascript object.
-	Value interface{} `json:"value,omitempty"`
+	// Optional. Primitive value or serializable javascript object as JSON.
+	Value json.RawMessage `json:"value,omitempty"`
 
-	// Optional. Primitive value which can not be JSON-stringified.
-	UnserializableValue UnserializableValueEnum `json:"unserializableValue,omitempty"`
+	// Optional. Primitive value which can not be JSON-stringified. One of the
+	// UnserializableValue values or a BigInt literal such as "123n".
+	UnserializableValue string `json:"unserializableValue,omitempty"`
 
 	// Optional. Remote object handle.
 	ObjectID RemoteObjectID `json:"objectId,omitempty"`
@@ -295,7 +302,7 @@ type ExecutionContextDescription struct {
 	Name string `json:"name"`
 
 	// Optional. Embedder-specific auxiliary data.
-	AuxData map[string]string `json:"auxData,omitempty"`
+	AuxData map[string]interface{} `json:"auxData,omitempty"`
 }
 
 /*
@@ -340,7 +347,7 @@ Timestamp is the number of milliseconds since epoch.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#type-Timestamp
 */
-type Timestamp int
+type Timestamp float64
 
 /*
 CallFrame is a stack entry for runtime errors and assertions.
@@ -241,3 +241,93 @@ func TestEnumObjectSubtype4(t *testing.T) {
 		t.Errorf("Expcected %d, got %d", ObjectSubtype.Typedarray, enum)
 	}
 }
+
+func TestEnumObjectSubtype5(t *testing.T) {
+	var enum ObjectSubtypeEnum
+	var err error
+	var result []byte
+
+	enum = ObjectSubtype.Weakref
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"weakref"` != string(result) {
+		t.Errorf("Expected '\"weakref\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"weakref"`), &enum)
+	if ObjectSubtype.Weakref != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Weakref, enum)
+	}
+
+	enum = ObjectSubtype.Arraybuffer
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"arraybuffer"` != string(result) {
+		t.Errorf("Expected '\"arraybuffer\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"arraybuffer"`), &enum)
+	if ObjectSubtype.Arraybuffer != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Arraybuffer, enum)
+	}
+
+	enum = ObjectSubtype.Dataview
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"dataview"` != string(result) {
+		t.Errorf("Expected '\"dataview\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"dataview"`), &enum)
+	if ObjectSubtype.Dataview != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Dataview, enum)
+	}
+
+	enum = ObjectSubtype.Webassemblymemory
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"webassemblymemory"` != string(result) {
+		t.Errorf("Expected '\"webassemblymemory\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"webassemblymemory"`), &enum)
+	if ObjectSubtype.Webassemblymemory != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Webassemblymemory, enum)
+	}
+}
+
+func TestEnumObjectSubtype6(t *testing.T) {
+	var enum ObjectSubtypeEnum
+	var err error
+	var result []byte
+
+	enum = ObjectSubtype.Wasmvalue
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"wasmvalue"` != string(result) {
+		t.Errorf("Expected '\"wasmvalue\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"wasmvalue"`), &enum)
+	if ObjectSubtype.Wasmvalue != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Wasmvalue, enum)
+	}
+
+	enum = ObjectSubtype.Trustedtype
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"trustedtype"` != string(result) {
+		t.Errorf("Expected '\"trustedtype\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"trustedtype"`), &enum)
+	if ObjectSubtype.Trustedtype != enum {
+		t.Errorf("Expcected %d, got %d", ObjectSubtype.Trustedtype, enum)
+	}
+}
@@ -14,6 +14,7 @@ type objectTypeEnum struct {
 	Boolean   ObjectTypeEnum
 	Symbol    ObjectTypeEnum
 	Accessor  ObjectTypeEnum
+	Bigint    ObjectTypeEnum
 }
 
 /*
@@ -28,6 +29,7 @@ var ObjectType = objectTypeEnum{
 	Boolean:   objectTypeBoolean,
 	Symbol:    objectTypeSymbol,
 	Accessor:  objectTypeAccessor,
+	Bigint:    objectTypeBigint,
 }
 
 /*
@@ -41,6 +43,7 @@ the property itself is an accessor property. Allowed values:
 	- ObjectType.Boolean   "boolean"
 	- ObjectType.Symbol    "symbol"
 	- ObjectType.Accessor  "accessor"
+	- ObjectType.Bigint    "bigint"
 
 https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#type-RemoteObject
 https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#type-ObjectPreview
@@ -101,6 +104,8 @@ const (
 	objectTypeSymbol
 	// objectTypeAccessor represents the "accessor" value.
 	objectTypeAccessor
+	// objectTypeBigint represents the "bigint" value.
+	objectTypeBigint
 )
 
 var _objectTypeEnums = map[ObjectTypeEnum]string{
@@ -112,4 +117,5 @@ var _objectTypeEnums = map[ObjectTypeEnum]string{
 	objectTypeBoolean:   "boolean",
 	objectTypeSymbol:    "symbol",
 	objectTypeAccessor:  "accessor",
+	objectTypeBigint:    "bigint",
 }
@@ -84,3 +84,17 @@ type InsecureContentStatus struct {
 	//	- State.Info
 	DisplayedInsecureContentStyle StateEnum `json:"displayedInsecureContentStyle"`
 }
+
+/*
+VisibleSecurityState is the security state of the page as shown to the user.
+EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Security/#type-VisibleSecurityState
+*/
+type VisibleSecurityState struct {
+	// The security level of the page.
+	SecurityState StateEnum `json:"securityState"`
+
+	// Array of security state issues ids.
+	SecurityStateIssueIDs []string `json:"securityStateIssueIds"`
+}
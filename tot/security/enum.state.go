@@ -6,31 +6,34 @@ import (
 )
 
 type stateEnum struct {
-	Unknown  StateEnum
-	Neutral  StateEnum
-	Insecure StateEnum
-	Secure   StateEnum
-	Info     StateEnum
+	Unknown        StateEnum
+	Neutral        StateEnum
+	Insecure       StateEnum
+	Secure         StateEnum
+	Info           StateEnum
+	InsecureBroken StateEnum
 }
 
 /*
 State provides named acces to the StateEnum values.
 */
 var State = stateEnum{
-	Unknown:  stateUnknown,
-	Neutral:  stateNeutral,
-	Insecure: stateInsecure,
-	Secure:   stateSecure,
-	Info:     stateInfo,
+	Unknown:        stateUnknown,
+	Neutral:        stateNeutral,
+	Insecure:       stateInsecure,
+	Secure:         stateSecure,
+	Info:           stateInfo,
+	InsecureBroken: stateInsecureBroken,
 }
 
 /*
 StateEnum is the security level of a page or resource. Allowed values:
-	- State.Unknown  "unknown"
-	- State.Neutral  "neutral"
-	- State.Insecure "insecure"
-	- State.Secure   "secure"
-	- State.Info     "info"
+	- State.Unknown        "unknown"
+	- State.Neutral        "neutral"
+	- State.Insecure       "insecure"
+	- State.Secure         "secure"
+	- State.Info           "info"
+	- State.InsecureBroken "insecure-broken"
 
 https://chromedevtools.github.io/devtools-protocol/tot/Security/#type-SecurityState
 */
@@ -83,12 +86,15 @@ const (
 	stateSecure
 	// stateInfo represents the "info" value.
 	stateInfo
+	// stateInsecureBroken represents the "insecure-broken" value.
+	stateInsecureBroken
 )
 
 var _stateEnums = map[StateEnum]string{
-	stateUnknown:  "unknown",
-	stateNeutral:  "neutral",
-	stateInsecure: "insecure",
-	stateSecure:   "secure",
-	stateInfo:     "info",
+	stateUnknown:        "unknown",
+	stateNeutral:        "neutral",
+	stateInsecure:       "insecure",
+	stateSecure:         "secure",
+	stateInfo:           "info",
+	stateInsecureBroken: "insecure-broken",
 }
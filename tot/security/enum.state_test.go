@@ -93,4 +93,17 @@ func TestEnumState2(t *testing.T) {
 	if State.Info != enum {
 		t.Errorf("Expcected %d, got %d", State.Info, enum)
 	}
+
+	enum = State.InsecureBroken
+	result, err = json.Marshal(enum)
+	if nil != err {
+		t.Errorf("Expected nil, got error")
+	}
+	if `"insecure-broken"` != string(result) {
+		t.Errorf("Expected '\"insecure-broken\"', got '%s'", result)
+	}
+	json.Unmarshal([]byte(`"insecure-broken"`), &enum)
+	if State.InsecureBroken != enum {
+		t.Errorf("Expcected %d, got %d", State.InsecureBroken, enum)
+	}
 }
@@ -50,3 +50,17 @@ type StateChangedEvent struct {
 	// Error information related to this event
 	Err error `json:"-"`
 }
+
+/*
+VisibleSecurityStateChangedEvent represents Security.visibleSecurityStateChanged
+event data. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Security/#event-visibleSecurityStateChanged
+*/
+type VisibleSecurityStateChangedEvent struct {
+	// Security state information about the page.
+	VisibleSecurityState *VisibleSecurityState `json:"visibleSecurityState"`
+
+	// Error information related to this event
+	Err error `json:"-"`
+}
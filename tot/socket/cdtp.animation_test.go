@@ -231,7 +231,7 @@ func TestAnimationResolveAnimation(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			ClassName:           "some-class",
 			Value:               nil,
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "Animation description",
 			ObjectID:            "object-id",
 			Preview: &runtime.ObjectPreview{
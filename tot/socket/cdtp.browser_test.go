@@ -201,6 +201,75 @@ func TestBrowserGetWindowForTarget(t *testing.T) {
 	}
 }
 
+func TestBrowserGrantPermissions(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestBrowserGrantPermissions")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &browser.GrantPermissionsParams{
+		Origin:      "https://example.com",
+		Permissions: []browser.PermissionType{"geolocation"},
+	}
+	resultChan := mockSocket.Browser().GrantPermissions(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:    mockSocket.CurCommandID(),
+		Error: &Error{},
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Browser().GrantPermissions(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
+func TestBrowserResetPermissions(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestBrowserResetPermissions")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &browser.ResetPermissionsParams{
+		BrowserContextID: target.BrowserContextID("context-id"),
+	}
+	resultChan := mockSocket.Browser().ResetPermissions(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:    mockSocket.CurCommandID(),
+		Error: &Error{},
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Browser().ResetPermissions(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestBrowserSetWindowBounds(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestBrowserSetWindowBounds")
 	mockSocket := NewMock(socketURL)
@@ -146,8 +146,8 @@ func TestDebuggerEvaluateOnCallFrame(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               1,
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`1`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
@@ -1023,8 +1023,8 @@ func TestDebuggerSetReturnValue(t *testing.T) {
 
 	resultChan := mockSocket.Debugger().SetReturnValue(&debugger.SetReturnValueParams{
 		NewValue: &runtime.CallArgument{
-			Value:               "some-value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"some-value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		},
 	})
@@ -1042,8 +1042,8 @@ func TestDebuggerSetReturnValue(t *testing.T) {
 
 	resultChan = mockSocket.Debugger().SetReturnValue(&debugger.SetReturnValueParams{
 		NewValue: &runtime.CallArgument{
-			Value:               "some-value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"some-value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		},
 	})
@@ -1220,8 +1220,8 @@ func TestDebuggerSetVariableValue(t *testing.T) {
 		ScopeNumber:  1,
 		VariableName: "varname",
 		NewValue: &runtime.CallArgument{
-			Value:               "some-value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"some-value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		},
 		CallFrameID: debugger.CallFrameID("call-frame-id"),
@@ -1242,8 +1242,8 @@ func TestDebuggerSetVariableValue(t *testing.T) {
 		ScopeNumber:  1,
 		VariableName: "varname",
 		NewValue: &runtime.CallArgument{
-			Value:               "some-value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"some-value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		},
 		CallFrameID: debugger.CallFrameID("call-frame-id"),
@@ -1431,9 +1431,9 @@ func TestDebuggerOnPaused(t *testing.T) {
 		CallFrames: []*debugger.CallFrame{{
 			CallFrameID: debugger.CallFrameID("call-frame-id"),
 		}},
-		Reason:                "reason",
-		Data:                  map[string]string{"key": "value"},
-		HitBreakpoints:        []string{"breakpoint1"},
+		Reason:                debugger.Reason.Other,
+		Data:                  json.RawMessage(`{"key":"value"}`),
+		HitBreakpoints:        []debugger.BreakpointID{"breakpoint1"},
 		AsyncStackTrace:       &runtime.StackTrace{},
 		AsyncStackTraceID:     &runtime.StackTraceID{},
 		AsyncCallStackTraceID: &runtime.StackTraceID{},
@@ -1530,7 +1530,7 @@ func TestDebuggerOnScriptFailedToParse(t *testing.T) {
 		EndColumn:               10,
 		ExecutionContextID:      runtime.ExecutionContextID(1),
 		Hash:                    "some hash",
-		ExecutionContextAuxData: map[string]string{"key": "value"},
+		ExecutionContextAuxData: map[string]interface{}{"key": "value"},
 		SourceMapURL:            "http://source-map.url",
 		HasSourceURL:            true,
 		IsModule:                true,
@@ -1587,7 +1587,7 @@ func TestDebuggerOnScriptParsed(t *testing.T) {
 		EndColumn:               10,
 		ExecutionContextID:      runtime.ExecutionContextID(1),
 		Hash:                    "some hash",
-		ExecutionContextAuxData: map[string]string{"key": "value"},
+		ExecutionContextAuxData: map[string]interface{}{"key": "value"},
 		IsLiveEdit:              true,
 		SourceMapURL:            "http://source-map.url",
 		HasSourceURL:            true,
@@ -1242,8 +1242,8 @@ func TestDOMResolveNode(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               "some-value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"some-value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
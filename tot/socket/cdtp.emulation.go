@@ -259,6 +259,32 @@ func (protocol *EmulationProtocol) SetGeolocationOverride(
 	return resultChan
 }
 
+/*
+SetLocaleOverride overrides the default host system locale with the specified
+one.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setLocaleOverride
+EXPERIMENTAL.
+*/
+func (protocol *EmulationProtocol) SetLocaleOverride(
+	params *emulation.SetLocaleOverrideParams,
+) <-chan *emulation.SetLocaleOverrideResult {
+	resultChan := make(chan *emulation.SetLocaleOverrideResult)
+	command := NewCommand(protocol.Socket, "Emulation.setLocaleOverride", params)
+	result := &emulation.SetLocaleOverrideResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 SetNavigatorOverrides overrides value returned by the javascript navigator
 object.
@@ -334,6 +360,32 @@ func (protocol *EmulationProtocol) SetScriptExecutionDisabled(
 	return resultChan
 }
 
+/*
+SetTimezoneOverride overrides the default host system timezone with the
+specified one.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setTimezoneOverride
+EXPERIMENTAL.
+*/
+func (protocol *EmulationProtocol) SetTimezoneOverride(
+	params *emulation.SetTimezoneOverrideParams,
+) <-chan *emulation.SetTimezoneOverrideResult {
+	resultChan := make(chan *emulation.SetTimezoneOverrideResult)
+	command := NewCommand(protocol.Socket, "Emulation.setTimezoneOverride", params)
+	result := &emulation.SetTimezoneOverrideResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 SetTouchEmulationEnabled enables touch on platforms which do not support it.
 
@@ -358,6 +410,30 @@ func (protocol *EmulationProtocol) SetTouchEmulationEnabled(
 	return resultChan
 }
 
+/*
+SetUserAgentOverride allows overriding the user agent with the given string.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Emulation/#method-setUserAgentOverride
+*/
+func (protocol *EmulationProtocol) SetUserAgentOverride(
+	params *emulation.SetUserAgentOverrideParams,
+) <-chan *emulation.SetUserAgentOverrideResult {
+	resultChan := make(chan *emulation.SetUserAgentOverrideResult)
+	command := NewCommand(protocol.Socket, "Emulation.setUserAgentOverride", params)
+	result := &emulation.SetUserAgentOverrideResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 SetVirtualTimePolicy turns on virtual time for all frames (replacing real-time
 with a synthetic time source) and sets the current virtual time policy. Note
@@ -370,7 +446,7 @@ func (protocol *EmulationProtocol) SetVirtualTimePolicy(
 	params *emulation.SetVirtualTimePolicyParams,
 ) <-chan *emulation.SetVirtualTimePolicyResult {
 	resultChan := make(chan *emulation.SetVirtualTimePolicyResult)
-	command := NewCommand(protocol.Socket, "Emulation.SetVirtualTimePolicy", nil)
+	command := NewCommand(protocol.Socket, "Emulation.setVirtualTimePolicy", params)
 	result := &emulation.SetVirtualTimePolicyResult{}
 
 	go func() {
@@ -403,6 +403,43 @@ func TestEmulationSetGeolocationOverride(t *testing.T) {
 	}
 }
 
+func TestEmulationSetLocaleOverride(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetLocaleOverride")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &emulation.SetLocaleOverrideParams{
+		Locale: "en_US",
+	}
+	resultChan := mockSocket.Emulation().SetLocaleOverride(params)
+	mockResult := &emulation.SetLocaleOverrideResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Emulation().SetLocaleOverride(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestEmulationSetNavigatorOverrides(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetNavigatorOverrides")
 	mockSocket := NewMock(socketURL)
@@ -514,6 +551,43 @@ func TestEmulationSetScriptExecutionDisabled(t *testing.T) {
 	}
 }
 
+func TestEmulationSetTimezoneOverride(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetTimezoneOverride")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &emulation.SetTimezoneOverrideParams{
+		TimezoneID: "Europe/Berlin",
+	}
+	resultChan := mockSocket.Emulation().SetTimezoneOverride(params)
+	mockResult := &emulation.SetTimezoneOverrideResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Emulation().SetTimezoneOverride(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestEmulationSetTouchEmulationEnabled(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetTouchEmulationEnabled")
 	mockSocket := NewMock(socketURL)
@@ -552,6 +626,45 @@ func TestEmulationSetTouchEmulationEnabled(t *testing.T) {
 	}
 }
 
+func TestEmulationSetUserAgentOverride(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetUserAgentOverride")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &emulation.SetUserAgentOverrideParams{
+		UserAgent:      "user-agent",
+		AcceptLanguage: "de-DE",
+		Platform:       "platform",
+	}
+	resultChan := mockSocket.Emulation().SetUserAgentOverride(params)
+	mockResult := &emulation.SetUserAgentOverrideResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Emulation().SetUserAgentOverride(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestEmulationSetVirtualTimePolicy(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestEmulationSetVirtualTimePolicy")
 	mockSocket := NewMock(socketURL)
@@ -559,7 +672,7 @@ func TestEmulationSetVirtualTimePolicy(t *testing.T) {
 	defer mockSocket.Stop()
 
 	params := &emulation.SetVirtualTimePolicyParams{
-		Policy:                            emulation.VirtualTimePolicy("policy"),
+		Policy:                            emulation.VirtualTimePolicy.Advance,
 		Budget:                            1,
 		MaxVirtualTimeTaskStarvationCount: 1,
 	}
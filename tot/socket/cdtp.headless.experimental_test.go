@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/mkenney/go-chrome/tot/headless/experimental"
-	"github.com/mkenney/go-chrome/tot/runtime"
 )
 
 func TestHeadlessExperimentalBeginFrame(t *testing.T) {
@@ -17,8 +16,8 @@ func TestHeadlessExperimentalBeginFrame(t *testing.T) {
 	defer mockSocket.Stop()
 
 	params := &experimental.BeginFrameParams{
-		FrameTime: runtime.Timestamp(time.Now().Unix()),
-		Deadline:  runtime.Timestamp(time.Now().Unix()),
+		FrameTime: float64(time.Now().Unix()),
+		Deadline:  float64(time.Now().Unix()),
 		Interval:  1.1,
 		Screenshot: &experimental.ScreenshotParams{
 			Format:  experimental.Format.Jpeg,
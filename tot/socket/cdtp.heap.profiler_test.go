@@ -205,8 +205,8 @@ func TestHeapProfilerGetObjectByHeapObjectID(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               &struct{ a string }{a: "somestring"},
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`{"a":"somestring"}`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
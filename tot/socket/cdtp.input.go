@@ -110,6 +110,32 @@ func (protocol *InputProtocol) EmulateTouchFromMouseEvent(
 	return resultChan
 }
 
+/*
+InsertText emulates inserting text that doesn't come from a key press, for
+example an emoji keyboard or an IME.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Input/#method-insertText
+EXPERIMENTAL.
+*/
+func (protocol *InputProtocol) InsertText(
+	params *input.InsertTextParams,
+) <-chan *input.InsertTextResult {
+	resultChan := make(chan *input.InsertTextResult)
+	command := NewCommand(protocol.Socket, "Input.insertText", params)
+	result := &input.InsertTextResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 SetIgnoreEvents ignores input events (useful while auditing page).
 
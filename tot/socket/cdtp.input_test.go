@@ -197,6 +197,43 @@ func TestInputEmulateTouchFromMouseEvent(t *testing.T) {
 	}
 }
 
+func TestInputInsertText(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestInputInsertText")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &input.InsertTextParams{
+		Text: "text",
+	}
+	resultChan := mockSocket.Input().InsertText(params)
+	mockResult := &input.InsertTextResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Input().InsertText(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestInputSetIgnoreEvents(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestInputSetIgnoreEvents")
 	mockSocket := NewMock(socketURL)
@@ -1,6 +1,8 @@
 package socket
 
 import (
+	"encoding/json"
+
 	"github.com/mkenney/go-chrome/tot/memory"
 )
 
@@ -19,17 +21,17 @@ GetDOMCounters is experimental.
 https://chromedevtools.github.io/devtools-protocol/tot/Memory/#method-getDOMCounters
 EXPERIMENTAL.
 */
-func (protocol *MemoryProtocol) GetDOMCounters(
-	params *memory.GetDOMCountersParams,
-) <-chan *memory.GetDOMCountersResult {
+func (protocol *MemoryProtocol) GetDOMCounters() <-chan *memory.GetDOMCountersResult {
 	resultChan := make(chan *memory.GetDOMCountersResult)
-	command := NewCommand(protocol.Socket, "Memory.getDOMCounters", params)
+	command := NewCommand(protocol.Socket, "Memory.getDOMCounters", nil)
 	result := &memory.GetDOMCountersResult{}
 
 	go func() {
 		response := <-protocol.Socket.SendCommand(command)
 		if nil != response.Error && 0 != response.Error.Code {
 			result.Err = response.Error
+		} else {
+			result.Err = json.Unmarshal(response.Result, &result)
 		}
 		resultChan <- result
 		close(resultChan)
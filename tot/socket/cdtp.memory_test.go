@@ -14,13 +14,12 @@ func TestMemoryGetDOMCounters(t *testing.T) {
 	mockSocket.Listen()
 	defer mockSocket.Stop()
 
-	params := &memory.GetDOMCountersParams{
+	resultChan := mockSocket.Memory().GetDOMCounters()
+	mockResult := &memory.GetDOMCountersResult{
 		Documents:        1,
-		Nodes:            1,
-		JsEventListeners: 1,
+		Nodes:            2,
+		JSEventListeners: 3,
 	}
-	resultChan := mockSocket.Memory().GetDOMCounters(params)
-	mockResult := &memory.GetDOMCountersResult{}
 	mockResultBytes, _ := json.Marshal(mockResult)
 	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
 		ID:     mockSocket.CurCommandID(),
@@ -31,8 +30,11 @@ func TestMemoryGetDOMCounters(t *testing.T) {
 	if nil != result.Err {
 		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
 	}
+	if mockResult.Nodes != result.Nodes || mockResult.JSEventListeners != result.JSEventListeners {
+		t.Errorf("Expected %v, got %v", mockResult, result)
+	}
 
-	resultChan = mockSocket.Memory().GetDOMCounters(params)
+	resultChan = mockSocket.Memory().GetDOMCounters()
 	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
 		ID: mockSocket.CurCommandID(),
 		Error: &Error{
@@ -396,6 +396,35 @@ func (protocol *NetworkProtocol) GetResponseBodyForInterception(
 	return resultChan
 }
 
+/*
+LoadNetworkResource fetches a resource through the browser's network stack
+and returns a stream to read its content with, e.g. a source map. The cookies
+and cache of the frame apply.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Network/#method-loadNetworkResource
+EXPERIMENTAL.
+*/
+func (protocol *NetworkProtocol) LoadNetworkResource(
+	params *network.LoadNetworkResourceParams,
+) <-chan *network.LoadNetworkResourceResult {
+	resultChan := make(chan *network.LoadNetworkResourceResult)
+	command := NewCommand(protocol.Socket, "Network.loadNetworkResource", params)
+	result := &network.LoadNetworkResourceResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		} else {
+			result.Err = json.Unmarshal(response.Result, &result)
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 ReplayXHR sends a new XMLHttpRequest which is identical to the original one. The
 following parameters should be identical: method, url, async, request body,
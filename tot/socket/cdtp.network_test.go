@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/io"
 	"github.com/mkenney/go-chrome/tot/network"
 	"github.com/mkenney/go-chrome/tot/page"
 	"github.com/mkenney/go-chrome/tot/runtime"
@@ -629,6 +630,55 @@ func TestNetworkGetResponseBodyForInterception(t *testing.T) {
 	}
 }
 
+func TestNetworkLoadNetworkResource(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestNetworkLoadNetworkResource")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &network.LoadNetworkResourceParams{
+		FrameID: page.FrameID("frame-id"),
+		URL:     "https://example.com/app.js.map",
+		Options: &network.LoadNetworkResourceOptions{IncludeCredentials: true},
+	}
+	resultChan := mockSocket.Network().LoadNetworkResource(params)
+	mockResult := &network.LoadNetworkResourceResult{
+		Resource: &network.LoadNetworkResourcePageResult{
+			Success:        true,
+			HTTPStatusCode: 200,
+			Stream:         io.StreamHandle("stream-handle"),
+			Headers:        network.Headers{"content-type": "application/json"},
+		},
+	}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+	if mockResult.Resource.Stream != result.Resource.Stream {
+		t.Errorf("Expected %s, got %s", mockResult.Resource.Stream, result.Resource.Stream)
+	}
+
+	resultChan = mockSocket.Network().LoadNetworkResource(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestNetworkReplayXHR(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestNetworkReplayXHR")
 	mockSocket := NewMock(socketURL)
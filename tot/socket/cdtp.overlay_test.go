@@ -736,7 +736,7 @@ func TestOverlayOnScreenshotRequested(t *testing.T) {
 		t.Errorf("Expected '%v', got: '%v'", mockResult, result)
 	}
 	if mockResult.Viewport.X != result.Viewport.X {
-		t.Errorf("Expected %d, got %d", mockResult.Viewport.X, result.Viewport.X)
+		t.Errorf("Expected %v, got %v", mockResult.Viewport.X, result.Viewport.X)
 	}
 
 	resultChan = make(chan *overlay.ScreenshotRequestedEvent)
@@ -428,7 +428,7 @@ func TestPageGetLayoutMetrics(t *testing.T) {
 		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
 	}
 	if mockResult.LayoutViewport.PageX != result.LayoutViewport.PageX {
-		t.Errorf("Expected %d, got %d", mockResult.LayoutViewport.PageX, result.LayoutViewport.PageX)
+		t.Errorf("Expected %v, got %v", mockResult.LayoutViewport.PageX, result.LayoutViewport.PageX)
 	}
 
 	resultChan = mockSocket.Page().GetLayoutMetrics()
@@ -714,6 +714,7 @@ func TestPagePrintToPDF(t *testing.T) {
 	mockSocket.Listen()
 	defer mockSocket.Stop()
 
+	margin := 1.0
 	params := &page.PrintToPDFParams{
 		Landscape:               true,
 		DisplayHeaderFooter:     true,
@@ -721,16 +722,21 @@ func TestPagePrintToPDF(t *testing.T) {
 		Scale:                   1,
 		PaperWidth:              1,
 		PaperHeight:             1,
-		MarginTop:               1,
-		MarginBottom:            1,
-		MarginLeft:              1,
-		MarginRight:             1,
+		MarginTop:               &margin,
+		MarginBottom:            &margin,
+		MarginLeft:              &margin,
+		MarginRight:             &margin,
 		PageRanges:              "1-2",
 		IgnoreInvalidPageRanges: true,
+		HeaderTemplate:          "<span class=title></span>",
+		FooterTemplate:          "<span class=pageNumber></span>",
+		PreferCSSPageSize:       true,
+		TransferMode:            page.TransferMode.ReturnAsStream,
 	}
 	resultChan := mockSocket.Page().PrintToPDF(params)
 	mockResult := &page.PrintToPDFResult{
-		Data: "result data",
+		Data:   "result data",
+		Stream: "stream handle",
 	}
 	mockResultBytes, _ := json.Marshal(mockResult)
 	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
@@ -745,6 +751,9 @@ func TestPagePrintToPDF(t *testing.T) {
 	if mockResult.Data != result.Data {
 		t.Errorf("Expected %s, got %s", mockResult.Data, result.Data)
 	}
+	if mockResult.Stream != result.Stream {
+		t.Errorf("Expected %s, got %s", mockResult.Stream, result.Stream)
+	}
 
 	resultChan = mockSocket.Page().PrintToPDF(params)
 	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
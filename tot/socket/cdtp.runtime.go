@@ -21,6 +21,33 @@ type RuntimeProtocol struct {
 	Socket Socketer
 }
 
+/*
+AddBinding adds a binding function named name to the global object of all
+inspected contexts, including contexts created later. Calls to the binding
+function fire the Runtime.bindingCalled event with the string argument as the
+payload. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#method-addBinding
+*/
+func (protocol *RuntimeProtocol) AddBinding(
+	params *runtime.AddBindingParams,
+) <-chan *runtime.AddBindingResult {
+	resultChan := make(chan *runtime.AddBindingResult)
+	command := NewCommand(protocol.Socket, "Runtime.addBinding", params)
+	result := &runtime.AddBindingResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 AwaitPromise adds handler to promise with given promise object ID.
 
@@ -322,6 +349,33 @@ func (protocol *RuntimeProtocol) ReleaseObjectGroup(
 	return resultChan
 }
 
+/*
+RemoveBinding removes a binding function from the global object of all
+inspected contexts. Binding functions that were already installed remain in
+existing contexts but no longer fire Runtime.bindingCalled events.
+EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#method-removeBinding
+*/
+func (protocol *RuntimeProtocol) RemoveBinding(
+	params *runtime.RemoveBindingParams,
+) <-chan *runtime.RemoveBindingResult {
+	resultChan := make(chan *runtime.RemoveBindingResult)
+	command := NewCommand(protocol.Socket, "Runtime.removeBinding", params)
+	result := &runtime.RemoveBindingResult{}
+
+	go func() {
+		response := <-protocol.Socket.SendCommand(command)
+		if nil != response.Error && 0 != response.Error.Code {
+			result.Err = response.Error
+		}
+		resultChan <- result
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
 /*
 RunIfWaitingForDebugger tells inspected instance to run if it was waiting for
 debugger to attach.
@@ -396,6 +450,30 @@ func (protocol *RuntimeProtocol) SetCustomObjectFormatterEnabled(
 	return resultChan
 }
 
+/*
+OnBindingCalled adds a handler to the Runtime.bindingCalled event.
+Runtime.bindingCalled fires when a binding function added with
+Runtime.addBinding is called. EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Runtime/#event-bindingCalled
+*/
+func (protocol *RuntimeProtocol) OnBindingCalled(
+	callback func(event *runtime.BindingCalledEvent),
+) {
+	handler := NewEventHandler(
+		"Runtime.bindingCalled",
+		func(response *Response) {
+			event := &runtime.BindingCalledEvent{}
+			json.Unmarshal([]byte(response.Params), event)
+			if nil != response.Error && 0 != response.Error.Code {
+				event.Err = response.Error
+			}
+			callback(event)
+		},
+	)
+	protocol.Socket.AddEventHandler(handler)
+}
+
 /*
 OnConsoleAPICalled adds a handler to the Runtime.consoleAPICalled event.
 Runtime.consoleAPICalled fires when the console API is called.
@@ -9,6 +9,44 @@ import (
 	"github.com/mkenney/go-chrome/tot/runtime"
 )
 
+func TestRuntimeAddBinding(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestRuntimeAddBinding")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &runtime.AddBindingParams{
+		Name:               "binding",
+		ExecutionContextID: runtime.ExecutionContextID(1),
+	}
+	resultChan := mockSocket.Runtime().AddBinding(params)
+	mockResult := &runtime.AddBindingResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Runtime().AddBinding(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestRuntimeAwaitPromise(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestRuntimeAwaitPromise")
 	mockSocket := NewMock(socketURL)
@@ -26,8 +64,8 @@ func TestRuntimeAwaitPromise(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               "value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
@@ -128,8 +166,8 @@ func TestRuntimeCallFunctionOn(t *testing.T) {
 		FunctionDeclaration: "function(){}",
 		ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		Arguments: []*runtime.CallArgument{{
-			Value:               "value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 		}},
 		Silent:             true,
@@ -146,8 +184,8 @@ func TestRuntimeCallFunctionOn(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               "value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
@@ -435,8 +473,8 @@ func TestRuntimeEvaluate(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               "value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
@@ -782,6 +820,43 @@ func TestRuntimeReleaseObjectGroup(t *testing.T) {
 	}
 }
 
+func TestRuntimeRemoveBinding(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestRuntimeRemoveBinding")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &runtime.RemoveBindingParams{
+		Name: "binding",
+	}
+	resultChan := mockSocket.Runtime().RemoveBinding(params)
+	mockResult := &runtime.RemoveBindingResult{}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = mockSocket.Runtime().RemoveBinding(params)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: mockSocket.CurCommandID(),
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestRuntimeRunIfWaitingForDebugger(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestRuntimeRunIfWaitingForDebugger")
 	mockSocket := NewMock(socketURL)
@@ -905,6 +980,55 @@ func TestRuntimeSetCustomObjectFormatterEnabled(t *testing.T) {
 	}
 }
 
+func TestRuntimeOnBindingCalled(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestRuntimeOnBindingCalled")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	resultChan := make(chan *runtime.BindingCalledEvent)
+	mockSocket.Runtime().OnBindingCalled(func(eventData *runtime.BindingCalledEvent) {
+		resultChan <- eventData
+	})
+	mockResult := &runtime.BindingCalledEvent{
+		Name:               "binding",
+		Payload:            "payload",
+		ExecutionContextID: runtime.ExecutionContextID(1),
+	}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     0,
+		Error:  &Error{},
+		Method: "Runtime.bindingCalled",
+		Params: mockResultBytes,
+	})
+	result := <-resultChan
+	if mockResult.Err != result.Err {
+		t.Errorf("Expected '%v', got: '%v'", mockResult, result)
+	}
+	if mockResult.Payload != result.Payload {
+		t.Errorf("Expected %s, got %s", mockResult.Payload, result.Payload)
+	}
+
+	resultChan = make(chan *runtime.BindingCalledEvent)
+	mockSocket.Runtime().OnBindingCalled(func(eventData *runtime.BindingCalledEvent) {
+		resultChan <- eventData
+	})
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: 0,
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+		Method: "Runtime.bindingCalled",
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
+
 func TestRuntimeOnConsoleAPICalled(t *testing.T) {
 	socketURL, _ := url.Parse("https://test:9222/TestRuntimeOnConsoleAPICalled")
 	mockSocket := NewMock(socketURL)
@@ -1031,7 +1155,7 @@ func TestRuntimeOnExceptionThrown(t *testing.T) {
 		t.Errorf("Expected '%v', got: '%v'", mockResult, result)
 	}
 	if mockResult.Timestamp != result.Timestamp {
-		t.Errorf("Expected %d, got %d", mockResult.Timestamp, result.Timestamp)
+		t.Errorf("Expected %f, got %f", mockResult.Timestamp, result.Timestamp)
 	}
 
 	resultChan = make(chan *runtime.ExceptionThrownEvent)
@@ -1068,7 +1192,7 @@ func TestRuntimeOnExecutionContextCreated(t *testing.T) {
 			ID:      runtime.ExecutionContextID(1),
 			Origin:  "origin",
 			Name:    "name",
-			AuxData: map[string]string{"key": "value"},
+			AuxData: map[string]interface{}{"key": "value"},
 		},
 	}
 	mockResultBytes, _ := json.Marshal(mockResult)
@@ -1209,8 +1333,8 @@ func TestRuntimeOnInspectRequested(t *testing.T) {
 			Type:                runtime.ObjectType.Object,
 			Subtype:             runtime.ObjectSubtype.Array,
 			ClassName:           "class-name",
-			Value:               "value",
-			UnserializableValue: runtime.UnserializableValue.Infinity,
+			Value:               json.RawMessage(`"value"`),
+			UnserializableValue: runtime.UnserializableValue.Infinity.String(),
 			Description:         "description",
 			ObjectID:            runtime.RemoteObjectID("remote-object-id"),
 			Preview: &runtime.ObjectPreview{
@@ -184,3 +184,28 @@ func (protocol *SecurityProtocol) OnSecurityStateChanged(
 	)
 	protocol.Socket.AddEventHandler(handler)
 }
+
+/*
+OnVisibleSecurityStateChanged adds a handler to the
+Security.visibleSecurityStateChanged event. Security.visibleSecurityStateChanged
+fires when the security state of the page as shown to the user changed.
+EXPERIMENTAL.
+
+https://chromedevtools.github.io/devtools-protocol/tot/Security/#event-visibleSecurityStateChanged
+*/
+func (protocol *SecurityProtocol) OnVisibleSecurityStateChanged(
+	callback func(event *security.VisibleSecurityStateChangedEvent),
+) {
+	handler := NewEventHandler(
+		"Security.visibleSecurityStateChanged",
+		func(response *Response) {
+			event := &security.VisibleSecurityStateChangedEvent{}
+			json.Unmarshal([]byte(response.Params), event)
+			if nil != response.Error && 0 != response.Error.Code {
+				event.Err = response.Error
+			}
+			callback(event)
+		},
+	)
+	protocol.Socket.AddEventHandler(handler)
+}
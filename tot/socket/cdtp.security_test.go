@@ -301,3 +301,53 @@ func TestSecurityOnSecurityStateChanged(t *testing.T) {
 		t.Errorf("Expected error, got success")
 	}
 }
+
+func TestSecurityOnVisibleSecurityStateChanged(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestSecurityOnVisibleSecurityStateChanged")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	resultChan := make(chan *security.VisibleSecurityStateChangedEvent)
+	mockSocket.Security().OnVisibleSecurityStateChanged(func(eventData *security.VisibleSecurityStateChangedEvent) {
+		resultChan <- eventData
+	})
+	mockResult := &security.VisibleSecurityStateChangedEvent{
+		VisibleSecurityState: &security.VisibleSecurityState{
+			SecurityState:         security.State.InsecureBroken,
+			SecurityStateIssueIDs: []string{"scheme-is-not-cryptographic"},
+		},
+	}
+	mockResultBytes, _ := json.Marshal(mockResult)
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     0,
+		Error:  &Error{},
+		Method: "Security.visibleSecurityStateChanged",
+		Params: mockResultBytes,
+	})
+	result := <-resultChan
+	if mockResult.Err != result.Err {
+		t.Errorf("Expected '%v', got: '%v'", mockResult, result)
+	}
+	if mockResult.VisibleSecurityState.SecurityState != result.VisibleSecurityState.SecurityState {
+		t.Errorf("Expected %s, got %s", mockResult.VisibleSecurityState.SecurityState, result.VisibleSecurityState.SecurityState)
+	}
+
+	resultChan = make(chan *security.VisibleSecurityStateChangedEvent)
+	mockSocket.Security().OnVisibleSecurityStateChanged(func(eventData *security.VisibleSecurityStateChangedEvent) {
+		resultChan <- eventData
+	})
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID: 0,
+		Error: &Error{
+			Code:    1,
+			Data:    []byte(`"error data"`),
+			Message: "error message",
+		},
+		Method: "Security.visibleSecurityStateChanged",
+	})
+	result = <-resultChan
+	if nil == result.Err {
+		t.Errorf("Expected error, got success")
+	}
+}
@@ -269,7 +269,7 @@ func TestTracingOnDataCollected(t *testing.T) {
 		resultChan <- eventData
 	})
 	mockResult := &tracing.DataCollectedEvent{
-		Value: []map[string]string{{"key": "value"}},
+		Value: []map[string]interface{}{{"key": "value"}},
 	}
 	mockResultBytes, _ := json.Marshal(mockResult)
 	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
@@ -18,6 +18,7 @@ func NewMock(socketURL *url.URL) *Socket {
 	socket := &Socket{
 		commandIDMux: &sync.Mutex{},
 		commands:     NewCommandMap(),
+		eventCounts:  map[string]int{},
 		handlers:     NewEventHandlerMap(),
 		mux:          &sync.Mutex{},
 		newSocket:    NewMockWebsocket,
@@ -26,44 +27,7 @@ func NewMock(socketURL *url.URL) *Socket {
 	}
 	log.Debugf("Created socket #%d", socket.socketID)
 
-	socket.accessibility = &AccessibilityProtocol{Socket: socket}
-	socket.animation = &AnimationProtocol{Socket: socket}
-	socket.applicationCache = &ApplicationCacheProtocol{Socket: socket}
-	socket.audits = &AuditsProtocol{Socket: socket}
-	socket.browser = &BrowserProtocol{Socket: socket}
-	socket.cacheStorage = &CacheStorageProtocol{Socket: socket}
-	socket.console = &ConsoleProtocol{Socket: socket}
-	socket.css = &CSSProtocol{Socket: socket}
-	socket.database = &DatabaseProtocol{Socket: socket}
-	socket.debugger = &DebuggerProtocol{Socket: socket}
-	socket.deviceOrientation = &DeviceOrientationProtocol{Socket: socket}
-	socket.domDebugger = &DOMDebuggerProtocol{Socket: socket}
-	socket.domSnapshot = &DOMSnapshotProtocol{Socket: socket}
-	socket.domStorage = &DOMStorageProtocol{Socket: socket}
-	socket.dom = &DOMProtocol{Socket: socket}
-	socket.emulation = &EmulationProtocol{Socket: socket}
-	socket.headlessExperimental = &HeadlessExperimentalProtocol{Socket: socket}
-	socket.heapProfiler = &HeapProfilerProtocol{Socket: socket}
-	socket.indexedDB = &IndexedDBProtocol{Socket: socket}
-	socket.input = &InputProtocol{Socket: socket}
-	socket.io = &IOProtocol{Socket: socket}
-	socket.layerTree = &LayerTreeProtocol{Socket: socket}
-	socket.log = &LogProtocol{Socket: socket}
-	socket.memory = &MemoryProtocol{Socket: socket}
-	socket.network = &NetworkProtocol{Socket: socket}
-	socket.overlay = &OverlayProtocol{Socket: socket}
-	socket.page = &PageProtocol{Socket: socket}
-	socket.performance = &PerformanceProtocol{Socket: socket}
-	socket.profiler = &ProfilerProtocol{Socket: socket}
-	socket.runtime = &RuntimeProtocol{Socket: socket}
-	socket.schema = &SchemaProtocol{Socket: socket}
-	socket.security = &SecurityProtocol{Socket: socket}
-	socket.serviceWorker = &ServiceWorkerProtocol{Socket: socket}
-	socket.storage = &StorageProtocol{Socket: socket}
-	socket.systemInfo = &SystemInfoProtocol{Socket: socket}
-	socket.target = &TargetProtocol{Socket: socket}
-	socket.tethering = &TetheringProtocol{Socket: socket}
-	socket.tracing = &TracingProtocol{Socket: socket}
+	socket.protocols = newProtocols(socket)
 
 	return socket
 }
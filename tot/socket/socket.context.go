@@ -0,0 +1,73 @@
+package socket
+
+import (
+	"context"
+)
+
+/*
+WithContext returns a Socketer that sends commands through socket and stops
+waiting for the response when ctx is done. Commands sent after ctx is done are
+not delivered. The protocol interfaces of the returned socket can be used like
+the interfaces of the wrapped socket:
+
+	result := <-socket.WithContext(ctx, tab.Socket()).Page().Navigate(params)
+*/
+func WithContext(ctx context.Context, socket Socketer) *ContextSocket {
+	contextSocket := &ContextSocket{
+		Socketer: socket,
+		ctx:      ctx,
+	}
+	contextSocket.protocols = newProtocols(contextSocket)
+	return contextSocket
+}
+
+/*
+ContextSocket is a Socketer and Protocoller implementation that binds the
+commands sent through it to a context.
+*/
+type ContextSocket struct {
+	Socketer
+	*protocols
+	ctx context.Context
+}
+
+/*
+SendCommand delivers a command payload to the wrapped socket. If the context
+is done before the socket responds the command fails with the context error and
+the response is discarded when it arrives.
+
+SendCommand is a Socketer implementation.
+*/
+func (socket *ContextSocket) SendCommand(command Commander) chan *Response {
+	responseChan := make(chan *Response, 1)
+	if err := socket.ctx.Err(); nil != err {
+		responseChan <- contextResponse(command, err)
+		return responseChan
+	}
+
+	responses := socket.Socketer.SendCommand(command)
+	go func() {
+		select {
+		case response := <-responses:
+			responseChan <- response
+		case <-socket.ctx.Done():
+			go func() { <-responses }()
+			responseChan <- contextResponse(command, socket.ctx.Err())
+		}
+	}()
+	return responseChan
+}
+
+/*
+contextResponse returns the response of a command that was abandoned because
+its context is done.
+*/
+func contextResponse(command Commander, err error) *Response {
+	return &Response{
+		ID: command.ID(),
+		Error: &Error{
+			Code:    1,
+			Message: err.Error(),
+		},
+	}
+}
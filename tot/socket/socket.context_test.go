@@ -0,0 +1,46 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/io"
+)
+
+func TestContextSocket(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestContextSocket")
+	mockSocket := NewMock(socketURL)
+	mockSocket.Listen()
+	defer mockSocket.Stop()
+
+	params := &io.CloseParams{
+		Handle: io.StreamHandle("stream-handle"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	resultChan := WithContext(ctx, mockSocket).IO().Close(params)
+	mockResultBytes, _ := json.Marshal(&io.CloseResult{})
+	mockSocket.Conn().(*MockChromeWebSocket).AddMockData(&Response{
+		ID:     mockSocket.CurCommandID(),
+		Error:  &Error{},
+		Result: mockResultBytes,
+	})
+	result := <-resultChan
+	if nil != result.Err {
+		t.Errorf("Expected nil, got error: '%s'", result.Err.Error())
+	}
+
+	resultChan = WithContext(ctx, mockSocket).IO().Close(params)
+	cancel()
+	result = <-resultChan
+	if nil == result.Err || !strings.Contains(result.Err.Error(), context.Canceled.Error()) {
+		t.Errorf("Expected context.Canceled, got '%v'", result.Err)
+	}
+
+	result = <-WithContext(ctx, mockSocket).IO().Close(params)
+	if nil == result.Err || !strings.Contains(result.Err.Error(), context.Canceled.Error()) {
+		t.Errorf("Expected context.Canceled, got '%v'", result.Err)
+	}
+}
@@ -37,6 +37,17 @@ type Response struct {
 	Method string          `json:"method"`
 	Params json.RawMessage `json:"params"`
 	Result json.RawMessage `json:"result"`
+
+	// The position of an event in the order events were read from the socket.
+	// Event handlers run concurrently, handlers that depend on the order of
+	// events can restore it from the sequence.
+	Sequence int `json:"-"`
+
+	// The number of events of each method read before an event or a command
+	// response, including the event itself. Events of a method are numbered
+	// by their count, and a response or a later event tells how many events of
+	// a method were read before it, so handlers can wait for all of them.
+	EventCounts map[string]int `json:"-"`
 }
 
 /*
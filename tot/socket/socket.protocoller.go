@@ -1,12 +1,104 @@
 package socket
 
+/*
+protocols holds the protocol interfaces bound to a socket. It is embedded by
+the Protocoller implementations in this package.
+*/
+type protocols struct {
+	accessibility        *AccessibilityProtocol
+	animation            *AnimationProtocol
+	applicationCache     *ApplicationCacheProtocol
+	audits               *AuditsProtocol
+	browser              *BrowserProtocol
+	cacheStorage         *CacheStorageProtocol
+	console              *ConsoleProtocol
+	css                  *CSSProtocol
+	database             *DatabaseProtocol
+	debugger             *DebuggerProtocol
+	deviceOrientation    *DeviceOrientationProtocol
+	domDebugger          *DOMDebuggerProtocol
+	domSnapshot          *DOMSnapshotProtocol
+	domStorage           *DOMStorageProtocol
+	dom                  *DOMProtocol
+	emulation            *EmulationProtocol
+	headlessExperimental *HeadlessExperimentalProtocol
+	heapProfiler         *HeapProfilerProtocol
+	indexedDB            *IndexedDBProtocol
+	input                *InputProtocol
+	io                   *IOProtocol
+	layerTree            *LayerTreeProtocol
+	log                  *LogProtocol
+	memory               *MemoryProtocol
+	network              *NetworkProtocol
+	overlay              *OverlayProtocol
+	page                 *PageProtocol
+	performance          *PerformanceProtocol
+	profiler             *ProfilerProtocol
+	runtime              *RuntimeProtocol
+	schema               *SchemaProtocol
+	security             *SecurityProtocol
+	serviceWorker        *ServiceWorkerProtocol
+	storage              *StorageProtocol
+	systemInfo           *SystemInfoProtocol
+	target               *TargetProtocol
+	tethering            *TetheringProtocol
+	tracing              *TracingProtocol
+}
+
+/*
+newProtocols returns the protocol interfaces for a socket. Commands sent through
+them are delivered with the SendCommand method of the socket.
+*/
+func newProtocols(socket Socketer) *protocols {
+	return &protocols{
+		accessibility:        &AccessibilityProtocol{Socket: socket},
+		animation:            &AnimationProtocol{Socket: socket},
+		applicationCache:     &ApplicationCacheProtocol{Socket: socket},
+		audits:               &AuditsProtocol{Socket: socket},
+		browser:              &BrowserProtocol{Socket: socket},
+		cacheStorage:         &CacheStorageProtocol{Socket: socket},
+		console:              &ConsoleProtocol{Socket: socket},
+		css:                  &CSSProtocol{Socket: socket},
+		database:             &DatabaseProtocol{Socket: socket},
+		debugger:             &DebuggerProtocol{Socket: socket},
+		deviceOrientation:    &DeviceOrientationProtocol{Socket: socket},
+		domDebugger:          &DOMDebuggerProtocol{Socket: socket},
+		domSnapshot:          &DOMSnapshotProtocol{Socket: socket},
+		domStorage:           &DOMStorageProtocol{Socket: socket},
+		dom:                  &DOMProtocol{Socket: socket},
+		emulation:            &EmulationProtocol{Socket: socket},
+		headlessExperimental: &HeadlessExperimentalProtocol{Socket: socket},
+		heapProfiler:         &HeapProfilerProtocol{Socket: socket},
+		indexedDB:            &IndexedDBProtocol{Socket: socket},
+		input:                &InputProtocol{Socket: socket},
+		io:                   &IOProtocol{Socket: socket},
+		layerTree:            &LayerTreeProtocol{Socket: socket},
+		log:                  &LogProtocol{Socket: socket},
+		memory:               &MemoryProtocol{Socket: socket},
+		network:              &NetworkProtocol{Socket: socket},
+		overlay:              &OverlayProtocol{Socket: socket},
+		page:                 &PageProtocol{Socket: socket},
+		performance:          &PerformanceProtocol{Socket: socket},
+		profiler:             &ProfilerProtocol{Socket: socket},
+		runtime:              &RuntimeProtocol{Socket: socket},
+		schema:               &SchemaProtocol{Socket: socket},
+		security:             &SecurityProtocol{Socket: socket},
+		serviceWorker:        &ServiceWorkerProtocol{Socket: socket},
+		storage:              &StorageProtocol{Socket: socket},
+		systemInfo:           &SystemInfoProtocol{Socket: socket},
+		target:               &TargetProtocol{Socket: socket},
+		tethering:            &TetheringProtocol{Socket: socket},
+		tracing:              &TracingProtocol{Socket: socket},
+	}
+}
+
 /*
 Accessibility returns the AccessibilityProtocol instance.
 
 Accessibility is a Protocoller implementation.
 */
-func (socket *Socket) Accessibility() *AccessibilityProtocol {
-	return socket.accessibility
+func (protocols *protocols) Accessibility() *AccessibilityProtocol {
+	return protocols.accessibility
 }
 
 /*
@@ -14,8 +106,8 @@ Animation returns the AnimationProtocol instance.
 
 Animation is a Protocoller implementation.
 */
-func (socket *Socket) Animation() *AnimationProtocol {
-	return socket.animation
+func (protocols *protocols) Animation() *AnimationProtocol {
+	return protocols.animation
 }
 
 /*
@@ -23,8 +115,8 @@ ApplicationCache returns the ApplicationCacheProtocol instance.
 
 ApplicationCache is a Protocoller implementation.
 */
-func (socket *Socket) ApplicationCache() *ApplicationCacheProtocol {
-	return socket.applicationCache
+func (protocols *protocols) ApplicationCache() *ApplicationCacheProtocol {
+	return protocols.applicationCache
 }
 
 /*
@@ -32,8 +124,8 @@ Audits returns the AuditsProtocol instance.
 
 Audits is a Protocoller implementation.
 */
-func (socket *Socket) Audits() *AuditsProtocol {
-	return socket.audits
+func (protocols *protocols) Audits() *AuditsProtocol {
+	return protocols.audits
 }
 
 /*
@@ -41,8 +133,8 @@ Browser returns the BrowserProtocol instance.
 
 Browser is a Protocoller implementation.
 */
-func (socket *Socket) Browser() *BrowserProtocol {
-	return socket.browser
+func (protocols *protocols) Browser() *BrowserProtocol {
+	return protocols.browser
 }
 
 /*
@@ -50,8 +142,8 @@ CacheStorage returns the CacheStorageProtocol instance.
 
 CacheStorage is a Protocoller implementation.
 */
-func (socket *Socket) CacheStorage() *CacheStorageProtocol {
-	return socket.cacheStorage
+func (protocols *protocols) CacheStorage() *CacheStorageProtocol {
+	return protocols.cacheStorage
 }
 
 /*
@@ -59,8 +151,8 @@ Console returns the ConsoleProtocol instance.
 
 Console is a Protocoller implementation.
 */
-func (socket *Socket) Console() *ConsoleProtocol {
-	return socket.console
+func (protocols *protocols) Console() *ConsoleProtocol {
+	return protocols.console
 }
 
 /*
@@ -68,8 +160,8 @@ CSS returns the CSSProtocol instance.
 
 CSS is a Protocoller implementation.
 */
-func (socket *Socket) CSS() *CSSProtocol {
-	return socket.css
+func (protocols *protocols) CSS() *CSSProtocol {
+	return protocols.css
 }
 
 /*
@@ -77,8 +169,8 @@ Database returns the DatabaseProtocol instance.
 
 Database is a Protocoller implementation.
 */
-func (socket *Socket) Database() *DatabaseProtocol {
-	return socket.database
+func (protocols *protocols) Database() *DatabaseProtocol {
+	return protocols.database
 }
 
 /*
@@ -86,8 +178,8 @@ Debugger returns the DebuggerProtocol instance.
 
 Debugger is a Protocoller implementation.
 */
-func (socket *Socket) Debugger() *DebuggerProtocol {
-	return socket.debugger
+func (protocols *protocols) Debugger() *DebuggerProtocol {
+	return protocols.debugger
 }
 
 /*
@@ -95,8 +187,8 @@ DeviceOrientation returns the DeviceOrientationProtocol instance.
 
 DeviceOrientation is a Protocoller implementation.
 */
-func (socket *Socket) DeviceOrientation() *DeviceOrientationProtocol {
-	return socket.deviceOrientation
+func (protocols *protocols) DeviceOrientation() *DeviceOrientationProtocol {
+	return protocols.deviceOrientation
 }
 
 /*
@@ -104,8 +196,8 @@ DOMDebugger returns the DOMDebuggerProtocol instance.
 
 DOMDebugger is a Protocoller implementation.
 */
-func (socket *Socket) DOMDebugger() *DOMDebuggerProtocol {
-	return socket.domDebugger
+func (protocols *protocols) DOMDebugger() *DOMDebuggerProtocol {
+	return protocols.domDebugger
 }
 
 /*
@@ -113,8 +205,8 @@ DOMSnapshot returns the DOMSnapshotProtocol instance.
 
 DOMSnapshot is a Protocoller implementation.
 */
-func (socket *Socket) DOMSnapshot() *DOMSnapshotProtocol {
-	return socket.domSnapshot
+func (protocols *protocols) DOMSnapshot() *DOMSnapshotProtocol {
+	return protocols.domSnapshot
 }
 
 /*
@@ -122,8 +214,8 @@ DOMStorage returns the DOMStorageProtocol instance.
 
 DOMStorage is a Protocoller implementation.
 */
-func (socket *Socket) DOMStorage() *DOMStorageProtocol {
-	return socket.domStorage
+func (protocols *protocols) DOMStorage() *DOMStorageProtocol {
+	return protocols.domStorage
 }
 
 /*
@@ -131,8 +223,8 @@ DOM returns the DOMProtocol instance.
 
 DOM is a Protocoller implementation.
 */
-func (socket *Socket) DOM() *DOMProtocol {
-	return socket.dom
+func (protocols *protocols) DOM() *DOMProtocol {
+	return protocols.dom
 }
 
 /*
@@ -140,8 +232,8 @@ Emulation returns the EmulationProtocol instance.
 
 Emulation is a Protocoller implementation.
 */
-func (socket *Socket) Emulation() *EmulationProtocol {
-	return socket.emulation
+func (protocols *protocols) Emulation() *EmulationProtocol {
+	return protocols.emulation
 }
 
 /*
@@ -149,8 +241,8 @@ HeadlessExperimental returns the HeadlessExperimentalProtocol instance.
 
 HeadlessExperimental is a Protocoller implementation.
 */
-func (socket *Socket) HeadlessExperimental() *HeadlessExperimentalProtocol {
-	return socket.headlessExperimental
+func (protocols *protocols) HeadlessExperimental() *HeadlessExperimentalProtocol {
+	return protocols.headlessExperimental
 }
 
 /*
@@ -158,8 +250,8 @@ HeapProfiler returns the HeapProfilerProtocol instance.
 
 HeapProfiler is a Protocoller implementation.
 */
-func (socket *Socket) HeapProfiler() *HeapProfilerProtocol {
-	return socket.heapProfiler
+func (protocols *protocols) HeapProfiler() *HeapProfilerProtocol {
+	return protocols.heapProfiler
 }
 
 /*
@@ -167,8 +259,8 @@ IndexedDB returns the IndexedDBProtocol instance.
 
 IndexedDB is a Protocoller implementation.
 */
-func (socket *Socket) IndexedDB() *IndexedDBProtocol {
-	return socket.indexedDB
+func (protocols *protocols) IndexedDB() *IndexedDBProtocol {
+	return protocols.indexedDB
 }
 
 /*
@@ -176,8 +268,8 @@ Input returns the InputProtocol instance.
 
 Input is a Protocoller implementation.
 */
-func (socket *Socket) Input() *InputProtocol {
-	return socket.input
+func (protocols *protocols) Input() *InputProtocol {
+	return protocols.input
 }
 
 /*
@@ -185,8 +277,8 @@ IO returns the IOProtocol instance.
 
 IO is a Protocoller implementation.
 */
-func (socket *Socket) IO() *IOProtocol {
-	return socket.io
+func (protocols *protocols) IO() *IOProtocol {
+	return protocols.io
 }
 
 /*
@@ -194,8 +286,8 @@ LayerTree returns the LayerTreeProtocol instance.
 
 LayerTree is a Protocoller implementation.
 */
-func (socket *Socket) LayerTree() *LayerTreeProtocol {
-	return socket.layerTree
+func (protocols *protocols) LayerTree() *LayerTreeProtocol {
+	return protocols.layerTree
 }
 
 /*
@@ -203,8 +295,8 @@ Log returns the LogProtocol instance.
 
 Log is a Protocoller implementation.
 */
-func (socket *Socket) Log() *LogProtocol {
-	return socket.log
+func (protocols *protocols) Log() *LogProtocol {
+	return protocols.log
 }
 
 /*
@@ -212,8 +304,8 @@ Memory returns the MemoryProtocol instance.
 
 Memory is a Protocoller implementation.
 */
-func (socket *Socket) Memory() *MemoryProtocol {
-	return socket.memory
+func (protocols *protocols) Memory() *MemoryProtocol {
+	return protocols.memory
 }
 
 /*
@@ -221,8 +313,8 @@ Network returns the NetworkProtocol instance.
 
 Network is a Protocoller implementation.
 */
-func (socket *Socket) Network() *NetworkProtocol {
-	return socket.network
+func (protocols *protocols) Network() *NetworkProtocol {
+	return protocols.network
 }
 
 /*
@@ -230,8 +322,8 @@ Overlay returns the OverlayProtocol instance.
 
 Overlay is a Protocoller implementation.
 */
-func (socket *Socket) Overlay() *OverlayProtocol {
-	return socket.overlay
+func (protocols *protocols) Overlay() *OverlayProtocol {
+	return protocols.overlay
 }
 
 /*
@@ -239,8 +331,8 @@ Page returns the PageProtocol instance.
 
 Page is a Protocoller implementation.
 */
-func (socket *Socket) Page() *PageProtocol {
-	return socket.page
+func (protocols *protocols) Page() *PageProtocol {
+	return protocols.page
 }
 
 /*
@@ -248,8 +340,8 @@ Performance returns the PerformanceProtocol instance.
 
 Performance is a Protocoller implementation.
 */
-func (socket *Socket) Performance() *PerformanceProtocol {
-	return socket.performance
+func (protocols *protocols) Performance() *PerformanceProtocol {
+	return protocols.performance
 }
 
 /*
@@ -257,8 +349,8 @@ Profiler returns the ProfilerProtocol instance.
 
 Profiler is a Protocoller implementation.
 */
-func (socket *Socket) Profiler() *ProfilerProtocol {
-	return socket.profiler
+func (protocols *protocols) Profiler() *ProfilerProtocol {
+	return protocols.profiler
 }
 
 /*
@@ -266,8 +358,8 @@ Runtime returns the RuntimeProtocol instance.
 
 Runtime is a Protocoller implementation.
 */
-func (socket *Socket) Runtime() *RuntimeProtocol {
-	return socket.runtime
+func (protocols *protocols) Runtime() *RuntimeProtocol {
+	return protocols.runtime
 }
 
 /*
@@ -275,8 +367,8 @@ Schema returns the SchemaProtocol instance.
 
 Schema is a Protocoller implementation.
 */
-func (socket *Socket) Schema() *SchemaProtocol {
-	return socket.schema
+func (protocols *protocols) Schema() *SchemaProtocol {
+	return protocols.schema
 }
 
 /*
@@ -284,8 +376,8 @@ Security returns the SecurityProtocol instance.
 
 Security is a Protocoller implementation.
 */
-func (socket *Socket) Security() *SecurityProtocol {
-	return socket.security
+func (protocols *protocols) Security() *SecurityProtocol {
+	return protocols.security
 }
 
 /*
@@ -293,8 +385,8 @@ ServiceWorker returns the ServiceWorkerProtocol instance.
 
 ServiceWorker is a Protocoller implementation.
 */
-func (socket *Socket) ServiceWorker() *ServiceWorkerProtocol {
-	return socket.serviceWorker
+func (protocols *protocols) ServiceWorker() *ServiceWorkerProtocol {
+	return protocols.serviceWorker
 }
 
 /*
@@ -302,8 +394,8 @@ Storage returns the StorageProtocol instance.
 
 Storage is a Protocoller implementation.
 */
-func (socket *Socket) Storage() *StorageProtocol {
-	return socket.storage
+func (protocols *protocols) Storage() *StorageProtocol {
+	return protocols.storage
 }
 
 /*
@@ -311,8 +403,8 @@ SystemInfo returns the SystemInfoProtocol instance.
 
 SystemInfo is a Protocoller implementation.
 */
-func (socket *Socket) SystemInfo() *SystemInfoProtocol {
-	return socket.systemInfo
+func (protocols *protocols) SystemInfo() *SystemInfoProtocol {
+	return protocols.systemInfo
 }
 
 /*
@@ -320,8 +412,8 @@ Target returns the TargetProtocol instance.
 
 Target is a Protocoller implementation.
 */
-func (socket *Socket) Target() *TargetProtocol {
-	return socket.target
+func (protocols *protocols) Target() *TargetProtocol {
+	return protocols.target
 }
 
 /*
@@ -329,8 +421,8 @@ Tethering returns the TetheringProtocol instance.
 
 Tethering is a Protocoller implementation.
 */
-func (socket *Socket) Tethering() *TetheringProtocol {
-	return socket.tethering
+func (protocols *protocols) Tethering() *TetheringProtocol {
+	return protocols.tethering
 }
 
 /*
@@ -338,6 +430,6 @@ Tracing returns the TracingProtocol instance.
 
 Tracing is a Protocoller implementation.
 */
-func (socket *Socket) Tracing() *TracingProtocol {
-	return socket.tracing
+func (protocols *protocols) Tracing() *TracingProtocol {
+	return protocols.tracing
 }
@@ -21,6 +21,7 @@ func New(url *url.URL) *Socket {
 		commandIDMux: &sync.Mutex{},
 		commands:     NewCommandMap(),
 		errCh:        make(chan error, 3),
+		eventCounts:  map[string]int{},
 		handlers:     NewEventHandlerMap(),
 		mux:          &sync.Mutex{},
 		newSocket:    NewWebsocket,
@@ -29,44 +30,7 @@ func New(url *url.URL) *Socket {
 	}
 
 	// Init the protocol interfaces for the API.
-	socket.accessibility = &AccessibilityProtocol{Socket: socket}
-	socket.animation = &AnimationProtocol{Socket: socket}
-	socket.applicationCache = &ApplicationCacheProtocol{Socket: socket}
-	socket.audits = &AuditsProtocol{Socket: socket}
-	socket.browser = &BrowserProtocol{Socket: socket}
-	socket.cacheStorage = &CacheStorageProtocol{Socket: socket}
-	socket.console = &ConsoleProtocol{Socket: socket}
-	socket.css = &CSSProtocol{Socket: socket}
-	socket.database = &DatabaseProtocol{Socket: socket}
-	socket.debugger = &DebuggerProtocol{Socket: socket}
-	socket.deviceOrientation = &DeviceOrientationProtocol{Socket: socket}
-	socket.domDebugger = &DOMDebuggerProtocol{Socket: socket}
-	socket.domSnapshot = &DOMSnapshotProtocol{Socket: socket}
-	socket.domStorage = &DOMStorageProtocol{Socket: socket}
-	socket.dom = &DOMProtocol{Socket: socket}
-	socket.emulation = &EmulationProtocol{Socket: socket}
-	socket.headlessExperimental = &HeadlessExperimentalProtocol{Socket: socket}
-	socket.heapProfiler = &HeapProfilerProtocol{Socket: socket}
-	socket.indexedDB = &IndexedDBProtocol{Socket: socket}
-	socket.input = &InputProtocol{Socket: socket}
-	socket.io = &IOProtocol{Socket: socket}
-	socket.layerTree = &LayerTreeProtocol{Socket: socket}
-	socket.log = &LogProtocol{Socket: socket}
-	socket.memory = &MemoryProtocol{Socket: socket}
-	socket.network = &NetworkProtocol{Socket: socket}
-	socket.overlay = &OverlayProtocol{Socket: socket}
-	socket.page = &PageProtocol{Socket: socket}
-	socket.performance = &PerformanceProtocol{Socket: socket}
-	socket.profiler = &ProfilerProtocol{Socket: socket}
-	socket.runtime = &RuntimeProtocol{Socket: socket}
-	socket.schema = &SchemaProtocol{Socket: socket}
-	socket.security = &SecurityProtocol{Socket: socket}
-	socket.serviceWorker = &ServiceWorkerProtocol{Socket: socket}
-	socket.storage = &StorageProtocol{Socket: socket}
-	socket.systemInfo = &SystemInfoProtocol{Socket: socket}
-	socket.target = &TargetProtocol{Socket: socket}
-	socket.tethering = &TetheringProtocol{Socket: socket}
-	socket.tracing = &TracingProtocol{Socket: socket}
+	socket.protocols = newProtocols(socket)
 
 	socket.Listen()
 
@@ -101,6 +65,8 @@ type Socket struct {
 	conn         WebSocketer
 	connected    bool
 	errCh        chan error
+	eventCounts  map[string]int
+	eventSeq     int
 	handlers     EventHandlerMapper
 	listenCh     chan bool
 	listening    bool
@@ -110,44 +76,7 @@ type Socket struct {
 	url          *url.URL
 
 	// Protocol interfaces for the API.
-	accessibility        *AccessibilityProtocol
-	animation            *AnimationProtocol
-	applicationCache     *ApplicationCacheProtocol
-	audits               *AuditsProtocol
-	browser              *BrowserProtocol
-	cacheStorage         *CacheStorageProtocol
-	console              *ConsoleProtocol
-	css                  *CSSProtocol
-	database             *DatabaseProtocol
-	debugger             *DebuggerProtocol
-	deviceOrientation    *DeviceOrientationProtocol
-	domDebugger          *DOMDebuggerProtocol
-	domSnapshot          *DOMSnapshotProtocol
-	domStorage           *DOMStorageProtocol
-	dom                  *DOMProtocol
-	emulation            *EmulationProtocol
-	headlessExperimental *HeadlessExperimentalProtocol
-	heapProfiler         *HeapProfilerProtocol
-	indexedDB            *IndexedDBProtocol
-	input                *InputProtocol
-	io                   *IOProtocol
-	layerTree            *LayerTreeProtocol
-	log                  *LogProtocol
-	memory               *MemoryProtocol
-	network              *NetworkProtocol
-	overlay              *OverlayProtocol
-	page                 *PageProtocol
-	performance          *PerformanceProtocol
-	profiler             *ProfilerProtocol
-	runtime              *RuntimeProtocol
-	schema               *SchemaProtocol
-	security             *SecurityProtocol
-	serviceWorker        *ServiceWorkerProtocol
-	storage              *StorageProtocol
-	systemInfo           *SystemInfoProtocol
-	target               *TargetProtocol
-	tethering            *TetheringProtocol
-	tracing              *TracingProtocol
+	*protocols
 }
 
 /*
@@ -178,6 +107,9 @@ handleResponse receives the responses to requests sent to the websocket
 connection.
 */
 func (socket *Socket) handleResponse(response *Response) {
+	// Responses are read by the goroutine that reads events.
+	response.EventCounts = socket.countEvents()
+
 	// Log a message on error
 	if command, err := socket.commands.Get(response.ID); nil != err {
 		err = errs.Wrap(err, codes.SocketCmdHandlerNotFound, fmt.Sprintf("command #%d not found", response.ID))
@@ -209,6 +141,12 @@ func (socket *Socket) handleEvent(
 			Error("Chrome has crashed!")
 	}
 
+	// Events are read by a single goroutine.
+	socket.eventSeq++
+	socket.eventCounts[response.Method]++
+	response.Sequence = socket.eventSeq
+	response.EventCounts = socket.countEvents()
+
 	if handlers, err := socket.handlers.Get(response.Method); nil != err {
 		log.WithFields(log.Fields{"error": err, "socketID": socket.socketID}).
 			Debug(err)
@@ -221,6 +159,17 @@ func (socket *Socket) handleEvent(
 	}
 }
 
+/*
+countEvents returns a copy of the number of events read for each method.
+*/
+func (socket *Socket) countEvents() map[string]int {
+	counts := make(map[string]int, len(socket.eventCounts))
+	for method, count := range socket.eventCounts {
+		counts[method] = count
+	}
+	return counts
+}
+
 /*
 handleUnknown receives all other socket responses.
 */
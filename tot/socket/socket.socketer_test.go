@@ -163,6 +163,46 @@ func TestRemoveEventHandler(t *testing.T) {
 	}
 }
 
+func TestEventSequence(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestEventSequence")
+	socket := New(socketURL)
+
+	sequence := make(chan int, 2)
+	socket.AddEventHandler(NewEventHandler("Test.event", func(response *Response) {
+		sequence <- response.Sequence
+	}))
+	socket.handleEvent(&Response{Method: "Test.event"})
+	socket.handleEvent(&Response{Method: "Test.other"})
+	socket.handleEvent(&Response{Method: "Test.event"})
+
+	received := map[int]bool{<-sequence: true, <-sequence: true}
+	if !received[1] || !received[3] {
+		t.Errorf("Expected events to be numbered in the order they were read, received %v", received)
+	}
+}
+
+func TestEventCounts(t *testing.T) {
+	socketURL, _ := url.Parse("https://test:9222/TestEventCounts")
+	socket := New(socketURL)
+
+	first := &Response{Method: "Test.event"}
+	second := &Response{Method: "Test.event"}
+	other := &Response{Method: "Test.other"}
+	socket.handleEvent(first)
+	socket.handleEvent(other)
+	socket.handleEvent(second)
+	if 1 != first.EventCounts["Test.event"] || 2 != second.EventCounts["Test.event"] || 1 != second.EventCounts["Test.other"] {
+		t.Errorf("Expected events to be counted by method, received %v and %v", first.EventCounts, second.EventCounts)
+	}
+
+	response := &Response{ID: 1}
+	socket.handleResponse(response)
+	socket.handleEvent(&Response{Method: "Test.event"})
+	if 2 != response.EventCounts["Test.event"] {
+		t.Errorf("Expected the response to count the events read before it, received %v", response.EventCounts)
+	}
+}
+
 //func TestReadJSONError(t *testing.T) {
 //	socketURL, _ := url.Parse("https://test:9222/TestReadJSONError")
 //	mockSocket := NewMock(socketURL)
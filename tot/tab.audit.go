@@ -0,0 +1,592 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/audit"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/dom"
+	cdtplog "github.com/mkenney/go-chrome/tot/log"
+	"github.com/mkenney/go-chrome/tot/network"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/security"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+const (
+	// auditTextLimit is the number of text elements checked for contrast.
+	auditTextLimit = 300
+
+	// auditAttribute marks the text elements whose background colors are
+	// requested.
+	auditAttribute = "data-go-chrome-audit"
+)
+
+/*
+auditScript collects the render-blocking resources, the images and the text
+elements of the document. Text elements are marked with auditAttribute and
+returned in document order.
+*/
+var auditScript = fmt.Sprintf(`(() => {
+	const selector = (el) => {
+		const parts = [];
+		for (; el && 1 === el.nodeType && parts.length < 4; el = el.parentElement) {
+			if (el.id) {
+				parts.unshift('#' + CSS.escape(el.id));
+				break;
+			}
+			let part = el.localName;
+			const siblings = el.parentElement ? [...el.parentElement.children].filter((s) => s.localName === el.localName) : [];
+			if (siblings.length > 1) {
+				part += ':nth-of-type(' + (siblings.indexOf(el) + 1) + ')';
+			}
+			parts.unshift(part);
+		}
+		return parts.join(' > ');
+	};
+
+	const blocking = [];
+	for (const el of document.head ? document.head.querySelectorAll('script[src], link[rel~="stylesheet"][href]') : []) {
+		if ('script' === el.localName) {
+			if (!el.async && !el.defer && 'module' !== el.type) blocking.push(el.src);
+		} else if (!el.disabled && (!el.media || matchMedia(el.media).matches)) {
+			blocking.push(el.href);
+		}
+	}
+
+	const images = [...document.images].map((img) => {
+		const rect = img.getBoundingClientRect();
+		return {
+			url: img.currentSrc || img.src,
+			selector: selector(img),
+			hasAlt: img.hasAttribute('alt'),
+			alt: img.getAttribute('alt') || '',
+			presentational: ['none', 'presentation'].includes(img.getAttribute('role')),
+			naturalWidth: img.naturalWidth,
+			naturalHeight: img.naturalHeight,
+			displayWidth: rect.width,
+			displayHeight: rect.height,
+		};
+	});
+
+	const elements = [];
+	const seen = new Set();
+	const walker = document.createTreeWalker(document.body || document.documentElement, NodeFilter.SHOW_TEXT);
+	for (let node = walker.nextNode(); node && elements.length < %d; node = walker.nextNode()) {
+		const el = node.parentElement;
+		if (!el || seen.has(el) || !node.data.trim() || ['script', 'style', 'noscript', 'template'].includes(el.localName)) continue;
+		seen.add(el);
+		const style = getComputedStyle(el);
+		if ('visible' !== style.visibility || 0 === el.getClientRects().length) continue;
+		elements.push([el, {
+			selector: selector(el),
+			text: node.data.trim().slice(0, 60),
+			color: style.color,
+			fontSize: parseFloat(style.fontSize),
+			fontWeight: parseInt(style.fontWeight, 10) || 400,
+		}]);
+	}
+	elements.sort((a, b) => a[0].compareDocumentPosition(b[0]) & Node.DOCUMENT_POSITION_FOLLOWING ? -1 : 1);
+	const text = elements.map(([el, info]) => {
+		el.setAttribute('%s', '');
+		return info;
+	});
+
+	return {url: location.href, devicePixelRatio: window.devicePixelRatio, blocking, images, text};
+})()`, auditTextLimit, auditAttribute)
+
+/*
+auditDocument is the result of auditScript. Images and text elements are
+decoded by field name.
+*/
+type auditDocument struct {
+	URL              string         `json:"url"`
+	DevicePixelRatio float64        `json:"devicePixelRatio"`
+	Blocking         []string       `json:"blocking"`
+	Images           []*audit.Image `json:"images"`
+	Text             []*audit.Text  `json:"text"`
+}
+
+/*
+auditRecord is an event recorded by the audit. Event handlers run
+concurrently, records are applied in the order of their sequence numbers when
+the audit runs.
+*/
+type auditRecord struct {
+	apply    func()
+	method   string
+	number   int
+	sequence int
+}
+
+/*
+Auditor records the resources, console messages and coverage of a page and
+audits it.
+*/
+type Auditor struct {
+	coverage  *Coverage
+	first     map[string]int
+	handlers  []socket.EventHandler
+	messages  []*audit.Message
+	mux       sync.Mutex
+	recorded  chan struct{}
+	records   []*auditRecord
+	requests  map[string]*audit.Resource
+	resources []*audit.Resource
+	ran       bool
+	security  string
+	stopped   bool
+	tab       *Tab
+}
+
+/*
+StartAudit starts recording the network requests, console messages, security
+state and coverage of the tab. Start the audit before navigating to the page,
+resources loaded earlier are not audited.
+
+	auditor, err := tab.StartAudit(ctx)
+	...
+	// navigate and wait for the page to load
+	...
+	report, err := auditor.Run(ctx)
+	...
+	err = report.WriteJSON(file)
+*/
+func (tab *Tab) StartAudit(ctx context.Context) (*Auditor, error) {
+	auditor := &Auditor{
+		recorded: make(chan struct{}),
+		requests: map[string]*audit.Resource{},
+		tab:      tab,
+	}
+	for name, decode := range map[string]func(params []byte) (func(), error){
+		"Network.requestWillBeSent": func(params []byte) (func(), error) {
+			event := &network.RequestWillBeSentEvent{}
+			return func() { auditor.requestWillBeSent(event) }, json.Unmarshal(params, event)
+		},
+		"Network.responseReceived": func(params []byte) (func(), error) {
+			event := &network.ResponseReceivedEvent{}
+			return func() { auditor.responseReceived(event) }, json.Unmarshal(params, event)
+		},
+		"Network.loadingFinished": func(params []byte) (func(), error) {
+			event := &network.LoadingFinishedEvent{}
+			return func() { auditor.loadingFinished(event) }, json.Unmarshal(params, event)
+		},
+		"Log.entryAdded": func(params []byte) (func(), error) {
+			event := &cdtplog.EntryAddedEvent{}
+			return func() { auditor.entryAdded(event) }, json.Unmarshal(params, event)
+		},
+		"Runtime.consoleAPICalled": func(params []byte) (func(), error) {
+			event := &runtime.ConsoleAPICalledEvent{}
+			return func() { auditor.consoleAPICalled(event) }, json.Unmarshal(params, event)
+		},
+		"Runtime.exceptionThrown": func(params []byte) (func(), error) {
+			event := &runtime.ExceptionThrownEvent{}
+			return func() { auditor.exceptionThrown(event) }, json.Unmarshal(params, event)
+		},
+		"Security.securityStateChanged": func(params []byte) (func(), error) {
+			event := &security.StateChangedEvent{}
+			return func() { auditor.security = event.State.String() }, json.Unmarshal(params, event)
+		},
+		"Security.visibleSecurityStateChanged": func(params []byte) (func(), error) {
+			event := &security.VisibleSecurityStateChangedEvent{}
+			return func() {
+				if nil != event.VisibleSecurityState {
+					auditor.security = event.VisibleSecurityState.SecurityState.String()
+				}
+			}, json.Unmarshal(params, event)
+		},
+	} {
+		name, decode := name, decode
+		auditor.handlers = append(auditor.handlers, socket.NewEventHandler(name, func(response *socket.Response) {
+			apply, err := decode([]byte(response.Params))
+			if nil != err {
+				log.Errorf("invalid %s event: %s", name, err)
+				apply = func() {}
+			}
+			auditor.record(&auditRecord{
+				apply:    apply,
+				method:   name,
+				number:   response.EventCounts[name],
+				sequence: response.Sequence,
+			})
+		}))
+	}
+	for _, handler := range auditor.handlers {
+		tab.AddEventHandler(handler)
+	}
+
+	// Events read before recording starts may not reach the handlers, the
+	// events counted by the Network.enable response are not audited.
+	enabled, err := sendCommand(ctx, tab.Socket(), "Network.enable", &network.EnableParams{})
+	if nil != err {
+		auditor.removeHandlers()
+		return nil, errs.Wrap(err, codes.AuditFailed, "Network.enable failed")
+	}
+	auditor.mux.Lock()
+	auditor.first = enabled.EventCounts
+	auditor.mux.Unlock()
+
+	// Runtime is left enabled, evaluations and bindings depend on it.
+	for _, command := range []*protocolCommand{
+		&protocolCommand{"Log.enable", func(protocol socket.Protocoller) error {
+			return (<-protocol.Log().Enable()).Err
+		}},
+		&protocolCommand{"Runtime.enable", func(protocol socket.Protocoller) error {
+			return (<-protocol.Runtime().Enable()).Err
+		}},
+		&protocolCommand{"Security.enable", func(protocol socket.Protocoller) error {
+			return (<-protocol.Security().Enable()).Err
+		}},
+	} {
+		if err := command.send(tab.withContext(ctx)); nil != err {
+			auditor.removeHandlers()
+			return nil, errs.Wrap(err, codes.AuditFailed, command.method+" failed")
+		}
+	}
+	cov, err := tab.StartCoverage(ctx, nil)
+	if nil != err {
+		auditor.removeHandlers()
+		return nil, errs.Wrap(err, codes.AuditFailed, "could not start coverage")
+	}
+	auditor.coverage = cov
+	return auditor, nil
+}
+
+/*
+record records an event unless recording stopped or the event was sent before
+recording started.
+*/
+func (auditor *Auditor) record(record *auditRecord) {
+	auditor.mux.Lock()
+	defer auditor.mux.Unlock()
+	if auditor.stopped || (nil != auditor.first && record.number <= auditor.first[record.method]) {
+		return
+	}
+	auditor.records = append(auditor.records, record)
+	close(auditor.recorded)
+	auditor.recorded = make(chan struct{})
+}
+
+/*
+replay applies the recorded events counted by last in the order they were
+sent and stops recording. The caller must hold the lock.
+*/
+func (auditor *Auditor) replay(last map[string]int) {
+	auditor.stopped = true
+	sort.SliceStable(auditor.records, func(a, b int) bool {
+		return auditor.records[a].sequence < auditor.records[b].sequence
+	})
+	for _, record := range auditor.records {
+		if record.number > auditor.first[record.method] && record.number <= last[record.method] {
+			record.apply()
+		}
+	}
+	auditor.records = nil
+}
+
+/*
+wait waits until the events counted by last that were sent after recording
+started are recorded.
+*/
+func (auditor *Auditor) wait(ctx context.Context, last map[string]int) error {
+	for {
+		auditor.mux.Lock()
+		recorded := map[string]int{}
+		for _, record := range auditor.records {
+			if record.number > auditor.first[record.method] && record.number <= last[record.method] {
+				recorded[record.method]++
+			}
+		}
+		complete := true
+		for _, handler := range auditor.handlers {
+			if recorded[handler.Name()] < last[handler.Name()]-auditor.first[handler.Name()] {
+				complete = false
+			}
+		}
+		changed := auditor.recorded
+		auditor.mux.Unlock()
+		if complete {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+requestWillBeSent records a resource. Redirects reuse the request ID, the
+redirected request is recorded as a new resource.
+*/
+func (auditor *Auditor) requestWillBeSent(event *network.RequestWillBeSentEvent) {
+	if nil == event.Request {
+		return
+	}
+	resource := &audit.Resource{URL: event.Request.URL, Type: event.Type.String()}
+	auditor.requests[string(event.RequestID)] = resource
+	auditor.resources = append(auditor.resources, resource)
+}
+
+/*
+responseReceived records the status and MIME type of a resource.
+*/
+func (auditor *Auditor) responseReceived(event *network.ResponseReceivedEvent) {
+	if resource, ok := auditor.requests[string(event.RequestID)]; ok && nil != event.Response {
+		resource.Status = event.Response.Status
+		resource.MimeType = event.Response.MimeType
+	}
+}
+
+/*
+loadingFinished records the transfer size of a resource.
+*/
+func (auditor *Auditor) loadingFinished(event *network.LoadingFinishedEvent) {
+	if resource, ok := auditor.requests[string(event.RequestID)]; ok {
+		resource.TransferSize = int(event.EncodedDataLength)
+	}
+}
+
+/*
+entryAdded records a log entry.
+*/
+func (auditor *Auditor) entryAdded(event *cdtplog.EntryAddedEvent) {
+	if nil == event.Entry {
+		return
+	}
+	auditor.messages = append(auditor.messages, &audit.Message{
+		Source: event.Entry.Source.String(),
+		Level:  event.Entry.Level.String(),
+		Text:   event.Entry.Text,
+		URL:    event.Entry.URL,
+		Line:   event.Entry.LineNumber,
+	})
+}
+
+/*
+consoleAPICalled records console errors, failed assertions and warnings.
+*/
+func (auditor *Auditor) consoleAPICalled(event *runtime.ConsoleAPICalledEvent) {
+	level := map[runtime.CallTypeEnum]string{
+		runtime.CallType.Error:   "error",
+		runtime.CallType.Assert:  "error",
+		runtime.CallType.Warning: "warning",
+	}[event.Type]
+	if "" == level {
+		return
+	}
+	message := &audit.Message{Source: "console-api", Level: level, Text: consoleText(event.Args)}
+	if nil != event.StackTrace && len(event.StackTrace.CallFrames) > 0 {
+		frame := event.StackTrace.CallFrames[0]
+		message.URL, message.Line = frame.URL, frame.LineNumber+1
+	}
+	auditor.messages = append(auditor.messages, message)
+}
+
+/*
+exceptionThrown records an uncaught exception.
+*/
+func (auditor *Auditor) exceptionThrown(event *runtime.ExceptionThrownEvent) {
+	if nil == event.ExceptionDetails {
+		return
+	}
+	jsErr := newJSError(event.ExceptionDetails)
+	message := &audit.Message{
+		Source: "exception",
+		Level:  "error",
+		Text:   strings.SplitN(jsErr.Error(), "\n", 2)[0],
+		URL:    jsErr.URL,
+		Line:   jsErr.LineNumber,
+	}
+	if "" == message.URL && nil != jsErr.StackTrace && len(jsErr.StackTrace.CallFrames) > 0 {
+		frame := jsErr.StackTrace.CallFrames[0]
+		message.URL, message.Line = frame.URL, frame.LineNumber+1
+	}
+	auditor.messages = append(auditor.messages, message)
+}
+
+/*
+consoleText formats the arguments of a console call.
+*/
+func consoleText(args []*runtime.RemoteObject) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		var text string
+		switch {
+		case runtime.ObjectType.String == arg.Type && nil == json.Unmarshal(arg.Value, &text):
+		case "" != arg.Description:
+			text = arg.Description
+		case 0 != len(arg.Value):
+			text = string(arg.Value)
+		case "" != arg.UnserializableValue:
+			text = arg.UnserializableValue
+		default:
+			text = arg.Type.String()
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+/*
+Run stops recording, inspects the document and audits the page. If no audits
+are given audit.Default runs. An auditor runs once.
+*/
+func (auditor *Auditor) Run(ctx context.Context, audits ...*audit.Audit) (*audit.Report, error) {
+	auditor.mux.Lock()
+	if auditor.ran {
+		auditor.mux.Unlock()
+		return nil, errs.New(codes.AuditFailed, "the audit has run")
+	}
+	auditor.ran = true
+	auditor.mux.Unlock()
+	defer auditor.stop(ctx)
+
+	// The recorded domains are disabled first, the last response counts the
+	// events sent before recording stopped.
+	var disabled *socket.Response
+	var err error
+	for _, method := range []string{"Network.disable", "Log.disable", "Security.disable"} {
+		response, disableErr := sendCommand(ctx, auditor.tab.Socket(), method, nil)
+		if nil != disableErr && nil == err {
+			err = errs.Wrap(disableErr, codes.AuditFailed, method+" failed")
+		}
+		disabled = response
+	}
+	if nil != err {
+		return nil, err
+	}
+	if err := auditor.wait(ctx, disabled.EventCounts); nil != err {
+		return nil, err
+	}
+
+	// Coverage is stopped first so that the inspection isn't covered.
+	report, err := auditor.coverage.Stop(ctx)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.AuditFailed, "could not take coverage")
+	}
+	document := &auditDocument{}
+	if err := auditor.tab.Eval(ctx, auditScript, document); nil != err {
+		return nil, errs.Wrap(err, codes.AuditFailed, "could not inspect the document")
+	}
+	if err := auditor.backgrounds(ctx, document.Text); nil != err {
+		return nil, err
+	}
+
+	auditor.mux.Lock()
+	defer auditor.mux.Unlock()
+	auditor.replay(disabled.EventCounts)
+	page := &audit.Page{
+		URL:              document.URL,
+		SecurityState:    auditor.security,
+		DevicePixelRatio: document.DevicePixelRatio,
+		Resources:        auditor.resources,
+		Coverage:         report,
+		Images:           document.Images,
+		Text:             document.Text,
+		Console:          auditor.messages,
+	}
+	for _, url := range document.Blocking {
+		resource := page.Resource(url)
+		if nil == resource {
+			// Resources loaded before the audit started.
+			resource = &audit.Resource{URL: url}
+			page.Resources = append(page.Resources, resource)
+		}
+		resource.RenderBlocking = true
+	}
+	return audit.Run(page, audits...), nil
+}
+
+/*
+backgrounds gets the background colors behind the text elements marked by
+auditScript and removes the marks.
+*/
+func (auditor *Auditor) backgrounds(ctx context.Context, text []*audit.Text) error {
+	defer func() {
+		if err := auditor.tab.Eval(context.Background(), fmt.Sprintf(
+			`document.querySelectorAll('[%[1]s]').forEach((el) => el.removeAttribute('%[1]s'))`, auditAttribute,
+		), nil); nil != err {
+			log.Warnf("could not remove the audit marks: %s", err)
+		}
+	}()
+	for _, command := range []*protocolCommand{
+		&protocolCommand{"DOM.enable", func(protocol socket.Protocoller) error {
+			return (<-protocol.DOM().Enable()).Err
+		}},
+		&protocolCommand{"CSS.enable", func(protocol socket.Protocoller) error {
+			return (<-protocol.CSS().Enable()).Err
+		}},
+	} {
+		if err := command.send(auditor.tab.withContext(ctx)); nil != err {
+			return errs.Wrap(err, codes.AuditFailed, command.method+" failed")
+		}
+	}
+	document := <-auditor.tab.withContext(ctx).DOM().GetDocument(&dom.GetDocumentParams{})
+	if nil != document.Err {
+		return errs.Wrap(document.Err, codes.AuditFailed, "could not get the document")
+	}
+	if nil == document.Root {
+		return errs.New(codes.AuditFailed, "the document has no root node")
+	}
+	nodes := <-auditor.tab.withContext(ctx).DOM().QuerySelectorAll(&dom.QuerySelectorAllParams{
+		NodeID:   document.Root.NodeID,
+		Selector: "[" + auditAttribute + "]",
+	})
+	if nil != nodes.Err {
+		return errs.Wrap(nodes.Err, codes.AuditFailed, "could not find the text elements")
+	}
+	if len(nodes.NodeIDs) != len(text) {
+		// The document changed since it was inspected.
+		log.Warnf("found %d of %d text elements, contrast is not checked", len(nodes.NodeIDs), len(text))
+		return nil
+	}
+	for a, nodeID := range nodes.NodeIDs {
+		result := <-auditor.tab.withContext(ctx).CSS().GetBackgroundColors(&css.GetBackgroundColorsParams{NodeID: nodeID})
+		if nil != result.Err {
+			log.Warnf("could not get the background colors of %s: %s", text[a].Selector, result.Err)
+			continue
+		}
+		text[a].Backgrounds = result.BackgroundColors
+	}
+	return nil
+}
+
+/*
+stop removes the event handlers and disables the domains enabled to inspect the
+page. The recorded domains are disabled when the audit runs.
+*/
+func (auditor *Auditor) stop(ctx context.Context) {
+	auditor.removeHandlers()
+	for _, command := range []*protocolCommand{
+		&protocolCommand{"CSS.disable", func(protocol socket.Protocoller) error {
+			return (<-protocol.CSS().Disable()).Err
+		}},
+		&protocolCommand{"DOM.disable", func(protocol socket.Protocoller) error {
+			return (<-protocol.DOM().Disable()).Err
+		}},
+	} {
+		if err := command.send(auditor.tab.withContext(ctx)); nil != err {
+			log.Warnf("%s failed: %s", command.method, err)
+		}
+	}
+}
+
+/*
+removeHandlers removes the audit event handlers.
+*/
+func (auditor *Auditor) removeHandlers() {
+	for _, handler := range auditor.handlers {
+		auditor.tab.RemoveEventHandler(handler)
+	}
+}
@@ -0,0 +1,156 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+testAuditDocument is the result of the audit script for a page with a
+render-blocking script, an image without alt text and two text elements.
+*/
+const testAuditDocument = `{
+	"url": "https://example.com/",
+	"devicePixelRatio": 1,
+	"blocking": ["https://example.com/app.js", "https://example.com/cached.css"],
+	"images": [{"url": "https://example.com/logo.png", "selector": "img#logo", "hasAlt": false, "naturalWidth": 10, "naturalHeight": 10, "displayWidth": 10, "displayHeight": 10}],
+	"text": [
+		{"selector": "h1", "text": "Title", "color": "rgb(0, 0, 0)", "fontSize": 32, "fontWeight": 700},
+		{"selector": "p", "text": "Muted", "color": "rgb(200, 200, 200)", "fontSize": 16, "fontWeight": 400}
+	]
+}`
+
+func TestTabAudit(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabAudit")
+	mockSocket.Concurrent()
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		if strings.Contains(params.(*runtime.EvaluateParams).Expression, "removeAttribute") {
+			return json.RawMessage(`{"result":{"type":"undefined"}}`), nil
+		}
+		return json.RawMessage(`{"result":{"type":"object","value":` + testAuditDocument + `}}`), nil
+	})
+	mockSocket.Respond("DOM.getDocument", func(params interface{}) (interface{}, *socket.Error) {
+		return &dom.GetDocumentResult{Root: &dom.Node{NodeID: 1}}, nil
+	})
+	mockSocket.Respond("DOM.querySelectorAll", func(params interface{}) (interface{}, *socket.Error) {
+		return &dom.QuerySelectorAllResult{NodeIDs: []dom.NodeID{5, 6}}, nil
+	})
+	mockSocket.Respond("CSS.getBackgroundColors", func(params interface{}) (interface{}, *socket.Error) {
+		return &css.GetBackgroundColorsResult{BackgroundColors: []string{"rgb(255, 255, 255)"}}, nil
+	})
+
+	auditor, err := tab.StartAudit(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, method := range []string{"Network.enable", "Log.enable", "Runtime.enable", "Security.enable", "Profiler.startPreciseCoverage"} {
+		if 1 != len(mockSocket.Commands(method)) {
+			t.Errorf("Expected %s to be sent", method)
+		}
+	}
+
+	// Handlers run concurrently, events are delivered in reverse order and
+	// recorded in the order of their sequence numbers. Run waits for the events
+	// sent before recording stopped.
+	events := []struct {
+		name   string
+		params string
+	}{
+		{"Network.requestWillBeSent", `{"requestId":"1","type":"Document","timestamp":1.25,"wallTime":1700000000.5,"request":{"url":"https://example.com/","method":"GET","headers":{},"initialPriority":"VeryHigh","referrerPolicy":"strict-origin-when-cross-origin"},"initiator":{"type":"other"}}`},
+		{"Network.requestWillBeSent", `{"requestId":"2","type":"Script","request":{"url":"https://example.com/app.js"},"initiator":{"type":"parser"}}`},
+		{"Network.requestWillBeSent", `{"requestId":"3","type":"Image","request":{"url":"http://example.com/logo.png"},"initiator":{"type":"parser"}}`},
+		{"Network.responseReceived", `{"requestId":"2","type":"Script","timestamp":1.5,"response":{"url":"https://example.com/app.js","status":200,"mimeType":"text/javascript","securityState":"secure","encodedDataLength":312.5,"timing":{"requestTime":1.25,"dnsStart":-1,"sendStart":0.125}}}`},
+		{"Network.loadingFinished", `{"requestId":"2","timestamp":1.75,"encodedDataLength":2048.5}`},
+		{"Network.loadingFinished", `{"requestId":"9","encodedDataLength":10}`},
+		{"Runtime.consoleAPICalled", `{"type":"error","timestamp":1700000000123.5,"args":[{"type":"string","value":"failed:"},{"type":"number","value":42,"description":"42"}],"stackTrace":{"callFrames":[{"functionName":"","scriptId":"1","url":"https://example.com/app.js","lineNumber":4,"columnNumber":0}]}}`},
+		{"Runtime.consoleAPICalled", `{"type":"log","args":[{"type":"string","value":"hello"}]}`},
+		{"Runtime.exceptionThrown", `{"timestamp":1.5,"exceptionDetails":{"exceptionId":1,"text":"Uncaught","lineNumber":9,"columnNumber":0,"url":"https://example.com/app.js","exception":{"type":"object","subtype":"error","description":"TypeError: x is not a function\n    at app.js:10:1"}}}`},
+		{"Log.entryAdded", `{"entry":{"source":"network","level":"error","text":"Failed to load resource","url":"https://example.com/missing.css","timestamp":1.5}}`},
+		{"Security.visibleSecurityStateChanged", `{"visibleSecurityState":{"securityState":"insecure-broken","securityStateIssueIds":[]}}`},
+	}
+	numbered := []*socket.Response{}
+	for _, event := range events {
+		numbered = append(numbered, mockSocket.Event(event.name, json.RawMessage(event.params)))
+	}
+	for a := len(numbered) - 1; a >= 0; a-- {
+		mockSocket.FireEvent(numbered[a])
+	}
+
+	report, err := auditor.Run(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/" != report.URL || 9 != len(report.Audits) {
+		t.Fatalf("Unexpected report %s", report)
+	}
+
+	blocking := report.Audit("render-blocking-resources")
+	if 2 != len(blocking.Details) || "https://example.com/app.js" != blocking.Details[0].URL || 2048 != blocking.Details[0].Bytes {
+		t.Errorf("Unexpected render-blocking resources %+v", blocking.Details)
+	}
+	if alt := report.Audit("image-alt"); 1 != len(alt.Details) || "img#logo" != alt.Details[0].Selector {
+		t.Errorf("Unexpected images %+v", alt.Details)
+	}
+	if contrast := report.Audit("color-contrast"); 1 != len(contrast.Details) || "p" != contrast.Details[0].Selector {
+		t.Errorf("Unexpected contrast failures %+v", contrast.Details)
+	}
+	if https := report.Audit("is-on-https"); 1 != len(https.Details) || "http://example.com/logo.png" != https.Details[0].URL {
+		t.Errorf("Unexpected mixed content %+v", https.Details)
+	}
+
+	errors := report.Audit("errors-in-console").Details
+	if 3 != len(errors) {
+		t.Fatalf("Expected 3 console errors, received %+v", errors)
+	}
+	if "failed: 42" != errors[0].Text || "https://example.com/app.js:5" != errors[0].URL {
+		t.Errorf("Unexpected console error %+v", errors[0])
+	}
+	if "Uncaught: TypeError: x is not a function" != errors[1].Text || "https://example.com/app.js:10" != errors[1].URL {
+		t.Errorf("Unexpected exception %+v", errors[1])
+	}
+	if "network" != errors[2].Value {
+		t.Errorf("Unexpected log entry %+v", errors[2])
+	}
+
+	if 2 != len(mockSocket.Commands("CSS.getBackgroundColors")) {
+		t.Errorf("Expected the background colors of each text element to be requested")
+	}
+	if 1 != len(mockSocket.Commands("Profiler.stopPreciseCoverage")) || 1 != len(mockSocket.Commands("Network.disable")) {
+		t.Errorf("Expected coverage and network recording to be stopped")
+	}
+	mockSocket.Fire("Runtime.consoleAPICalled", json.RawMessage(`{"type":"error","args":[]}`))
+	if _, err := auditor.Run(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabAuditError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabAuditError")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Execution context was destroyed."}
+	})
+	auditor, err := tab.StartAudit(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if _, err := auditor.Run(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 1 != len(mockSocket.Commands("Security.disable")) {
+		t.Errorf("Expected the audit to be stopped after an error")
+	}
+
+	mockSocket.Respond("Security.enable", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Security.enable wasn't found"}
+	})
+	if _, err := tab.StartAudit(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
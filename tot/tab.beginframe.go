@@ -0,0 +1,274 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/headless/experimental"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+DefaultFrameInterval is the interval between frames reported to the
+compositor, 60 frames per second.
+*/
+const DefaultFrameInterval = time.Second / 60
+
+/*
+Frame is the result of a BeginFrame.
+*/
+type Frame struct {
+	// The frame time reported to the compositor.
+	Time time.Time
+
+	// Whether the frame resulted in damage and a new frame was committed to
+	// the display.
+	HasDamage bool
+
+	// Whether the main frame submitted a new display frame.
+	MainFrameContentUpdated bool
+
+	// The decoded screenshot, if one was requested and the frame had damage.
+	Screenshot []byte
+}
+
+/*
+BeginFrames drives rendering for a tab in a browser launched in deterministic
+mode (see DeterministicFlags). The compositor doesn't produce frames on its own;
+each call issues a HeadlessExperimental.beginFrame with a frame time that
+advances by a fixed interval, so animations and screenshots are reproducible.
+*/
+type BeginFrames struct {
+	enabled     bool
+	frames      int
+	handler     socket.EventHandler
+	interval    time.Duration
+	mux         sync.Mutex
+	needsFrames bool
+	start       time.Time
+	tab         *Tab
+}
+
+/*
+BeginFrames returns the BeginFrame controller for this tab.
+
+	frames := tab.BeginFrames()
+	err := tab.VirtualTime().Navigate(ctx, "https://example.com/", 500*time.Millisecond)
+	...
+	png, err := frames.Snapshot(ctx, 2*time.Second, nil)
+*/
+func (tab *Tab) BeginFrames() *BeginFrames {
+	tab.beginFramesMux.Lock()
+	defer tab.beginFramesMux.Unlock()
+	if nil == tab.beginFrames {
+		tab.beginFrames = &BeginFrames{
+			interval: DefaultFrameInterval,
+			tab:      tab,
+		}
+	}
+	return tab.beginFrames
+}
+
+/*
+Advance advances virtual time by d, issuing a BeginFrame after each frame
+interval so that animations and requestAnimationFrame callbacks run frame by
+frame. The last frame is returned.
+*/
+func (frames *BeginFrames) Advance(ctx context.Context, d time.Duration) (*Frame, error) {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	return frames.advance(ctx, d, nil)
+}
+
+/*
+Frame issues a single BeginFrame without advancing virtual time.
+*/
+func (frames *BeginFrames) Frame(ctx context.Context) (*Frame, error) {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	return frames.beginFrame(ctx, nil)
+}
+
+/*
+Interval returns the interval between frames.
+*/
+func (frames *BeginFrames) Interval() time.Duration {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	return frames.interval
+}
+
+/*
+NeedsFrames returns true if the compositor has reported that it needs
+BeginFrames to make progress, e.g. while an animation is running.
+*/
+func (frames *BeginFrames) NeedsFrames() bool {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	return frames.needsFrames
+}
+
+/*
+Screenshot issues a BeginFrame and captures the frame. params may be nil to
+capture a PNG image. The compositor only returns a screenshot for frames with
+damage; a frame with no damage is an error.
+*/
+func (frames *BeginFrames) Screenshot(ctx context.Context, params *experimental.ScreenshotParams) ([]byte, error) {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	frame, err := frames.beginFrame(ctx, screenshotParams(params))
+	if nil != err {
+		return nil, err
+	}
+	return frame.screenshot()
+}
+
+/*
+SetClock sets the frame time of the next frame and the interval between
+frames. By default frames start at the time of the first BeginFrame and are
+DefaultFrameInterval apart.
+*/
+func (frames *BeginFrames) SetClock(start time.Time, interval time.Duration) error {
+	if interval <= 0 {
+		return errs.New(codes.RenderingClockInvalid, fmt.Sprintf("invalid frame interval %s", interval))
+	}
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+	frames.frames = 0
+	frames.interval = interval
+	frames.start = start
+	return nil
+}
+
+/*
+Snapshot advances virtual time to a point in time relative to when it was
+first paused, issuing a BeginFrame each frame interval, and captures the last
+frame. params may be nil to capture a PNG image.
+*/
+func (frames *BeginFrames) Snapshot(ctx context.Context, at time.Duration, params *experimental.ScreenshotParams) ([]byte, error) {
+	frames.mux.Lock()
+	defer frames.mux.Unlock()
+
+	elapsed := frames.tab.VirtualTime().Elapsed()
+	if at < elapsed {
+		return nil, errs.New(codes.EmulationVirtualTimeInvalid, fmt.Sprintf("virtual time %s has passed, %s has elapsed", at, elapsed))
+	}
+	frame, err := frames.advance(ctx, at-elapsed, screenshotParams(params))
+	if nil != err {
+		return nil, err
+	}
+	return frame.screenshot()
+}
+
+/*
+advance advances virtual time by d in frame intervals and issues a BeginFrame
+after each one. The screenshot is only requested for the last frame. A
+BeginFrame is issued even if d is 0.
+*/
+func (frames *BeginFrames) advance(ctx context.Context, d time.Duration, params *experimental.ScreenshotParams) (*Frame, error) {
+	for d > 0 {
+		step := frames.interval
+		if step > d {
+			step = d
+		}
+		if err := frames.tab.VirtualTime().Advance(ctx, step); nil != err {
+			return nil, err
+		}
+		d -= step
+		if d <= 0 {
+			break
+		}
+		if _, err := frames.beginFrame(ctx, nil); nil != err {
+			return nil, err
+		}
+	}
+	return frames.beginFrame(ctx, params)
+}
+
+/*
+beginFrame issues a BeginFrame at the next frame time.
+*/
+func (frames *BeginFrames) beginFrame(ctx context.Context, params *experimental.ScreenshotParams) (*Frame, error) {
+	frames.enable(ctx)
+	if frames.start.IsZero() {
+		frames.start = time.Now()
+	}
+	frameTime := frames.start.Add(time.Duration(frames.frames) * frames.interval)
+
+	result := <-frames.tab.withContext(ctx).HeadlessExperimental().BeginFrame(&experimental.BeginFrameParams{
+		FrameTime:  float64(frameTime.UnixNano()) / float64(time.Millisecond),
+		Interval:   float64(frames.interval) / float64(time.Millisecond),
+		Screenshot: params,
+	})
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.RenderingFailed, "BeginFrame failed")
+	}
+	frames.frames++
+
+	frame := &Frame{
+		Time:                    frameTime,
+		HasDamage:               result.HasDamage,
+		MainFrameContentUpdated: result.MainFrameContentUpdated,
+	}
+	if "" != result.ScreenshotData {
+		data, err := base64.StdEncoding.DecodeString(result.ScreenshotData)
+		if nil != err {
+			return nil, errs.Wrap(err, codes.CaptureDecodeFailed, "could not decode the frame screenshot")
+		}
+		frame.Screenshot = data
+	}
+	return frame, nil
+}
+
+/*
+enable registers the needsBeginFramesChanged handler and enables the
+HeadlessExperimental domain. BeginFrames work without the domain events, so a
+failure is only logged.
+*/
+func (frames *BeginFrames) enable(ctx context.Context) {
+	if frames.enabled {
+		return
+	}
+	frames.handler = socket.NewEventHandler("HeadlessExperimental.needsBeginFramesChanged", func(response *socket.Response) {
+		event := &experimental.NeedsBeginFramesChangedEvent{}
+		if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+			log.Warnf("could not decode needsBeginFramesChanged event: %v", err)
+			return
+		}
+		frames.mux.Lock()
+		frames.needsFrames = event.NeedsBeginFrames
+		frames.mux.Unlock()
+	})
+	frames.tab.AddEventHandler(frames.handler)
+	if result := <-frames.tab.withContext(ctx).HeadlessExperimental().Enable(); nil != result.Err {
+		log.Warnf("could not enable the HeadlessExperimental domain: %v", result.Err)
+	}
+	frames.enabled = true
+}
+
+/*
+screenshot returns the frame's screenshot or an error if the frame had none.
+*/
+func (frame *Frame) screenshot() ([]byte, error) {
+	if nil == frame.Screenshot {
+		return nil, errs.New(codes.CaptureFailed, "the frame has no screenshot, it had no damage")
+	}
+	return frame.Screenshot, nil
+}
+
+/*
+screenshotParams returns the screenshot parameters, defaulting to PNG.
+*/
+func screenshotParams(params *experimental.ScreenshotParams) *experimental.ScreenshotParams {
+	if nil == params {
+		return &experimental.ScreenshotParams{Format: experimental.Format.Png}
+	}
+	return params
+}
@@ -0,0 +1,151 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/headless/experimental"
+	"github.com/mkenney/go-chrome/tot/socket"
+	"github.com/mkenney/go-chrome/tot/target"
+)
+
+/*
+mockBeginFrame responds to HeadlessExperimental.beginFrame with damage and a
+screenshot when one is requested.
+*/
+func mockBeginFrame(params interface{}) (interface{}, *socket.Error) {
+	result := &experimental.BeginFrameResult{HasDamage: true}
+	if nil != params.(*experimental.BeginFrameParams).Screenshot {
+		result.ScreenshotData = base64.StdEncoding.EncodeToString([]byte("frame"))
+	}
+	return result, nil
+}
+
+func TestBeginFramesClock(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestBeginFramesClock")
+	mockSocket.Respond("HeadlessExperimental.beginFrame", mockBeginFrame)
+
+	frames := tab.BeginFrames()
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := frames.SetClock(start, 40*time.Millisecond); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for a := 0; a < 3; a++ {
+		frame, err := frames.Frame(context.Background())
+		if nil != err {
+			t.Fatalf("Expected nil, received error: %v", err)
+		}
+		if !frame.Time.Equal(start.Add(time.Duration(a) * 40 * time.Millisecond)) {
+			t.Errorf("Unexpected frame time %s", frame.Time)
+		}
+	}
+	commands := mockSocket.Commands("HeadlessExperimental.beginFrame")
+	params := commands[2].Params().(*experimental.BeginFrameParams)
+	if float64(start.UnixNano())/1e6+80 != params.FrameTime || 40 != params.Interval {
+		t.Errorf("Unexpected BeginFrame parameters %+v", params)
+	}
+	if 1 != len(mockSocket.Commands("HeadlessExperimental.enable")) {
+		t.Errorf("Expected the HeadlessExperimental domain to be enabled once")
+	}
+
+	mockSocket.Fire("HeadlessExperimental.needsBeginFramesChanged", &experimental.NeedsBeginFramesChangedEvent{NeedsBeginFrames: true})
+	if !frames.NeedsFrames() {
+		t.Errorf("Expected the compositor to need frames")
+	}
+	if err := frames.SetClock(start, 0); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestBeginFramesScreenshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestBeginFramesScreenshot")
+	mockSocket.Respond("HeadlessExperimental.beginFrame", mockBeginFrame)
+
+	data, err := tab.BeginFrames().Screenshot(context.Background(), &experimental.ScreenshotParams{
+		Format:  experimental.Format.Jpeg,
+		Quality: 80,
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "frame" != string(data) {
+		t.Errorf("Expected the frame screenshot, received '%s'", data)
+	}
+	params := mockSocket.Commands("HeadlessExperimental.beginFrame")[0].Params().(*experimental.BeginFrameParams)
+	if experimental.Format.Jpeg != params.Screenshot.Format || 80 != params.Screenshot.Quality {
+		t.Errorf("Unexpected screenshot parameters %+v", params.Screenshot)
+	}
+
+	mockSocket.Respond("HeadlessExperimental.beginFrame", func(params interface{}) (interface{}, *socket.Error) {
+		return &experimental.BeginFrameResult{}, nil
+	})
+	if _, err := tab.BeginFrames().Screenshot(context.Background(), nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestBeginFramesSnapshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestBeginFramesSnapshot")
+	mockSocket.Respond("Emulation.setVirtualTimePolicy", func(params interface{}) (interface{}, *socket.Error) {
+		if params.(*emulation.SetVirtualTimePolicyParams).Budget > 0 {
+			mockSocket.Fire("Emulation.virtualTimeBudgetExpired", &emulation.VirtualTimeBudgetExpiredEvent{})
+		}
+		return nil, nil
+	})
+	mockSocket.Respond("HeadlessExperimental.beginFrame", mockBeginFrame)
+
+	frames := tab.BeginFrames()
+	frames.SetClock(time.Now(), 40*time.Millisecond)
+	data, err := frames.Snapshot(context.Background(), 100*time.Millisecond, nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "frame" != string(data) {
+		t.Errorf("Expected the frame screenshot, received '%s'", data)
+	}
+	if 100*time.Millisecond != tab.VirtualTime().Elapsed() {
+		t.Errorf("Expected 100ms of virtual time to elapse, received %s", tab.VirtualTime().Elapsed())
+	}
+	commands := mockSocket.Commands("HeadlessExperimental.beginFrame")
+	if 3 != len(commands) {
+		t.Fatalf("Expected a BeginFrame per frame interval, received %d", len(commands))
+	}
+	if nil != commands[1].Params().(*experimental.BeginFrameParams).Screenshot || nil == commands[2].Params().(*experimental.BeginFrameParams).Screenshot {
+		t.Errorf("Expected only the last frame to be captured")
+	}
+
+	if _, err := frames.Snapshot(context.Background(), 50*time.Millisecond, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	mockSocket.Respond("HeadlessExperimental.beginFrame", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "error message"}
+	})
+	if _, err := frames.Advance(context.Background(), 40*time.Millisecond); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestCreateTarget(t *testing.T) {
+	mockSocket := NewMockSocket(nil)
+	mockSocket.Respond("Target.createTarget", func(params interface{}) (interface{}, *socket.Error) {
+		return &target.CreateTargetResult{ID: "target-1"}, nil
+	})
+	targetID, err := createTarget(mockSocket, "https://TestCreateTarget")
+	if nil != err || "target-1" != targetID {
+		t.Fatalf("Expected target-1, received '%s' (%v)", targetID, err)
+	}
+	params := mockSocket.Commands("Target.createTarget")[0].Params().(*target.CreateTargetParams)
+	if "https://TestCreateTarget" != params.URL || !params.EnableBeginFrameControl {
+		t.Errorf("Expected BeginFrame control to be enabled, received %+v", params)
+	}
+
+	mockSocket.Respond("Target.createTarget", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Not supported"}
+	})
+	if _, err := createTarget(mockSocket, "https://TestCreateTarget"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
@@ -0,0 +1,223 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+bindingName is the name of the Runtime binding that carries calls to exposed
+functions from the page. A single binding is shared by all exposed functions;
+the page stub includes the function name in the payload.
+*/
+const bindingName = "__goChromeBinding"
+
+/*
+bindingStub is the page script that installs an exposed function. The function
+forwards its arguments to the binding and returns a promise that is settled
+when Go delivers the result. It is formatted with the JSON encoded function and
+binding names.
+*/
+const bindingStub = `(function (name, bindingName) {
+	var binding = globalThis[bindingName];
+	if (!binding || (globalThis[name] && globalThis[name].__goChromeBinding)) {
+		return;
+	}
+	var callbacks = new Map();
+	var seq = 0;
+	var fn = function () {
+		var id = ++seq;
+		var args = Array.prototype.slice.call(arguments);
+		return new Promise(function (resolve, reject) {
+			callbacks.set(id, {resolve: resolve, reject: reject});
+			binding(JSON.stringify({name: name, seq: id, args: args}));
+		});
+	};
+	fn.__goChromeBinding = true;
+	fn.__deliver = function (id, error, result) {
+		var callback = callbacks.get(id);
+		if (!callback) {
+			return;
+		}
+		callbacks.delete(id);
+		if (null !== error) {
+			callback.reject(new Error(error));
+		} else {
+			callback.resolve(result);
+		}
+	};
+	globalThis[name] = fn;
+})(%s, %s);`
+
+/*
+BindingFunc is a Go function exposed to page JavaScript. It receives the JSON
+encoded arguments of the JavaScript call. The returned value is JSON encoded and
+resolves the promise returned to the page; a returned error rejects it.
+*/
+type BindingFunc func(args ...json.RawMessage) (interface{}, error)
+
+/*
+bindingPayload is the payload sent by the page stub to the binding.
+*/
+type bindingPayload struct {
+	Name string            `json:"name"`
+	Seq  int               `json:"seq"`
+	Args []json.RawMessage `json:"args"`
+}
+
+/*
+bindings holds the functions exposed to a tab.
+*/
+type bindings struct {
+	functions map[string]BindingFunc
+	mux       sync.Mutex
+}
+
+/*
+ExposeFunction installs a global JavaScript function named name in the page
+that calls fn. The JavaScript function returns a promise that resolves with the
+value returned by fn or rejects with the error it returns. The function is
+installed in the current document and in every document loaded afterwards,
+including those created by navigation.
+
+	tab.ExposeFunction("add", func(args ...json.RawMessage) (interface{}, error) {
+		var a, b int
+		json.Unmarshal(args[0], &a)
+		json.Unmarshal(args[1], &b)
+		return a + b, nil
+	})
+*/
+func (tab *Tab) ExposeFunction(name string, fn BindingFunc) error {
+	if "" == name || nil == fn {
+		return errs.New(codes.RuntimeArgumentInvalid, "an exposed function requires a name and a function")
+	}
+
+	tab.bindingsMux.Lock()
+	defer tab.bindingsMux.Unlock()
+	if nil == tab.bindings {
+		if err := tab.installBinding(); nil != err {
+			return err
+		}
+	}
+	tab.bindings.mux.Lock()
+	_, ok := tab.bindings.functions[name]
+	tab.bindings.mux.Unlock()
+	if ok {
+		return errs.New(codes.RuntimeArgumentInvalid, fmt.Sprintf("function '%s' is already exposed", name))
+	}
+
+	nameJSON, _ := json.Marshal(name)
+	bindingJSON, _ := json.Marshal(bindingName)
+	source := fmt.Sprintf(bindingStub, nameJSON, bindingJSON)
+
+	result := <-tab.Page().AddScriptToEvaluateOnNewDocument(&page.AddScriptToEvaluateOnNewDocumentParams{
+		Source: source,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.RuntimeBindingFailed, fmt.Sprintf("could not install function '%s'", name))
+	}
+
+	tab.bindings.mux.Lock()
+	tab.bindings.functions[name] = fn
+	tab.bindings.mux.Unlock()
+
+	if err := tab.Eval(context.Background(), source, nil); nil != err {
+		// Undo the installation so that exposing the function can be retried.
+		tab.bindings.mux.Lock()
+		delete(tab.bindings.functions, name)
+		tab.bindings.mux.Unlock()
+		remove := <-tab.Page().RemoveScriptToEvaluateOnNewDocument(&page.RemoveScriptToEvaluateOnNewDocumentParams{
+			Identifier: result.Identifier,
+		})
+		if nil != remove.Err {
+			log.Errorf("could not remove the script of function '%s': %s", name, remove.Err)
+		}
+		return errs.Wrap(err, codes.RuntimeBindingFailed, fmt.Sprintf("could not install function '%s' in the current document", name))
+	}
+	return nil
+}
+
+/*
+installBinding enables the Runtime domain, adds the shared binding and starts
+listening for binding calls. The caller must hold the bindings mutex.
+*/
+func (tab *Tab) installBinding() error {
+	if result := <-tab.Runtime().Enable(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.RuntimeBindingFailed, "could not enable the Runtime domain")
+	}
+	result := <-tab.Runtime().AddBinding(&runtime.AddBindingParams{
+		Name: bindingName,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.RuntimeBindingFailed, "could not add binding")
+	}
+
+	tab.bindings = &bindings{functions: map[string]BindingFunc{}}
+	tab.Runtime().OnBindingCalled(func(event *runtime.BindingCalledEvent) {
+		if nil != event.Err || bindingName != event.Name {
+			return
+		}
+		tab.handleBindingCall(event)
+	})
+	return nil
+}
+
+/*
+handleBindingCall invokes the exposed function for a binding call and delivers
+the result to the calling execution context.
+*/
+func (tab *Tab) handleBindingCall(event *runtime.BindingCalledEvent) {
+	payload := &bindingPayload{}
+	if err := json.Unmarshal([]byte(event.Payload), payload); nil != err {
+		log.Errorf("invalid binding payload: %s", err)
+		return
+	}
+
+	tab.bindings.mux.Lock()
+	fn, ok := tab.bindings.functions[payload.Name]
+	tab.bindings.mux.Unlock()
+	if !ok {
+		return
+	}
+
+	errJSON := []byte("null")
+	resultJSON := []byte("undefined")
+	result, err := callBinding(fn, payload.Args)
+	if nil == err && nil != result {
+		resultJSON, err = json.Marshal(result)
+	}
+	if nil != err {
+		errJSON, _ = json.Marshal(err.Error())
+		resultJSON = []byte("undefined")
+	}
+
+	nameJSON, _ := json.Marshal(payload.Name)
+	deliver := <-tab.Runtime().Evaluate(&runtime.EvaluateParams{
+		Expression: fmt.Sprintf("globalThis[%s].__deliver(%d, %s, %s)", nameJSON, payload.Seq, errJSON, resultJSON),
+		ContextID:  event.ExecutionContextID,
+	})
+	if nil != deliver.Err {
+		log.Errorf("could not deliver the result of '%s': %s", payload.Name, deliver.Err)
+	}
+}
+
+/*
+callBinding calls an exposed function, converting a panic into an error so that
+the page promise is always settled.
+*/
+func callBinding(fn BindingFunc, args []json.RawMessage) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); nil != r {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return fn(args...)
+}
@@ -0,0 +1,128 @@
+package chrome
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestTabExposeFunction(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabExposeFunction")
+
+	err := tab.ExposeFunction("add", func(args ...json.RawMessage) (interface{}, error) {
+		var a, b int
+		json.Unmarshal(args[0], &a)
+		json.Unmarshal(args[1], &b)
+		return a + b, nil
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	bindings := mockSocket.Commands("Runtime.addBinding")
+	if 1 != len(bindings) || bindingName != bindings[0].Params().(*runtime.AddBindingParams).Name {
+		t.Fatalf("Expected the binding to be added once, received %d", len(bindings))
+	}
+	scripts := mockSocket.Commands("Page.addScriptToEvaluateOnNewDocument")
+	if 1 != len(scripts) {
+		t.Fatalf("Expected 1 new document script, received %d", len(scripts))
+	}
+	if source := scripts[0].Params().(*page.AddScriptToEvaluateOnNewDocumentParams).Source; !strings.HasSuffix(source, `})("add", "__goChromeBinding");`) {
+		t.Errorf("Unexpected stub source: %s", source)
+	}
+	if 1 != len(mockSocket.Commands("Runtime.evaluate")) {
+		t.Errorf("Expected the stub to be evaluated in the current document")
+	}
+
+	mockSocket.Fire("Runtime.bindingCalled", &runtime.BindingCalledEvent{
+		Name:               bindingName,
+		Payload:            `{"name":"add","seq":3,"args":[1,2]}`,
+		ExecutionContextID: 7,
+	})
+	evaluations := mockSocket.Commands("Runtime.evaluate")
+	if 2 != len(evaluations) {
+		t.Fatalf("Expected the result to be delivered, received %d evaluations", len(evaluations))
+	}
+	params := evaluations[1].Params().(*runtime.EvaluateParams)
+	if `globalThis["add"].__deliver(3, null, 3)` != params.Expression {
+		t.Errorf("Unexpected delivery expression: %s", params.Expression)
+	}
+	if 7 != params.ContextID {
+		t.Errorf("Expected context 7, received %d", params.ContextID)
+	}
+
+	if err := tab.ExposeFunction("add", func(args ...json.RawMessage) (interface{}, error) { return nil, nil }); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabExposeFunctionError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabExposeFunctionError")
+
+	tab.ExposeFunction("fail", func(args ...json.RawMessage) (interface{}, error) {
+		return nil, errors.New("no fixtures")
+	})
+	tab.ExposeFunction("panic", func(args ...json.RawMessage) (interface{}, error) {
+		panic("boom")
+	})
+	if 1 != len(mockSocket.Commands("Runtime.addBinding")) {
+		t.Errorf("Expected the binding to be shared by all functions")
+	}
+
+	mockSocket.Fire("Runtime.bindingCalled", &runtime.BindingCalledEvent{
+		Name:    bindingName,
+		Payload: `{"name":"fail","seq":1,"args":[]}`,
+	})
+	mockSocket.Fire("Runtime.bindingCalled", &runtime.BindingCalledEvent{
+		Name:    bindingName,
+		Payload: `{"name":"panic","seq":2,"args":[]}`,
+	})
+	mockSocket.Fire("Runtime.bindingCalled", &runtime.BindingCalledEvent{
+		Name:    "otherBinding",
+		Payload: `{"name":"fail","seq":3,"args":[]}`,
+	})
+
+	evaluations := mockSocket.Commands("Runtime.evaluate")
+	if 4 != len(evaluations) {
+		t.Fatalf("Expected 4 evaluations, received %d", len(evaluations))
+	}
+	expected := []string{
+		`globalThis["fail"].__deliver(1, "no fixtures", undefined)`,
+		`globalThis["panic"].__deliver(2, "boom", undefined)`,
+	}
+	for k, expression := range expected {
+		if expression != evaluations[k+2].Params().(*runtime.EvaluateParams).Expression {
+			t.Errorf("Expected %s, received %s", expression, evaluations[k+2].Params().(*runtime.EvaluateParams).Expression)
+		}
+	}
+}
+
+func TestTabExposeFunctionRetry(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabExposeFunctionRetry")
+	mockSocket.Respond("Page.addScriptToEvaluateOnNewDocument", func(params interface{}) (interface{}, *socket.Error) {
+		return &page.AddScriptToEvaluateOnNewDocumentResult{Identifier: "script-1"}, nil
+	})
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Execution context was destroyed."}
+	})
+	fn := func(args ...json.RawMessage) (interface{}, error) {
+		return nil, nil
+	}
+	if err := tab.ExposeFunction("add", fn); nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	removed := mockSocket.Commands("Page.removeScriptToEvaluateOnNewDocument")
+	if 1 != len(removed) || "script-1" != removed[0].Params().(*page.RemoveScriptToEvaluateOnNewDocumentParams).Identifier {
+		t.Errorf("Expected the new document script to be removed")
+	}
+
+	mockSocket.Respond("Runtime.evaluate", nil)
+	if err := tab.ExposeFunction("add", fn); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+}
@@ -0,0 +1,375 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/coverage"
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/profiler"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+CoverageOptions configures a coverage collector. The zero value collects
+JavaScript and CSS coverage.
+*/
+type CoverageOptions struct {
+	// Optional. Collect JavaScript coverage. If neither JavaScript nor CSS is
+	// set both are collected.
+	JavaScript bool
+
+	// Optional. Collect CSS rule usage.
+	CSS bool
+
+	// Optional. Report the coverage of the original sources of scripts and
+	// style sheets that reference a source map. Source maps are loaded from
+	// the page's resources or through the browser's network stack.
+	SourceMaps bool
+}
+
+/*
+javaScript reports whether JavaScript coverage is collected.
+*/
+func (opts *CoverageOptions) javaScript() bool {
+	return opts.JavaScript || !opts.CSS
+}
+
+/*
+css reports whether CSS coverage is collected.
+*/
+func (opts *CoverageOptions) css() bool {
+	return opts.CSS || !opts.JavaScript
+}
+
+/*
+Coverage collects JavaScript and CSS coverage across navigations.
+*/
+type Coverage struct {
+	handlers []socket.EventHandler
+	mux      sync.Mutex
+	opts     *CoverageOptions
+	report   *coverage.Report
+	scripts  map[runtime.ScriptID]*coverageSource
+	sheets   map[css.StyleSheetID]*coverageSource
+	stopped  bool
+	tab      *Tab
+}
+
+/*
+coverageSource is a parsed script or style sheet. Sources are fetched when the
+script or style sheet is parsed because they are not available after the page
+navigates.
+*/
+type coverageSource struct {
+	sourceMapURL string
+	text         string
+	url          string
+}
+
+/*
+StartCoverage starts collecting coverage. Chrome resets the coverage counters
+each time coverage is taken, the collector adds the coverage of each take to
+the report by URL. Take coverage before navigating away from a page whose
+scripts should be reported, scripts that are garbage collected with the page
+are not.
+
+	cov, err := tab.StartCoverage(ctx, nil)
+	...
+	report, err := cov.Stop(ctx)
+	...
+	err = report.WriteLcov(file)
+*/
+func (tab *Tab) StartCoverage(ctx context.Context, opts *CoverageOptions) (*Coverage, error) {
+	if nil == opts {
+		opts = &CoverageOptions{}
+	}
+	cov := &Coverage{
+		opts:    opts,
+		report:  &coverage.Report{},
+		scripts: map[runtime.ScriptID]*coverageSource{},
+		sheets:  map[css.StyleSheetID]*coverageSource{},
+		tab:     tab,
+	}
+
+	commands := []*protocolCommand{}
+	if opts.javaScript() {
+		cov.handlers = append(cov.handlers, socket.NewEventHandler("Debugger.scriptParsed", func(response *socket.Response) {
+			event := &debugger.ScriptParsedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid scriptParsed event: %s", err)
+				return
+			}
+			cov.scriptParsed(event)
+		}))
+		commands = append(commands,
+			&protocolCommand{"Profiler.enable", func(protocol socket.Protocoller) error {
+				return (<-protocol.Profiler().Enable()).Err
+			}},
+			&protocolCommand{"Profiler.startPreciseCoverage", func(protocol socket.Protocoller) error {
+				return (<-protocol.Profiler().StartPreciseCoverage(&profiler.StartPreciseCoverageParams{CallCount: true, Detailed: true})).Err
+			}},
+			&protocolCommand{"Debugger.enable", func(protocol socket.Protocoller) error {
+				return (<-protocol.Debugger().Enable()).Err
+			}},
+			&protocolCommand{"Debugger.setSkipAllPauses", func(protocol socket.Protocoller) error {
+				return (<-protocol.Debugger().SetSkipAllPauses(&debugger.SetSkipAllPausesParams{Skip: true})).Err
+			}},
+		)
+	}
+	if opts.css() {
+		cov.handlers = append(cov.handlers, socket.NewEventHandler("CSS.styleSheetAdded", func(response *socket.Response) {
+			event := &css.StyleSheetAddedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid styleSheetAdded event: %s", err)
+				return
+			}
+			cov.styleSheetAdded(event)
+		}))
+		commands = append(commands,
+			&protocolCommand{"DOM.enable", func(protocol socket.Protocoller) error {
+				return (<-protocol.DOM().Enable()).Err
+			}},
+			&protocolCommand{"CSS.enable", func(protocol socket.Protocoller) error {
+				return (<-protocol.CSS().Enable()).Err
+			}},
+			&protocolCommand{"CSS.startRuleUsageTracking", func(protocol socket.Protocoller) error {
+				return (<-protocol.CSS().StartRuleUsageTracking()).Err
+			}},
+		)
+	}
+	for _, handler := range cov.handlers {
+		tab.AddEventHandler(handler)
+	}
+
+	protocol := tab.withContext(ctx)
+	for _, command := range commands {
+		if err := command.send(protocol); nil != err {
+			cov.removeHandlers()
+			return nil, errs.Wrap(err, codes.CoverageFailed, fmt.Sprintf("%s failed", command.method))
+		}
+	}
+	return cov, nil
+}
+
+/*
+scriptParsed records a script and fetches its source. Scripts without a URL
+are not reported.
+*/
+func (cov *Coverage) scriptParsed(event *debugger.ScriptParsedEvent) {
+	if "" == event.URL {
+		return
+	}
+	source := &coverageSource{
+		sourceMapURL: event.SourceMapURL,
+		url:          event.URL,
+	}
+	// Inline scripts share the URL of their document.
+	if !event.HasSourceURL && (0 != event.StartLine || 0 != event.StartColumn) {
+		source.url = fmt.Sprintf("%s#%d:%d", event.URL, event.StartLine+1, event.StartColumn+1)
+	}
+	result := <-cov.tab.Debugger().GetScriptSource(&debugger.GetScriptSourceParams{
+		ScriptID: event.ScriptID,
+	})
+	if nil != result.Err {
+		log.Warnf("could not get the source of %s: %s", event.URL, result.Err)
+		return
+	}
+	source.text = result.ScriptSource
+
+	cov.mux.Lock()
+	cov.scripts[event.ScriptID] = source
+	cov.mux.Unlock()
+}
+
+/*
+styleSheetAdded records a style sheet and fetches its text. Style sheets
+without a URL are not reported.
+*/
+func (cov *Coverage) styleSheetAdded(event *css.StyleSheetAddedEvent) {
+	header := event.Header
+	if nil == header || "" == header.SourceURL {
+		return
+	}
+	source := &coverageSource{
+		sourceMapURL: header.SourceMapURL,
+		url:          header.SourceURL,
+	}
+	if header.IsInline {
+		source.url = fmt.Sprintf("%s#%d:%d", header.SourceURL, header.StartLine+1, header.StartColumn+1)
+	}
+	result := <-cov.tab.CSS().GetStyleSheetText(&css.GetStyleSheetTextParams{
+		StyleSheetID: header.StyleSheetID,
+	})
+	if nil != result.Err {
+		log.Warnf("could not get the text of %s: %s", header.SourceURL, result.Err)
+		return
+	}
+	source.text = result.Text
+
+	cov.mux.Lock()
+	cov.sheets[header.StyleSheetID] = source
+	cov.mux.Unlock()
+}
+
+/*
+Take adds the coverage recorded since the last take to the report.
+*/
+func (cov *Coverage) Take(ctx context.Context) error {
+	cov.mux.Lock()
+	stopped := cov.stopped
+	cov.mux.Unlock()
+	if stopped {
+		return errs.New(codes.CoverageFailed, "coverage is stopped")
+	}
+	return cov.take(ctx, false)
+}
+
+/*
+take adds the JavaScript coverage and the CSS rule usage to the report. If stop
+is true the CSS rule usage tracking is stopped.
+*/
+func (cov *Coverage) take(ctx context.Context, stop bool) error {
+	protocol := cov.tab.withContext(ctx)
+	if cov.opts.javaScript() {
+		result := <-protocol.Profiler().TakePreciseCoverage()
+		if nil != result.Err {
+			return errs.Wrap(result.Err, codes.CoverageFailed, "could not take JavaScript coverage")
+		}
+		for _, script := range result.Result {
+			cov.mux.Lock()
+			source, ok := cov.scripts[script.ScriptID]
+			cov.mux.Unlock()
+			if !ok {
+				continue
+			}
+			file := coverage.FromScriptCoverage(script, source.text)
+			file.URL, file.SourceMapURL = source.url, source.sourceMapURL
+			cov.add(file)
+		}
+	}
+
+	if cov.opts.css() {
+		var usage []*css.RuleUsage
+		if stop {
+			result := <-protocol.CSS().StopRuleUsageTracking()
+			if nil != result.Err {
+				return errs.Wrap(result.Err, codes.CoverageFailed, "could not take CSS coverage")
+			}
+			usage = result.RuleUsage
+		} else {
+			result := <-protocol.CSS().TakeCoverageDelta()
+			if nil != result.Err {
+				return errs.Wrap(result.Err, codes.CoverageFailed, "could not take CSS coverage")
+			}
+			usage = result.Coverage
+		}
+		rules := map[css.StyleSheetID][]*css.RuleUsage{}
+		ids := []css.StyleSheetID{}
+		for _, rule := range usage {
+			if _, ok := rules[rule.StyleSheetID]; !ok {
+				ids = append(ids, rule.StyleSheetID)
+			}
+			rules[rule.StyleSheetID] = append(rules[rule.StyleSheetID], rule)
+		}
+		for _, id := range ids {
+			cov.mux.Lock()
+			source, ok := cov.sheets[id]
+			cov.mux.Unlock()
+			if !ok {
+				continue
+			}
+			file := coverage.FromRuleUsage(source.url, source.text, rules[id])
+			file.SourceMapURL = source.sourceMapURL
+			cov.add(file)
+		}
+	}
+	return nil
+}
+
+/*
+add adds the coverage of a file to the report.
+*/
+func (cov *Coverage) add(file *coverage.File) {
+	cov.mux.Lock()
+	defer cov.mux.Unlock()
+	cov.report.Add(file)
+}
+
+/*
+Stop takes the remaining coverage, stops collecting coverage and returns the
+report. If source maps are enabled, files with a source map are replaced by
+their original sources; source maps that can't be loaded are logged and the
+generated file is reported.
+*/
+func (cov *Coverage) Stop(ctx context.Context) (*coverage.Report, error) {
+	cov.mux.Lock()
+	if cov.stopped {
+		cov.mux.Unlock()
+		return nil, errs.New(codes.CoverageFailed, "coverage is stopped")
+	}
+	cov.stopped = true
+	cov.mux.Unlock()
+	defer cov.removeHandlers()
+
+	if err := cov.take(ctx, true); nil != err {
+		return nil, err
+	}
+	commands := []*protocolCommand{}
+	if cov.opts.javaScript() {
+		commands = append(commands,
+			&protocolCommand{"Profiler.stopPreciseCoverage", func(protocol socket.Protocoller) error {
+				return (<-protocol.Profiler().StopPreciseCoverage()).Err
+			}},
+			&protocolCommand{"Profiler.disable", func(protocol socket.Protocoller) error {
+				return (<-protocol.Profiler().Disable()).Err
+			}},
+			&protocolCommand{"Debugger.disable", func(protocol socket.Protocoller) error {
+				return (<-protocol.Debugger().Disable()).Err
+			}},
+		)
+	}
+	if cov.opts.css() {
+		commands = append(commands,
+			&protocolCommand{"CSS.disable", func(protocol socket.Protocoller) error {
+				return (<-protocol.CSS().Disable()).Err
+			}},
+			&protocolCommand{"DOM.disable", func(protocol socket.Protocoller) error {
+				return (<-protocol.DOM().Disable()).Err
+			}},
+		)
+	}
+	protocol := cov.tab.withContext(ctx)
+	for _, command := range commands {
+		if err := command.send(protocol); nil != err {
+			log.Warnf("%s failed: %s", command.method, err)
+		}
+	}
+
+	cov.mux.Lock()
+	defer cov.mux.Unlock()
+	if cov.opts.SourceMaps {
+		if err := cov.report.ApplySourceMaps(func(url string) ([]byte, error) {
+			return cov.tab.fetchSourceMap(ctx, "", url)
+		}); nil != err {
+			log.Warnf("could not apply source maps: %s", err)
+		}
+	}
+	return cov.report, nil
+}
+
+/*
+removeHandlers removes the coverage event handlers.
+*/
+func (cov *Coverage) removeHandlers() {
+	for _, handler := range cov.handlers {
+		cov.tab.RemoveEventHandler(handler)
+	}
+}
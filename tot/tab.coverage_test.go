@@ -0,0 +1,155 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/css"
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/profiler"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+wholeScript returns the coverage of a script that ran once.
+*/
+func wholeScript(id runtime.ScriptID, url string, length int) *profiler.ScriptCoverage {
+	return &profiler.ScriptCoverage{
+		ScriptID: id,
+		URL:      url,
+		Functions: []*profiler.FunctionCoverage{
+			{Ranges: []*profiler.CoverageRange{{StartOffset: 0, EndOffset: length, Count: 1}}},
+		},
+	}
+}
+
+func TestTabCoverage(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabCoverage")
+	sources := map[string]string{
+		"1":  "a();\nb();\n",
+		"3":  "c();",
+		"s1": "a {}\n.b {}\n",
+	}
+	mockSocket.Respond("Debugger.getScriptSource", func(params interface{}) (interface{}, *socket.Error) {
+		return &debugger.GetScriptSourceResult{ScriptSource: sources[string(params.(*debugger.GetScriptSourceParams).ScriptID)]}, nil
+	})
+	mockSocket.Respond("CSS.getStyleSheetText", func(params interface{}) (interface{}, *socket.Error) {
+		return &css.GetStyleSheetTextResult{Text: sources[string(params.(*css.GetStyleSheetTextParams).StyleSheetID)]}, nil
+	})
+
+	cov, err := tab.StartCoverage(context.Background(), nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params := mockSocket.Commands("Profiler.startPreciseCoverage")[0].Params().(*profiler.StartPreciseCoverageParams)
+	if !params.CallCount || !params.Detailed {
+		t.Errorf("Expected block coverage with call counts, received %+v", params)
+	}
+	if 1 != len(mockSocket.Commands("CSS.startRuleUsageTracking")) {
+		t.Errorf("Expected CSS rule usage to be tracked")
+	}
+
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "1", URL: "https://example.com/app.js"})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "2"})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "3", URL: "https://example.com/", StartLine: 4, StartColumn: 8})
+	mockSocket.Fire("CSS.styleSheetAdded", &css.StyleSheetAddedEvent{Header: &css.StyleSheetHeader{StyleSheetID: "s1", SourceURL: "https://example.com/app.css", Origin: css.StyleSheetOrigin.Log}})
+	if 2 != len(mockSocket.Commands("Debugger.getScriptSource")) {
+		t.Errorf("Expected the sources of scripts with a URL to be fetched")
+	}
+
+	mockSocket.Respond("Profiler.takePreciseCoverage", func(params interface{}) (interface{}, *socket.Error) {
+		return &profiler.TakePreciseCoverageResult{Result: []*profiler.ScriptCoverage{
+			wholeScript("1", "https://example.com/app.js", 10),
+			wholeScript("3", "https://example.com/", 4),
+		}}, nil
+	})
+	mockSocket.Respond("CSS.takeCoverageDelta", func(params interface{}) (interface{}, *socket.Error) {
+		return &css.TakeCoverageDeltaResult{Coverage: []*css.RuleUsage{{StyleSheetID: "s1", StartOffset: 0, EndOffset: 4, Used: true}}}, nil
+	})
+	if err := cov.Take(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	mockSocket.Respond("CSS.stopRuleUsageTracking", func(params interface{}) (interface{}, *socket.Error) {
+		return &css.StopRuleUsageTrackingResult{RuleUsage: []*css.RuleUsage{
+			{StyleSheetID: "s1", StartOffset: 0, EndOffset: 4, Used: false},
+			{StyleSheetID: "s1", StartOffset: 5, EndOffset: 10, Used: false},
+		}}, nil
+	})
+	report, err := cov.Stop(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 3 != len(report.Files) {
+		t.Fatalf("Expected 3 files, received %s", report)
+	}
+	if file := report.File("https://example.com/app.js"); nil == file || 2 != file.Lines[1].Count {
+		t.Errorf("Expected the coverage of both takes to be added, received %+v", file)
+	}
+	if nil == report.File("https://example.com/#5:9") {
+		t.Errorf("Expected the inline script to be reported by position")
+	}
+	if covered, total := report.File("https://example.com/app.css").Summary(); 1 != covered || 2 != total {
+		t.Errorf("Expected 1 of 2 rules to be used, received %d of %d", covered, total)
+	}
+	for _, method := range []string{"Profiler.stopPreciseCoverage", "Debugger.disable", "CSS.disable"} {
+		if 1 != len(mockSocket.Commands(method)) {
+			t.Errorf("Expected %s to be sent", method)
+		}
+	}
+
+	if _, err := cov.Stop(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if err := cov.Take(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabCoverageSourceMaps(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabCoverageSourceMaps")
+	mockSocket.Respond("Debugger.getScriptSource", func(params interface{}) (interface{}, *socket.Error) {
+		return &debugger.GetScriptSourceResult{ScriptSource: "a();"}, nil
+	})
+	fetched := ""
+	mockSocket.Respond("Page.getResourceContent", func(params interface{}) (interface{}, *socket.Error) {
+		fetched = params.(*page.GetResourceContentParams).URL
+		return &page.GetResourceContentResult{Content: `{"version":3,"sources":["src/a.js"],"names":[],"mappings":"AAAA"}`}, nil
+	})
+	cov, err := tab.StartCoverage(context.Background(), &CoverageOptions{JavaScript: true, SourceMaps: true})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 0 != len(mockSocket.Commands("CSS.enable")) {
+		t.Errorf("Expected CSS coverage not to be collected")
+	}
+	// Chrome reports a boolean isDefault in the aux data.
+	mockSocket.Fire("Debugger.scriptParsed", json.RawMessage(`{"scriptId":"1","url":"https://example.com/dist/app.js","sourceMapURL":"app.js.map","executionContextAuxData":{"isDefault":true,"frameId":"F1"}}`))
+	mockSocket.Respond("Profiler.takePreciseCoverage", func(params interface{}) (interface{}, *socket.Error) {
+		return &profiler.TakePreciseCoverageResult{Result: []*profiler.ScriptCoverage{wholeScript("1", "https://example.com/dist/app.js", 4)}}, nil
+	})
+
+	report, err := cov.Stop(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/dist/app.js.map" != fetched {
+		t.Errorf("Expected the source map to be fetched, received '%s'", fetched)
+	}
+	if 1 != len(report.Files) || "https://example.com/dist/src/a.js" != report.Files[0].URL {
+		t.Errorf("Expected the original source to be reported, received %s", report)
+	}
+}
+
+func TestTabCoverageError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabCoverageError")
+	mockSocket.Respond("Profiler.startPreciseCoverage", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Profiler is not enabled"}
+	})
+	if _, err := tab.StartCoverage(context.Background(), nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
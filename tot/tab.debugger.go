@@ -0,0 +1,750 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/sourcemap"
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+debuggerPauseBuffer is the number of pauses buffered for the Pauses channel.
+*/
+const debuggerPauseBuffer = 16
+
+/*
+Debugger drives the JavaScript debugger of a tab. It tracks parsed scripts,
+sets breakpoints by file and line and delivers pauses on a channel. Lines and
+columns are 1-based.
+
+	dbg, err := tab.StartDebugger(ctx)
+	...
+	_, err = dbg.SetBreakpoint(ctx, "src/app.ts", 12, nil)
+	...
+	pause, err := dbg.WaitForPause(ctx)
+	...
+	var total int
+	err = pause.CallFrames[0].Eval(ctx, "total", &total)
+	...
+	err = dbg.Resume(ctx)
+*/
+type Debugger struct {
+	breakpoints map[debugger.BreakpointID]*Breakpoint
+	handlers    []socket.EventHandler
+	mux         sync.Mutex
+	parsed      chan struct{}
+	paused      *Pause
+	pauses      chan *Pause
+	sequence    int
+	sourceMaps  *SourceMaps
+	stopped     bool
+	tab         *Tab
+}
+
+/*
+Script is a script parsed by the page.
+*/
+type Script struct {
+	// The script ID.
+	ID runtime.ScriptID
+
+	// The script URL. Inline scripts have the URL of their document.
+	URL string
+
+	// Optional. The URL of the script's source map.
+	SourceMapURL string
+
+	// The hash of the script content.
+	Hash string
+
+	// The position of the script in its resource (0-based), non-zero for
+	// inline scripts.
+	StartLine   int
+	StartColumn int
+
+	// The execution context the script was parsed in and its frame.
+	ExecutionContextID runtime.ExecutionContextID
+	FrameID            page.FrameID
+}
+
+/*
+BreakpointOptions configures a breakpoint.
+*/
+type BreakpointOptions struct {
+	// Optional. The column to break at (1-based), the first statement of the
+	// line by default.
+	Column int
+
+	// Optional. A JavaScript expression, the debugger only pauses if it is
+	// true.
+	Condition string
+
+	// Optional. Turns the breakpoint into a logpoint that logs the message to
+	// the console instead of pausing. Expressions in braces are evaluated,
+	// e.g. "total is {total}".
+	LogMessage string
+}
+
+/*
+Breakpoint is a breakpoint set by the debugger.
+*/
+type Breakpoint struct {
+	// The breakpoint ID.
+	ID debugger.BreakpointID
+
+	// The file and position the breakpoint was requested for (1-based).
+	File   string
+	Line   int
+	Column int
+
+	// The generated script and position the breakpoint was set on (1-based)
+	// if the file is an original source resolved through a source map.
+	URL           string
+	GeneratedLine int
+
+	// The condition sent to the debugger, including logpoints.
+	Condition string
+
+	dbg       *Debugger
+	locations []*debugger.Location
+}
+
+/*
+Pause is a pause of the debugger.
+*/
+type Pause struct {
+	// The reason for the pause, e.g. debugger.Reason.Other for breakpoints or
+	// debugger.Reason.Exception.
+	Reason debugger.ReasonEnum
+
+	// The breakpoints that were hit.
+	HitBreakpoints []debugger.BreakpointID
+
+	// The call stack, innermost frame first.
+	CallFrames []*CallFrame
+
+	// Optional. Reason specific data, e.g. the exception object.
+	Data json.RawMessage
+}
+
+/*
+CallFrame is a call frame of a paused call stack.
+*/
+type CallFrame struct {
+	// The call frame ID.
+	ID debugger.CallFrameID
+
+	// The name of the function, empty for top level code.
+	FunctionName string
+
+	// The script and position of the frame (1-based).
+	ScriptID runtime.ScriptID
+	URL      string
+	Line     int
+	Column   int
+
+	// The scope chain of the frame, innermost scope first.
+	Scopes []*Scope
+
+	sourceMaps *SourceMaps
+	tab        *Tab
+}
+
+/*
+Scope is a scope of a call frame.
+*/
+type Scope struct {
+	// The scope type, e.g. debugger.ScopeType.Local.
+	Type debugger.ScopeTypeEnum
+
+	// Optional. The name of the scope, e.g. the function name.
+	Name string
+
+	object *runtime.RemoteObject
+	tab    *Tab
+}
+
+/*
+StartDebugger enables the debugger and starts tracking parsed scripts. Scripts
+parsed before the debugger is started are reported when it is enabled.
+*/
+func (tab *Tab) StartDebugger(ctx context.Context) (*Debugger, error) {
+	dbg := &Debugger{
+		breakpoints: map[debugger.BreakpointID]*Breakpoint{},
+		parsed:      make(chan struct{}),
+		pauses:      make(chan *Pause, debuggerPauseBuffer),
+		sourceMaps:  newSourceMaps(tab),
+		tab:         tab,
+	}
+	dbg.handlers = []socket.EventHandler{
+		socket.NewEventHandler("Debugger.scriptParsed", func(response *socket.Response) {
+			event := &debugger.ScriptParsedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid scriptParsed event: %s", err)
+				return
+			}
+			dbg.scriptParsed(event)
+		}),
+		socket.NewEventHandler("Debugger.paused", func(response *socket.Response) {
+			event := &debugger.PausedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid paused event: %s", err)
+				return
+			}
+			dbg.pausedEvent(response.Sequence, event)
+		}),
+		socket.NewEventHandler("Debugger.resumed", func(response *socket.Response) {
+			dbg.mux.Lock()
+			if response.Sequence >= dbg.sequence {
+				dbg.sequence = response.Sequence
+				dbg.paused = nil
+			}
+			dbg.mux.Unlock()
+		}),
+		socket.NewEventHandler("Debugger.breakpointResolved", func(response *socket.Response) {
+			event := &debugger.BreakpointResolvedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid breakpointResolved event: %s", err)
+				return
+			}
+			dbg.mux.Lock()
+			if breakpoint, ok := dbg.breakpoints[event.BreakpointID]; ok {
+				breakpoint.locations = append(breakpoint.locations, event.Location)
+			}
+			dbg.mux.Unlock()
+		}),
+	}
+	for _, handler := range dbg.handlers {
+		tab.AddEventHandler(handler)
+	}
+
+	if result := <-tab.withContext(ctx).Debugger().Enable(); nil != result.Err {
+		dbg.removeHandlers()
+		return nil, errs.Wrap(result.Err, codes.DebuggerFailed, "Debugger.enable failed")
+	}
+	return dbg, nil
+}
+
+/*
+scriptParsed records a script and wakes up WaitForScript.
+*/
+func (dbg *Debugger) scriptParsed(event *debugger.ScriptParsedEvent) {
+	dbg.sourceMaps.scriptParsed(event)
+	dbg.mux.Lock()
+	defer dbg.mux.Unlock()
+	close(dbg.parsed)
+	dbg.parsed = make(chan struct{})
+}
+
+/*
+pausedEvent records the current pause and delivers it. Pauses are dropped with
+a warning if the channel is full.
+*/
+func (dbg *Debugger) pausedEvent(sequence int, event *debugger.PausedEvent) {
+	pause := &Pause{
+		Reason:         event.Reason,
+		HitBreakpoints: event.HitBreakpoints,
+		CallFrames:     make([]*CallFrame, 0, len(event.CallFrames)),
+		Data:           event.Data,
+	}
+
+	dbg.mux.Lock()
+	defer dbg.mux.Unlock()
+	for _, frame := range event.CallFrames {
+		callFrame := &CallFrame{
+			ID:           frame.CallFrameID,
+			FunctionName: frame.FunctionName,
+			URL:          frame.URL,
+			Scopes:       make([]*Scope, 0, len(frame.ScopeChain)),
+			sourceMaps:   dbg.sourceMaps,
+			tab:          dbg.tab,
+		}
+		if nil != frame.Location {
+			callFrame.ScriptID = frame.Location.ScriptID
+			callFrame.Line = int(frame.Location.LineNumber) + 1
+			callFrame.Column = int(frame.Location.ColumnNumber) + 1
+		}
+		if script := dbg.sourceMaps.Script(callFrame.ScriptID); "" == callFrame.URL && nil != script {
+			callFrame.URL = script.URL
+		}
+		for _, scope := range frame.ScopeChain {
+			callFrame.Scopes = append(callFrame.Scopes, &Scope{
+				Type:   scope.Type,
+				Name:   scope.Name,
+				object: scope.Object,
+				tab:    dbg.tab,
+			})
+		}
+		pause.CallFrames = append(pause.CallFrames, callFrame)
+	}
+
+	if sequence >= dbg.sequence {
+		dbg.sequence = sequence
+		dbg.paused = pause
+	}
+	if dbg.stopped {
+		return
+	}
+	select {
+	case dbg.pauses <- pause:
+	default:
+		log.Warnf("debugger pause dropped, %d pauses are waiting to be received", debuggerPauseBuffer)
+	}
+}
+
+/*
+Pauses returns the channel pauses are delivered on. The channel is closed when
+the debugger is stopped.
+*/
+func (dbg *Debugger) Pauses() <-chan *Pause {
+	return dbg.pauses
+}
+
+/*
+WaitForPause waits for the next pause.
+*/
+func (dbg *Debugger) WaitForPause(ctx context.Context) (*Pause, error) {
+	select {
+	case pause, ok := <-dbg.pauses:
+		if !ok {
+			return nil, errs.New(codes.DebuggerFailed, "the debugger is stopped")
+		}
+		return pause, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+/*
+Paused returns the current pause, or nil if the debugger isn't paused.
+*/
+func (dbg *Debugger) Paused() *Pause {
+	dbg.mux.Lock()
+	defer dbg.mux.Unlock()
+	return dbg.paused
+}
+
+/*
+Scripts returns the parsed scripts in the order they were parsed.
+*/
+func (dbg *Debugger) Scripts() []*Script {
+	return dbg.sourceMaps.Scripts()
+}
+
+/*
+Script returns a parsed script by ID, or nil.
+*/
+func (dbg *Debugger) Script(id runtime.ScriptID) *Script {
+	return dbg.sourceMaps.Script(id)
+}
+
+/*
+ScriptsByURL returns the parsed scripts whose URL is file or ends with the path
+file, e.g. "js/app.js".
+*/
+func (dbg *Debugger) ScriptsByURL(file string) []*Script {
+	return dbg.sourceMaps.ScriptsByURL(file)
+}
+
+/*
+SourceMaps returns the source maps of the parsed scripts, e.g. to map the
+stack traces of exceptions.
+*/
+func (dbg *Debugger) SourceMaps() *SourceMaps {
+	return dbg.sourceMaps
+}
+
+/*
+WaitForScript waits until a script matching file is parsed and returns it.
+*/
+func (dbg *Debugger) WaitForScript(ctx context.Context, file string) (*Script, error) {
+	for {
+		dbg.mux.Lock()
+		parsed := dbg.parsed
+		dbg.mux.Unlock()
+		if scripts := dbg.ScriptsByURL(file); len(scripts) > 0 {
+			return scripts[len(scripts)-1], nil
+		}
+		select {
+		case <-parsed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+/*
+Source returns the source of a parsed script.
+*/
+func (dbg *Debugger) Source(ctx context.Context, id runtime.ScriptID) (string, error) {
+	result := <-dbg.tab.withContext(ctx).Debugger().GetScriptSource(&debugger.GetScriptSourceParams{
+		ScriptID: id,
+	})
+	if nil != result.Err {
+		return "", errs.Wrap(result.Err, codes.DebuggerFailed, fmt.Sprintf("could not get the source of script %s", id))
+	}
+	return result.ScriptSource, nil
+}
+
+/*
+SetBreakpoint sets a breakpoint on a line (1-based) of a file. The file is a
+URL or the end of a URL path, e.g. "js/app.js", and the breakpoint applies to
+matching scripts parsed now and later. Files that are original sources of a
+parsed script's source map, e.g. "src/app.ts", are resolved to the generated
+script; set those after the script is parsed.
+
+	// Log instead of pausing.
+	_, err := dbg.SetBreakpoint(ctx, "app.js", 12, &BreakpointOptions{
+		LogMessage: "total is {total}",
+	})
+*/
+func (dbg *Debugger) SetBreakpoint(ctx context.Context, file string, line int, opts *BreakpointOptions) (*Breakpoint, error) {
+	if nil == opts {
+		opts = &BreakpointOptions{}
+	}
+	if "" == file || line < 1 || opts.Column < 0 {
+		return nil, errs.New(codes.DebuggerBreakpointInvalid, fmt.Sprintf("invalid breakpoint location %s:%d:%d", file, line, opts.Column))
+	}
+	condition, err := breakpointCondition(opts)
+	if nil != err {
+		return nil, err
+	}
+
+	breakpoint := &Breakpoint{
+		File:      file,
+		Line:      line,
+		Column:    opts.Column,
+		Condition: condition,
+		dbg:       dbg,
+	}
+	params := &debugger.SetBreakpointByURLParams{
+		LineNumber: int64(line - 1),
+		Condition:  condition,
+	}
+	if opts.Column > 0 {
+		params.ColumnNumber = int64(opts.Column - 1)
+	}
+	if script, genLine, genColumn, ok := dbg.generated(ctx, file, line-1, params.ColumnNumber); ok {
+		breakpoint.URL = script.URL
+		breakpoint.GeneratedLine = genLine + 1
+		params.URL = script.URL
+		params.LineNumber = int64(genLine)
+		params.ColumnNumber = int64(genColumn)
+	} else if strings.Contains(file, "://") {
+		params.URL = file
+	} else {
+		params.URLRegex = "(^|/)" + regexp.QuoteMeta(strings.TrimPrefix(strings.TrimPrefix(file, "./"), "/")) + "([?#].*)?$"
+	}
+
+	result := <-dbg.tab.withContext(ctx).Debugger().SetBreakpointByURL(params)
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.DebuggerBreakpointInvalid, fmt.Sprintf("could not set a breakpoint at %s:%d", file, line))
+	}
+	breakpoint.ID = result.BreakpointID
+
+	dbg.mux.Lock()
+	breakpoint.locations = append(breakpoint.locations, result.Locations...)
+	dbg.breakpoints[breakpoint.ID] = breakpoint
+	dbg.mux.Unlock()
+	return breakpoint, nil
+}
+
+/*
+generated resolves an original source position (0-based) through the source
+maps of the parsed scripts. It returns the first script that maps the file and
+the generated position in the script's resource.
+*/
+func (dbg *Debugger) generated(ctx context.Context, file string, line int, column int64) (*Script, int, int, bool) {
+	for _, script := range dbg.Scripts() {
+		if "" == script.SourceMapURL || "" == script.URL {
+			continue
+		}
+		sm, err := dbg.sourceMaps.Map(ctx, script.ID)
+		if nil != err {
+			log.Warnf("%s", err)
+			continue
+		}
+		genLine, genColumn, ok := sm.Generated(file, line, int(column))
+		if !ok {
+			continue
+		}
+		// Positions in inline scripts are relative to the script.
+		if 0 == genLine {
+			genColumn += script.StartColumn
+		}
+		return script, genLine + script.StartLine, genColumn, true
+	}
+	return nil, 0, 0, false
+}
+
+/*
+breakpointCondition returns the condition of a breakpoint. Logpoints log their
+message and evaluate to false so that the debugger doesn't pause.
+*/
+func breakpointCondition(opts *BreakpointOptions) (string, error) {
+	if "" == opts.LogMessage {
+		return opts.Condition, nil
+	}
+	template, err := logpointTemplate(opts.LogMessage)
+	if nil != err {
+		return "", err
+	}
+	logpoint := fmt.Sprintf("console.log(%s), false", template)
+	if "" == opts.Condition {
+		return logpoint, nil
+	}
+	return fmt.Sprintf("(%s) && (%s)", opts.Condition, logpoint), nil
+}
+
+/*
+logpointTemplate converts a logpoint message into a JavaScript template
+literal, e.g. "total is {total}" into `total is ${total}`.
+*/
+func logpointTemplate(message string) (string, error) {
+	template := &strings.Builder{}
+	template.WriteString("`")
+	depth := 0
+	for _, char := range message {
+		switch {
+		case '{' == char:
+			if 0 == depth {
+				template.WriteString("${")
+			} else {
+				template.WriteRune(char)
+			}
+			depth++
+		case '}' == char && depth > 0:
+			depth--
+			template.WriteRune(char)
+		case 0 == depth && ('`' == char || '\\' == char || '$' == char):
+			template.WriteRune('\\')
+			template.WriteRune(char)
+		default:
+			template.WriteRune(char)
+		}
+	}
+	if 0 != depth {
+		return "", errs.New(codes.DebuggerBreakpointInvalid, fmt.Sprintf("unterminated expression in log message '%s'", message))
+	}
+	template.WriteString("`")
+	return template.String(), nil
+}
+
+/*
+Locations returns the script locations the breakpoint resolved to (0-based,
+as reported by the debugger).
+*/
+func (breakpoint *Breakpoint) Locations() []*debugger.Location {
+	breakpoint.dbg.mux.Lock()
+	defer breakpoint.dbg.mux.Unlock()
+	return append(breakpoint.locations[:0:0], breakpoint.locations...)
+}
+
+/*
+RemoveBreakpoint removes a breakpoint.
+*/
+func (dbg *Debugger) RemoveBreakpoint(ctx context.Context, breakpoint *Breakpoint) error {
+	if result := <-dbg.tab.withContext(ctx).Debugger().RemoveBreakpoint(&debugger.RemoveBreakpointParams{
+		BreakpointID: breakpoint.ID,
+	}); nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, fmt.Sprintf("could not remove breakpoint %s", breakpoint.ID))
+	}
+	dbg.mux.Lock()
+	delete(dbg.breakpoints, breakpoint.ID)
+	dbg.mux.Unlock()
+	return nil
+}
+
+/*
+SetPauseOnExceptions sets whether the debugger pauses on exceptions.
+*/
+func (dbg *Debugger) SetPauseOnExceptions(ctx context.Context, state debugger.StateEnum) error {
+	if result := <-dbg.tab.withContext(ctx).Debugger().SetPauseOnExceptions(&debugger.SetPauseOnExceptionsParams{
+		State: state,
+	}); nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, fmt.Sprintf("could not pause on '%s' exceptions", state))
+	}
+	return nil
+}
+
+/*
+Pause pauses the page at the next statement.
+*/
+func (dbg *Debugger) Pause(ctx context.Context) error {
+	if result := <-dbg.tab.withContext(ctx).Debugger().Pause(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, "Debugger.pause failed")
+	}
+	return nil
+}
+
+/*
+Resume resumes the paused page.
+*/
+func (dbg *Debugger) Resume(ctx context.Context) error {
+	return dbg.step(ctx, &protocolCommand{"Debugger.resume", func(protocol socket.Protocoller) error {
+		return (<-protocol.Debugger().Resume()).Err
+	}})
+}
+
+/*
+StepOver steps to the next statement of the paused function.
+*/
+func (dbg *Debugger) StepOver(ctx context.Context) error {
+	return dbg.step(ctx, &protocolCommand{"Debugger.stepOver", func(protocol socket.Protocoller) error {
+		return (<-protocol.Debugger().StepOver()).Err
+	}})
+}
+
+/*
+StepInto steps into the function called by the paused statement.
+*/
+func (dbg *Debugger) StepInto(ctx context.Context) error {
+	return dbg.step(ctx, &protocolCommand{"Debugger.stepInto", func(protocol socket.Protocoller) error {
+		return (<-protocol.Debugger().StepInto(&debugger.StepIntoParams{})).Err
+	}})
+}
+
+/*
+StepOut steps out of the paused function.
+*/
+func (dbg *Debugger) StepOut(ctx context.Context) error {
+	return dbg.step(ctx, &protocolCommand{"Debugger.stepOut", func(protocol socket.Protocoller) error {
+		return (<-protocol.Debugger().StepOut()).Err
+	}})
+}
+
+/*
+step sends a command that resumes the paused page. Stepping pauses again and
+delivers the new pause.
+*/
+func (dbg *Debugger) step(ctx context.Context, command *protocolCommand) error {
+	if nil == dbg.Paused() {
+		return errs.New(codes.DebuggerNotPaused, fmt.Sprintf("%s requires a paused debugger", command.method))
+	}
+	if err := command.send(dbg.tab.withContext(ctx)); nil != err {
+		return errs.Wrap(err, codes.DebuggerFailed, fmt.Sprintf("%s failed", command.method))
+	}
+	return nil
+}
+
+/*
+Stop disables the debugger, which resumes a paused page, and closes the Pauses
+channel.
+*/
+func (dbg *Debugger) Stop(ctx context.Context) error {
+	dbg.mux.Lock()
+	if dbg.stopped {
+		dbg.mux.Unlock()
+		return nil
+	}
+	dbg.stopped = true
+	dbg.paused = nil
+	close(dbg.pauses)
+	dbg.mux.Unlock()
+
+	dbg.removeHandlers()
+	if result := <-dbg.tab.withContext(ctx).Debugger().Disable(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, "Debugger.disable failed")
+	}
+	return nil
+}
+
+/*
+removeHandlers removes the debugger event handlers.
+*/
+func (dbg *Debugger) removeHandlers() {
+	for _, handler := range dbg.handlers {
+		dbg.tab.RemoveEventHandler(handler)
+	}
+}
+
+/*
+Original returns the position of the call frame in its original source if the
+script has a source map. The position is 0-based.
+*/
+func (frame *CallFrame) Original(ctx context.Context) (*sourcemap.Position, bool) {
+	return frame.sourceMaps.Original(ctx, frame.ScriptID, frame.Line-1, frame.Column-1)
+}
+
+/*
+Eval evaluates a JavaScript expression in the scope of the call frame and
+decodes the value into out. out may be nil to discard the result. A JSError is
+returned if the expression throws.
+*/
+func (frame *CallFrame) Eval(ctx context.Context, expression string, out interface{}) error {
+	result := <-frame.tab.withContext(ctx).Debugger().EvaluateOnCallFrame(&debugger.EvaluateOnCallFrameParams{
+		CallFrameID:   frame.ID,
+		Expression:    expression,
+		ReturnByValue: true,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, "evaluation failed")
+	}
+	if nil != result.ExceptionDetails {
+		return newJSError(result.ExceptionDetails)
+	}
+	return decodeRemoteValue(result.Result, out)
+}
+
+/*
+Locals returns the variables of the frame's local scope.
+*/
+func (frame *CallFrame) Locals(ctx context.Context) (map[string]*JSValue, error) {
+	for _, scope := range frame.Scopes {
+		if debugger.ScopeType.Local == scope.Type {
+			return scope.Variables(ctx)
+		}
+	}
+	return map[string]*JSValue{}, nil
+}
+
+/*
+Variables returns the variables of the scope by name. Objects are returned by
+value if they can be serialized, otherwise Decode leaves the target unchanged.
+*/
+func (scope *Scope) Variables(ctx context.Context) (map[string]*JSValue, error) {
+	variables := map[string]*JSValue{}
+	if nil == scope.object || "" == scope.object.ObjectID {
+		return variables, nil
+	}
+	result := <-scope.tab.withContext(ctx).Runtime().GetProperties(&runtime.GetPropertiesParams{
+		ObjectID:      scope.object.ObjectID,
+		OwnProperties: true,
+	})
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.DebuggerFailed, fmt.Sprintf("could not get the variables of the %s scope", scope.Type))
+	}
+	if nil != result.ExceptionDetails {
+		return nil, newJSError(result.ExceptionDetails)
+	}
+
+	for _, property := range result.Result {
+		if nil == property.Value {
+			continue
+		}
+		value := property.Value
+		if runtime.ObjectType.Object == value.Type && "" != value.ObjectID && runtime.ObjectSubtype.Node != value.Subtype {
+			byValue, err := scope.tab.callFunctionOn(ctx, &runtime.CallFunctionOnParams{
+				FunctionDeclaration: "function() { return this; }",
+				ObjectID:            value.ObjectID,
+				ReturnByValue:       true,
+			})
+			if nil == err {
+				value = byValue
+			}
+		}
+		variables[property.Name] = &JSValue{value: value}
+	}
+	return variables, nil
+}
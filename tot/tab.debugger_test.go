@@ -0,0 +1,264 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+testDebuggerSourceMap maps the second line of app.js to the second line of
+src/app.ts.
+*/
+const testDebuggerSourceMap = `{"version":3,"sources":["src/app.ts"],"mappings":"AAAA;EACA"}`
+
+func TestTabDebugger(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabDebugger")
+	mockSocket.Respond("Debugger.setBreakpointByUrl", func(params interface{}) (interface{}, *socket.Error) {
+		return &debugger.SetBreakpointByURLResult{
+			BreakpointID: debugger.BreakpointID(fmt.Sprintf("bp%d", len(mockSocket.Commands("Debugger.setBreakpointByUrl")))),
+		}, nil
+	})
+
+	dbg, err := tab.StartDebugger(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 1 != len(mockSocket.Commands("Debugger.enable")) {
+		t.Errorf("Expected the debugger to be enabled")
+	}
+
+	sourceMapURL := "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(testDebuggerSourceMap))
+	mockSocket.Fire("Debugger.scriptParsed", json.RawMessage(`{"scriptId":"1","url":"https://example.com/js/app.js","sourceMapURL":"`+sourceMapURL+`","executionContextAuxData":{"isDefault":true}}`))
+	mockSocket.Fire("Debugger.scriptParsed", json.RawMessage(`{"scriptId":"2","url":"https://example.com/","startLine":4,"startColumn":8}`))
+	if 2 != len(dbg.Scripts()) || 4 != dbg.Script("2").StartLine || nil != dbg.Script("3") {
+		t.Errorf("Unexpected scripts %+v", dbg.Scripts())
+	}
+	if scripts := dbg.ScriptsByURL("js/app.js"); 1 != len(scripts) || "1" != scripts[0].ID {
+		t.Errorf("Unexpected scripts %+v", scripts)
+	}
+
+	// Original sources resolve to the generated script.
+	mapped, err := dbg.SetBreakpoint(context.Background(), "src/app.ts", 2, nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params := mockSocket.Commands("Debugger.setBreakpointByUrl")[0].Params().(*debugger.SetBreakpointByURLParams)
+	if "https://example.com/js/app.js" != params.URL || 1 != params.LineNumber || 2 != params.ColumnNumber {
+		t.Errorf("Unexpected breakpoint parameters %+v", params)
+	}
+	if "bp1" != mapped.ID || 2 != mapped.GeneratedLine {
+		t.Errorf("Unexpected breakpoint %+v", mapped)
+	}
+
+	logpoint, err := dbg.SetBreakpoint(context.Background(), "lib.js", 3, &BreakpointOptions{Condition: "x > 1", LogMessage: "x is {x}"})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params = mockSocket.Commands("Debugger.setBreakpointByUrl")[1].Params().(*debugger.SetBreakpointByURLParams)
+	if `(^|/)lib\.js([?#].*)?$` != params.URLRegex || 2 != params.LineNumber {
+		t.Errorf("Unexpected breakpoint parameters %+v", params)
+	}
+	if "(x > 1) && (console.log(`x is ${x}`), false)" != params.Condition {
+		t.Errorf("Unexpected condition %s", params.Condition)
+	}
+	mockSocket.Fire("Debugger.breakpointResolved", &debugger.BreakpointResolvedEvent{BreakpointID: "bp2", Location: &debugger.Location{ScriptID: "3", LineNumber: 2}})
+	if locations := logpoint.Locations(); 1 != len(locations) || "3" != locations[0].ScriptID {
+		t.Errorf("Unexpected locations %+v", locations)
+	}
+
+	if err := dbg.StepOver(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	} else if codes.DebuggerNotPaused != err.(*errs.Err).Code() {
+		t.Errorf("Expected code %d, received %d", codes.DebuggerNotPaused, err.(*errs.Err).Code())
+	}
+
+	mockSocket.Fire("Debugger.paused", json.RawMessage(`{
+		"reason": "other",
+		"hitBreakpoints": ["bp1"],
+		"callFrames": [{
+			"callFrameId": "frame1",
+			"functionName": "f",
+			"location": {"scriptId": "1", "lineNumber": 1, "columnNumber": 2},
+			"url": "",
+			"scopeChain": [
+				{"type": "local", "object": {"type": "object", "objectId": "scope1"}},
+				{"type": "script", "object": {"type": "object", "objectId": "scope2"}}
+			]
+		}]
+	}`))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	pause, err := dbg.WaitForPause(ctx)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if debugger.Reason.Other != pause.Reason || 1 != len(pause.HitBreakpoints) || 1 != len(pause.CallFrames) || pause != dbg.Paused() {
+		t.Fatalf("Unexpected pause %+v", pause)
+	}
+	frame := pause.CallFrames[0]
+	if "https://example.com/js/app.js" != frame.URL || 2 != frame.Line || 3 != frame.Column || 2 != len(frame.Scopes) {
+		t.Errorf("Unexpected call frame %+v", frame)
+	}
+	if pos, ok := frame.Original(context.Background()); !ok || "https://example.com/js/src/app.ts" != pos.Source || 1 != pos.Line {
+		t.Errorf("Unexpected original position %+v", pos)
+	}
+
+	mockSocket.Respond("Debugger.evaluateOnCallFrame", func(params interface{}) (interface{}, *socket.Error) {
+		if "frame1" != params.(*debugger.EvaluateOnCallFrameParams).CallFrameID {
+			return nil, &socket.Error{Code: 1, Message: "Invalid call frame"}
+		}
+		return json.RawMessage(`{"result":{"type":"number","value":3}}`), nil
+	})
+	var total int
+	if err := frame.Eval(context.Background(), "total", &total); nil != err || 3 != total {
+		t.Errorf("Expected 3, received %d (%v)", total, err)
+	}
+
+	mockSocket.Respond("Runtime.getProperties", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":[
+			{"name":"total","value":{"type":"number","value":3}},
+			{"name":"items","value":{"type":"object","subtype":"array","objectId":"items1"}},
+			{"name":"accessor","get":{"type":"function"}}
+		]}`), nil
+	})
+	mockSocket.Respond("Runtime.callFunctionOn", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"object","subtype":"array","value":[1,2]}}`), nil
+	})
+	locals, err := frame.Locals(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	items := []int{}
+	if err := locals["items"].Decode(&items); nil != err || 2 != len(items) || 2 != len(locals) {
+		t.Errorf("Unexpected locals %+v (%v)", locals, err)
+	}
+	if "scope1" != mockSocket.Commands("Runtime.getProperties")[0].Params().(*runtime.GetPropertiesParams).ObjectID {
+		t.Errorf("Expected the variables of the local scope")
+	}
+
+	if err := dbg.StepOver(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	mockSocket.Fire("Debugger.resumed", json.RawMessage(`{}`))
+	if nil != dbg.Paused() {
+		t.Errorf("Expected the debugger to be resumed")
+	}
+	if 1 != len(mockSocket.Commands("Debugger.stepOver")) {
+		t.Errorf("Expected Debugger.stepOver to be sent")
+	}
+
+	if err := dbg.RemoveBreakpoint(context.Background(), logpoint); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if err := dbg.Stop(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if _, ok := <-dbg.Pauses(); ok {
+		t.Errorf("Expected the pause channel to be closed")
+	}
+	if 1 != len(mockSocket.Commands("Debugger.disable")) {
+		t.Errorf("Expected the debugger to be disabled")
+	}
+}
+
+func TestTabDebuggerPauseOrder(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabDebuggerPauseOrder")
+	mockSocket.Concurrent()
+	dbg, err := tab.StartDebugger(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	defer dbg.Stop(context.Background())
+
+	// Handlers run concurrently, a pause handled after the resume that
+	// followed it doesn't pause the debugger.
+	paused := mockSocket.Event("Debugger.paused", json.RawMessage(`{"reason":"other","callFrames":[]}`))
+	resumed := mockSocket.Event("Debugger.resumed", json.RawMessage(`{}`))
+	mockSocket.FireEvent(resumed)
+	mockSocket.FireEvent(paused)
+	mockSocket.Wait()
+	if nil != dbg.Paused() {
+		t.Errorf("Expected the debugger to be resumed, received %+v", dbg.Paused())
+	}
+
+	mockSocket.Fire("Debugger.paused", json.RawMessage(`{"reason":"exception","callFrames":[]}`))
+	mockSocket.Wait()
+	if pause := dbg.Paused(); nil == pause || debugger.Reason.Exception != pause.Reason {
+		t.Errorf("Expected the debugger to be paused, received %+v", pause)
+	}
+}
+
+func TestTabDebuggerWaitForScript(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabDebuggerWaitForScript")
+	dbg, err := tab.StartDebugger(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	go mockSocket.Fire("Debugger.scriptParsed", json.RawMessage(`{"scriptId":"1","url":"https://example.com/app.js?v=1"}`))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if script, err := dbg.WaitForScript(ctx, "https://example.com/app.js?v=1"); nil != err || "1" != script.ID {
+		t.Errorf("Unexpected script %+v (%v)", script, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := dbg.WaitForScript(ctx, "missing.js"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabDebuggerErrors(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabDebuggerErrors")
+	dbg, err := tab.StartDebugger(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, test := range []struct {
+		file string
+		line int
+		opts *BreakpointOptions
+	}{
+		{"", 1, nil},
+		{"app.js", 0, nil},
+		{"app.js", 1, &BreakpointOptions{LogMessage: "x is {x"}},
+	} {
+		if _, err := dbg.SetBreakpoint(context.Background(), test.file, test.line, test.opts); nil == err {
+			t.Errorf("%s:%d: expected error, received nil", test.file, test.line)
+		}
+	}
+	if 0 != len(mockSocket.Commands("Debugger.setBreakpointByUrl")) {
+		t.Errorf("Expected invalid breakpoints not to be sent")
+	}
+
+	mockSocket.Respond("Debugger.enable", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Debugger agent is not enabled"}
+	})
+	if _, err := tab.StartDebugger(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestLogpointTemplate(t *testing.T) {
+	for message, expected := range map[string]string{
+		"hit":                  "`hit`",
+		"x is {x}":             "`x is ${x}`",
+		"{a} and {({b: 1}).b}": "`${a} and ${({b: 1}).b}`",
+		"cost: $5 `quoted` \\": "`cost: \\$5 \\`quoted\\` \\\\`",
+	} {
+		template, err := logpointTemplate(message)
+		if nil != err || expected != template {
+			t.Errorf("%s: expected %s, received %s (%v)", message, expected, template, err)
+		}
+	}
+}
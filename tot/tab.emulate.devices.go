@@ -0,0 +1,88 @@
+package chrome
+
+/*
+Devices is the catalog of built-in device descriptors, keyed by name. Mobile
+devices are described in portrait orientation; use Device.Landscape for the
+rotated variant. Custom descriptors loaded with LoadDevices may be added to the
+catalog.
+*/
+var Devices = map[string]*Device{
+	"Galaxy S5": {
+		Name:      "Galaxy S5",
+		UserAgent: "Mozilla/5.0 (Linux; Android 5.0; SM-G900P Build/LRX21T) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3765.0 Mobile Safari/537.36",
+		Viewport:  DeviceViewport{Width: 360, Height: 640, DeviceScaleFactor: 3, IsMobile: true, HasTouch: true},
+	},
+	"Galaxy S9+": {
+		Name:      "Galaxy S9+",
+		UserAgent: "Mozilla/5.0 (Linux; Android 8.0.0; SM-G965U Build/R16NW) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/63.0.3239.111 Mobile Safari/537.36",
+		Viewport:  DeviceViewport{Width: 320, Height: 658, DeviceScaleFactor: 4.5, IsMobile: true, HasTouch: true},
+	},
+	"iPad": {
+		Name:      "iPad",
+		UserAgent: "Mozilla/5.0 (iPad; CPU OS 11_0 like Mac OS X) AppleWebKit/604.1.34 (KHTML, like Gecko) Version/11.0 Mobile/15A5341f Safari/604.1",
+		Viewport:  DeviceViewport{Width: 768, Height: 1024, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"iPad Pro": {
+		Name:      "iPad Pro",
+		UserAgent: "Mozilla/5.0 (iPad; CPU OS 11_0 like Mac OS X) AppleWebKit/604.1.34 (KHTML, like Gecko) Version/11.0 Mobile/15A5341f Safari/604.1",
+		Viewport:  DeviceViewport{Width: 1024, Height: 1366, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"iPhone SE": {
+		Name:      "iPhone SE",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 10_3_1 like Mac OS X) AppleWebKit/603.1.30 (KHTML, like Gecko) Version/10.0 Mobile/14E304 Safari/602.1",
+		Viewport:  DeviceViewport{Width: 320, Height: 568, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"iPhone 8": {
+		Name:      "iPhone 8",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 11_0 like Mac OS X) AppleWebKit/604.1.38 (KHTML, like Gecko) Version/11.0 Mobile/15A372 Safari/604.1",
+		Viewport:  DeviceViewport{Width: 375, Height: 667, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"iPhone 8 Plus": {
+		Name:      "iPhone 8 Plus",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 11_0 like Mac OS X) AppleWebKit/604.1.38 (KHTML, like Gecko) Version/11.0 Mobile/15A372 Safari/604.1",
+		Viewport:  DeviceViewport{Width: 414, Height: 736, DeviceScaleFactor: 3, IsMobile: true, HasTouch: true},
+	},
+	"iPhone X": {
+		Name:      "iPhone X",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 11_0 like Mac OS X) AppleWebKit/604.1.38 (KHTML, like Gecko) Version/11.0 Mobile/15A372 Safari/604.1",
+		Viewport:  DeviceViewport{Width: 375, Height: 812, DeviceScaleFactor: 3, IsMobile: true, HasTouch: true},
+	},
+	"iPhone 11": {
+		Name:      "iPhone 11",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 13_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/12.0 Mobile/15E148 Safari/604.1",
+		Viewport:  DeviceViewport{Width: 414, Height: 828, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"iPhone 12 Pro": {
+		Name:      "iPhone 12 Pro",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_7_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.2 Mobile/15E148 Safari/604.1",
+		Viewport:  DeviceViewport{Width: 390, Height: 844, DeviceScaleFactor: 3, IsMobile: true, HasTouch: true},
+	},
+	"Nexus 7": {
+		Name:      "Nexus 7",
+		UserAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 7 Build/MOB30X) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3765.0 Safari/537.36",
+		Viewport:  DeviceViewport{Width: 600, Height: 960, DeviceScaleFactor: 2, IsMobile: true, HasTouch: true},
+	},
+	"Pixel 2": {
+		Name:      "Pixel 2",
+		UserAgent: "Mozilla/5.0 (Linux; Android 8.0; Pixel 2 Build/OPD3.170816.012) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3765.0 Mobile Safari/537.36",
+		Viewport:  DeviceViewport{Width: 411, Height: 731, DeviceScaleFactor: 2.625, IsMobile: true, HasTouch: true},
+	},
+	"Pixel 2 XL": {
+		Name:      "Pixel 2 XL",
+		UserAgent: "Mozilla/5.0 (Linux; Android 8.0.0; Pixel 2 XL Build/OPD1.170816.004) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/75.0.3765.0 Mobile Safari/537.36",
+		Viewport:  DeviceViewport{Width: 411, Height: 823, DeviceScaleFactor: 3.5, IsMobile: true, HasTouch: true},
+	},
+	"Pixel 5": {
+		Name:      "Pixel 5",
+		UserAgent: "Mozilla/5.0 (Linux; Android 11; Pixel 5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.91 Mobile Safari/537.36",
+		Viewport:  DeviceViewport{Width: 393, Height: 851, DeviceScaleFactor: 2.75, IsMobile: true, HasTouch: true},
+	},
+	"Laptop HiDPI": {
+		Name:     "Laptop HiDPI",
+		Viewport: DeviceViewport{Width: 1440, Height: 900, DeviceScaleFactor: 2, IsLandscape: true},
+	},
+	"Desktop 1080p": {
+		Name:     "Desktop 1080p",
+		Viewport: DeviceViewport{Width: 1920, Height: 1080, DeviceScaleFactor: 1, IsLandscape: true},
+	},
+}
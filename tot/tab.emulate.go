@@ -0,0 +1,280 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+Device describes a device to emulate. The JSON representation matches the
+device descriptors used by the DevTools front-end and Puppeteer:
+
+	{
+		"name": "Pixel 2",
+		"userAgent": "Mozilla/5.0 (Linux; Android 8.0; Pixel 2 ...",
+		"viewport": {
+			"width": 411,
+			"height": 731,
+			"deviceScaleFactor": 2.625,
+			"isMobile": true,
+			"hasTouch": true,
+			"isLandscape": false
+		}
+	}
+*/
+type Device struct {
+	// The device name.
+	Name string `json:"name"`
+
+	// Optional. The user agent string, the browser default is used if empty.
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// The device viewport.
+	Viewport DeviceViewport `json:"viewport"`
+}
+
+/*
+DeviceViewport describes the viewport of an emulated device.
+*/
+type DeviceViewport struct {
+	// Viewport width and height in CSS pixels.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// Optional. Device pixel ratio, the screen's ratio is used if 0.
+	DeviceScaleFactor float64 `json:"deviceScaleFactor,omitempty"`
+
+	// Optional. Emulate a mobile device: meta viewport tags, overlay
+	// scrollbars and text autosizing.
+	IsMobile bool `json:"isMobile,omitempty"`
+
+	// Optional. Emulate a touch screen.
+	HasTouch bool `json:"hasTouch,omitempty"`
+
+	// Optional. Report a landscape screen orientation.
+	IsLandscape bool `json:"isLandscape,omitempty"`
+}
+
+/*
+LoadDevices reads custom device descriptors from JSON. The input may be a
+single descriptor or an array of descriptors.
+
+	devices, err := chrome.LoadDevices(file)
+	...
+	for _, device := range devices {
+		chrome.Devices[device.Name] = device
+	}
+*/
+func LoadDevices(r io.Reader) ([]*Device, error) {
+	data, err := ioutil.ReadAll(r)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.EmulationDeviceInvalid, "could not read device descriptors")
+	}
+	devices := []*Device{}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &devices)
+	} else {
+		device := &Device{}
+		err = json.Unmarshal(data, device)
+		devices = append(devices, device)
+	}
+	if nil != err {
+		return nil, errs.Wrap(err, codes.EmulationDeviceInvalid, "could not decode device descriptors")
+	}
+	for _, device := range devices {
+		if err := device.validate(); nil != err {
+			return nil, err
+		}
+	}
+	return devices, nil
+}
+
+/*
+Landscape returns a copy of the device rotated to landscape orientation.
+*/
+func (device *Device) Landscape() *Device {
+	rotated := *device
+	if !device.Viewport.IsLandscape {
+		rotated.Name = device.Name + " landscape"
+		rotated.Viewport.Width, rotated.Viewport.Height = device.Viewport.Height, device.Viewport.Width
+		rotated.Viewport.IsLandscape = true
+	}
+	return &rotated
+}
+
+/*
+validate checks that the device can be emulated.
+*/
+func (device *Device) validate() error {
+	if nil == device {
+		return errs.New(codes.EmulationDeviceInvalid, "device is nil")
+	}
+	if device.Viewport.Width <= 0 || device.Viewport.Height <= 0 {
+		return errs.New(codes.EmulationDeviceInvalid, fmt.Sprintf("device '%s' has an invalid viewport %dx%d", device.Name, device.Viewport.Width, device.Viewport.Height))
+	}
+	if device.Viewport.DeviceScaleFactor < 0 {
+		return errs.New(codes.EmulationDeviceInvalid, fmt.Sprintf("device '%s' has an invalid scale factor %v", device.Name, device.Viewport.DeviceScaleFactor))
+	}
+	return nil
+}
+
+/*
+userAgent returns the user agent of the device, or an empty string, which
+removes the user agent override, if device is nil.
+*/
+func (device *Device) userAgent() string {
+	if nil == device {
+		return ""
+	}
+	return device.UserAgent
+}
+
+/*
+emulationCommands returns the commands that apply the device emulation, or
+the commands that remove every override if device is nil. userAgent is the
+user agent override that goes with the emulation.
+*/
+func emulationCommands(device *Device, userAgent *emulation.SetUserAgentOverrideParams) []*protocolCommand {
+	metrics := &protocolCommand{"Emulation.clearDeviceMetricsOverride", func(protocol socket.Protocoller) error {
+		return (<-protocol.Emulation().ClearDeviceMetricsOverride()).Err
+	}}
+	touch := &emulation.SetTouchEmulationEnabledParams{}
+	mouse := &emulation.SetEmitTouchEventsForMouseParams{}
+	if nil != device {
+		params := deviceMetrics(device, device.Viewport.Width, device.Viewport.Height)
+		metrics = &protocolCommand{"Emulation.setDeviceMetricsOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetDeviceMetricsOverride(params)).Err
+		}}
+		touch.Enabled = device.Viewport.HasTouch
+		mouse.Enabled = device.Viewport.HasTouch
+		mouse.Configuration = emulation.Configuration.Desktop
+		if device.Viewport.HasTouch {
+			touch.MaxTouchPoints = 5
+		}
+		if device.Viewport.IsMobile {
+			mouse.Configuration = emulation.Configuration.Mobile
+		}
+	}
+
+	return []*protocolCommand{
+		metrics,
+		{"Emulation.setTouchEmulationEnabled", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetTouchEmulationEnabled(touch)).Err
+		}},
+		{"Emulation.setEmitTouchEventsForMouse", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetEmitTouchEventsForMouse(mouse)).Err
+		}},
+		{"Emulation.setUserAgentOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetUserAgentOverride(userAgent)).Err
+		}},
+	}
+}
+
+/*
+deviceMetrics returns a device metrics override for a device with the given
+viewport size. If device is nil the override only sets the viewport size.
+*/
+func deviceMetrics(device *Device, width, height int) *emulation.SetDeviceMetricsOverrideParams {
+	params := &emulation.SetDeviceMetricsOverrideParams{
+		Width:  width,
+		Height: height,
+	}
+	if nil == device {
+		return params
+	}
+	params.DeviceScaleFactor = device.Viewport.DeviceScaleFactor
+	params.Mobile = device.Viewport.IsMobile
+	params.ScreenWidth = device.Viewport.Width
+	params.ScreenHeight = device.Viewport.Height
+	params.ScreenOrientation = &emulation.ScreenOrientation{
+		Type:  emulation.OrientationType.PortraitPrimary,
+		Angle: 0,
+	}
+	if device.Viewport.IsLandscape {
+		params.ScreenOrientation.Type = emulation.OrientationType.LandscapePrimary
+		params.ScreenOrientation.Angle = 90
+	}
+	return params
+}
+
+/*
+Emulate emulates a device: viewport size, device pixel ratio, mobile
+rendering, touch input and user agent. The overrides are applied together; if
+any of them fails the previous emulation state is restored and an error is
+returned. Use ClearEmulation to revert to the browser defaults.
+
+	err := tab.Emulate(ctx, chrome.Devices["Pixel 2"].Landscape())
+*/
+func (tab *Tab) Emulate(ctx context.Context, device *Device) error {
+	if err := device.validate(); nil != err {
+		return err
+	}
+	emulated := *device
+	return tab.applyEmulation(ctx, &emulated)
+}
+
+/*
+ClearEmulation removes the device emulation applied by Emulate.
+*/
+func (tab *Tab) ClearEmulation(ctx context.Context) error {
+	return tab.applyEmulation(ctx, nil)
+}
+
+/*
+EmulatedDevice returns the device currently emulated by the tab, or nil.
+*/
+func (tab *Tab) EmulatedDevice() *Device {
+	tab.emulatedMux.Lock()
+	defer tab.emulatedMux.Unlock()
+	if nil == tab.emulated {
+		return nil
+	}
+	device := *tab.emulated
+	return &device
+}
+
+/*
+applyEmulation applies the emulation commands for a device and rolls back to
+the previous state if a command fails.
+*/
+func (tab *Tab) applyEmulation(ctx context.Context, device *Device) error {
+	// The user agent override also carries the Accept-Language and platform
+	// overrides of the environment.
+	env := tab.Environment()
+	tab.emulatedMux.Lock()
+	defer tab.emulatedMux.Unlock()
+
+	userAgent, err := tab.userAgentOverride(ctx, device, env)
+	if nil != err {
+		return err
+	}
+	previous, err := tab.userAgentOverride(ctx, tab.emulated, env)
+	if nil != err {
+		return err
+	}
+
+	protocol := tab.withContext(ctx)
+	for _, command := range emulationCommands(device, userAgent) {
+		if err := command.send(protocol); nil != err {
+			for _, rollback := range emulationCommands(tab.emulated, previous) {
+				rollback.send(tab)
+			}
+			return errs.Wrap(err, codes.EmulationFailed, fmt.Sprintf("%s failed", command.method))
+		}
+	}
+	tab.emulated = device
+	tab.emulatedMetrics = nil
+	if nil != device {
+		tab.emulatedMetrics = deviceMetrics(device, device.Viewport.Width, device.Viewport.Height)
+	}
+	return nil
+}
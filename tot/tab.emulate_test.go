@@ -0,0 +1,137 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestTabEmulate(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEmulate")
+
+	if err := tab.Emulate(context.Background(), Devices["Pixel 2"].Landscape()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	metrics := mockSocket.Commands("Emulation.setDeviceMetricsOverride")[0].Params().(*emulation.SetDeviceMetricsOverrideParams)
+	if 731 != metrics.Width || 411 != metrics.Height || 2.625 != metrics.DeviceScaleFactor || !metrics.Mobile {
+		t.Errorf("Unexpected device metrics %+v", metrics)
+	}
+	if emulation.OrientationType.LandscapePrimary != metrics.ScreenOrientation.Type || 90 != metrics.ScreenOrientation.Angle {
+		t.Errorf("Expected landscape orientation, received %+v", metrics.ScreenOrientation)
+	}
+	touch := mockSocket.Commands("Emulation.setTouchEmulationEnabled")[0].Params().(*emulation.SetTouchEmulationEnabledParams)
+	if !touch.Enabled {
+		t.Errorf("Expected touch emulation to be enabled")
+	}
+	mouse := mockSocket.Commands("Emulation.setEmitTouchEventsForMouse")[0].Params().(*emulation.SetEmitTouchEventsForMouseParams)
+	if !mouse.Enabled || emulation.Configuration.Mobile != mouse.Configuration {
+		t.Errorf("Expected mobile touch events for mouse, received %+v", mouse)
+	}
+	userAgent := mockSocket.Commands("Emulation.setUserAgentOverride")[0].Params().(*emulation.SetUserAgentOverrideParams)
+	if !strings.Contains(userAgent.UserAgent, "Pixel 2") {
+		t.Errorf("Expected the Pixel 2 user agent, received %s", userAgent.UserAgent)
+	}
+	if device := tab.EmulatedDevice(); nil == device || "Pixel 2 landscape" != device.Name {
+		t.Errorf("Expected the emulated device to be Pixel 2 landscape, received %+v", device)
+	}
+
+	if err := tab.ClearEmulation(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 1 != len(mockSocket.Commands("Emulation.clearDeviceMetricsOverride")) {
+		t.Errorf("Expected the device metrics override to be cleared")
+	}
+	userAgent = mockSocket.Commands("Emulation.setUserAgentOverride")[1].Params().(*emulation.SetUserAgentOverrideParams)
+	if "" != userAgent.UserAgent {
+		t.Errorf("Expected the user agent override to be cleared, received %s", userAgent.UserAgent)
+	}
+	if nil != tab.EmulatedDevice() {
+		t.Errorf("Expected no emulated device")
+	}
+}
+
+func TestTabEmulateRollback(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEmulateRollback")
+	if err := tab.Emulate(context.Background(), Devices["iPhone X"]); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	failed := false
+	mockSocket.Respond("Emulation.setUserAgentOverride", func(params interface{}) (interface{}, *socket.Error) {
+		if failed {
+			return nil, nil
+		}
+		failed = true
+		return nil, &socket.Error{Code: 1, Message: "error message"}
+	})
+	if err := tab.Emulate(context.Background(), Devices["Pixel 5"]); nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+
+	overrides := mockSocket.Commands("Emulation.setDeviceMetricsOverride")
+	if 3 != len(overrides) {
+		t.Fatalf("Expected 3 device metrics overrides, received %d", len(overrides))
+	}
+	if 375 != overrides[2].Params().(*emulation.SetDeviceMetricsOverrideParams).Width {
+		t.Errorf("Expected the iPhone X metrics to be restored")
+	}
+	userAgents := mockSocket.Commands("Emulation.setUserAgentOverride")
+	if !strings.Contains(userAgents[len(userAgents)-1].Params().(*emulation.SetUserAgentOverrideParams).UserAgent, "iPhone") {
+		t.Errorf("Expected the iPhone X user agent to be restored")
+	}
+	if "iPhone X" != tab.EmulatedDevice().Name {
+		t.Errorf("Expected the emulated device to be iPhone X, received %s", tab.EmulatedDevice().Name)
+	}
+}
+
+func TestTabEmulateScreenshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEmulateScreenshot")
+	mockSocket.Respond("Page.getLayoutMetrics", mockLayoutMetrics)
+	mockSocket.Respond("Page.captureScreenshot", mockScreenshot)
+	tab.Emulate(context.Background(), Devices["iPhone 8"])
+
+	if _, err := tab.Screenshot(context.Background(), &ScreenshotOptions{FullPage: true}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	overrides := mockSocket.Commands("Emulation.setDeviceMetricsOverride")
+	capture := overrides[1].Params().(*emulation.SetDeviceMetricsOverrideParams)
+	if !capture.Mobile || 2 != capture.DeviceScaleFactor {
+		t.Errorf("Expected the capture to keep the device metrics, received %+v", capture)
+	}
+	restored := overrides[2].Params().(*emulation.SetDeviceMetricsOverrideParams)
+	if 375 != restored.Width || 667 != restored.Height {
+		t.Errorf("Expected the device viewport to be restored, received %dx%d", restored.Width, restored.Height)
+	}
+	if 0 != len(mockSocket.Commands("Emulation.clearDeviceMetricsOverride")) {
+		t.Errorf("Expected the device metrics override not to be cleared")
+	}
+}
+
+func TestLoadDevices(t *testing.T) {
+	devices, err := LoadDevices(strings.NewReader(`[
+		{"name": "Kiosk", "viewport": {"width": 1080, "height": 1920, "hasTouch": true}},
+		{"name": "Watch", "userAgent": "Watch/1.0", "viewport": {"width": 200, "height": 200, "deviceScaleFactor": 2, "isMobile": true}}
+	]`))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 2 != len(devices) || "Kiosk" != devices[0].Name || !devices[0].Viewport.HasTouch || 2 != devices[1].Viewport.DeviceScaleFactor {
+		data, _ := json.Marshal(devices)
+		t.Errorf("Unexpected devices %s", data)
+	}
+
+	devices, err = LoadDevices(strings.NewReader(`{"name": "Single", "viewport": {"width": 100, "height": 100}}`))
+	if nil != err || 1 != len(devices) {
+		t.Errorf("Expected a single device, received %v %v", devices, err)
+	}
+
+	for _, invalid := range []string{`{"name": "Empty"}`, `[{"name": }]`} {
+		if _, err := LoadDevices(strings.NewReader(invalid)); nil == err {
+			t.Errorf("%s: expected error, received nil", invalid)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/browser"
+	"github.com/mkenney/go-chrome/tot/device/orientation"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+Environment describes the environment of an emulated user: where they are,
+their timezone and language, how they hold their device and the permissions
+they have granted.
+
+	err := tab.SetEnvironment(ctx, &chrome.Environment{
+		Geolocation: &chrome.Geolocation{Latitude: 52.52, Longitude: 13.405, Accuracy: 10},
+		Timezone:    "Europe/Berlin",
+		Locale:      "de-DE",
+	})
+*/
+type Environment struct {
+	// Optional. The position reported by the Geolocation API. Setting a
+	// position grants the geolocation permission.
+	Geolocation *Geolocation
+
+	// Optional. An IANA timezone identifier, e.g. "Europe/Berlin".
+	Timezone string
+
+	// Optional. A BCP 47 language tag, e.g. "de-DE", used for Intl
+	// formatting.
+	Locale string
+
+	// Optional. The Accept-Language header and navigator.languages value,
+	// derived from Locale if empty.
+	AcceptLanguage string
+
+	// Optional. The navigator.platform value.
+	Platform string
+
+	// Optional. The device orientation reported by deviceorientation events.
+	Orientation *Orientation
+
+	// Optional. Permissions granted to all origins, e.g. "notifications".
+	Permissions []browser.PermissionType
+}
+
+/*
+Geolocation is an emulated position.
+*/
+type Geolocation struct {
+	// Latitude and longitude in degrees.
+	Latitude  float64
+	Longitude float64
+
+	// Optional. Accuracy in meters.
+	Accuracy float64
+}
+
+/*
+Orientation is an emulated device orientation in degrees.
+*/
+type Orientation struct {
+	Alpha float64
+	Beta  float64
+	Gamma float64
+}
+
+/*
+SetEnvironment applies a user environment. The overrides are applied together;
+if any of them fails the previous environment is restored and an error is
+returned. The Accept-Language and platform overrides are sent with the user
+agent of the emulated device and are kept by Emulate. Use ClearEnvironment to
+revert to the browser defaults.
+*/
+func (tab *Tab) SetEnvironment(ctx context.Context, env *Environment) error {
+	if err := env.validate(); nil != err {
+		return err
+	}
+	environment := *env
+	return tab.applyEnvironment(ctx, &environment)
+}
+
+/*
+ClearEnvironment removes the user environment applied by SetEnvironment.
+*/
+func (tab *Tab) ClearEnvironment(ctx context.Context) error {
+	return tab.applyEnvironment(ctx, nil)
+}
+
+/*
+Environment returns the user environment currently applied to the tab, or nil.
+*/
+func (tab *Tab) Environment() *Environment {
+	tab.environmentMux.Lock()
+	defer tab.environmentMux.Unlock()
+	if nil == tab.environment {
+		return nil
+	}
+	env := *tab.environment
+	return &env
+}
+
+/*
+validate checks that the environment can be applied.
+*/
+func (env *Environment) validate() error {
+	if nil == env {
+		return errs.New(codes.EmulationConditionsInvalid, "environment is nil")
+	}
+	if location := env.Geolocation; nil != location {
+		if location.Latitude < -90 || location.Latitude > 90 || location.Longitude < -180 || location.Longitude > 180 {
+			return errs.New(codes.EmulationConditionsInvalid, fmt.Sprintf("invalid position %g,%g", location.Latitude, location.Longitude))
+		}
+		if location.Accuracy < 0 {
+			return errs.New(codes.EmulationConditionsInvalid, fmt.Sprintf("invalid accuracy %g", location.Accuracy))
+		}
+	}
+	return nil
+}
+
+/*
+environmentCommands returns the commands that replace the previous environment
+with env, or that remove the overrides of the previous environment if env is
+nil. Geolocation, device orientation and permissions are only cleared if the
+previous environment set them, so overrides applied outside of the environment
+are kept. userAgent is the user agent override that carries the
+Accept-Language and platform overrides.
+*/
+func environmentCommands(previous, env *Environment, userAgent *emulation.SetUserAgentOverrideParams) []*protocolCommand {
+	if nil == previous {
+		previous = &Environment{}
+	}
+	if nil == env {
+		env = &Environment{}
+	}
+
+	commands := []*protocolCommand{}
+	permissions := env.Permissions
+	if nil == env.Geolocation && nil != previous.Geolocation {
+		commands = append(commands, &protocolCommand{"Emulation.clearGeolocationOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().ClearGeolocationOverride()).Err
+		}})
+	} else if nil != env.Geolocation {
+		params := &emulation.SetGeolocationOverrideParams{
+			Latitude:  env.Geolocation.Latitude,
+			Longitude: env.Geolocation.Longitude,
+			Accuracy:  env.Geolocation.Accuracy,
+		}
+		commands = append(commands, &protocolCommand{"Emulation.setGeolocationOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetGeolocationOverride(params)).Err
+		}})
+		permissions = append(append([]browser.PermissionType{}, permissions...), "geolocation")
+	}
+
+	timezone := &emulation.SetTimezoneOverrideParams{
+		TimezoneID: env.Timezone,
+	}
+	locale := &emulation.SetLocaleOverrideParams{
+		Locale: strings.Replace(env.Locale, "-", "_", -1),
+	}
+	commands = append(commands,
+		&protocolCommand{"Emulation.setTimezoneOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetTimezoneOverride(timezone)).Err
+		}},
+		&protocolCommand{"Emulation.setLocaleOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetLocaleOverride(locale)).Err
+		}},
+		&protocolCommand{"Emulation.setUserAgentOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.Emulation().SetUserAgentOverride(userAgent)).Err
+		}},
+	)
+
+	if nil == env.Orientation && nil != previous.Orientation {
+		commands = append(commands, &protocolCommand{"DeviceOrientation.clearDeviceOrientationOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.DeviceOrientation().ClearOverride()).Err
+		}})
+	} else if nil != env.Orientation {
+		params := &orientation.SetOverrideParams{
+			Alpha: env.Orientation.Alpha,
+			Beta:  env.Orientation.Beta,
+			Gamma: env.Orientation.Gamma,
+		}
+		commands = append(commands, &protocolCommand{"DeviceOrientation.setDeviceOrientationOverride", func(protocol socket.Protocoller) error {
+			return (<-protocol.DeviceOrientation().SetOverride(params)).Err
+		}})
+	}
+
+	if len(previous.Permissions) > 0 || nil != previous.Geolocation {
+		commands = append(commands, &protocolCommand{"Browser.resetPermissions", func(protocol socket.Protocoller) error {
+			return (<-protocol.Browser().ResetPermissions(&browser.ResetPermissionsParams{})).Err
+		}})
+	}
+	if len(permissions) > 0 {
+		params := &browser.GrantPermissionsParams{
+			Permissions: permissions,
+		}
+		commands = append(commands, &protocolCommand{"Browser.grantPermissions", func(protocol socket.Protocoller) error {
+			return (<-protocol.Browser().GrantPermissions(params)).Err
+		}})
+	}
+	return commands
+}
+
+/*
+userAgentOverride returns the user agent override for an emulated device and a
+user environment. The Accept-Language and platform overrides are part of the
+user agent override, so they are sent with the user agent of the device or,
+without one, the user agent of the browser. The override is empty, which
+removes it, if neither the device nor the environment sets a value.
+*/
+func (tab *Tab) userAgentOverride(ctx context.Context, device *Device, env *Environment) (*emulation.SetUserAgentOverrideParams, error) {
+	params := &emulation.SetUserAgentOverrideParams{
+		UserAgent: device.userAgent(),
+	}
+	if nil != env {
+		params.AcceptLanguage = env.AcceptLanguage
+		if "" == params.AcceptLanguage {
+			params.AcceptLanguage = acceptLanguageFor(env.Locale)
+		}
+		params.Platform = env.Platform
+	}
+	if "" == params.UserAgent && ("" != params.AcceptLanguage || "" != params.Platform) {
+		version := <-tab.withContext(ctx).Browser().GetVersion()
+		if nil != version.Err {
+			return nil, errs.Wrap(version.Err, codes.EmulationFailed, "could not get the browser user agent")
+		}
+		params.UserAgent = version.UserAgent
+	}
+	return params, nil
+}
+
+/*
+acceptLanguageFor returns an Accept-Language value for a locale that prefers
+the locale and falls back to its language, e.g. "de-DE,de;q=0.9".
+*/
+func acceptLanguageFor(locale string) string {
+	locale = strings.Replace(locale, "_", "-", -1)
+	language := strings.SplitN(locale, "-", 2)[0]
+	if language == locale {
+		return locale
+	}
+	return fmt.Sprintf("%s,%s;q=0.9", locale, language)
+}
+
+/*
+applyEnvironment applies the environment commands and rolls back to the
+previous environment if a command fails.
+*/
+func (tab *Tab) applyEnvironment(ctx context.Context, env *Environment) error {
+	tab.environmentMux.Lock()
+	defer tab.environmentMux.Unlock()
+
+	device := tab.EmulatedDevice()
+	userAgent, err := tab.userAgentOverride(ctx, device, env)
+	if nil != err {
+		return err
+	}
+	previous, err := tab.userAgentOverride(ctx, device, tab.environment)
+	if nil != err {
+		return err
+	}
+
+	protocol := tab.withContext(ctx)
+	for _, command := range environmentCommands(tab.environment, env, userAgent) {
+		if err := command.send(protocol); nil != err {
+			for _, rollback := range environmentCommands(env, tab.environment, previous) {
+				rollback.send(tab)
+			}
+			return errs.Wrap(err, codes.EmulationFailed, fmt.Sprintf("%s failed", command.method))
+		}
+	}
+	tab.environment = env
+	return nil
+}
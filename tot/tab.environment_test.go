@@ -0,0 +1,149 @@
+package chrome
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/browser"
+	"github.com/mkenney/go-chrome/tot/device/orientation"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestTabSetEnvironment(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabSetEnvironment")
+	mockSocket.Respond("Browser.getVersion", func(params interface{}) (interface{}, *socket.Error) {
+		return &browser.GetVersionResult{UserAgent: "HeadlessChrome/120.0"}, nil
+	})
+
+	if err := tab.SetEnvironment(context.Background(), &Environment{
+		Geolocation: &Geolocation{Latitude: 35.6762, Longitude: 139.6503, Accuracy: 10},
+		Timezone:    "Asia/Tokyo",
+		Locale:      "ja-JP",
+		Orientation: &Orientation{Alpha: 90, Beta: 45.5},
+		Permissions: []browser.PermissionType{"notifications"},
+	}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	geolocation := mockSocket.Commands("Emulation.setGeolocationOverride")[0].Params().(*emulation.SetGeolocationOverrideParams)
+	if 35.6762 != geolocation.Latitude || 139.6503 != geolocation.Longitude {
+		t.Errorf("Unexpected geolocation %+v", geolocation)
+	}
+	if "Asia/Tokyo" != mockSocket.Commands("Emulation.setTimezoneOverride")[0].Params().(*emulation.SetTimezoneOverrideParams).TimezoneID {
+		t.Errorf("Expected the Asia/Tokyo timezone")
+	}
+	if "ja_JP" != mockSocket.Commands("Emulation.setLocaleOverride")[0].Params().(*emulation.SetLocaleOverrideParams).Locale {
+		t.Errorf("Expected the ja_JP locale")
+	}
+	userAgent := mockSocket.Commands("Emulation.setUserAgentOverride")[0].Params().(*emulation.SetUserAgentOverrideParams)
+	if "HeadlessChrome/120.0" != userAgent.UserAgent || "ja-JP,ja;q=0.9" != userAgent.AcceptLanguage {
+		t.Errorf("Unexpected user agent override %+v", userAgent)
+	}
+	if 45.5 != mockSocket.Commands("DeviceOrientation.setDeviceOrientationOverride")[0].Params().(*orientation.SetOverrideParams).Beta {
+		t.Errorf("Expected the device orientation to be set")
+	}
+	permissions := mockSocket.Commands("Browser.grantPermissions")[0].Params().(*browser.GrantPermissionsParams).Permissions
+	if 2 != len(permissions) || "notifications" != permissions[0] || "geolocation" != permissions[1] {
+		t.Errorf("Expected notifications and geolocation to be granted, received %v", permissions)
+	}
+	if env := tab.Environment(); nil == env || "Asia/Tokyo" != env.Timezone {
+		t.Errorf("Expected the environment to be recorded, received %+v", env)
+	}
+
+	// The emulated device keeps the Accept-Language override.
+	if err := tab.Emulate(context.Background(), Devices["iPhone X"]); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	userAgent = mockSocket.Commands("Emulation.setUserAgentOverride")[1].Params().(*emulation.SetUserAgentOverrideParams)
+	if !strings.Contains(userAgent.UserAgent, "iPhone") || "ja-JP,ja;q=0.9" != userAgent.AcceptLanguage {
+		t.Errorf("Unexpected user agent override %+v", userAgent)
+	}
+	if err := tab.ClearEmulation(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	if err := tab.ClearEnvironment(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	overrides := mockSocket.Commands("Emulation.setUserAgentOverride")
+	if userAgent := overrides[len(overrides)-1].Params().(*emulation.SetUserAgentOverrideParams); "" != userAgent.UserAgent || "" != userAgent.AcceptLanguage {
+		t.Errorf("Expected the user agent override to be cleared, received %+v", userAgent)
+	}
+	if 1 != len(mockSocket.Commands("Emulation.clearGeolocationOverride")) || 1 != len(mockSocket.Commands("DeviceOrientation.clearDeviceOrientationOverride")) {
+		t.Errorf("Expected the geolocation and orientation overrides to be cleared")
+	}
+	if "" != mockSocket.Commands("Emulation.setTimezoneOverride")[1].Params().(*emulation.SetTimezoneOverrideParams).TimezoneID {
+		t.Errorf("Expected the timezone override to be cleared")
+	}
+	if 1 != len(mockSocket.Commands("Browser.resetPermissions")) {
+		t.Errorf("Expected permissions to be reset once")
+	}
+	if nil != tab.Environment() {
+		t.Errorf("Expected no environment")
+	}
+}
+
+func TestTabSetEnvironmentRollback(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabSetEnvironmentRollback")
+	tab.Emulate(context.Background(), Devices["iPhone X"])
+	if err := tab.SetEnvironment(context.Background(), &Environment{Timezone: "Europe/Berlin"}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 0 != len(mockSocket.Commands("Browser.getVersion")) {
+		t.Errorf("Expected the emulated device user agent to be used")
+	}
+
+	mockSocket.Respond("Emulation.setTimezoneOverride", func(params interface{}) (interface{}, *socket.Error) {
+		if "Mars/Olympus_Mons" == params.(*emulation.SetTimezoneOverrideParams).TimezoneID {
+			return nil, &socket.Error{Code: 1, Message: "Invalid timezone ID"}
+		}
+		return nil, nil
+	})
+	if err := tab.SetEnvironment(context.Background(), &Environment{Timezone: "Mars/Olympus_Mons"}); nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	timezones := mockSocket.Commands("Emulation.setTimezoneOverride")
+	if "Europe/Berlin" != timezones[len(timezones)-1].Params().(*emulation.SetTimezoneOverrideParams).TimezoneID {
+		t.Errorf("Expected the Europe/Berlin timezone to be restored")
+	}
+	if "Europe/Berlin" != tab.Environment().Timezone {
+		t.Errorf("Expected the environment to be unchanged, received %+v", tab.Environment())
+	}
+
+	if err := tab.SetEnvironment(context.Background(), &Environment{Geolocation: &Geolocation{Latitude: 91}}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabSetEnvironmentKeepsOverrides(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabSetEnvironmentKeepsOverrides")
+	if err := tab.SetEnvironment(context.Background(), &Environment{Timezone: "Europe/Berlin"}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if err := tab.ClearEnvironment(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, method := range []string{
+		"Browser.resetPermissions",
+		"DeviceOrientation.clearDeviceOrientationOverride",
+		"Emulation.clearGeolocationOverride",
+	} {
+		if 0 != len(mockSocket.Commands(method)) {
+			t.Errorf("Expected no %s command for an environment that didn't set it", method)
+		}
+	}
+}
+
+func TestAcceptLanguageFor(t *testing.T) {
+	for locale, expected := range map[string]string{
+		"de-DE": "de-DE,de;q=0.9",
+		"pt_BR": "pt-BR,pt;q=0.9",
+		"fr":    "fr",
+		"":      "",
+	} {
+		if acceptLanguage := acceptLanguageFor(locale); expected != acceptLanguage {
+			t.Errorf("%s: expected '%s', received '%s'", locale, expected, acceptLanguage)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package chrome
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+JSError is returned when evaluated JavaScript throws an exception or returns a
+rejected promise.
+*/
+type JSError struct {
+	// The exception text reported by the runtime, e.g. "Uncaught".
+	Text string
+
+	// The description of the thrown value. For Error objects this is the
+	// message followed by the JavaScript stack.
+	Description string
+
+	// The location of the exception (1-based).
+	URL          string
+	LineNumber   int
+	ColumnNumber int
+
+	// The JavaScript stack trace if available.
+	StackTrace *runtime.StackTrace
+
+	scriptID runtime.ScriptID
+}
+
+/*
+newJSError converts the exception details of an evaluation into a JSError.
+*/
+func newJSError(details *runtime.ExceptionDetails) *JSError {
+	err := &JSError{
+		Text:         details.Text,
+		URL:          details.URL,
+		LineNumber:   details.LineNumber + 1,
+		ColumnNumber: details.ColumnNumber + 1,
+		StackTrace:   details.StackTrace,
+		scriptID:     details.ScriptID,
+	}
+	if nil != details.Exception {
+		err.Description = details.Exception.Description
+		if "" == err.Description && 0 != len(details.Exception.Value) {
+			err.Description = string(details.Exception.Value)
+		}
+		if "" == err.Description {
+			err.Description = details.Exception.UnserializableValue
+		}
+	}
+	return err
+}
+
+/*
+Error implements error. The message contains the exception text, the thrown
+value and the JavaScript stack in the format used by V8.
+*/
+func (err *JSError) Error() string {
+	msg := err.Text
+	if "" != err.Description {
+		msg = fmt.Sprintf("%s: %s", msg, err.Description)
+	}
+
+	// Error objects include the stack in their description already.
+	if strings.Contains(err.Description, "\n    at ") {
+		return msg
+	}
+	for _, frame := range err.Frames() {
+		msg += "\n    at " + frame
+	}
+	return msg
+}
+
+/*
+Frames returns the formatted call frames of the JavaScript stack trace,
+including any asynchronous parent stacks.
+*/
+func (err *JSError) Frames() []string {
+	frames := []string{}
+	for trace := err.StackTrace; nil != trace; trace = trace.Parent {
+		if trace != err.StackTrace && "" != trace.Description {
+			frames = append(frames, fmt.Sprintf("(%s)", trace.Description))
+		}
+		for _, frame := range trace.CallFrames {
+			location := fmt.Sprintf("%s:%d:%d", frame.URL, frame.LineNumber+1, frame.ColumnNumber+1)
+			if "" == frame.FunctionName {
+				frames = append(frames, location)
+			} else {
+				frames = append(frames, fmt.Sprintf("%s (%s)", frame.FunctionName, location))
+			}
+		}
+	}
+	return frames
+}
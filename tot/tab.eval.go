@@ -0,0 +1,169 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+JSValue is a value returned by value from the page.
+*/
+type JSValue struct {
+	value *runtime.RemoteObject
+}
+
+/*
+Decode decodes the value into out, which must be a pointer. NaN, Infinity,
+-Infinity and -0 may be decoded into float types and BigInt values into
+big.Int or integer types. An undefined value leaves out unchanged.
+*/
+func (value *JSValue) Decode(out interface{}) error {
+	return decodeRemoteValue(value.value, out)
+}
+
+/*
+Type returns the JavaScript type of the value, e.g. "object", "number" or
+"bigint".
+*/
+func (value *JSValue) Type() string {
+	return value.value.Type.String()
+}
+
+/*
+Call calls a JavaScript function declaration with the specified arguments and
+returns the result by value. Promises are awaited. Arguments are serialized as
+JSON except for *JSHandle values, which are passed by reference, Undefined and
+values that cannot be represented in JSON such as NaN or *big.Int. A JSError is
+returned if the function throws.
+
+	value, err := tab.Call(ctx, "(a, b) => a + b", 1, 2)
+*/
+func (tab *Tab) Call(ctx context.Context, fn string, args ...interface{}) (*JSValue, error) {
+	global, err := tab.EvalHandle(ctx, "globalThis")
+	if nil != err {
+		return nil, err
+	}
+	defer global.Release(context.Background())
+	return global.Call(ctx, fn, args...)
+}
+
+/*
+ElementHandle returns a handle to the JavaScript object for a DOM node so that
+it can be passed to Call. The handle must be released when it is no longer
+needed; use an ObjectScope to release handles together.
+*/
+func (tab *Tab) ElementHandle(ctx context.Context, nodeID dom.NodeID) (*JSHandle, error) {
+	value, err := tab.resolveNode(ctx, nodeID, "")
+	if nil != err {
+		return nil, err
+	}
+	return newJSHandle(tab, value, "", 0), nil
+}
+
+/*
+Eval evaluates a JavaScript expression in the page, awaits the result if it is
+a promise and decodes the value into out. out may be nil to discard the result.
+A JSError is returned if the expression throws.
+
+	var title string
+	err := tab.Eval(ctx, "document.title", &title)
+*/
+func (tab *Tab) Eval(ctx context.Context, expression string, out interface{}) error {
+	value, err := tab.evaluate(ctx, &runtime.EvaluateParams{
+		Expression:    expression,
+		ReturnByValue: true,
+		AwaitPromise:  true,
+	})
+	if nil != err {
+		return err
+	}
+	return decodeRemoteValue(value, out)
+}
+
+/*
+EvalHandle evaluates a JavaScript expression in the page, awaits the result if
+it is a promise and returns a handle to the resulting object. The handle must
+be released when it is no longer needed; use an ObjectScope to release handles
+together.
+*/
+func (tab *Tab) EvalHandle(ctx context.Context, expression string) (*JSHandle, error) {
+	value, err := tab.evaluate(ctx, &runtime.EvaluateParams{
+		Expression:   expression,
+		AwaitPromise: true,
+	})
+	if nil != err {
+		return nil, err
+	}
+	return newJSHandle(tab, value, "", 0), nil
+}
+
+/*
+callFunctionOn calls Runtime.callFunctionOn and returns the result value.
+*/
+func (tab *Tab) callFunctionOn(ctx context.Context, params *runtime.CallFunctionOnParams) (*runtime.RemoteObject, error) {
+	result := <-tab.withContext(ctx).Runtime().CallFunctionOn(params)
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.RuntimeEvaluateFailed, "function call failed")
+	}
+	if nil != result.ExceptionDetails {
+		return nil, newJSError(result.ExceptionDetails)
+	}
+	if nil == result.Result {
+		return &runtime.RemoteObject{Type: runtime.ObjectType.Undefined}, nil
+	}
+	return result.Result, nil
+}
+
+/*
+evaluate calls Runtime.evaluate and returns the result value.
+*/
+func (tab *Tab) evaluate(ctx context.Context, params *runtime.EvaluateParams) (*runtime.RemoteObject, error) {
+	result := <-tab.withContext(ctx).Runtime().Evaluate(params)
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.RuntimeEvaluateFailed, "evaluation failed")
+	}
+	if nil != result.ExceptionDetails {
+		return nil, newJSError(result.ExceptionDetails)
+	}
+	if nil == result.Result {
+		return &runtime.RemoteObject{Type: runtime.ObjectType.Undefined}, nil
+	}
+	return result.Result, nil
+}
+
+/*
+resolveNode resolves a DOM node into a remote object in an object group.
+*/
+func (tab *Tab) resolveNode(ctx context.Context, nodeID dom.NodeID, group string) (*runtime.RemoteObject, error) {
+	result := <-tab.withContext(ctx).DOM().ResolveNode(&dom.ResolveNodeParams{
+		NodeID:      nodeID,
+		ObjectGroup: group,
+	})
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.RuntimeEvaluateFailed, fmt.Sprintf("could not resolve node %d", nodeID))
+	}
+	if nil == result.Object || "" == result.Object.ObjectID {
+		return nil, errs.New(codes.RuntimeEvaluateFailed, fmt.Sprintf("node %d did not resolve to an object", nodeID))
+	}
+	return result.Object, nil
+}
+
+/*
+newCallArguments serializes a list of Go values as call arguments.
+*/
+func newCallArguments(args []interface{}) ([]*runtime.CallArgument, error) {
+	arguments := make([]*runtime.CallArgument, 0, len(args))
+	for _, arg := range args {
+		argument, err := newCallArgument(arg)
+		if nil != err {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+	}
+	return arguments, nil
+}
@@ -0,0 +1,190 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	goruntime "runtime"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+DebugObjects enables leak detection for remote object handles. When it is set,
+a warning is logged for every JSHandle and ObjectScope that is garbage
+collected without being released or closed. It only affects handles and scopes
+created after it is set.
+*/
+var DebugObjects = false
+
+/*
+JSHandle is a reference to a JavaScript value in the page. Objects are held by
+the runtime until the handle is released, the object group it belongs to is
+released or its execution context is destroyed; primitive values are held by
+the handle itself.
+*/
+type JSHandle struct {
+	state *handleState
+	tab   *Tab
+	value *runtime.RemoteObject
+}
+
+/*
+handleState is the lifetime state of a remote object. It is shared between a
+handle and the tab's object registry so that the registry can invalidate
+handles without keeping them reachable.
+*/
+type handleState struct {
+	contextID runtime.ExecutionContextID
+	group     string
+	invalid   bool
+	mux       sync.Mutex
+	objectID  runtime.RemoteObjectID
+	released  bool
+}
+
+/*
+done returns whether the remote object has been released or invalidated.
+*/
+func (state *handleState) done() bool {
+	state.mux.Lock()
+	defer state.mux.Unlock()
+	return state.released || state.invalid
+}
+
+/*
+newJSHandle returns a handle for a remote value in an object group created in
+an execution context, or in an unknown context if contextID is 0. Handles to
+objects are registered with the tab so that they are invalidated when their
+execution context is destroyed.
+*/
+func newJSHandle(tab *Tab, value *runtime.RemoteObject, group string, contextID runtime.ExecutionContextID) *JSHandle {
+	handle := &JSHandle{
+		tab:   tab,
+		value: value,
+		state: &handleState{
+			contextID: contextID,
+			group:     group,
+			objectID:  value.ObjectID,
+		},
+	}
+	if "" == value.ObjectID {
+		return handle
+	}
+
+	tab.Objects().track(handle.state)
+	if DebugObjects {
+		goruntime.SetFinalizer(handle, func(handle *JSHandle) {
+			if !handle.state.done() && "" == handle.state.group {
+				log.Warnf("remote object %s (%s) was garbage collected without being released", handle.state.objectID, handle.value.Description)
+			}
+		})
+	}
+	return handle
+}
+
+/*
+Call calls a JavaScript function declaration with this handle bound to `this`
+and returns the result by value. See Tab.Call.
+
+	value, err := handle.Call(ctx, "function () { return this.innerText }")
+*/
+func (handle *JSHandle) Call(ctx context.Context, fn string, args ...interface{}) (*JSValue, error) {
+	if "" == handle.value.ObjectID {
+		return nil, errs.New(codes.RuntimeArgumentInvalid, "cannot call a function on a primitive value handle")
+	}
+	if err := handle.check(); nil != err {
+		return nil, err
+	}
+	arguments, err := newCallArguments(args)
+	if nil != err {
+		return nil, err
+	}
+	value, err := handle.tab.callFunctionOn(ctx, &runtime.CallFunctionOnParams{
+		FunctionDeclaration: fn,
+		ObjectID:            handle.value.ObjectID,
+		Arguments:           arguments,
+		ReturnByValue:       true,
+		AwaitPromise:        true,
+	})
+	if nil != err {
+		return nil, err
+	}
+	return &JSValue{value: value}, nil
+}
+
+/*
+Description returns the runtime description of the value, e.g.
+"HTMLDivElement" or "Window".
+*/
+func (handle *JSHandle) Description() string {
+	return handle.value.Description
+}
+
+/*
+ObjectGroup returns the name of the object group the remote object belongs to.
+Handles that do not belong to a scope have no object group.
+*/
+func (handle *JSHandle) ObjectGroup() string {
+	return handle.state.group
+}
+
+/*
+ObjectID returns the remote object ID of the value. Primitive values have no
+object ID.
+*/
+func (handle *JSHandle) ObjectID() runtime.RemoteObjectID {
+	return handle.value.ObjectID
+}
+
+/*
+Release releases the remote object. Releasing a primitive value handle or a
+handle that was already released or invalidated is a no-op.
+*/
+func (handle *JSHandle) Release(ctx context.Context) error {
+	if "" == handle.value.ObjectID || handle.state.done() {
+		return nil
+	}
+	result := <-handle.tab.withContext(ctx).Runtime().ReleaseObject(&runtime.ReleaseObjectParams{
+		ObjectID: handle.value.ObjectID,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.RuntimeEvaluateFailed, "could not release object")
+	}
+	handle.tab.Objects().release(handle.state)
+	return nil
+}
+
+/*
+Type returns the JavaScript type of the value, e.g. "object" or "function".
+*/
+func (handle *JSHandle) Type() string {
+	return handle.value.Type.String()
+}
+
+/*
+Valid returns whether the handle still refers to a live remote object. Handles
+become invalid when they are released, when their object scope is closed and
+when their execution context is destroyed, for example by navigation.
+*/
+func (handle *JSHandle) Valid() bool {
+	return !handle.state.done()
+}
+
+/*
+check returns an error if the handle no longer refers to a live remote object.
+*/
+func (handle *JSHandle) check() error {
+	handle.state.mux.Lock()
+	defer handle.state.mux.Unlock()
+	if handle.state.released {
+		return errs.New(codes.RuntimeObjectInvalid, fmt.Sprintf("remote object %s has been released", handle.state.objectID))
+	}
+	if handle.state.invalid {
+		return errs.New(codes.RuntimeObjectInvalid, fmt.Sprintf("the execution context of remote object %s has been destroyed", handle.state.objectID))
+	}
+	return nil
+}
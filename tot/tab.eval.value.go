@@ -0,0 +1,182 @@
+package chrome
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+Undefined can be passed as a call argument to send the JavaScript undefined
+value. A nil argument is sent as null.
+*/
+var Undefined = undefined{}
+
+type undefined struct{}
+
+/*
+newCallArgument serializes a Go value as a call argument. Handles and remote
+object IDs are passed by reference, NaN, infinities, negative zero and big.Int
+values are passed as unserializable values and everything else is passed as
+JSON.
+*/
+func newCallArgument(arg interface{}) (*runtime.CallArgument, error) {
+	switch value := arg.(type) {
+	case nil:
+		return &runtime.CallArgument{Value: json.RawMessage("null")}, nil
+	case undefined:
+		return &runtime.CallArgument{}, nil
+	case *JSHandle:
+		if nil == value {
+			return &runtime.CallArgument{Value: json.RawMessage("null")}, nil
+		}
+		if err := value.check(); nil != err {
+			return nil, err
+		}
+		if "" == value.ObjectID() {
+			return &runtime.CallArgument{Value: value.value.Value, UnserializableValue: value.value.UnserializableValue}, nil
+		}
+		return &runtime.CallArgument{ObjectID: value.ObjectID()}, nil
+	case runtime.RemoteObjectID:
+		return &runtime.CallArgument{ObjectID: value}, nil
+	case *big.Int:
+		if nil == value {
+			return &runtime.CallArgument{Value: json.RawMessage("null")}, nil
+		}
+		return &runtime.CallArgument{UnserializableValue: value.String() + "n"}, nil
+	case float32:
+		return newCallArgument(float64(value))
+	case float64:
+		if literal := unserializableFloat(value); "" != literal {
+			return &runtime.CallArgument{UnserializableValue: literal}, nil
+		}
+	}
+
+	data, err := json.Marshal(arg)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.RuntimeArgumentInvalid, fmt.Sprintf("could not serialize argument of type %T", arg))
+	}
+	return &runtime.CallArgument{Value: data}, nil
+}
+
+/*
+unserializableFloat returns the unserializable literal for a float that cannot
+be represented in JSON, or an empty string.
+*/
+func unserializableFloat(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	case 0 == value && math.Signbit(value):
+		return "-0"
+	}
+	return ""
+}
+
+/*
+decodeRemoteValue decodes a value returned by the runtime into out. undefined
+values leave out unchanged.
+*/
+func decodeRemoteValue(value *runtime.RemoteObject, out interface{}) error {
+	if nil == out || nil == value {
+		return nil
+	}
+	if "" != value.UnserializableValue {
+		return decodeUnserializable(value.UnserializableValue, out)
+	}
+	if runtime.ObjectType.Undefined == value.Type || 0 == len(value.Value) {
+		return nil
+	}
+	if err := json.Unmarshal(value.Value, out); nil != err {
+		return errs.Wrap(err, codes.RuntimeDecodeFailed, fmt.Sprintf("could not decode %s value into %T", value.Type, out))
+	}
+	return nil
+}
+
+/*
+decodeUnserializable converts an unserializable literal (NaN, Infinity,
+-Infinity, -0 or a BigInt such as 123n) into out. Numbers may be decoded into
+float types, BigInt values into big.Int or integer types, and any literal may
+be decoded into a string or interface{}.
+*/
+func decodeUnserializable(literal string, out interface{}) error {
+	var number float64
+	var bigint *big.Int
+
+	switch literal {
+	case "NaN":
+		number = math.NaN()
+	case "Infinity":
+		number = math.Inf(1)
+	case "-Infinity":
+		number = math.Inf(-1)
+	case "-0":
+		number = math.Copysign(0, -1)
+	default:
+		var ok bool
+		if strings.HasSuffix(literal, "n") {
+			bigint, ok = new(big.Int).SetString(strings.TrimSuffix(literal, "n"), 10)
+		}
+		if !ok {
+			return errs.New(codes.RuntimeDecodeFailed, fmt.Sprintf("unknown unserializable value '%s'", literal))
+		}
+		number, _ = new(big.Float).SetInt(bigint).Float64()
+	}
+
+	switch target := out.(type) {
+	case *string:
+		*target = literal
+		return nil
+	case *float64:
+		*target = number
+		return nil
+	case *float32:
+		*target = float32(number)
+		return nil
+	case *interface{}:
+		if nil != bigint {
+			*target = bigint
+		} else {
+			*target = number
+		}
+		return nil
+	}
+
+	if nil == bigint {
+		return errs.New(codes.RuntimeDecodeFailed, fmt.Sprintf("cannot decode %s into %T", literal, out))
+	}
+	switch target := out.(type) {
+	case *big.Int:
+		target.Set(bigint)
+		return nil
+	case **big.Int:
+		*target = bigint
+		return nil
+	case *int64:
+		if bigint.IsInt64() {
+			*target = bigint.Int64()
+			return nil
+		}
+	case *int:
+		if bigint.IsInt64() && int64(int(bigint.Int64())) == bigint.Int64() {
+			*target = int(bigint.Int64())
+			return nil
+		}
+	case *uint64:
+		if bigint.IsUint64() {
+			*target = bigint.Uint64()
+			return nil
+		}
+	}
+	return errs.New(codes.RuntimeDecodeFailed, fmt.Sprintf("cannot decode %s into %T", literal, out))
+}
@@ -0,0 +1,84 @@
+package chrome
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+func TestNewCallArgument(t *testing.T) {
+	bigint, _ := new(big.Int).SetString("12345678901234567890", 10)
+	tests := []struct {
+		Arg                 interface{}
+		Value               string
+		UnserializableValue string
+	}{
+		{nil, "null", ""},
+		{"text", `"text"`, ""},
+		{map[string]int{"a": 1}, `{"a":1}`, ""},
+		{1.5, "1.5", ""},
+		{math.NaN(), "", "NaN"},
+		{math.Inf(1), "", "Infinity"},
+		{float32(math.Inf(-1)), "", "-Infinity"},
+		{math.Copysign(0, -1), "", "-0"},
+		{bigint, "", "12345678901234567890n"},
+	}
+	for _, test := range tests {
+		arg, err := newCallArgument(test.Arg)
+		if nil != err {
+			t.Errorf("%v: expected nil, received error: %v", test.Arg, err)
+			continue
+		}
+		if test.Value != string(arg.Value) || test.UnserializableValue != arg.UnserializableValue {
+			t.Errorf("%v: expected %s/%s, received %s/%s", test.Arg, test.Value, test.UnserializableValue, arg.Value, arg.UnserializableValue)
+		}
+	}
+
+	if _, err := newCallArgument(make(chan int)); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestDecodeRemoteValue(t *testing.T) {
+	var number float64
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Number, UnserializableValue: "NaN"}, &number); nil != err || !math.IsNaN(number) {
+		t.Errorf("Expected NaN, received %v (%v)", number, err)
+	}
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Number, UnserializableValue: "-0"}, &number); nil != err || 0 != number || !math.Signbit(number) {
+		t.Errorf("Expected -0, received %v (%v)", number, err)
+	}
+
+	var bigint *big.Int
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Bigint, UnserializableValue: "-12345678901234567890n"}, &bigint); nil != err || "-12345678901234567890" != bigint.String() {
+		t.Errorf("Expected -12345678901234567890, received %v (%v)", bigint, err)
+	}
+
+	var integer int64
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Bigint, UnserializableValue: "42n"}, &integer); nil != err || 42 != integer {
+		t.Errorf("Expected 42, received %v (%v)", integer, err)
+	}
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Bigint, UnserializableValue: "12345678901234567890n"}, &integer); nil == err {
+		t.Errorf("Expected overflow error, received nil")
+	}
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Number, UnserializableValue: "Infinity"}, &integer); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+
+	var any interface{}
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Bigint, UnserializableValue: "7n"}, &any); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	} else if value, ok := any.(*big.Int); !ok || 7 != value.Int64() {
+		t.Errorf("Expected *big.Int 7, received %T %v", any, any)
+	}
+
+	text := "unchanged"
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Undefined}, &text); nil != err || "unchanged" != text {
+		t.Errorf("Expected undefined to leave the value unchanged, received '%s' (%v)", text, err)
+	}
+	if err := decodeRemoteValue(&runtime.RemoteObject{Type: runtime.ObjectType.Number, Value: json.RawMessage("1")}, &text); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
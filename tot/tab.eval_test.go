@@ -0,0 +1,169 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestTabEval(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEval")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"object","value":{"title":"Example","links":3}}}`), nil
+	})
+
+	page := struct {
+		Title string `json:"title"`
+		Links int    `json:"links"`
+	}{}
+	if err := tab.Eval(context.Background(), "({title: document.title, links: document.links.length})", &page); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "Example" != page.Title || 3 != page.Links {
+		t.Errorf("Expected {Example 3}, received %+v", page)
+	}
+
+	params := mockSocket.Commands("Runtime.evaluate")[0].Params().(*runtime.EvaluateParams)
+	if !params.ReturnByValue || !params.AwaitPromise {
+		t.Errorf("Expected returnByValue and awaitPromise, received %+v", params)
+	}
+}
+
+func TestTabEvalException(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEvalException")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{
+			"result": {"type": "object", "subtype": "error"},
+			"exceptionDetails": {
+				"exceptionId": 1,
+				"text": "Uncaught",
+				"lineNumber": 1,
+				"columnNumber": 8,
+				"exception": {"type": "bigint", "unserializableValue": "42n", "description": "42n"},
+				"stackTrace": {"callFrames": [
+					{"functionName": "fail", "url": "https://example.com/app.js", "lineNumber": 1, "columnNumber": 8},
+					{"functionName": "", "url": "https://example.com/app.js", "lineNumber": 4, "columnNumber": 0}
+				]}
+			}
+		}`), nil
+	})
+
+	err := tab.Eval(context.Background(), "fail()", nil)
+	jsErr, ok := err.(*JSError)
+	if !ok {
+		t.Fatalf("Expected *JSError, received %T: %v", err, err)
+	}
+	if 2 != jsErr.LineNumber || 9 != jsErr.ColumnNumber {
+		t.Errorf("Expected 2:9, received %d:%d", jsErr.LineNumber, jsErr.ColumnNumber)
+	}
+	expected := "Uncaught: 42n\n" +
+		"    at fail (https://example.com/app.js:2:9)\n" +
+		"    at https://example.com/app.js:5:1"
+	if expected != jsErr.Error() {
+		t.Errorf("Expected '%s', received '%s'", expected, jsErr.Error())
+	}
+}
+
+func TestTabEvalProtocolError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabEvalProtocolError")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "error message"}
+	})
+
+	err := tab.Eval(context.Background(), "1", nil)
+	if nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	if _, ok := err.(*JSError); ok {
+		t.Errorf("Expected protocol error, received *JSError")
+	}
+}
+
+func TestTabEvalCancel(t *testing.T) {
+	tab, _ := newMockTab(t, "https://TestTabEvalCancel")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := tab.Eval(ctx, "1", nil); nil == err || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("Expected context.Canceled, received %v", err)
+	}
+}
+
+func TestTabCall(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabCall")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"object","className":"Window","objectId":"window-1"}}`), nil
+	})
+	mockSocket.Respond("DOM.resolveNode", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"object":{"type":"object","subtype":"node","objectId":"node-1"}}`), nil
+	})
+	mockSocket.Respond("Runtime.callFunctionOn", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"number","unserializableValue":"-Infinity"}}`), nil
+	})
+
+	element, err := tab.ElementHandle(context.Background(), 10)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	value, err := tab.Call(context.Background(), "(el, a, b) => a / b", element, -1, 0, Undefined)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	var result string
+	if err := value.Decode(&result); nil != err || "-Infinity" != result {
+		t.Errorf("Expected -Infinity, received '%s' (%v)", result, err)
+	}
+
+	params := mockSocket.Commands("Runtime.callFunctionOn")[0].Params().(*runtime.CallFunctionOnParams)
+	if "window-1" != params.ObjectID {
+		t.Errorf("Expected window-1, received '%s'", params.ObjectID)
+	}
+	if 4 != len(params.Arguments) {
+		t.Fatalf("Expected 4 arguments, received %d", len(params.Arguments))
+	}
+	if "node-1" != params.Arguments[0].ObjectID {
+		t.Errorf("Expected node-1, received '%s'", params.Arguments[0].ObjectID)
+	}
+	if "-1" != string(params.Arguments[1].Value) {
+		t.Errorf("Expected -1, received '%s'", params.Arguments[1].Value)
+	}
+	if data, _ := json.Marshal(params.Arguments[3]); "{}" != string(data) {
+		t.Errorf("Expected {}, received '%s'", data)
+	}
+
+	releases := mockSocket.Commands("Runtime.releaseObject")
+	if 1 != len(releases) {
+		t.Errorf("Expected the global object to be released, received %d releases", len(releases))
+	}
+}
+
+func TestJSHandleCallPrimitive(t *testing.T) {
+	tab, _ := newMockTab(t, "https://TestJSHandleCallPrimitive")
+	handle := newJSHandle(tab, &runtime.RemoteObject{Type: runtime.ObjectType.Number, Value: json.RawMessage("1")}, "", 0)
+
+	if _, err := handle.Call(context.Background(), "function () {}"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if err := handle.Release(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+}
+
+func TestJSErrorDescriptionStack(t *testing.T) {
+	err := newJSError(&runtime.ExceptionDetails{
+		Text: "Uncaught",
+		Exception: &runtime.RemoteObject{
+			Type:        runtime.ObjectType.Object,
+			Subtype:     runtime.ObjectSubtype.Error,
+			Description: "Error: boom\n    at fail (app.js:2:9)",
+		},
+	})
+	if !strings.HasSuffix(err.Error(), "at fail (app.js:2:9)") ||
+		1 != strings.Count(err.Error(), "    at ") {
+		t.Errorf("Expected the description stack only, received '%s'", err.Error())
+	}
+}
@@ -0,0 +1,255 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/heap"
+	heapprofiler "github.com/mkenney/go-chrome/tot/heap/profiler"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+HeapSnapshotOptions configures a heap snapshot.
+*/
+type HeapSnapshotOptions struct {
+	// Optional. Collect garbage before taking the snapshot.
+	CollectGarbage bool
+
+	// Optional. Called with the number of nodes serialized and the total
+	// number of nodes while the snapshot is taken. Progress events are
+	// handled concurrently and may arrive out of order, all of them are
+	// handled before the snapshot is written.
+	Progress func(done, total int)
+}
+
+/*
+Heap snapshot events.
+*/
+const (
+	heapSnapshotChunkEvent    = "HeapProfiler.addHeapSnapshotChunk"
+	heapSnapshotProgressEvent = "HeapProfiler.reportHeapSnapshotProgress"
+)
+
+/*
+heapSnapshotWriter writes the chunks of a heap snapshot in the order the socket
+read them. The socket numbers the events of each method, the events of a
+snapshot follow the events read before it was requested and the response to the
+snapshot command counts the events read before it.
+*/
+type heapSnapshotWriter struct {
+	chunks        map[int]string
+	done          chan struct{}
+	err           error
+	firstChunk    int
+	firstProgress int
+	lastChunk     int
+	lastProgress  int
+	mux           sync.Mutex
+	next          int
+	progressed    int
+	stopped       bool
+	taken         bool
+	w             io.Writer
+}
+
+/*
+WriteHeapSnapshot takes a heap snapshot and writes it to w in the .heapsnapshot
+format, which can be loaded in the DevTools memory panel. The snapshot is
+received in chunk events that are written in the order the socket received
+them as they arrive. Snapshots of a tab must not be taken concurrently.
+
+	file, err := os.Create("page.heapsnapshot")
+	...
+	err = tab.WriteHeapSnapshot(ctx, file, &chrome.HeapSnapshotOptions{
+		CollectGarbage: true,
+	})
+*/
+func (tab *Tab) WriteHeapSnapshot(ctx context.Context, w io.Writer, opts *HeapSnapshotOptions) error {
+	if nil == opts {
+		opts = &HeapSnapshotOptions{}
+	}
+
+	// The profiler is left enabled, heap object IDs are only stable across
+	// snapshots while it is.
+	enabled, err := sendCommand(ctx, tab.Socket(), "HeapProfiler.enable", nil)
+	if nil != err {
+		return errs.Wrap(err, codes.HeapSnapshotFailed, "HeapProfiler.enable failed")
+	}
+	if opts.CollectGarbage {
+		if result := <-tab.withContext(ctx).HeapProfiler().CollectGarbage(); nil != result.Err {
+			return errs.Wrap(result.Err, codes.HeapSnapshotFailed, "HeapProfiler.collectGarbage failed")
+		}
+	}
+
+	snapshot := &heapSnapshotWriter{
+		chunks:        map[int]string{},
+		done:          make(chan struct{}),
+		firstChunk:    enabled.EventCounts[heapSnapshotChunkEvent] + 1,
+		firstProgress: enabled.EventCounts[heapSnapshotProgressEvent] + 1,
+		next:          enabled.EventCounts[heapSnapshotChunkEvent] + 1,
+		w:             w,
+	}
+	defer snapshot.stop()
+	handlers := []socket.EventHandler{
+		socket.NewEventHandler(heapSnapshotChunkEvent, func(response *socket.Response) {
+			event := &heapprofiler.AddHeapSnapshotChunkEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid addHeapSnapshotChunk event: %s", err)
+			}
+			snapshot.chunk(response.EventCounts[heapSnapshotChunkEvent], event.Chunk)
+		}),
+	}
+	if nil != opts.Progress {
+		handlers = append(handlers, socket.NewEventHandler(heapSnapshotProgressEvent, func(response *socket.Response) {
+			number := response.EventCounts[heapSnapshotProgressEvent]
+			event := &heapprofiler.ReportHeapSnapshotProgressEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid reportHeapSnapshotProgress event: %s", err)
+			} else if snapshot.reporting(number) {
+				opts.Progress(event.Done, event.Total)
+			}
+			snapshot.reported(number)
+		}))
+	}
+	for _, handler := range handlers {
+		tab.AddEventHandler(handler)
+	}
+	defer func() {
+		for _, handler := range handlers {
+			tab.RemoveEventHandler(handler)
+		}
+	}()
+
+	taken, err := sendCommand(ctx, tab.Socket(), "HeapProfiler.takeHeapSnapshot", &heapprofiler.TakeHeapSnapshotParams{
+		ReportProgress: nil != opts.Progress,
+	})
+	if nil != err {
+		return errs.Wrap(err, codes.HeapSnapshotFailed, "HeapProfiler.takeHeapSnapshot failed")
+	}
+	lastProgress := snapshot.firstProgress - 1
+	if nil != opts.Progress {
+		lastProgress = taken.EventCounts[heapSnapshotProgressEvent]
+	}
+	if err := snapshot.wait(ctx, taken.EventCounts[heapSnapshotChunkEvent], lastProgress); nil != err {
+		return err
+	}
+	snapshot.mux.Lock()
+	defer snapshot.mux.Unlock()
+	if nil != snapshot.err {
+		return errs.Wrap(snapshot.err, codes.HeapSnapshotWriteFailed, "could not write the heap snapshot")
+	}
+	return nil
+}
+
+/*
+chunk writes the chunk with the given number and the chunks after it that have
+arrived, or keeps it until the chunks before it arrive.
+*/
+func (snapshot *heapSnapshotWriter) chunk(number int, data string) {
+	snapshot.mux.Lock()
+	defer snapshot.mux.Unlock()
+	if snapshot.stopped || number < snapshot.next {
+		return
+	}
+	snapshot.chunks[number] = data
+	for {
+		data, ok := snapshot.chunks[snapshot.next]
+		if !ok {
+			break
+		}
+		delete(snapshot.chunks, snapshot.next)
+		snapshot.next++
+		if nil == snapshot.err {
+			_, snapshot.err = io.WriteString(snapshot.w, data)
+		}
+	}
+	snapshot.complete()
+}
+
+/*
+complete signals that the snapshot is written once the chunks and the progress
+events counted by the response to the snapshot command are handled. The caller
+must hold the lock.
+*/
+func (snapshot *heapSnapshotWriter) complete() {
+	if !snapshot.taken || snapshot.stopped || snapshot.next <= snapshot.lastChunk {
+		return
+	}
+	if snapshot.progressed <= snapshot.lastProgress-snapshot.firstProgress {
+		return
+	}
+	snapshot.stopped = true
+	close(snapshot.done)
+}
+
+/*
+reported records that the progress event with the given number was handled.
+*/
+func (snapshot *heapSnapshotWriter) reported(number int) {
+	snapshot.mux.Lock()
+	defer snapshot.mux.Unlock()
+	if number >= snapshot.firstProgress {
+		snapshot.progressed++
+		snapshot.complete()
+	}
+}
+
+/*
+reporting returns whether the progress event with the given number belongs to
+the snapshot and the snapshot is being taken.
+*/
+func (snapshot *heapSnapshotWriter) reporting(number int) bool {
+	snapshot.mux.Lock()
+	defer snapshot.mux.Unlock()
+	return number >= snapshot.firstProgress && !snapshot.stopped
+}
+
+/*
+stop stops writing chunks.
+*/
+func (snapshot *heapSnapshotWriter) stop() {
+	snapshot.mux.Lock()
+	defer snapshot.mux.Unlock()
+	snapshot.stopped = true
+}
+
+/*
+wait waits until the chunks and the progress events up to the given numbers
+are handled.
+*/
+func (snapshot *heapSnapshotWriter) wait(ctx context.Context, lastChunk, lastProgress int) error {
+	snapshot.mux.Lock()
+	if lastChunk < snapshot.firstChunk {
+		snapshot.mux.Unlock()
+		return errs.New(codes.HeapSnapshotFailed, "the heap snapshot is empty")
+	}
+	snapshot.taken = true
+	snapshot.lastChunk, snapshot.lastProgress = lastChunk, lastProgress
+	snapshot.complete()
+	snapshot.mux.Unlock()
+
+	select {
+	case <-snapshot.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+HeapSnapshot takes a heap snapshot and parses it.
+*/
+func (tab *Tab) HeapSnapshot(ctx context.Context, opts *HeapSnapshotOptions) (*heap.Snapshot, error) {
+	buf := &bytes.Buffer{}
+	if err := tab.WriteHeapSnapshot(ctx, buf, opts); nil != err {
+		return nil, err
+	}
+	return heap.Parse(buf)
+}
@@ -0,0 +1,129 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	heapprofiler "github.com/mkenney/go-chrome/tot/heap/profiler"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+testHeapSnapshot is the smallest valid heap snapshot: a root that references
+one object.
+*/
+const testHeapSnapshot = `{"snapshot":{"meta":{` +
+	`"node_fields":["type","name","id","self_size","edge_count"],` +
+	`"node_types":[["synthetic","object"],"string","number","number","number"],` +
+	`"edge_fields":["type","name_or_index","to_node"],` +
+	`"edge_types":[["property"],"string_or_number","node"]}},` +
+	`"nodes":[0,0,1,0,1,1,1,3,16,0],"edges":[0,2,5],"strings":["","Item","item"]}`
+
+/*
+heapSnapshotEvents returns the final progress event of a heap snapshot followed
+by its chunk events.
+*/
+func heapSnapshotEvents(chunks ...string) []*socket.Response {
+	events := []*socket.Response{
+		mockEvent("HeapProfiler.reportHeapSnapshotProgress", &heapprofiler.ReportHeapSnapshotProgressEvent{Done: 2, Total: 2, Finished: true}),
+	}
+	for _, chunk := range chunks {
+		events = append(events, mockEvent("HeapProfiler.addHeapSnapshotChunk", &heapprofiler.AddHeapSnapshotChunkEvent{Chunk: chunk}))
+	}
+	return events
+}
+
+func TestTabWriteHeapSnapshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabWriteHeapSnapshot")
+	mockSocket.Concurrent()
+	mockSocket.RespondEvents("HeapProfiler.takeHeapSnapshot", heapSnapshotEvents(`{"snap`, `shot":`, `{}}`)...)
+
+	progress := 0
+	buf := &bytes.Buffer{}
+	if err := tab.WriteHeapSnapshot(context.Background(), buf, &HeapSnapshotOptions{
+		CollectGarbage: true,
+		Progress: func(done, total int) {
+			progress = done
+		},
+	}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if `{"snapshot":{}}` != buf.String() {
+		t.Errorf("Expected the chunks to be written in order, received '%s'", buf.String())
+	}
+	if 2 != progress {
+		t.Errorf("Expected progress to be reported, received %d", progress)
+	}
+	if 1 != len(mockSocket.Commands("HeapProfiler.collectGarbage")) {
+		t.Errorf("Expected garbage to be collected")
+	}
+	params := mockSocket.Commands("HeapProfiler.takeHeapSnapshot")[0].Params().(*heapprofiler.TakeHeapSnapshotParams)
+	if !params.ReportProgress {
+		t.Errorf("Expected progress to be requested")
+	}
+
+	// The handlers are removed.
+	mockSocket.Fire("HeapProfiler.addHeapSnapshotChunk", &heapprofiler.AddHeapSnapshotChunkEvent{Chunk: "x"})
+	mockSocket.Wait()
+	if `{"snapshot":{}}` != buf.String() {
+		t.Errorf("Expected chunks to be ignored after the snapshot, received '%s'", buf.String())
+	}
+}
+
+func TestTabHeapSnapshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabHeapSnapshot")
+	mockSocket.Concurrent()
+	mockSocket.RespondEvents("HeapProfiler.takeHeapSnapshot", heapSnapshotEvents(testHeapSnapshot[:40], testHeapSnapshot[40:])...)
+
+	snapshot, err := tab.HeapSnapshot(context.Background(), nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if item := snapshot.Node(3); nil == item || "Item" != item.Name || 16 != item.RetainedSize {
+		t.Errorf("Unexpected node %+v", item)
+	}
+	if 0 != len(mockSocket.Commands("HeapProfiler.collectGarbage")) {
+		t.Errorf("Expected garbage not to be collected")
+	}
+}
+
+func TestTabHeapSnapshotError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabHeapSnapshotError")
+	if err := tab.WriteHeapSnapshot(context.Background(), &bytes.Buffer{}, nil); nil == err {
+		t.Errorf("Expected an error for an empty snapshot, received nil")
+	}
+
+	mockSocket.Respond("HeapProfiler.takeHeapSnapshot", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Heap snapshot failed"}
+	})
+	if _, err := tab.HeapSnapshot(context.Background(), nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+
+	mockSocket.RespondEvents("HeapProfiler.takeHeapSnapshot", heapSnapshotEvents("{")...)
+	if _, err := tab.HeapSnapshot(context.Background(), nil); nil == err {
+		t.Errorf("Expected an error for an invalid snapshot, received nil")
+	}
+}
+
+func TestTabHeapSnapshotMissingChunk(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabHeapSnapshotMissingChunk")
+	mockSocket.Respond("HeapProfiler.takeHeapSnapshot", func(params interface{}) (interface{}, *socket.Error) {
+		mockSocket.Event("HeapProfiler.addHeapSnapshotChunk", &heapprofiler.AddHeapSnapshotChunkEvent{Chunk: `{"snap`})
+		mockSocket.Fire("HeapProfiler.addHeapSnapshotChunk", &heapprofiler.AddHeapSnapshotChunkEvent{Chunk: `shot":{}}`})
+		return nil, nil
+	})
+
+	// The snapshot waits for the first chunk, which never arrives.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	buf := &bytes.Buffer{}
+	if err := tab.WriteHeapSnapshot(ctx, buf, nil); context.DeadlineExceeded != err {
+		t.Errorf("Expected the context error, received %v", err)
+	}
+	if 0 != buf.Len() {
+		t.Errorf("Expected chunks not to be written before the missing chunk, received '%s'", buf.String())
+	}
+}
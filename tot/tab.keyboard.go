@@ -0,0 +1,339 @@
+package chrome
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/input"
+)
+
+/*
+Modifier bit field values used by the Input protocol.
+*/
+const (
+	ModifierAlt   = 1
+	ModifierCtrl  = 2
+	ModifierMeta  = 4
+	ModifierShift = 8
+)
+
+/*
+keyAliases maps commonly used key names to their layout names.
+*/
+var keyAliases = map[string]string{
+	"\n":      "Enter",
+	"\r":      "Enter",
+	"Cmd":     "Meta",
+	"Command": "Meta",
+	"Ctrl":    "Control",
+	"Esc":     "Escape",
+	"Option":  "Alt",
+	"Return":  "Enter",
+}
+
+/*
+Keyboard provides a high-level interface for sending keyboard input to a tab.
+It tracks the keys currently held down and applies the matching modifier bit
+field to every event it dispatches.
+*/
+type Keyboard struct {
+	layout    KeyboardLayout
+	modifiers int
+	mux       sync.Mutex
+	pressed   map[string]bool
+	tab       *Tab
+}
+
+/*
+Keyboard returns the Keyboard instance for this tab.
+*/
+func (tab *Tab) Keyboard() *Keyboard {
+	tab.keyboardMux.Lock()
+	defer tab.keyboardMux.Unlock()
+	if nil == tab.keyboard {
+		tab.keyboard = &Keyboard{
+			layout:  USKeyboardLayout,
+			pressed: map[string]bool{},
+			tab:     tab,
+		}
+	}
+	return tab.keyboard
+}
+
+/*
+Down dispatches a keyDown event for the specified key and, if the key produces
+text, a char event. Modifier keys held down with Down are applied to all
+subsequent keyboard and mouse events until they are released with Up.
+*/
+func (keyboard *Keyboard) Down(key string) error {
+	keyboard.mux.Lock()
+	desc, err := keyboard.describe(key)
+	if nil != err {
+		keyboard.mux.Unlock()
+		return err
+	}
+	autoRepeat := keyboard.pressed[desc.Code]
+	previous := keyboard.modifiers
+	keyboard.pressed[desc.Code] = true
+	keyboard.modifiers |= modifierBit(desc.Key)
+	modifiers := keyboard.modifiers
+	keyboard.mux.Unlock()
+
+	if err = keyboard.dispatch(&input.DispatchKeyEventParams{
+		Type:                  input.KeyEvent.RawKeyDown,
+		Modifiers:             modifiers,
+		Key:                   desc.Key,
+		Code:                  desc.Code,
+		WindowsVirtualKeyCode: desc.KeyCode,
+		NativeVirtualKeyCode:  desc.KeyCode,
+		AutoRepeat:            autoRepeat,
+		IsKeypad:              3 == desc.Location,
+		Location:              desc.Location,
+	}); nil != err {
+		keyboard.rollback(desc.Code, autoRepeat, previous)
+		return err
+	}
+
+	if "" == desc.Text {
+		return nil
+	}
+	return keyboard.dispatch(&input.DispatchKeyEventParams{
+		Type:                  input.KeyEvent.Char,
+		Modifiers:             modifiers,
+		Text:                  desc.Text,
+		UnmodifiedText:        desc.Text,
+		Key:                   desc.Key,
+		Code:                  desc.Code,
+		WindowsVirtualKeyCode: desc.KeyCode,
+		NativeVirtualKeyCode:  desc.KeyCode,
+		AutoRepeat:            autoRepeat,
+		IsKeypad:              3 == desc.Location,
+		Location:              desc.Location,
+	})
+}
+
+/*
+InsertText dispatches text to the focused element without generating any key
+events, the way an IME or emoji picker would.
+*/
+func (keyboard *Keyboard) InsertText(text string) error {
+	result := <-keyboard.tab.Input().InsertText(&input.InsertTextParams{
+		Text: text,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.InputDispatchFailed, "insert text failed")
+	}
+	return nil
+}
+
+/*
+Modifiers returns the modifier bit field for the modifier keys currently held
+down.
+*/
+func (keyboard *Keyboard) Modifiers() int {
+	keyboard.mux.Lock()
+	defer keyboard.mux.Unlock()
+	return keyboard.modifiers
+}
+
+/*
+Press presses and releases a key or a key combination. Combinations are joined
+with '+' and are pressed in order and released in reverse order, e.g.
+"Control+Shift+K".
+*/
+func (keyboard *Keyboard) Press(keys string) error {
+	combo := splitKeyCombo(keys)
+	for k, key := range combo {
+		if err := keyboard.Down(key); nil != err {
+			keyboard.release(combo[:k])
+			return err
+		}
+	}
+	return keyboard.release(combo)
+}
+
+/*
+SetLayout replaces the keyboard layout used to resolve key names. The default
+layout is USKeyboardLayout.
+*/
+func (keyboard *Keyboard) SetLayout(layout KeyboardLayout) {
+	keyboard.mux.Lock()
+	defer keyboard.mux.Unlock()
+	keyboard.layout = layout
+}
+
+/*
+Type sends a keyDown, char and keyUp sequence for each character in text.
+Characters that are not defined in the keyboard layout are inserted with
+InsertText.
+*/
+func (keyboard *Keyboard) Type(text string) error {
+	for _, char := range text {
+		var err error
+		if keyboard.defined(string(char)) {
+			err = keyboard.Press(string(char))
+		} else {
+			err = keyboard.InsertText(string(char))
+		}
+		if nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Up dispatches a keyUp event for the specified key.
+*/
+func (keyboard *Keyboard) Up(key string) error {
+	keyboard.mux.Lock()
+	desc, err := keyboard.describe(key)
+	if nil != err {
+		keyboard.mux.Unlock()
+		return err
+	}
+	delete(keyboard.pressed, desc.Code)
+	keyboard.modifiers &^= modifierBit(desc.Key)
+	modifiers := keyboard.modifiers
+	keyboard.mux.Unlock()
+
+	return keyboard.dispatch(&input.DispatchKeyEventParams{
+		Type:                  input.KeyEvent.KeyUp,
+		Modifiers:             modifiers,
+		Key:                   desc.Key,
+		Code:                  desc.Code,
+		WindowsVirtualKeyCode: desc.KeyCode,
+		NativeVirtualKeyCode:  desc.KeyCode,
+		IsKeypad:              3 == desc.Location,
+		Location:              desc.Location,
+	})
+}
+
+/*
+defined returns whether a key name resolves in the current layout.
+*/
+func (keyboard *Keyboard) defined(key string) bool {
+	keyboard.mux.Lock()
+	defer keyboard.mux.Unlock()
+	_, err := keyboard.describe(key)
+	return nil == err
+}
+
+/*
+describe resolves a key name into the key definition to dispatch, taking the
+current modifier state into account. The caller must hold the mutex.
+*/
+func (keyboard *Keyboard) describe(key string) (*KeyDefinition, error) {
+	if alias, ok := keyAliases[key]; ok {
+		key = alias
+	}
+	def, ok := keyboard.layout[key]
+	if !ok {
+		return nil, errs.New(codes.InputKeyUnknown, fmt.Sprintf("unknown key '%s'", key))
+	}
+
+	shift := 0 != keyboard.modifiers&ModifierShift
+	desc := &KeyDefinition{
+		Code:     def.Code,
+		Key:      def.Key,
+		KeyCode:  def.KeyCode,
+		Location: def.Location,
+	}
+	if shift && "" != def.ShiftKey {
+		desc.Key = def.ShiftKey
+	}
+	if shift && 0 != def.ShiftKeyCode {
+		desc.KeyCode = def.ShiftKeyCode
+	}
+
+	if 1 == utf8.RuneCountInString(desc.Key) {
+		desc.Text = desc.Key
+	}
+	if "" != def.Text {
+		desc.Text = def.Text
+	}
+	if shift && "" != def.ShiftText {
+		desc.Text = def.ShiftText
+	}
+
+	// Chromium does not generate text while any modifier other than Shift is
+	// held down.
+	if 0 != keyboard.modifiers&^ModifierShift {
+		desc.Text = ""
+	}
+	return desc, nil
+}
+
+/*
+dispatch sends a single key event to the tab.
+*/
+func (keyboard *Keyboard) dispatch(params *input.DispatchKeyEventParams) error {
+	result := <-keyboard.tab.Input().DispatchKeyEvent(params)
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.InputDispatchFailed, fmt.Sprintf("%s event for key '%s' failed", params.Type, params.Key))
+	}
+	return nil
+}
+
+/*
+release releases a list of keys in reverse order, returning the first error
+encountered.
+*/
+func (keyboard *Keyboard) release(keys []string) error {
+	var err error
+	for k := len(keys) - 1; k >= 0; k-- {
+		if upErr := keyboard.Up(keys[k]); nil != upErr && nil == err {
+			err = upErr
+		}
+	}
+	return err
+}
+
+/*
+rollback restores the key state recorded before a keyDown event that failed to
+dispatch, so the key isn't left held down and its modifier bit isn't applied to
+later events.
+*/
+func (keyboard *Keyboard) rollback(code string, pressed bool, modifiers int) {
+	keyboard.mux.Lock()
+	defer keyboard.mux.Unlock()
+	if !pressed {
+		delete(keyboard.pressed, code)
+	}
+	keyboard.modifiers = modifiers
+}
+
+/*
+modifierBit returns the modifier bit field value for a modifier key name.
+*/
+func modifierBit(key string) int {
+	switch key {
+	case "Alt":
+		return ModifierAlt
+	case "Control":
+		return ModifierCtrl
+	case "Meta":
+		return ModifierMeta
+	case "Shift":
+		return ModifierShift
+	}
+	return 0
+}
+
+/*
+splitKeyCombo splits a '+' delimited key combination into key names. A literal
+'+' key may be used as the last key, e.g. "Shift++".
+*/
+func splitKeyCombo(keys string) []string {
+	if "+" == keys {
+		return []string{"+"}
+	}
+	if strings.HasSuffix(keys, "++") {
+		return append(splitKeyCombo(strings.TrimSuffix(keys, "++")), "+")
+	}
+	return strings.Split(keys, "+")
+}
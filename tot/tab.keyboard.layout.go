@@ -0,0 +1,210 @@
+package chrome
+
+import (
+	"strconv"
+)
+
+/*
+KeyDefinition describes a single physical key on a keyboard layout.
+*/
+type KeyDefinition struct {
+	// Code is the DOM defined string value for the physical key (e.g.,
+	// 'KeyA').
+	Code string
+
+	// Key is the DOM defined string value describing the meaning of the key
+	// with no modifiers pressed (e.g., 'a').
+	Key string
+
+	// Optional. ShiftKey is the meaning of the key while the Shift modifier
+	// is pressed (e.g., 'A').
+	ShiftKey string
+
+	// KeyCode is the Windows virtual key code.
+	KeyCode int
+
+	// Optional. ShiftKeyCode is the Windows virtual key code while the Shift
+	// modifier is pressed.
+	ShiftKeyCode int
+
+	// Optional. Text generated by the key. Defaults to Key when Key is a single
+	// character.
+	Text string
+
+	// Optional. ShiftText is the text generated by the key while the Shift
+	// modifier is pressed. Defaults to ShiftKey when ShiftKey is a single
+	// character.
+	ShiftText string
+
+	// Optional. Location of the key on the keyboard. 1=Left, 2=Right,
+	// 3=Numpad.
+	Location int
+}
+
+/*
+KeyboardLayout maps key names to key definitions. Every definition is reachable
+by its Code, its Key and its ShiftKey values so that "KeyA", "a" and "A" all
+resolve to the same physical key.
+*/
+type KeyboardLayout map[string]*KeyDefinition
+
+/*
+NewKeyboardLayout indexes a list of physical key definitions into a
+KeyboardLayout. When more than one key produces the same Key or ShiftKey value,
+the first definition in the list wins, so main keyboard keys should be listed
+before their keypad equivalents.
+*/
+func NewKeyboardLayout(keys []*KeyDefinition) KeyboardLayout {
+	layout := KeyboardLayout{}
+	for _, key := range keys {
+		layout[key.Code] = key
+	}
+	for _, key := range keys {
+		if _, ok := layout[key.Key]; !ok && "" != key.Key {
+			def := *key
+			layout[key.Key] = &def
+		}
+	}
+	for _, key := range keys {
+		if _, ok := layout[key.ShiftKey]; !ok && "" != key.ShiftKey {
+			def := *key
+			def.Key = key.ShiftKey
+			def.Text = key.ShiftText
+			def.ShiftKey = ""
+			def.ShiftText = ""
+			if 0 != key.ShiftKeyCode {
+				def.KeyCode = key.ShiftKeyCode
+			}
+			layout[key.ShiftKey] = &def
+		}
+	}
+	return layout
+}
+
+/*
+USKeyboardLayout is the standard US keyboard layout. It is the default layout
+used by Keyboard.
+*/
+var USKeyboardLayout = NewKeyboardLayout(usKeyDefinitions())
+
+/*
+usKeyDefinitions returns the physical keys of a standard US keyboard.
+*/
+func usKeyDefinitions() []*KeyDefinition {
+	keys := []*KeyDefinition{
+		// Function and control keys.
+		{Code: "Power", Key: "Power"},
+		{Code: "Eject", Key: "Eject"},
+		{Code: "Abort", Key: "Cancel", KeyCode: 3},
+		{Code: "Help", Key: "Help", KeyCode: 6},
+		{Code: "Backspace", Key: "Backspace", KeyCode: 8},
+		{Code: "Tab", Key: "Tab", KeyCode: 9},
+		{Code: "Enter", Key: "Enter", KeyCode: 13, Text: "\r"},
+		{Code: "ShiftLeft", Key: "Shift", KeyCode: 16, Location: 1},
+		{Code: "ShiftRight", Key: "Shift", KeyCode: 16, Location: 2},
+		{Code: "ControlLeft", Key: "Control", KeyCode: 17, Location: 1},
+		{Code: "ControlRight", Key: "Control", KeyCode: 17, Location: 2},
+		{Code: "AltLeft", Key: "Alt", KeyCode: 18, Location: 1},
+		{Code: "AltRight", Key: "Alt", KeyCode: 18, Location: 2},
+		{Code: "Pause", Key: "Pause", KeyCode: 19},
+		{Code: "CapsLock", Key: "CapsLock", KeyCode: 20},
+		{Code: "Escape", Key: "Escape", KeyCode: 27},
+		{Code: "Convert", Key: "Convert", KeyCode: 28},
+		{Code: "NonConvert", Key: "NonConvert", KeyCode: 29},
+		{Code: "Space", Key: " ", KeyCode: 32},
+		{Code: "PageUp", Key: "PageUp", KeyCode: 33},
+		{Code: "PageDown", Key: "PageDown", KeyCode: 34},
+		{Code: "End", Key: "End", KeyCode: 35},
+		{Code: "Home", Key: "Home", KeyCode: 36},
+		{Code: "ArrowLeft", Key: "ArrowLeft", KeyCode: 37},
+		{Code: "ArrowUp", Key: "ArrowUp", KeyCode: 38},
+		{Code: "ArrowRight", Key: "ArrowRight", KeyCode: 39},
+		{Code: "ArrowDown", Key: "ArrowDown", KeyCode: 40},
+		{Code: "Select", Key: "Select", KeyCode: 41},
+		{Code: "Open", Key: "Execute", KeyCode: 43},
+		{Code: "PrintScreen", Key: "PrintScreen", KeyCode: 44},
+		{Code: "Insert", Key: "Insert", KeyCode: 45},
+		{Code: "Delete", Key: "Delete", KeyCode: 46},
+		{Code: "MetaLeft", Key: "Meta", KeyCode: 91, Location: 1},
+		{Code: "MetaRight", Key: "Meta", KeyCode: 92, Location: 2},
+		{Code: "ContextMenu", Key: "ContextMenu", KeyCode: 93},
+		{Code: "NumLock", Key: "NumLock", KeyCode: 144},
+		{Code: "ScrollLock", Key: "ScrollLock", KeyCode: 145},
+		{Code: "AudioVolumeMute", Key: "AudioVolumeMute", KeyCode: 173},
+		{Code: "AudioVolumeDown", Key: "AudioVolumeDown", KeyCode: 174},
+		{Code: "AudioVolumeUp", Key: "AudioVolumeUp", KeyCode: 175},
+		{Code: "MediaTrackNext", Key: "MediaTrackNext", KeyCode: 176},
+		{Code: "MediaTrackPrevious", Key: "MediaTrackPrevious", KeyCode: 177},
+		{Code: "MediaStop", Key: "MediaStop", KeyCode: 178},
+		{Code: "MediaPlayPause", Key: "MediaPlayPause", KeyCode: 179},
+		{Code: "AltGraph", Key: "AltGraph", KeyCode: 225},
+
+		// Printable keys.
+		{Code: "Digit0", Key: "0", ShiftKey: ")", KeyCode: 48},
+		{Code: "Digit1", Key: "1", ShiftKey: "!", KeyCode: 49},
+		{Code: "Digit2", Key: "2", ShiftKey: "@", KeyCode: 50},
+		{Code: "Digit3", Key: "3", ShiftKey: "#", KeyCode: 51},
+		{Code: "Digit4", Key: "4", ShiftKey: "$", KeyCode: 52},
+		{Code: "Digit5", Key: "5", ShiftKey: "%", KeyCode: 53},
+		{Code: "Digit6", Key: "6", ShiftKey: "^", KeyCode: 54},
+		{Code: "Digit7", Key: "7", ShiftKey: "&", KeyCode: 55},
+		{Code: "Digit8", Key: "8", ShiftKey: "*", KeyCode: 56},
+		{Code: "Digit9", Key: "9", ShiftKey: "(", KeyCode: 57},
+		{Code: "Semicolon", Key: ";", ShiftKey: ":", KeyCode: 186},
+		{Code: "Equal", Key: "=", ShiftKey: "+", KeyCode: 187},
+		{Code: "Comma", Key: ",", ShiftKey: "<", KeyCode: 188},
+		{Code: "Minus", Key: "-", ShiftKey: "_", KeyCode: 189},
+		{Code: "Period", Key: ".", ShiftKey: ">", KeyCode: 190},
+		{Code: "Slash", Key: "/", ShiftKey: "?", KeyCode: 191},
+		{Code: "Backquote", Key: "`", ShiftKey: "~", KeyCode: 192},
+		{Code: "BracketLeft", Key: "[", ShiftKey: "{", KeyCode: 219},
+		{Code: "Backslash", Key: "\\", ShiftKey: "|", KeyCode: 220},
+		{Code: "BracketRight", Key: "]", ShiftKey: "}", KeyCode: 221},
+		{Code: "Quote", Key: "'", ShiftKey: "\"", KeyCode: 222},
+	}
+
+	// Letters.
+	for char := 'a'; char <= 'z'; char++ {
+		upper := char - 'a' + 'A'
+		keys = append(keys, &KeyDefinition{
+			Code:     "Key" + string(upper),
+			Key:      string(char),
+			ShiftKey: string(upper),
+			KeyCode:  int(upper),
+		})
+	}
+
+	// Function keys.
+	for num := 1; num <= 24; num++ {
+		name := "F" + strconv.Itoa(num)
+		keys = append(keys, &KeyDefinition{
+			Code:    name,
+			Key:     name,
+			KeyCode: 111 + num,
+		})
+	}
+
+	// Numeric keypad. Listed last so that the main keyboard keys take
+	// precedence when resolving by Key or ShiftKey.
+	keys = append(keys, []*KeyDefinition{
+		{Code: "Numpad0", Key: "Insert", ShiftKey: "0", KeyCode: 45, ShiftKeyCode: 96, Location: 3},
+		{Code: "Numpad1", Key: "End", ShiftKey: "1", KeyCode: 35, ShiftKeyCode: 97, Location: 3},
+		{Code: "Numpad2", Key: "ArrowDown", ShiftKey: "2", KeyCode: 40, ShiftKeyCode: 98, Location: 3},
+		{Code: "Numpad3", Key: "PageDown", ShiftKey: "3", KeyCode: 34, ShiftKeyCode: 99, Location: 3},
+		{Code: "Numpad4", Key: "ArrowLeft", ShiftKey: "4", KeyCode: 37, ShiftKeyCode: 100, Location: 3},
+		{Code: "Numpad5", Key: "Clear", ShiftKey: "5", KeyCode: 12, ShiftKeyCode: 101, Location: 3},
+		{Code: "Numpad6", Key: "ArrowRight", ShiftKey: "6", KeyCode: 39, ShiftKeyCode: 102, Location: 3},
+		{Code: "Numpad7", Key: "Home", ShiftKey: "7", KeyCode: 36, ShiftKeyCode: 103, Location: 3},
+		{Code: "Numpad8", Key: "ArrowUp", ShiftKey: "8", KeyCode: 38, ShiftKeyCode: 104, Location: 3},
+		{Code: "Numpad9", Key: "PageUp", ShiftKey: "9", KeyCode: 33, ShiftKeyCode: 105, Location: 3},
+		{Code: "NumpadMultiply", Key: "*", KeyCode: 106, Location: 3},
+		{Code: "NumpadAdd", Key: "+", KeyCode: 107, Location: 3},
+		{Code: "NumpadSubtract", Key: "-", KeyCode: 109, Location: 3},
+		{Code: "NumpadDecimal", Key: "\u0000", ShiftKey: ".", KeyCode: 46, ShiftKeyCode: 110, Location: 3},
+		{Code: "NumpadDivide", Key: "/", KeyCode: 111, Location: 3},
+		{Code: "NumpadEnter", Key: "Enter", KeyCode: 13, Text: "\r", Location: 3},
+		{Code: "NumpadEqual", Key: "=", KeyCode: 187, Location: 3},
+	}...)
+
+	return keys
+}
@@ -0,0 +1,215 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/input"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func keyEvents(mockSocket *MockSocket) []*input.DispatchKeyEventParams {
+	events := []*input.DispatchKeyEventParams{}
+	for _, command := range mockSocket.Commands("Input.dispatchKeyEvent") {
+		events = append(events, command.Params().(*input.DispatchKeyEventParams))
+	}
+	return events
+}
+
+func TestKeyboardType(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardType")
+
+	if err := tab.Keyboard().Type("Hi!"); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+
+	events := keyEvents(mockSocket)
+	if 9 != len(events) {
+		t.Fatalf("Expected 9 key events, received %d", len(events))
+	}
+	expected := []struct {
+		Type input.KeyEventEnum
+		Key  string
+		Code string
+		Text string
+	}{
+		{input.KeyEvent.RawKeyDown, "H", "KeyH", ""},
+		{input.KeyEvent.Char, "H", "KeyH", "H"},
+		{input.KeyEvent.KeyUp, "H", "KeyH", ""},
+		{input.KeyEvent.RawKeyDown, "i", "KeyI", ""},
+		{input.KeyEvent.Char, "i", "KeyI", "i"},
+		{input.KeyEvent.KeyUp, "i", "KeyI", ""},
+		{input.KeyEvent.RawKeyDown, "!", "Digit1", ""},
+		{input.KeyEvent.Char, "!", "Digit1", "!"},
+		{input.KeyEvent.KeyUp, "!", "Digit1", ""},
+	}
+	for k, event := range events {
+		if expected[k].Type != event.Type ||
+			expected[k].Key != event.Key ||
+			expected[k].Code != event.Code ||
+			expected[k].Text != event.Text {
+			t.Errorf("Event %d: expected %+v, received %+v", k, expected[k], event)
+		}
+	}
+	if 72 != events[0].WindowsVirtualKeyCode {
+		t.Errorf("Expected 72, received %d", events[0].WindowsVirtualKeyCode)
+	}
+}
+
+func TestKeyboardTypeInsertText(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardTypeInsertText")
+
+	if err := tab.Keyboard().Type("é"); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if 0 != len(keyEvents(mockSocket)) {
+		t.Errorf("Expected no key events, received %d", len(keyEvents(mockSocket)))
+	}
+	commands := mockSocket.Commands("Input.insertText")
+	if 1 != len(commands) {
+		t.Fatalf("Expected 1 insertText command, received %d", len(commands))
+	}
+	if "é" != commands[0].Params().(*input.InsertTextParams).Text {
+		t.Errorf("Expected 'é', received '%s'", commands[0].Params().(*input.InsertTextParams).Text)
+	}
+}
+
+func TestKeyboardPress(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardPress")
+
+	if err := tab.Keyboard().Press("Ctrl+Shift+K"); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if 0 != tab.Keyboard().Modifiers() {
+		t.Errorf("Expected all modifiers to be released, received %d", tab.Keyboard().Modifiers())
+	}
+
+	events := keyEvents(mockSocket)
+	if 6 != len(events) {
+		t.Fatalf("Expected 6 key events, received %d", len(events))
+	}
+	expected := []struct {
+		Type      input.KeyEventEnum
+		Key       string
+		Modifiers int
+	}{
+		{input.KeyEvent.RawKeyDown, "Control", ModifierCtrl},
+		{input.KeyEvent.RawKeyDown, "Shift", ModifierCtrl | ModifierShift},
+		{input.KeyEvent.RawKeyDown, "K", ModifierCtrl | ModifierShift},
+		{input.KeyEvent.KeyUp, "K", ModifierCtrl | ModifierShift},
+		{input.KeyEvent.KeyUp, "Shift", ModifierCtrl},
+		{input.KeyEvent.KeyUp, "Control", 0},
+	}
+	for k, event := range events {
+		if expected[k].Type != event.Type ||
+			expected[k].Key != event.Key ||
+			expected[k].Modifiers != event.Modifiers {
+			t.Errorf("Event %d: expected %+v, received %+v", k, expected[k], event)
+		}
+		if "" != event.Text {
+			t.Errorf("Event %d: expected no text, received '%s'", k, event.Text)
+		}
+	}
+}
+
+func TestKeyboardDownUp(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardDownUp")
+	keyboard := tab.Keyboard()
+
+	keyboard.Down("Shift")
+	keyboard.Down("a")
+	keyboard.Down("a")
+	keyboard.Up("a")
+	keyboard.Up("Shift")
+
+	events := keyEvents(mockSocket)
+	if 7 != len(events) {
+		t.Fatalf("Expected 7 key events, received %d", len(events))
+	}
+	if "A" != events[2].Text {
+		t.Errorf("Expected 'A', received '%s'", events[2].Text)
+	}
+	if events[1].AutoRepeat {
+		t.Errorf("Expected first keyDown not to repeat")
+	}
+	if !events[3].AutoRepeat {
+		t.Errorf("Expected second keyDown to repeat")
+	}
+	if 1 != events[0].Location {
+		t.Errorf("Expected 'Shift' to resolve to ShiftLeft, received location %d", events[0].Location)
+	}
+}
+
+func TestKeyboardDownError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardDownError")
+	mockSocket.Respond("Input.dispatchKeyEvent", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Input dispatch failed"}
+	})
+
+	for _, key := range []string{"Shift", "a"} {
+		if err := tab.Keyboard().Down(key); nil == err {
+			t.Errorf("Expected error, received nil")
+		}
+	}
+	if 0 != tab.Keyboard().Modifiers() {
+		t.Errorf("Expected the modifier to be rolled back, received %d", tab.Keyboard().Modifiers())
+	}
+
+	mockSocket.Respond("Input.dispatchKeyEvent", nil)
+	tab.Keyboard().Down("a")
+	if events := keyEvents(mockSocket); events[len(events)-2].AutoRepeat || 0 != events[len(events)-2].Modifiers {
+		t.Errorf("Expected the failed key press to be rolled back, received %+v", events[len(events)-2])
+	}
+}
+
+func TestKeyboardUnknownKey(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardUnknownKey")
+
+	if err := tab.Keyboard().Press("Control+NotAKey"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 0 != tab.Keyboard().Modifiers() {
+		t.Errorf("Expected all modifiers to be released, received %d", tab.Keyboard().Modifiers())
+	}
+	if 2 != len(keyEvents(mockSocket)) {
+		t.Errorf("Expected 2 key events, received %d", len(keyEvents(mockSocket)))
+	}
+}
+
+func TestKeyboardLayout(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestKeyboardLayout")
+	tab.Keyboard().SetLayout(NewKeyboardLayout([]*KeyDefinition{
+		{Code: "KeyQ", Key: "a", ShiftKey: "A", KeyCode: 65},
+	}))
+
+	if err := tab.Keyboard().Press("a"); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if err := tab.Keyboard().Press("b"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	events := keyEvents(mockSocket)
+	if "KeyQ" != events[0].Code {
+		t.Errorf("Expected 'KeyQ', received '%s'", events[0].Code)
+	}
+}
+
+func TestSplitKeyCombo(t *testing.T) {
+	tests := map[string][]string{
+		"a":              {"a"},
+		"+":              {"+"},
+		"Shift++":        {"Shift", "+"},
+		"Control+Alt+F4": {"Control", "Alt", "F4"},
+	}
+	for combo, expected := range tests {
+		keys := splitKeyCombo(combo)
+		if len(expected) != len(keys) {
+			t.Errorf("%s: expected %v, received %v", combo, expected, keys)
+			continue
+		}
+		for k := range keys {
+			if expected[k] != keys[k] {
+				t.Errorf("%s: expected %v, received %v", combo, expected, keys)
+			}
+		}
+	}
+}
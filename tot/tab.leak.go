@@ -0,0 +1,390 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/heap"
+	"github.com/mkenney/go-chrome/tot/performance"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+LeakScenario drives the tab through one iteration of a user scenario. A
+scenario that doesn't leak returns the page to the state it started in.
+*/
+type LeakScenario func(ctx context.Context, tab *Tab) error
+
+/*
+LeakThresholds is the growth per iteration above which a memory counter is
+considered to leak.
+*/
+type LeakThresholds struct {
+	// Bytes of JavaScript heap.
+	JSHeapUsedSize float64
+
+	// DOM nodes.
+	Nodes float64
+
+	// JavaScript event listeners.
+	JSEventListeners float64
+
+	// Documents, including frames.
+	Documents float64
+}
+
+/*
+DefaultLeakThresholds tolerates the growth of caches that fill up during the
+first iterations.
+*/
+var DefaultLeakThresholds = &LeakThresholds{
+	JSHeapUsedSize:   100 * 1024,
+	Nodes:            10,
+	JSEventListeners: 5,
+	Documents:        0.5,
+}
+
+/*
+LeakOptions configures leak detection. The zero value runs the scenario once to
+warm up and 5 times measured.
+*/
+type LeakOptions struct {
+	// Optional. The number of measured iterations, defaults to 5.
+	Iterations int
+
+	// Optional. The number of iterations run before measuring, defaults to 1.
+	// Set a negative value to measure from the first iteration.
+	Warmup int
+
+	// Optional. The growth thresholds, zero thresholds default to the
+	// DefaultLeakThresholds value of the counter.
+	Thresholds *LeakThresholds
+
+	// Optional. Don't diff heap snapshots of the first and last measurement.
+	// Without snapshots the report doesn't identify the retained objects.
+	SkipHeapSnapshots bool
+}
+
+/*
+LeakSample is a measurement taken after an iteration. Garbage is collected
+before measuring.
+*/
+type LeakSample struct {
+	// The number of iterations run since the baseline measurement.
+	Iteration int
+
+	// The used JavaScript heap size in bytes.
+	JSHeapUsedSize float64
+
+	// The number of DOM nodes.
+	Nodes float64
+
+	// The number of JavaScript event listeners.
+	JSEventListeners float64
+
+	// The number of documents.
+	Documents float64
+}
+
+/*
+LeakTrend is the growth of a memory counter fitted over the samples.
+*/
+type LeakTrend struct {
+	// The counter name.
+	Name string
+
+	// The least squares growth per iteration.
+	Slope float64
+
+	// The growth per iteration above which the counter leaks.
+	Threshold float64
+
+	// The counter leaks.
+	Leaking bool
+}
+
+/*
+LeakReport is the result of leak detection.
+*/
+type LeakReport struct {
+	// The baseline and per iteration measurements.
+	Samples []*LeakSample
+
+	// The growth of the JS heap, nodes, listeners and documents.
+	Trends []*LeakTrend
+
+	// The classes of the objects allocated after the baseline measurement and
+	// still retained after the last iteration, largest growth first.
+	Retained []*heap.ConstructorDiff
+
+	// A counter grew faster than its threshold.
+	Leaking bool
+}
+
+/*
+leakRetainedLimit is the number of retained classes listed by
+LeakReport.String.
+*/
+const leakRetainedLimit = 10
+
+/*
+String implements Stringer.
+*/
+func (report *LeakReport) String() string {
+	lines := []string{}
+	if report.Leaking {
+		lines = append(lines, fmt.Sprintf("memory leak detected over %d iterations", len(report.Samples)-1))
+	} else {
+		lines = append(lines, fmt.Sprintf("no memory leak detected over %d iterations", len(report.Samples)-1))
+	}
+	for _, trend := range report.Trends {
+		status := ""
+		if trend.Leaking {
+			status = " LEAK"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %+.1f per iteration (threshold %g)%s", trend.Name, trend.Slope, trend.Threshold, status))
+	}
+	if len(report.Retained) > 0 {
+		lines = append(lines, "retained objects:")
+	}
+	for a, diff := range report.Retained {
+		if a == leakRetainedLimit {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(report.Retained)-a))
+			break
+		}
+		line := fmt.Sprintf("  %s: %+d objects, %+d bytes", diff.Name, diff.CountDelta, diff.SizeDelta)
+		if path := diff.AddedNodes[0].RetainingPath(); len(path) > 0 {
+			line += ", e.g. " + path.String()
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+/*
+DetectLeaks runs a scenario repeatedly and detects memory that grows with each
+iteration. Garbage is collected and the JS heap size and DOM counters are
+measured after each iteration, and a trend is fitted to each counter. If a
+counter grows faster than its threshold DetectLeaks returns the report and a
+LeakDetected error. The report lists the objects allocated during the measured
+iterations that are still retained, from a diff of heap snapshots.
+
+	report, err := tab.DetectLeaks(ctx, func(ctx context.Context, tab *chrome.Tab) error {
+		// open and close a dialog
+		...
+	}, nil)
+	if nil != err {
+		t.Fatalf("%s\n%s", err, report)
+	}
+*/
+func (tab *Tab) DetectLeaks(ctx context.Context, scenario LeakScenario, opts *LeakOptions) (*LeakReport, error) {
+	if nil == opts {
+		opts = &LeakOptions{}
+	}
+	iterations, warmup := opts.Iterations, opts.Warmup
+	if iterations <= 0 {
+		iterations = 5
+	}
+	if 0 == warmup {
+		warmup = 1
+	}
+	thresholds := opts.Thresholds.withDefaults()
+
+	if result := <-tab.withContext(ctx).Performance().Enable(); nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.LeakDetectionFailed, "could not enable performance metrics")
+	}
+	defer func() {
+		if result := <-tab.withContext(ctx).Performance().Disable(); nil != result.Err {
+			log.Warnf("could not disable performance metrics: %s", result.Err)
+		}
+	}()
+
+	run := func(iteration string) error {
+		if err := scenario(ctx, tab); nil != err {
+			return errs.Wrap(err, codes.LeakDetectionFailed, iteration+" failed")
+		}
+		return nil
+	}
+	for a := 1; a <= warmup; a++ {
+		if err := run(fmt.Sprintf("warmup iteration %d", a)); nil != err {
+			return nil, err
+		}
+	}
+
+	report := &LeakReport{}
+	var before *heap.Snapshot
+	for a := 0; a <= iterations; a++ {
+		if a > 0 {
+			if err := run(fmt.Sprintf("iteration %d", a)); nil != err {
+				return nil, err
+			}
+		}
+		sample, err := tab.leakSample(ctx, a)
+		if nil != err {
+			return nil, err
+		}
+		report.Samples = append(report.Samples, sample)
+		if 0 == a && !opts.SkipHeapSnapshots {
+			if before, err = tab.HeapSnapshot(ctx, nil); nil != err {
+				return nil, errs.Wrap(err, codes.LeakDetectionFailed, "could not take the baseline heap snapshot")
+			}
+		}
+	}
+
+	report.trends(thresholds)
+	if !report.Leaking {
+		return report, nil
+	}
+	if nil != before {
+		after, err := tab.HeapSnapshot(ctx, nil)
+		if nil != err {
+			return nil, errs.Wrap(err, codes.LeakDetectionFailed, "could not take the final heap snapshot")
+		}
+		for _, diff := range heap.Diff(before, after) {
+			if diff.Added > 0 && diff.CountDelta > 0 {
+				report.Retained = append(report.Retained, diff)
+			}
+		}
+	}
+	leaking := []string{}
+	for _, trend := range report.Trends {
+		if trend.Leaking {
+			leaking = append(leaking, trend.Name)
+		}
+	}
+	return report, errs.New(codes.LeakDetected, fmt.Sprintf("%s grew across %d iterations", strings.Join(leaking, ", "), iterations))
+}
+
+/*
+leakSample collects garbage and measures the memory counters.
+*/
+func (tab *Tab) leakSample(ctx context.Context, iteration int) (*LeakSample, error) {
+	// prepareForLeakDetection clears caches and collects garbage, the second
+	// collection frees objects released by finalizers.
+	for _, command := range []*protocolCommand{
+		&protocolCommand{"Memory.prepareForLeakDetection", func(protocol socket.Protocoller) error {
+			return (<-protocol.Memory().PrepareForLeakDetection()).Err
+		}},
+		&protocolCommand{"HeapProfiler.collectGarbage", func(protocol socket.Protocoller) error {
+			return (<-protocol.HeapProfiler().CollectGarbage()).Err
+		}},
+	} {
+		if err := command.send(tab.withContext(ctx)); nil != err {
+			return nil, errs.Wrap(err, codes.LeakDetectionFailed, command.method+" failed")
+		}
+	}
+	counters := <-tab.withContext(ctx).Memory().GetDOMCounters()
+	if nil != counters.Err {
+		return nil, errs.Wrap(counters.Err, codes.LeakDetectionFailed, "could not get the DOM counters")
+	}
+	metrics, err := tab.performanceMetrics(ctx)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.LeakDetectionFailed, "could not get the performance metrics")
+	}
+	return &LeakSample{
+		Iteration:        iteration,
+		JSHeapUsedSize:   metrics["JSHeapUsedSize"],
+		Nodes:            float64(counters.Nodes),
+		JSEventListeners: float64(counters.JSEventListeners),
+		Documents:        float64(counters.Documents),
+	}, nil
+}
+
+/*
+performanceMetrics returns the current run-time metrics by name. The
+Performance domain must be enabled.
+*/
+func (tab *Tab) performanceMetrics(ctx context.Context) (map[string]float64, error) {
+	result := <-tab.withContext(ctx).Performance().GetMetrics()
+	if nil != result.Err {
+		return nil, result.Err
+	}
+	return metricValues(result.Metrics), nil
+}
+
+/*
+metricValues returns the metric values by name.
+*/
+func metricValues(metrics []*performance.Metric) map[string]float64 {
+	values := make(map[string]float64, len(metrics))
+	for _, metric := range metrics {
+		values[metric.Name] = metric.Value
+	}
+	return values
+}
+
+/*
+withDefaults returns a copy of the thresholds with the DefaultLeakThresholds
+value of each unset threshold.
+*/
+func (thresholds *LeakThresholds) withDefaults() *LeakThresholds {
+	result := *DefaultLeakThresholds
+	if nil == thresholds {
+		return &result
+	}
+	if 0 != thresholds.JSHeapUsedSize {
+		result.JSHeapUsedSize = thresholds.JSHeapUsedSize
+	}
+	if 0 != thresholds.Nodes {
+		result.Nodes = thresholds.Nodes
+	}
+	if 0 != thresholds.JSEventListeners {
+		result.JSEventListeners = thresholds.JSEventListeners
+	}
+	if 0 != thresholds.Documents {
+		result.Documents = thresholds.Documents
+	}
+	return &result
+}
+
+/*
+trends fits the growth of each counter.
+*/
+func (report *LeakReport) trends(thresholds *LeakThresholds) {
+	for _, counter := range []struct {
+		name      string
+		threshold float64
+		value     func(*LeakSample) float64
+	}{
+		{"JSHeapUsedSize", thresholds.JSHeapUsedSize, func(s *LeakSample) float64 { return s.JSHeapUsedSize }},
+		{"Nodes", thresholds.Nodes, func(s *LeakSample) float64 { return s.Nodes }},
+		{"JSEventListeners", thresholds.JSEventListeners, func(s *LeakSample) float64 { return s.JSEventListeners }},
+		{"Documents", thresholds.Documents, func(s *LeakSample) float64 { return s.Documents }},
+	} {
+		values := make([]float64, len(report.Samples))
+		for a, sample := range report.Samples {
+			values[a] = counter.value(sample)
+		}
+		trend := &LeakTrend{
+			Name:      counter.name,
+			Slope:     slope(values),
+			Threshold: counter.threshold,
+		}
+		trend.Leaking = trend.Slope > trend.Threshold
+		report.Leaking = report.Leaking || trend.Leaking
+		report.Trends = append(report.Trends, trend)
+	}
+}
+
+/*
+slope returns the least squares slope of values over their index.
+*/
+func slope(values []float64) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for a, y := range values {
+		x := float64(a)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
@@ -0,0 +1,131 @@
+package chrome
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	heapprofiler "github.com/mkenney/go-chrome/tot/heap/profiler"
+	"github.com/mkenney/go-chrome/tot/memory"
+	"github.com/mkenney/go-chrome/tot/performance"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+testHeapSnapshotLeak is testHeapSnapshot with a second Item retained by the
+first.
+*/
+const testHeapSnapshotLeak = `{"snapshot":{"meta":{` +
+	`"node_fields":["type","name","id","self_size","edge_count"],` +
+	`"node_types":[["synthetic","object"],"string","number","number","number"],` +
+	`"edge_fields":["type","name_or_index","to_node"],` +
+	`"edge_types":[["property"],"string_or_number","node"]}},` +
+	`"nodes":[0,0,1,0,1,1,1,3,16,1,1,1,5,16,0],"edges":[0,2,5,0,3,10],"strings":["","Item","item","next"]}`
+
+func TestDetectLeaks(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestDetectLeaks")
+	measurements := 0
+	mockSocket.Respond("Memory.getDOMCounters", func(params interface{}) (interface{}, *socket.Error) {
+		measurements++
+		return &memory.GetDOMCountersResult{Documents: 1, Nodes: 100 + 5*measurements, JSEventListeners: 3}, nil
+	})
+	mockSocket.Respond("Performance.getMetrics", func(params interface{}) (interface{}, *socket.Error) {
+		return &performance.GetMetricsResult{Metrics: []*performance.Metric{
+			{Name: "Timestamp", Value: 12.5},
+			{Name: "JSHeapUsedSize", Value: float64(1e6 + 200*1024*measurements)},
+		}}, nil
+	})
+	snapshots := []string{testHeapSnapshot, testHeapSnapshotLeak}
+	mockSocket.Respond("HeapProfiler.takeHeapSnapshot", func(params interface{}) (interface{}, *socket.Error) {
+		mockSocket.Fire("HeapProfiler.addHeapSnapshotChunk", &heapprofiler.AddHeapSnapshotChunkEvent{Chunk: snapshots[0]})
+		snapshots = snapshots[1:]
+		return nil, nil
+	})
+
+	runs := 0
+	report, err := tab.DetectLeaks(context.Background(), func(ctx context.Context, tab *Tab) error {
+		runs++
+		return nil
+	}, &LeakOptions{Iterations: 3, Thresholds: &LeakThresholds{JSHeapUsedSize: 150 * 1024}})
+	if nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	if 4 != runs || 4 != len(report.Samples) || 3 != report.Samples[3].Iteration {
+		t.Fatalf("Expected 1 warmup and 3 measured iterations, received %d runs and %d samples", runs, len(report.Samples))
+	}
+	if heap := report.Trends[0]; "JSHeapUsedSize" != heap.Name || 200*1024 != heap.Slope || 150*1024 != heap.Threshold || !heap.Leaking {
+		t.Errorf("Expected the JS heap to leak, received %+v", heap)
+	}
+	// Thresholds that aren't set default to DefaultLeakThresholds.
+	if nodes := report.Trends[1]; 5 != nodes.Slope || DefaultLeakThresholds.Nodes != nodes.Threshold || nodes.Leaking {
+		t.Errorf("Expected nodes not to leak, received %+v", nodes)
+	}
+	if !report.Leaking || 1 != len(report.Retained) || "Item" != report.Retained[0].Name {
+		t.Fatalf("Expected the retained Item to be reported, received %v", report.Retained)
+	}
+	if !strings.Contains(report.String(), "Item: +1 objects, +16 bytes, e.g. Item.next -> Item @5") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+	for _, method := range []string{"Memory.prepareForLeakDetection", "HeapProfiler.collectGarbage"} {
+		if 4 != len(mockSocket.Commands(method)) {
+			t.Errorf("Expected %s before each measurement", method)
+		}
+	}
+	if 1 != len(mockSocket.Commands("Performance.disable")) {
+		t.Errorf("Expected performance metrics to be disabled")
+	}
+}
+
+func TestDetectLeaksNone(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestDetectLeaksNone")
+
+	report, err := tab.DetectLeaks(context.Background(), func(ctx context.Context, tab *Tab) error {
+		return nil
+	}, &LeakOptions{Warmup: -1, SkipHeapSnapshots: true})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if report.Leaking || 6 != len(report.Samples) {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+	if 0 != len(mockSocket.Commands("HeapProfiler.takeHeapSnapshot")) {
+		t.Errorf("Expected no heap snapshots")
+	}
+}
+
+func TestDetectLeaksError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestDetectLeaksError")
+
+	if _, err := tab.DetectLeaks(context.Background(), func(ctx context.Context, tab *Tab) error {
+		return errors.New("button not found")
+	}, nil); nil == err || !strings.Contains(err.Error(), "warmup iteration 1 failed") {
+		t.Errorf("Expected the scenario error, received %v", err)
+	}
+
+	mockSocket.Respond("Memory.prepareForLeakDetection", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Memory is not available"}
+	})
+	if _, err := tab.DetectLeaks(context.Background(), func(ctx context.Context, tab *Tab) error {
+		return nil
+	}, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestSlope(t *testing.T) {
+	for _, test := range []struct {
+		values   []float64
+		expected float64
+	}{
+		{nil, 0},
+		{[]float64{5}, 0},
+		{[]float64{1, 3, 5, 7}, 2},
+		{[]float64{4, 4, 4}, 0},
+		{[]float64{10, 8, 6}, -2},
+	} {
+		if slope := slope(test.values); test.expected != slope {
+			t.Errorf("%v: expected %g, received %g", test.values, test.expected, slope)
+		}
+	}
+}
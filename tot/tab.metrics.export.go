@@ -0,0 +1,148 @@
+package chrome
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+WriteCSV writes the samples to w as CSV with a time, step and title column
+followed by a column for each metric in alphabetical order. Metrics missing
+from a sample are empty.
+*/
+func (sampler *MetricsSampler) WriteCSV(w io.Writer) error {
+	names := sampler.Names()
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{"time", "step", "title"}, names...))
+	for _, sample := range sampler.Samples() {
+		record := []string{sample.Time.Format(time.RFC3339Nano), sample.Step, sample.Title}
+		for _, name := range names {
+			value := ""
+			if v, ok := sample.Values[name]; ok {
+				value = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+			record = append(record, value)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); nil != err {
+		return errs.Wrap(err, codes.MetricsWriteFailed, "could not write the metrics")
+	}
+	return nil
+}
+
+/*
+metricsJSON is the JSON export of a sampler.
+*/
+type metricsJSON struct {
+	Metrics []string         `json:"metrics"`
+	Samples []*MetricsSample `json:"samples"`
+	Steps   []*MetricsDelta  `json:"steps"`
+}
+
+/*
+WriteJSON writes the metric names, the samples and the step deltas to w as
+JSON. Step durations are in nanoseconds.
+*/
+func (sampler *MetricsSampler) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&metricsJSON{
+		Metrics: sampler.Names(),
+		Samples: sampler.Samples(),
+		Steps:   sampler.Deltas(),
+	}); nil != err {
+		return errs.Wrap(err, codes.MetricsWriteFailed, "could not write the metrics")
+	}
+	return nil
+}
+
+/*
+WritePrometheus writes the latest value of each metric to w as a gauge in the
+Prometheus text exposition format. Metric names are converted to snake case
+and prefixed with the namespace, e.g. JSHeapUsedSize is exported as
+chrome_js_heap_used_size.
+*/
+func (sampler *MetricsSampler) WritePrometheus(w io.Writer) error {
+	latest := map[string]float64{}
+	for _, sample := range sampler.Samples() {
+		for name, value := range sample.Values {
+			latest[name] = value
+		}
+	}
+	namespace := sampler.opts.Namespace
+	if "" == namespace {
+		namespace = "chrome"
+	}
+	labels := prometheusLabels(sampler.opts.Labels)
+
+	buf := &strings.Builder{}
+	for _, name := range sampler.Names() {
+		metric := namespace + "_" + snakeCase(name)
+		fmt.Fprintf(buf, "# TYPE %s gauge\n%s%s %s\n", metric, metric, labels, strconv.FormatFloat(latest[name], 'g', -1, 64))
+	}
+	if _, err := io.WriteString(w, buf.String()); nil != err {
+		return errs.Wrap(err, codes.MetricsWriteFailed, "could not write the metrics")
+	}
+	return nil
+}
+
+/*
+ServeHTTP serves the latest metrics in the Prometheus text exposition format.
+The sampler can be registered as a scrape target:
+
+	http.Handle("/metrics", sampler)
+*/
+func (sampler *MetricsSampler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	sampler.WritePrometheus(w)
+}
+
+/*
+prometheusLabels formats labels sorted by name.
+*/
+func prometheusLabels(labels map[string]string) string {
+	if 0 == len(labels) {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(names))
+	for a, name := range names {
+		pairs[a] = fmt.Sprintf(`%s="%s"`, name, escape.Replace(labels[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+/*
+snakeCase converts a camel case metric name to snake case. Acronyms are kept
+together, JSHeapUsedSize becomes js_heap_used_size.
+*/
+func snakeCase(name string) string {
+	runes := []rune(name)
+	buf := &strings.Builder{}
+	for a, r := range runes {
+		if unicode.IsUpper(r) && a > 0 &&
+			(!unicode.IsUpper(runes[a-1]) || (a+1 < len(runes) && unicode.IsLower(runes[a+1]))) {
+			buf.WriteRune('_')
+		}
+		buf.WriteRune(unicode.ToLower(r))
+	}
+	return buf.String()
+}
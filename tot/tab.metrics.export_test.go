@@ -0,0 +1,92 @@
+package chrome
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+/*
+testSampler returns a sampler with two samples in a step.
+*/
+func testSampler(opts *MetricsOptions) *MetricsSampler {
+	start := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := &MetricsSample{Time: start, Step: "load", Values: map[string]float64{"JSHeapUsedSize": 1000, "LayoutCount": 2}}
+	return &MetricsSampler{
+		opts: opts,
+		samples: []*MetricsSample{
+			first,
+			{Time: start.Add(time.Second), Step: "load", Title: "ready", Values: map[string]float64{"LayoutCount": 3}},
+			{Time: start.Add(2 * time.Second), Step: "load", Values: map[string]float64{"JSHeapUsedSize": 1500.5, "LayoutCount": 4}},
+		},
+		steps: []*MetricsSample{first},
+	}
+}
+
+func TestMetricsWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := testSampler(&MetricsOptions{}).WriteCSV(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	expected := `time,step,title,JSHeapUsedSize,LayoutCount
+2018-01-02T03:04:05Z,load,,1000,2
+2018-01-02T03:04:06Z,load,ready,,3
+2018-01-02T03:04:07Z,load,,1500.5,4
+`
+	if expected != buf.String() {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestMetricsWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := testSampler(&MetricsOptions{}).WriteJSON(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	result := &metricsJSON{}
+	if err := json.Unmarshal(buf.Bytes(), result); nil != err {
+		t.Fatalf("Expected JSON, received error: %v", err)
+	}
+	if 2 != len(result.Metrics) || 3 != len(result.Samples) || 1 != len(result.Steps) {
+		t.Fatalf("Unexpected JSON %s", buf.String())
+	}
+	if step := result.Steps[0]; 2*time.Second != step.Duration || 500.5 != step.Values["JSHeapUsedSize"] || 2 != step.Values["LayoutCount"] {
+		t.Errorf("Unexpected step %+v", step)
+	}
+}
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	sampler := testSampler(&MetricsOptions{Labels: map[string]string{"test": `soak "1"`, "browser": "headless"}})
+	recorder := httptest.NewRecorder()
+	sampler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	expected := `# TYPE chrome_js_heap_used_size gauge
+chrome_js_heap_used_size{browser="headless",test="soak \"1\""} 1500.5
+# TYPE chrome_layout_count gauge
+chrome_layout_count{browser="headless",test="soak \"1\""} 4
+`
+	if expected != recorder.Body.String() {
+		t.Errorf("Unexpected metrics:\n%s", recorder.Body.String())
+	}
+
+	buf := &bytes.Buffer{}
+	testSampler(&MetricsOptions{Namespace: "soak"}).WritePrometheus(buf)
+	if !bytes.Contains(buf.Bytes(), []byte("soak_layout_count 4\n")) {
+		t.Errorf("Expected the namespace to be used, received:\n%s", buf.String())
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"JSHeapUsedSize":       "js_heap_used_size",
+		"LayoutCount":          "layout_count",
+		"Nodes":                "nodes",
+		"FirstMeaningfulPaint": "first_meaningful_paint",
+		"V8CompileDuration":    "v8_compile_duration",
+	} {
+		if actual := snakeCase(name); expected != actual {
+			t.Errorf("%s: expected '%s', received '%s'", name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package chrome
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/performance"
+)
+
+/*
+MetricsOptions configures a metrics sampler. The zero value samples every
+metric once a second.
+*/
+type MetricsOptions struct {
+	// Optional. The sampling interval, defaults to 1 second.
+	Interval time.Duration
+
+	// Optional. The metrics to record, e.g. "JSHeapUsedSize", "Nodes",
+	// "LayoutCount" or "TaskDuration". Defaults to all metrics.
+	Metrics []string
+
+	// Optional. The prefix of Prometheus metric names, defaults to "chrome".
+	Namespace string
+
+	// Optional. Labels added to each Prometheus metric.
+	Labels map[string]string
+}
+
+/*
+MetricsSample is the value of the metrics at a point in time.
+*/
+type MetricsSample struct {
+	// The time the sample was taken.
+	Time time.Time `json:"time"`
+
+	// The scenario step the sample belongs to, see MetricsSampler.Step.
+	Step string `json:"step,omitempty"`
+
+	// The console.timeStamp title of samples reported by the page.
+	Title string `json:"title,omitempty"`
+
+	// The metric values by name.
+	Values map[string]float64 `json:"values"`
+}
+
+/*
+MetricsDelta is the change of the metrics during a scenario step.
+*/
+type MetricsDelta struct {
+	// The step name.
+	Step string `json:"step"`
+
+	// The duration of the step.
+	Duration time.Duration `json:"duration"`
+
+	// The change of each metric from the first to the last sample of the
+	// step.
+	Values map[string]float64 `json:"values"`
+}
+
+/*
+MetricsSampler records time series of the Performance domain metrics.
+*/
+type MetricsSampler struct {
+	done     chan struct{}
+	interval time.Duration
+	metrics  map[string]bool
+	mux      sync.Mutex
+	opts     *MetricsOptions
+	samples  []*MetricsSample
+	step     string
+	steps    []*MetricsSample
+	stopped  bool
+	tab      *Tab
+	wg       sync.WaitGroup
+}
+
+/*
+StartMetrics enables the Performance domain and samples its metrics in the
+background until the sampler is stopped. Metrics reported by the page with
+console.timeStamp are recorded as they arrive.
+
+	sampler, err := tab.StartMetrics(ctx, &chrome.MetricsOptions{
+		Interval: 100 * time.Millisecond,
+		Metrics:  []string{"JSHeapUsedSize", "Nodes", "LayoutCount"},
+	})
+	...
+	sampler.Step(ctx, "open dialog")
+	...
+	sampler.Stop(ctx)
+	sampler.WriteCSV(file)
+*/
+func (tab *Tab) StartMetrics(ctx context.Context, opts *MetricsOptions) (*MetricsSampler, error) {
+	if nil == opts {
+		opts = &MetricsOptions{}
+	}
+	sampler := &MetricsSampler{
+		done:     make(chan struct{}),
+		interval: opts.Interval,
+		opts:     opts,
+		tab:      tab,
+	}
+	if sampler.interval <= 0 {
+		sampler.interval = time.Second
+	}
+	if len(opts.Metrics) > 0 {
+		sampler.metrics = map[string]bool{}
+		for _, name := range opts.Metrics {
+			sampler.metrics[name] = true
+		}
+	}
+
+	// The handler can't be removed, it ignores events once the sampler is
+	// stopped.
+	tab.Performance().OnMetrics(func(event *performance.MetricsEvent) {
+		if nil != event.Err {
+			log.Errorf("invalid metrics event: %s", event.Err)
+			return
+		}
+		sampler.mux.Lock()
+		defer sampler.mux.Unlock()
+		sampler.add(metricValues(event.Metrics), event.Title, time.Now())
+	})
+	if result := <-tab.withContext(ctx).Performance().Enable(); nil != result.Err {
+		sampler.stop()
+		return nil, errs.Wrap(result.Err, codes.MetricsFailed, "could not enable performance metrics")
+	}
+	if err := sampler.Sample(ctx); nil != err {
+		sampler.stop()
+		return nil, err
+	}
+
+	sampler.wg.Add(1)
+	go sampler.run(sampler.done)
+	return sampler, nil
+}
+
+/*
+run samples the metrics at the interval until the sampler is stopped.
+*/
+func (sampler *MetricsSampler) run(done chan struct{}) {
+	defer sampler.wg.Done()
+	ticker := time.NewTicker(sampler.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := sampler.Sample(context.Background()); nil != err {
+				log.Warnf("could not sample metrics: %s", err)
+			}
+		}
+	}
+}
+
+/*
+Sample records the current metrics.
+*/
+func (sampler *MetricsSampler) Sample(ctx context.Context) error {
+	values, err := sampler.tab.performanceMetrics(ctx)
+	if nil != err {
+		return errs.Wrap(err, codes.MetricsFailed, "could not get the performance metrics")
+	}
+	sampler.mux.Lock()
+	defer sampler.mux.Unlock()
+	sampler.add(values, "", time.Now())
+	return nil
+}
+
+/*
+Step samples the metrics and starts a scenario step. Samples are tagged with
+the current step and MetricsSampler.Deltas reports the change of the metrics
+during each step.
+*/
+func (sampler *MetricsSampler) Step(ctx context.Context, name string) error {
+	values, err := sampler.tab.performanceMetrics(ctx)
+	if nil != err {
+		return errs.Wrap(err, codes.MetricsFailed, "could not get the performance metrics")
+	}
+	sampler.mux.Lock()
+	defer sampler.mux.Unlock()
+	sampler.step = name
+	if sample := sampler.add(values, "", time.Now()); nil != sample {
+		sampler.steps = append(sampler.steps, sample)
+	}
+	return nil
+}
+
+/*
+add records a sample and returns it, or nil if the sampler is stopped. The
+caller must hold the lock.
+*/
+func (sampler *MetricsSampler) add(values map[string]float64, title string, now time.Time) *MetricsSample {
+	if sampler.stopped {
+		return nil
+	}
+	if nil != sampler.metrics {
+		for name := range values {
+			if !sampler.metrics[name] {
+				delete(values, name)
+			}
+		}
+	}
+	sample := &MetricsSample{
+		Time:   now,
+		Step:   sampler.step,
+		Title:  title,
+		Values: values,
+	}
+	sampler.samples = append(sampler.samples, sample)
+	return sample
+}
+
+/*
+Stop takes a final sample, stops sampling and disables the Performance domain.
+*/
+func (sampler *MetricsSampler) Stop(ctx context.Context) error {
+	sampler.mux.Lock()
+	done := sampler.done
+	sampler.done = nil
+	sampler.mux.Unlock()
+	if nil == done {
+		return errs.New(codes.MetricsFailed, "the sampler is stopped")
+	}
+	close(done)
+	sampler.wg.Wait()
+
+	err := sampler.Sample(ctx)
+	sampler.stop()
+	if result := <-sampler.tab.withContext(ctx).Performance().Disable(); nil != result.Err {
+		log.Warnf("could not disable performance metrics: %s", result.Err)
+	}
+	return err
+}
+
+/*
+stop stops recording samples.
+*/
+func (sampler *MetricsSampler) stop() {
+	sampler.mux.Lock()
+	defer sampler.mux.Unlock()
+	sampler.stopped = true
+}
+
+/*
+Samples returns the recorded samples in time order.
+*/
+func (sampler *MetricsSampler) Samples() []*MetricsSample {
+	sampler.mux.Lock()
+	defer sampler.mux.Unlock()
+	samples := append(sampler.samples[:0:0], sampler.samples...)
+	sort.SliceStable(samples, func(a, b int) bool {
+		return samples[a].Time.Before(samples[b].Time)
+	})
+	return samples
+}
+
+/*
+Series returns the time series of a metric.
+*/
+func (sampler *MetricsSampler) Series(name string) ([]time.Time, []float64) {
+	times, values := []time.Time{}, []float64{}
+	for _, sample := range sampler.Samples() {
+		if value, ok := sample.Values[name]; ok {
+			times = append(times, sample.Time)
+			values = append(values, value)
+		}
+	}
+	return times, values
+}
+
+/*
+Names returns the names of the recorded metrics in alphabetical order.
+*/
+func (sampler *MetricsSampler) Names() []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, sample := range sampler.Samples() {
+		for name := range sample.Values {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+Deltas returns the change of the metrics during each scenario step, in order.
+A step ends when the next step starts, the last step ends with the last
+sample.
+*/
+func (sampler *MetricsSampler) Deltas() []*MetricsDelta {
+	sampler.mux.Lock()
+	steps := append(sampler.steps[:0:0], sampler.steps...)
+	sampler.mux.Unlock()
+
+	var end *MetricsSample
+	for _, sample := range sampler.Samples() {
+		if "" == sample.Title {
+			end = sample
+		}
+	}
+	deltas := []*MetricsDelta{}
+	for a, first := range steps {
+		last := end
+		if a+1 < len(steps) {
+			last = steps[a+1]
+		}
+		delta := &MetricsDelta{
+			Step:     first.Step,
+			Duration: last.Time.Sub(first.Time),
+			Values:   map[string]float64{},
+		}
+		for name, value := range last.Values {
+			if start, ok := first.Values[name]; ok {
+				delta.Values[name] = value - start
+			}
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
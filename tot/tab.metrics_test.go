@@ -0,0 +1,114 @@
+package chrome
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/performance"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestMetricsSampler(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMetricsSampler")
+	calls := 0.0
+	mockSocket.Respond("Performance.getMetrics", func(params interface{}) (interface{}, *socket.Error) {
+		calls++
+		return &performance.GetMetricsResult{Metrics: []*performance.Metric{
+			{Name: "JSHeapUsedSize", Value: 1000 * calls},
+			{Name: "Nodes", Value: 10 * calls},
+			{Name: "TaskDuration", Value: 0.25 * calls},
+		}}, nil
+	})
+
+	sampler, err := tab.StartMetrics(context.Background(), &MetricsOptions{
+		Interval: time.Hour,
+		Metrics:  []string{"JSHeapUsedSize", "Nodes"},
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if err := sampler.Step(context.Background(), "open"); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if err := sampler.Sample(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	mockSocket.Fire("Performance.metrics", &performance.MetricsEvent{
+		Title:   "dialog shown",
+		Metrics: []*performance.Metric{{Name: "Nodes", Value: 5}},
+	})
+	if err := sampler.Step(context.Background(), "close"); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if err := sampler.Stop(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if err := sampler.Stop(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+
+	samples := sampler.Samples()
+	if 6 != len(samples) || "" != samples[0].Step || "open" != samples[2].Step || "dialog shown" != samples[3].Title {
+		t.Fatalf("Unexpected samples %+v", samples)
+	}
+	if _, ok := samples[0].Values["TaskDuration"]; ok {
+		t.Errorf("Expected unselected metrics to be dropped")
+	}
+	if _, values := sampler.Series("JSHeapUsedSize"); 5 != len(values) || 5000 != values[4] {
+		t.Errorf("Unexpected series %v", values)
+	}
+	if names := sampler.Names(); 2 != len(names) || "JSHeapUsedSize" != names[0] {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	deltas := sampler.Deltas()
+	if 2 != len(deltas) || "open" != deltas[0].Step || 2000 != deltas[0].Values["JSHeapUsedSize"] || 10 != deltas[1].Values["Nodes"] {
+		t.Errorf("Unexpected deltas %+v", deltas)
+	}
+
+	// Samples are no longer recorded.
+	mockSocket.Fire("Performance.metrics", &performance.MetricsEvent{})
+	if 6 != len(sampler.Samples()) {
+		t.Errorf("Expected no samples after the sampler is stopped")
+	}
+	if 1 != len(mockSocket.Commands("Performance.disable")) {
+		t.Errorf("Expected performance metrics to be disabled")
+	}
+}
+
+func TestMetricsSamplerInterval(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMetricsSamplerInterval")
+	mockSocket.Respond("Performance.getMetrics", func(params interface{}) (interface{}, *socket.Error) {
+		return &performance.GetMetricsResult{Metrics: []*performance.Metric{
+			{Name: "JSHeapUsedSize", Value: 1000},
+			{Name: "Nodes", Value: 10},
+			{Name: "TaskDuration", Value: 0.25},
+		}}, nil
+	})
+
+	sampler, err := tab.StartMetrics(context.Background(), &MetricsOptions{Interval: time.Millisecond})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := sampler.Stop(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if samples := sampler.Samples(); len(samples) < 3 || 3 != len(samples[0].Values) {
+		t.Errorf("Expected the metrics to be sampled in the background, received %d samples", len(samples))
+	}
+	if 0 != len(sampler.Deltas()) {
+		t.Errorf("Expected no deltas without steps")
+	}
+}
+
+func TestMetricsSamplerError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMetricsSamplerError")
+	mockSocket.Respond("Performance.getMetrics", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Performance is not enabled"}
+	})
+	if _, err := tab.StartMetrics(context.Background(), nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
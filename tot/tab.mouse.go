@@ -0,0 +1,281 @@
+package chrome
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/input"
+)
+
+/*
+Mouse provides a high-level interface for sending mouse input to a tab. It
+tracks the pointer position and the buttons currently held down so that every
+dispatched event carries a consistent state.
+*/
+type Mouse struct {
+	buttons int
+	mux     sync.Mutex
+	tab     *Tab
+	x       int
+	y       int
+}
+
+/*
+Mouse returns the Mouse instance for this tab.
+*/
+func (tab *Tab) Mouse() *Mouse {
+	tab.mouseMux.Lock()
+	defer tab.mouseMux.Unlock()
+	if nil == tab.mouse {
+		tab.mouse = &Mouse{tab: tab}
+	}
+	return tab.mouse
+}
+
+/*
+Click moves the pointer to the specified coordinates and clicks the button
+clickCount times. Each successive press carries an incremented clickCount so
+that the page receives dblclick and triple click selection events.
+*/
+func (mouse *Mouse) Click(x, y int, button input.ButtonEventEnum, clickCount int) error {
+	if clickCount < 1 {
+		clickCount = 1
+	}
+	if err := mouse.Move(x, y, 1); nil != err {
+		return err
+	}
+	for count := 1; count <= clickCount; count++ {
+		if err := mouse.Down(button, count); nil != err {
+			return err
+		}
+		if err := mouse.Up(button, count); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+DoubleClick double clicks the left button at the specified coordinates.
+*/
+func (mouse *Mouse) DoubleClick(x, y int) error {
+	return mouse.Click(x, y, input.ButtonEvent.Left, 2)
+}
+
+/*
+Down presses a mouse button at the current pointer position.
+*/
+func (mouse *Mouse) Down(button input.ButtonEventEnum, clickCount int) error {
+	mouse.mux.Lock()
+	mouse.buttons |= buttonBit(button)
+	params := mouse.params(input.MouseEvent.MousePressed)
+	mouse.mux.Unlock()
+
+	params.Button = button
+	params.ClickCount = clickCount
+	return mouse.dispatch(params)
+}
+
+/*
+Drag presses the left button at the start coordinates, moves the pointer to
+the end coordinates in the specified number of steps and releases the button.
+*/
+func (mouse *Mouse) Drag(fromX, fromY, toX, toY, steps int) error {
+	if err := mouse.Move(fromX, fromY, 1); nil != err {
+		return err
+	}
+	if err := mouse.Down(input.ButtonEvent.Left, 1); nil != err {
+		return err
+	}
+	if err := mouse.Move(toX, toY, steps); nil != err {
+		mouse.Up(input.ButtonEvent.Left, 1)
+		return err
+	}
+	return mouse.Up(input.ButtonEvent.Left, 1)
+}
+
+/*
+DragAndDrop drags the center of one element onto the center of another using
+pointer events.
+*/
+func (mouse *Mouse) DragAndDrop(from, to dom.NodeID, steps int) error {
+	fromX, fromY, err := mouse.tab.elementCenter(from)
+	if nil != err {
+		return err
+	}
+	toX, toY, err := mouse.tab.elementCenter(to)
+	if nil != err {
+		return err
+	}
+	return mouse.Drag(fromX, fromY, toX, toY, steps)
+}
+
+/*
+Move moves the pointer from its current position to the specified coordinates,
+dispatching steps intermediate mouseMoved events along a straight line.
+*/
+func (mouse *Mouse) Move(x, y, steps int) error {
+	if steps < 1 {
+		steps = 1
+	}
+	mouse.mux.Lock()
+	fromX, fromY := mouse.x, mouse.y
+	mouse.mux.Unlock()
+
+	for step := 1; step <= steps; step++ {
+		mouse.mux.Lock()
+		mouse.x = fromX + int(math.Floor(float64(x-fromX)*float64(step)/float64(steps)+0.5))
+		mouse.y = fromY + int(math.Floor(float64(y-fromY)*float64(step)/float64(steps)+0.5))
+		params := mouse.params(input.MouseEvent.MouseMoved)
+		mouse.mux.Unlock()
+
+		params.Button = mouse.pressedButton()
+		if err := mouse.dispatch(params); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+MoveToElement moves the pointer to the center of an element.
+*/
+func (mouse *Mouse) MoveToElement(nodeID dom.NodeID, steps int) error {
+	x, y, err := mouse.tab.elementCenter(nodeID)
+	if nil != err {
+		return err
+	}
+	return mouse.Move(x, y, steps)
+}
+
+/*
+Position returns the current pointer coordinates.
+*/
+func (mouse *Mouse) Position() (x, y int) {
+	mouse.mux.Lock()
+	defer mouse.mux.Unlock()
+	return mouse.x, mouse.y
+}
+
+/*
+Pressed returns whether a mouse button is currently held down.
+*/
+func (mouse *Mouse) Pressed(button input.ButtonEventEnum) bool {
+	mouse.mux.Lock()
+	defer mouse.mux.Unlock()
+	return 0 != mouse.buttons&buttonBit(button)
+}
+
+/*
+TripleClick triple clicks the left button at the specified coordinates.
+*/
+func (mouse *Mouse) TripleClick(x, y int) error {
+	return mouse.Click(x, y, input.ButtonEvent.Left, 3)
+}
+
+/*
+Up releases a mouse button at the current pointer position.
+*/
+func (mouse *Mouse) Up(button input.ButtonEventEnum, clickCount int) error {
+	mouse.mux.Lock()
+	mouse.buttons &^= buttonBit(button)
+	params := mouse.params(input.MouseEvent.MouseReleased)
+	mouse.mux.Unlock()
+
+	params.Button = button
+	params.ClickCount = clickCount
+	return mouse.dispatch(params)
+}
+
+/*
+Wheel dispatches a mouse wheel event at the current pointer position.
+*/
+func (mouse *Mouse) Wheel(deltaX, deltaY int) error {
+	mouse.mux.Lock()
+	params := mouse.params(input.MouseEvent.MouseWheel)
+	mouse.mux.Unlock()
+
+	params.DeltaX = deltaX
+	params.DeltaY = deltaY
+	return mouse.dispatch(params)
+}
+
+/*
+dispatch sends a single mouse event to the tab.
+*/
+func (mouse *Mouse) dispatch(params *input.DispatchMouseEventParams) error {
+	result := <-mouse.tab.Input().DispatchMouseEvent(params)
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.InputDispatchFailed, fmt.Sprintf("%s event at %d,%d failed", params.Type, params.X, params.Y))
+	}
+	return nil
+}
+
+/*
+params returns the event parameters for the current mouse state. The caller
+must hold the mutex.
+*/
+func (mouse *Mouse) params(eventType input.MouseEventEnum) *input.DispatchMouseEventParams {
+	return &input.DispatchMouseEventParams{
+		Type:      eventType,
+		X:         mouse.x,
+		Y:         mouse.y,
+		Modifiers: mouse.tab.Keyboard().Modifiers(),
+		Buttons:   mouse.buttons,
+	}
+}
+
+/*
+pressedButton returns the button reported with mouseMoved events, the first
+button currently held down.
+*/
+func (mouse *Mouse) pressedButton() input.ButtonEventEnum {
+	for _, button := range []input.ButtonEventEnum{
+		input.ButtonEvent.Left,
+		input.ButtonEvent.Right,
+		input.ButtonEvent.Middle,
+	} {
+		if mouse.Pressed(button) {
+			return button
+		}
+	}
+	return input.ButtonEvent.None
+}
+
+/*
+buttonBit returns the buttons bit field value for a mouse button.
+*/
+func buttonBit(button input.ButtonEventEnum) int {
+	switch button {
+	case input.ButtonEvent.Left:
+		return 1
+	case input.ButtonEvent.Right:
+		return 2
+	case input.ButtonEvent.Middle:
+		return 4
+	}
+	return 0
+}
+
+/*
+elementCenter returns the viewport coordinates of the center of an element's
+border box.
+*/
+func (tab *Tab) elementCenter(nodeID dom.NodeID) (int, int, error) {
+	result := <-tab.DOM().GetBoxModel(&dom.GetBoxModelParams{
+		NodeID: nodeID,
+	})
+	if nil != result.Err {
+		return 0, 0, errs.Wrap(result.Err, codes.InputElementNotVisible, fmt.Sprintf("could not get box model for node %d", nodeID))
+	}
+	if nil == result.Model || 0 == result.Model.Width || 0 == result.Model.Height {
+		return 0, 0, errs.New(codes.InputElementNotVisible, fmt.Sprintf("node %d is not visible", nodeID))
+	}
+	x := result.Model.Border[0] + float64(result.Model.Width)/2
+	y := result.Model.Border[1] + float64(result.Model.Height)/2
+	return int(math.Floor(x + 0.5)), int(math.Floor(y + 0.5)), nil
+}
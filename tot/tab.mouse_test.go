@@ -0,0 +1,134 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/input"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func mouseEvents(mockSocket *MockSocket) []*input.DispatchMouseEventParams {
+	events := []*input.DispatchMouseEventParams{}
+	for _, command := range mockSocket.Commands("Input.dispatchMouseEvent") {
+		events = append(events, command.Params().(*input.DispatchMouseEventParams))
+	}
+	return events
+}
+
+func TestMouseMove(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMouseMove")
+
+	if err := tab.Mouse().Move(100, 50, 4); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := mouseEvents(mockSocket)
+	if 4 != len(events) {
+		t.Fatalf("Expected 4 mouse events, received %d", len(events))
+	}
+	expected := [][2]int{{25, 13}, {50, 25}, {75, 38}, {100, 50}}
+	for k, event := range events {
+		if input.MouseEvent.MouseMoved != event.Type {
+			t.Errorf("Event %d: expected mouseMoved, received %s", k, event.Type)
+		}
+		if expected[k][0] != event.X || expected[k][1] != event.Y {
+			t.Errorf("Event %d: expected %v, received %d,%d", k, expected[k], event.X, event.Y)
+		}
+	}
+	if x, y := tab.Mouse().Position(); 100 != x || 50 != y {
+		t.Errorf("Expected 100,50, received %d,%d", x, y)
+	}
+}
+
+func TestMouseClickCount(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMouseClickCount")
+
+	if err := tab.Mouse().TripleClick(10, 20); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := mouseEvents(mockSocket)
+	if 7 != len(events) {
+		t.Fatalf("Expected 7 mouse events, received %d", len(events))
+	}
+	for k, event := range events[1:] {
+		count := k/2 + 1
+		if count != event.ClickCount {
+			t.Errorf("Event %d: expected clickCount %d, received %d", k+1, count, event.ClickCount)
+		}
+		if input.ButtonEvent.Left != event.Button {
+			t.Errorf("Event %d: expected left button, received %s", k+1, event.Button)
+		}
+	}
+	if input.MouseEvent.MousePressed != events[1].Type || 1 != events[1].Buttons {
+		t.Errorf("Expected mousePressed with buttons=1, received %s with buttons=%d", events[1].Type, events[1].Buttons)
+	}
+	if input.MouseEvent.MouseReleased != events[2].Type || 0 != events[2].Buttons {
+		t.Errorf("Expected mouseReleased with buttons=0, received %s with buttons=%d", events[2].Type, events[2].Buttons)
+	}
+	if tab.Mouse().Pressed(input.ButtonEvent.Left) {
+		t.Errorf("Expected left button to be released")
+	}
+}
+
+func TestMouseModifiers(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMouseModifiers")
+
+	tab.Keyboard().Down("Shift")
+	tab.Mouse().Click(1, 1, input.ButtonEvent.Right, 1)
+	tab.Keyboard().Up("Shift")
+
+	for k, event := range mouseEvents(mockSocket) {
+		if ModifierShift != event.Modifiers {
+			t.Errorf("Event %d: expected modifiers %d, received %d", k, ModifierShift, event.Modifiers)
+		}
+	}
+}
+
+func TestMouseWheel(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMouseWheel")
+
+	tab.Mouse().Move(5, 5, 1)
+	if err := tab.Mouse().Wheel(0, 120); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := mouseEvents(mockSocket)
+	if input.MouseEvent.MouseWheel != events[1].Type || 120 != events[1].DeltaY || 5 != events[1].X {
+		t.Errorf("Expected mouseWheel at 5,5 with deltaY 120, received %+v", events[1])
+	}
+}
+
+func TestMouseDragAndDrop(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestMouseDragAndDrop")
+	mockSocket.Respond("DOM.getBoxModel", func(params interface{}) (interface{}, *socket.Error) {
+		nodeID := params.(*dom.GetBoxModelParams).NodeID
+		if 3 == nodeID {
+			return nil, &socket.Error{Code: 1, Message: "Could not compute box model."}
+		}
+		return &dom.GetBoxModelResult{Model: &dom.BoxModel{
+			Border: dom.Quad{float64(nodeID) * 100, 10},
+			Width:  20,
+			Height: 10,
+		}}, nil
+	})
+
+	if err := tab.Mouse().DragAndDrop(1, 2, 2); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := mouseEvents(mockSocket)
+	if 5 != len(events) {
+		t.Fatalf("Expected 5 mouse events, received %d", len(events))
+	}
+	if 110 != events[0].X || 15 != events[0].Y {
+		t.Errorf("Expected drag to start at 110,15, received %d,%d", events[0].X, events[0].Y)
+	}
+	if input.ButtonEvent.Left != events[2].Button || 1 != events[2].Buttons {
+		t.Errorf("Expected move with the left button held, received %+v", events[2])
+	}
+	if 210 != events[4].X || 15 != events[4].Y || input.MouseEvent.MouseReleased != events[4].Type {
+		t.Errorf("Expected drop at 210,15, received %+v", events[4])
+	}
+
+	if err := tab.Mouse().DragAndDrop(1, 3, 2); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
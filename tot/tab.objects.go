@@ -0,0 +1,298 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	goruntime "runtime"
+	"sync"
+	"sync/atomic"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/runtime"
+)
+
+/*
+scopeCount is used to generate unique object group names.
+*/
+var scopeCount int64
+
+/*
+ObjectRegistry tracks the remote objects referenced by a tab's handles and
+invalidates them when their execution context is destroyed.
+*/
+type ObjectRegistry struct {
+	contexts  map[runtime.ExecutionContextID]map[*handleState]bool
+	listening bool
+	mux       sync.Mutex
+	tab       *Tab
+}
+
+/*
+Objects returns the remote object registry for this tab.
+*/
+func (tab *Tab) Objects() *ObjectRegistry {
+	tab.objectsMux.Lock()
+	defer tab.objectsMux.Unlock()
+	if nil == tab.objects {
+		tab.objects = &ObjectRegistry{
+			contexts: map[runtime.ExecutionContextID]map[*handleState]bool{},
+			tab:      tab,
+		}
+	}
+	return tab.objects
+}
+
+/*
+Len returns the number of live remote objects referenced by handles.
+*/
+func (registry *ObjectRegistry) Len() int {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	count := 0
+	for _, states := range registry.contexts {
+		count += len(states)
+	}
+	return count
+}
+
+/*
+invalidate marks the handles in an execution context as invalid. The zero
+context ID invalidates every handle.
+*/
+func (registry *ObjectRegistry) invalidate(contextID runtime.ExecutionContextID) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	for id, states := range registry.contexts {
+		if 0 != contextID && id != contextID && 0 != id {
+			continue
+		}
+		for state := range states {
+			state.mux.Lock()
+			state.invalid = true
+			state.mux.Unlock()
+		}
+		delete(registry.contexts, id)
+	}
+}
+
+/*
+listen subscribes to the execution context events that invalidate handles and
+enables the Runtime domain so that they are delivered. Handles to objects whose
+execution context can't be determined are invalidated whenever any context is
+destroyed.
+*/
+func (registry *ObjectRegistry) listen() {
+	registry.tab.Runtime().OnExecutionContextDestroyed(func(event *runtime.ExecutionContextDestroyedEvent) {
+		if nil == event.Err {
+			registry.invalidate(event.ExecutionContextID)
+		}
+	})
+	registry.tab.Runtime().OnExecutionContextsCleared(func(event *runtime.ExecutionContextsClearedEvent) {
+		if nil == event.Err {
+			registry.invalidate(0)
+		}
+	})
+	if result := <-registry.tab.Runtime().Enable(); nil != result.Err {
+		log.Warnf("could not enable the Runtime domain, handles will not be invalidated: %s", result.Err)
+	}
+}
+
+/*
+release removes released handles from the registry.
+*/
+func (registry *ObjectRegistry) release(states ...*handleState) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	for _, state := range states {
+		state.mux.Lock()
+		state.released = true
+		state.mux.Unlock()
+		delete(registry.contexts[state.contextID], state)
+		if 0 == len(registry.contexts[state.contextID]) {
+			delete(registry.contexts, state.contextID)
+		}
+	}
+}
+
+/*
+track adds a handle to the registry.
+*/
+func (registry *ObjectRegistry) track(state *handleState) {
+	registry.mux.Lock()
+	listening := registry.listening
+	registry.listening = true
+	if _, ok := registry.contexts[state.contextID]; !ok {
+		registry.contexts[state.contextID] = map[*handleState]bool{}
+	}
+	registry.contexts[state.contextID][state] = true
+	registry.mux.Unlock()
+
+	if !listening {
+		registry.listen()
+	}
+}
+
+/*
+ObjectScope owns the remote objects created through it. Every object belongs
+to the scope's object group and is released together with the others when the
+scope is closed.
+
+	scope := tab.NewObjectScope()
+	defer scope.Close(ctx)
+	body, err := scope.EvalHandle(ctx, "document.body")
+*/
+type ObjectScope struct {
+	closed bool
+	group  string
+	mux    sync.Mutex
+	states []*handleState
+	tab    *Tab
+}
+
+/*
+NewObjectScope returns a new object scope with a unique object group.
+*/
+func (tab *Tab) NewObjectScope() *ObjectScope {
+	scope := &ObjectScope{
+		group: fmt.Sprintf("go-chrome-scope-%d", atomic.AddInt64(&scopeCount, 1)),
+		tab:   tab,
+	}
+	if DebugObjects {
+		goruntime.SetFinalizer(scope, func(scope *ObjectScope) {
+			scope.mux.Lock()
+			defer scope.mux.Unlock()
+			if !scope.closed && 0 != len(scope.states) {
+				log.Warnf("object scope %s was garbage collected without being closed, %d remote objects leaked", scope.group, len(scope.states))
+			}
+		})
+	}
+	return scope
+}
+
+/*
+Call calls a JavaScript function declaration with the specified arguments and
+returns a handle to the result. See Tab.Call.
+*/
+func (scope *ObjectScope) Call(ctx context.Context, fn string, args ...interface{}) (*JSHandle, error) {
+	global, err := scope.EvalHandle(ctx, "globalThis")
+	if nil != err {
+		return nil, err
+	}
+	arguments, err := newCallArguments(args)
+	if nil != err {
+		return nil, err
+	}
+	value, err := scope.tab.callFunctionOn(ctx, &runtime.CallFunctionOnParams{
+		FunctionDeclaration: fn,
+		ObjectID:            global.ObjectID(),
+		Arguments:           arguments,
+		AwaitPromise:        true,
+		ObjectGroup:         scope.group,
+	})
+	if nil != err {
+		return nil, err
+	}
+	return scope.handle(value, global.state.contextID)
+}
+
+/*
+Close releases every remote object in the scope. Handles created by the scope
+are invalid afterwards, even if the objects can't be released before ctx is
+done. Closing a closed scope is a no-op.
+*/
+func (scope *ObjectScope) Close(ctx context.Context) error {
+	scope.mux.Lock()
+	if scope.closed {
+		scope.mux.Unlock()
+		return nil
+	}
+	scope.closed = true
+	states := scope.states
+	scope.states = nil
+	scope.mux.Unlock()
+
+	scope.tab.Objects().release(states...)
+	result := <-scope.tab.withContext(ctx).Runtime().ReleaseObjectGroup(&runtime.ReleaseObjectGroupParams{
+		ObjectGroup: scope.group,
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.RuntimeEvaluateFailed, fmt.Sprintf("could not release object group '%s'", scope.group))
+	}
+	return nil
+}
+
+/*
+ElementHandle returns a handle to the JavaScript object for a DOM node.
+*/
+func (scope *ObjectScope) ElementHandle(ctx context.Context, nodeID dom.NodeID) (*JSHandle, error) {
+	value, err := scope.tab.resolveNode(ctx, nodeID, scope.group)
+	if nil != err {
+		return nil, err
+	}
+	return scope.handle(value, 0)
+}
+
+/*
+EvalHandle evaluates a JavaScript expression and returns a handle to the
+result. See Tab.EvalHandle.
+*/
+func (scope *ObjectScope) EvalHandle(ctx context.Context, expression string) (*JSHandle, error) {
+	if err := scope.check(); nil != err {
+		return nil, err
+	}
+	value, err := scope.tab.evaluate(ctx, &runtime.EvaluateParams{
+		Expression:   expression,
+		ObjectGroup:  scope.group,
+		AwaitPromise: true,
+	})
+	if nil != err {
+		return nil, err
+	}
+	return scope.handle(value, 0)
+}
+
+/*
+ObjectGroup returns the name of the scope's object group.
+*/
+func (scope *ObjectScope) ObjectGroup() string {
+	return scope.group
+}
+
+/*
+check returns an error if the scope has been closed.
+*/
+func (scope *ObjectScope) check() error {
+	scope.mux.Lock()
+	defer scope.mux.Unlock()
+	if scope.closed {
+		return errs.New(codes.RuntimeObjectInvalid, fmt.Sprintf("object scope %s is closed", scope.group))
+	}
+	return nil
+}
+
+/*
+handle returns a handle for a remote value created in the scope in an execution
+context, or in an unknown context if contextID is 0. If the scope was closed
+while the value was being created the object is released immediately.
+*/
+func (scope *ObjectScope) handle(value *runtime.RemoteObject, contextID runtime.ExecutionContextID) (*JSHandle, error) {
+	handle := newJSHandle(scope.tab, value, scope.group, contextID)
+	if "" == value.ObjectID {
+		return handle, nil
+	}
+	scope.mux.Lock()
+	closed := scope.closed
+	if !closed {
+		scope.states = append(scope.states, handle.state)
+	}
+	scope.mux.Unlock()
+
+	if closed {
+		handle.Release(context.Background())
+		return nil, errs.New(codes.RuntimeObjectInvalid, fmt.Sprintf("object scope %s is closed", scope.group))
+	}
+	return handle, nil
+}
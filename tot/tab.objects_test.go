@@ -0,0 +1,99 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestObjectScope(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestObjectScope")
+	count := 0
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		count++
+		return json.RawMessage(fmt.Sprintf(`{"result":{"type":"object","objectId":"123.4.%d"}}`, count)), nil
+	})
+
+	scope := tab.NewObjectScope()
+	first, err := scope.EvalHandle(context.Background(), "document.body")
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	second, _ := scope.EvalHandle(context.Background(), "document.head")
+	if 2 != tab.Objects().Len() {
+		t.Errorf("Expected 2 tracked objects, received %d", tab.Objects().Len())
+	}
+
+	params := mockSocket.Commands("Runtime.evaluate")[0].Params().(*runtime.EvaluateParams)
+	if scope.ObjectGroup() != params.ObjectGroup || scope.ObjectGroup() != first.ObjectGroup() {
+		t.Errorf("Expected object group %s, received %s", scope.ObjectGroup(), params.ObjectGroup)
+	}
+
+	if err := scope.Close(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if first.Valid() || second.Valid() {
+		t.Errorf("Expected handles to be invalid after the scope is closed")
+	}
+	if 0 != tab.Objects().Len() {
+		t.Errorf("Expected no tracked objects, received %d", tab.Objects().Len())
+	}
+	releases := mockSocket.Commands("Runtime.releaseObjectGroup")
+	if 1 != len(releases) || scope.ObjectGroup() != releases[0].Params().(*runtime.ReleaseObjectGroupParams).ObjectGroup {
+		t.Errorf("Expected the object group to be released once")
+	}
+
+	if _, err := first.Call(context.Background(), "function () {}"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if _, err := scope.EvalHandle(context.Background(), "window"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if err := scope.Close(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+}
+
+func TestObjectContextDestroyed(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestObjectContextDestroyed")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		expression := params.(*runtime.EvaluateParams).Expression
+		return json.RawMessage(fmt.Sprintf(`{"result":{"type":"object","objectId":%q}}`, expression)), nil
+	})
+
+	main := newJSHandle(tab, &runtime.RemoteObject{Type: runtime.ObjectType.Object, ObjectID: "main"}, "", 1)
+	frame := newJSHandle(tab, &runtime.RemoteObject{Type: runtime.ObjectType.Object, ObjectID: "frame"}, "", 2)
+	unknown, _ := tab.EvalHandle(context.Background(), "unknown")
+
+	mockSocket.Fire("Runtime.executionContextDestroyed", &runtime.ExecutionContextDestroyedEvent{
+		ExecutionContextID: 2,
+	})
+	if !main.Valid() {
+		t.Errorf("Expected the main context handle to be valid")
+	}
+	if frame.Valid() {
+		t.Errorf("Expected the frame context handle to be invalid")
+	}
+	if unknown.Valid() {
+		t.Errorf("Expected the handle of an unknown context to be invalid")
+	}
+	if _, err := tab.Call(context.Background(), "(el) => el", frame); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+
+	mockSocket.Fire("Runtime.executionContextsCleared", &runtime.ExecutionContextsClearedEvent{})
+	if main.Valid() {
+		t.Errorf("Expected the main context handle to be invalid")
+	}
+	releases := len(mockSocket.Commands("Runtime.releaseObject"))
+	if err := main.Release(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if releases != len(mockSocket.Commands("Runtime.releaseObject")) {
+		t.Errorf("Expected invalid handles not to be released")
+	}
+}
@@ -0,0 +1,279 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	cdtpio "github.com/mkenney/go-chrome/tot/io"
+	"github.com/mkenney/go-chrome/tot/page"
+)
+
+/*
+Length is a print length in inches, the unit used by Page.printToPDF.
+*/
+type Length float64
+
+/*
+Length units.
+*/
+const (
+	Inch       Length = 1
+	Centimeter Length = Inch / 2.54
+	Millimeter Length = Centimeter / 10
+	Point      Length = Inch / 72
+	Pixel      Length = Inch / 96
+)
+
+/*
+lengthUnits maps unit suffixes accepted by ParseLength to their lengths.
+*/
+var lengthUnits = map[string]Length{
+	"in": Inch,
+	"cm": Centimeter,
+	"mm": Millimeter,
+	"pt": Point,
+	"px": Pixel,
+}
+
+/*
+ParseLength parses a length with an optional unit suffix, e.g. "210mm",
+"8.5in", "1cm", "12pt" or "96px". Lengths without a unit are CSS pixels.
+*/
+func ParseLength(value string) (Length, error) {
+	number := strings.ToLower(strings.TrimSpace(value))
+	unit := Pixel
+	for suffix, length := range lengthUnits {
+		if strings.HasSuffix(number, suffix) {
+			number, unit = strings.TrimSpace(strings.TrimSuffix(number, suffix)), length
+			break
+		}
+	}
+	parsed, err := strconv.ParseFloat(number, 64)
+	if nil != err || parsed < 0 {
+		return 0, errs.New(codes.CaptureOptionsInvalid, fmt.Sprintf("invalid length '%s'", value))
+	}
+	return Length(parsed) * unit, nil
+}
+
+/*
+Inches returns the length in inches.
+*/
+func (length Length) Inches() float64 {
+	return float64(length)
+}
+
+/*
+PaperSize is the size of a sheet of paper in portrait orientation.
+*/
+type PaperSize struct {
+	Width  Length
+	Height Length
+}
+
+/*
+PaperSizes are the named paper sizes accepted by PDFOptions.Paper. Names are
+matched case-insensitively. Sizes are in portrait orientation, so Ledger is the
+same sheet as Tabloid; set PDFOptions.Landscape to print it 17in wide.
+*/
+var PaperSizes = map[string]PaperSize{
+	"Letter":  {8.5 * Inch, 11 * Inch},
+	"Legal":   {8.5 * Inch, 14 * Inch},
+	"Tabloid": {11 * Inch, 17 * Inch},
+	"Ledger":  {11 * Inch, 17 * Inch},
+	"A0":      {841 * Millimeter, 1189 * Millimeter},
+	"A1":      {594 * Millimeter, 841 * Millimeter},
+	"A2":      {420 * Millimeter, 594 * Millimeter},
+	"A3":      {297 * Millimeter, 420 * Millimeter},
+	"A4":      {210 * Millimeter, 297 * Millimeter},
+	"A5":      {148 * Millimeter, 210 * Millimeter},
+	"A6":      {105 * Millimeter, 148 * Millimeter},
+}
+
+/*
+PDFMargin defines the page margins. Values are lengths accepted by
+ParseLength; empty values use the browser default of 1cm.
+*/
+type PDFMargin struct {
+	Top    string
+	Right  string
+	Bottom string
+	Left   string
+}
+
+/*
+PDFOptions configures a PDF rendering. The zero value prints the page on
+Letter paper with the browser's default margins.
+*/
+type PDFOptions struct {
+	// Optional. Paper orientation.
+	Landscape bool
+
+	// Optional. Print background graphics.
+	PrintBackground bool
+
+	// Optional. Scale of the webpage rendering, defaults to 1.
+	Scale float64
+
+	// Optional. Named paper size from PaperSizes, e.g. "A4".
+	Paper string
+
+	// Optional. Paper width and height, override the named paper size.
+	Width  string
+	Height string
+
+	// Optional. Page margins.
+	Margin PDFMargin
+
+	// Optional. Pages to print, e.g. "1-5, 8, 11-13".
+	PageRanges string
+
+	// Optional. HTML templates for the print header and footer. Elements
+	// with the classes date, title, url, pageNumber and totalPages receive
+	// the corresponding print values. Setting either template displays the
+	// header and footer.
+	HeaderTemplate string
+	FooterTemplate string
+
+	// Optional. Use the page size defined by CSS @page rules instead of
+	// scaling the content to the paper size.
+	PreferCSSPageSize bool
+}
+
+/*
+PDF renders the page as a PDF document and streams it to w. Web fonts are
+loaded before printing and the document is transferred in chunks rather than
+in a single response, so large documents are never held in memory.
+
+	file, _ := os.Create("report.pdf")
+	err := tab.PDF(ctx, file, &chrome.PDFOptions{
+		Paper:          "A4",
+		Margin:         chrome.PDFMargin{Top: "2cm", Bottom: "2cm"},
+		FooterTemplate: `<div style="font-size:8px"><span class=pageNumber></span></div>`,
+	})
+*/
+func (tab *Tab) PDF(ctx context.Context, w io.Writer, opts *PDFOptions) error {
+	if nil == opts {
+		opts = &PDFOptions{}
+	}
+	params, err := opts.params()
+	if nil != err {
+		return err
+	}
+
+	ready := false
+	if err := tab.Eval(ctx, "document.fonts.ready.then(() => true)", &ready); nil != err {
+		return errs.Wrap(err, codes.CaptureFailed, "could not wait for fonts to load")
+	}
+
+	result := <-tab.withContext(ctx).Page().PrintToPDF(params)
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.CaptureFailed, "could not print to PDF")
+	}
+	if "" == result.Stream {
+		data, err := base64.StdEncoding.DecodeString(result.Data)
+		if nil != err {
+			return errs.Wrap(err, codes.CaptureDecodeFailed, "could not decode PDF data")
+		}
+		if _, err := w.Write(data); nil != err {
+			return errs.Wrap(err, codes.CaptureEncodeFailed, "could not write PDF data")
+		}
+		return nil
+	}
+	return tab.readStream(ctx, result.Stream, w)
+}
+
+/*
+readStream copies a protocol stream into w and closes the stream.
+*/
+func (tab *Tab) readStream(ctx context.Context, handle cdtpio.StreamHandle, w io.Writer) error {
+	stream := tab.OpenStream(ctx, handle)
+	defer stream.Close()
+	if _, err := io.Copy(w, stream); nil != err {
+		return errs.Wrap(err, codes.CaptureFailed, "could not copy PDF data")
+	}
+	return nil
+}
+
+/*
+params converts the options to Page.printToPDF parameters.
+*/
+func (opts *PDFOptions) params() (*page.PrintToPDFParams, error) {
+	params := &page.PrintToPDFParams{
+		Landscape:           opts.Landscape,
+		DisplayHeaderFooter: "" != opts.HeaderTemplate || "" != opts.FooterTemplate,
+		PrintBackground:     opts.PrintBackground,
+		Scale:               opts.Scale,
+		PageRanges:          opts.PageRanges,
+		HeaderTemplate:      opts.HeaderTemplate,
+		FooterTemplate:      opts.FooterTemplate,
+		PreferCSSPageSize:   opts.PreferCSSPageSize,
+		TransferMode:        page.TransferMode.ReturnAsStream,
+	}
+	if params.DisplayHeaderFooter {
+		// Chrome prints its own default for a missing template.
+		if "" == params.HeaderTemplate {
+			params.HeaderTemplate = "<span></span>"
+		}
+		if "" == params.FooterTemplate {
+			params.FooterTemplate = "<span></span>"
+		}
+	}
+
+	if "" != opts.Paper {
+		found := false
+		for name, size := range PaperSizes {
+			if strings.EqualFold(name, opts.Paper) {
+				params.PaperWidth, params.PaperHeight = size.Width.Inches(), size.Height.Inches()
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errs.New(codes.CaptureOptionsInvalid, fmt.Sprintf("unknown paper size '%s'", opts.Paper))
+		}
+	}
+
+	for _, size := range []struct {
+		value  string
+		inches *float64
+	}{
+		{opts.Width, &params.PaperWidth},
+		{opts.Height, &params.PaperHeight},
+	} {
+		if "" == size.value {
+			continue
+		}
+		length, err := ParseLength(size.value)
+		if nil != err {
+			return nil, err
+		}
+		*size.inches = length.Inches()
+	}
+
+	for _, margin := range []struct {
+		value  string
+		inches **float64
+	}{
+		{opts.Margin.Top, &params.MarginTop},
+		{opts.Margin.Right, &params.MarginRight},
+		{opts.Margin.Bottom, &params.MarginBottom},
+		{opts.Margin.Left, &params.MarginLeft},
+	} {
+		if "" == margin.value {
+			continue
+		}
+		length, err := ParseLength(margin.value)
+		if nil != err {
+			return nil, err
+		}
+		inches := length.Inches()
+		*margin.inches = &inches
+	}
+	return params, nil
+}
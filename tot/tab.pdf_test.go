@@ -0,0 +1,125 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	cdtpio "github.com/mkenney/go-chrome/tot/io"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestTabPDF(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabPDF")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"boolean","value":true}}`), nil
+	})
+	mockSocket.Respond("Page.printToPDF", func(params interface{}) (interface{}, *socket.Error) {
+		return &page.PrintToPDFResult{Stream: "pdf-stream"}, nil
+	})
+	mockSocket.RespondStream("%PDF-1.4\n", "body\n", "%%EOF")
+
+	buf := &bytes.Buffer{}
+	err := tab.PDF(context.Background(), buf, &PDFOptions{
+		Paper:          "a4",
+		Margin:         PDFMargin{Top: "1in", Bottom: "0", Left: "25.4mm"},
+		FooterTemplate: "<span class=pageNumber></span>",
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "%PDF-1.4\nbody\n%%EOF" != buf.String() {
+		t.Errorf("Unexpected PDF data %q", buf.String())
+	}
+
+	if 1 != len(mockSocket.Commands("Runtime.evaluate")) {
+		t.Errorf("Expected to wait for fonts to load")
+	}
+	params := mockSocket.Commands("Page.printToPDF")[0].Params().(*page.PrintToPDFParams)
+	if "ReturnAsStream" != params.TransferMode.String() {
+		t.Errorf("Expected ReturnAsStream, received %s", params.TransferMode)
+	}
+	if math.Abs(8.2677-params.PaperWidth) > 0.0001 || math.Abs(11.6929-params.PaperHeight) > 0.0001 {
+		t.Errorf("Expected A4 paper, received %vx%v", params.PaperWidth, params.PaperHeight)
+	}
+	if 1 != *params.MarginTop || 0 != *params.MarginBottom || math.Abs(1-*params.MarginLeft) > 0.0001 || nil != params.MarginRight {
+		t.Errorf("Unexpected margins %v %v %v %v", params.MarginTop, params.MarginRight, params.MarginBottom, params.MarginLeft)
+	}
+	if !params.DisplayHeaderFooter || "" == params.HeaderTemplate {
+		t.Errorf("Expected the header and footer to be displayed with an empty header")
+	}
+
+	closes := mockSocket.Commands("IO.close")
+	if 1 != len(closes) || "pdf-stream" != closes[0].Params().(*cdtpio.CloseParams).Handle {
+		t.Errorf("Expected the stream to be closed")
+	}
+}
+
+func TestTabPDFWriteError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabPDFWriteError")
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"boolean","value":true}}`), nil
+	})
+	mockSocket.Respond("Page.printToPDF", func(params interface{}) (interface{}, *socket.Error) {
+		return &page.PrintToPDFResult{Stream: "pdf-stream"}, nil
+	})
+	mockSocket.RespondStream("%PDF-1.4\n", "%%EOF")
+
+	if err := tab.PDF(context.Background(), failingWriter{}, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 1 != len(mockSocket.Commands("IO.read")) {
+		t.Errorf("Expected reading to stop after the write error")
+	}
+	if 1 != len(mockSocket.Commands("IO.close")) {
+		t.Errorf("Expected the stream to be closed")
+	}
+}
+
+func TestTabPDFInvalidOptions(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabPDFInvalidOptions")
+
+	for _, opts := range []*PDFOptions{
+		{Paper: "B52"},
+		{Width: "wide"},
+		{Margin: PDFMargin{Left: "-1cm"}},
+	} {
+		if err := tab.PDF(context.Background(), &bytes.Buffer{}, opts); nil == err {
+			t.Errorf("%+v: expected error, received nil", opts)
+		}
+	}
+	if 0 != len(mockSocket.Commands("Page.printToPDF")) {
+		t.Errorf("Expected invalid options not to be printed")
+	}
+}
+
+func TestParseLength(t *testing.T) {
+	tests := map[string]Length{
+		"8.5in":  8.5,
+		"2.54cm": 1,
+		"254mm":  10,
+		"72pt":   1,
+		"96px":   1,
+		"192":    2,
+		" 1 IN ": 1,
+	}
+	for value, expected := range tests {
+		length, err := ParseLength(value)
+		if nil != err {
+			t.Errorf("%s: expected nil, received error: %v", value, err)
+		}
+		if math.Abs(float64(expected-length)) > 0.0001 {
+			t.Errorf("%s: expected %v, received %v", value, expected, length)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package chrome
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+pngSignature is the 8 byte PNG file signature.
+*/
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+/*
+WriteGIF encodes the recording as an animated GIF. Frames are dithered to the
+web-safe palette; use WriteAPNG for lossless output.
+*/
+func (recorder *Recorder) WriteGIF(w io.Writer) error {
+	frames := recorder.Frames()
+	if 0 == len(frames) {
+		return errs.New(codes.CaptureEncodeFailed, "the recording has no frames")
+	}
+	images, err := decodeFrames(frames)
+	if nil != err {
+		return err
+	}
+
+	animation := &gif.GIF{}
+	for k, img := range images {
+		paletted := image.NewPaletted(img.Bounds(), palette.WebSafe)
+		draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, image.Point{})
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, frameDelay(frames[k].Duration, 100))
+	}
+	if err := gif.EncodeAll(w, animation); nil != err {
+		return errs.Wrap(err, codes.CaptureEncodeFailed, "could not encode GIF")
+	}
+	return nil
+}
+
+/*
+WriteAPNG encodes the recording as an animated PNG.
+*/
+func (recorder *Recorder) WriteAPNG(w io.Writer) error {
+	frames := recorder.Frames()
+	if 0 == len(frames) {
+		return errs.New(codes.CaptureEncodeFailed, "the recording has no frames")
+	}
+	images, err := decodeFrames(frames)
+	if nil != err {
+		return err
+	}
+
+	encoder := &apngEncoder{w: w}
+	for k, img := range images {
+		frame := image.NewNRGBA(img.Bounds())
+		draw.Draw(frame, frame.Bounds(), img, img.Bounds().Min, draw.Src)
+		if err := encoder.writeFrame(frame, len(images), frames[k].Duration); nil != err {
+			return errs.Wrap(err, codes.CaptureEncodeFailed, "could not write APNG frame")
+		}
+	}
+	if err := encoder.writeChunk("IEND", nil); nil != err {
+		return errs.Wrap(err, codes.CaptureEncodeFailed, "could not write APNG")
+	}
+	return nil
+}
+
+/*
+apngEncoder assembles an animated PNG from image frames. Every frame is written
+as 8-bit RGBA so that it matches the header of the animation. The first frame
+is the default image; the image data of later frames is written as fdAT chunks.
+*/
+type apngEncoder struct {
+	frames   int
+	sequence uint32
+	w        io.Writer
+}
+
+/*
+writeChunk writes a PNG chunk with its length and CRC.
+*/
+func (encoder *apngEncoder) writeChunk(name string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, crc.Sum32())
+
+	for _, part := range [][]byte{header, data, sum} {
+		if _, err := encoder.w.Write(part); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+writeFrame writes a frame control chunk followed by the image data of a frame.
+*/
+func (encoder *apngEncoder) writeFrame(img *image.NRGBA, count int, duration time.Duration) error {
+	width, height := uint32(img.Bounds().Dx()), uint32(img.Bounds().Dy())
+	if 0 == encoder.frames {
+		if _, err := encoder.w.Write(pngSignature); nil != err {
+			return err
+		}
+		ihdr := make([]byte, 13)
+		binary.BigEndian.PutUint32(ihdr[0:4], width)
+		binary.BigEndian.PutUint32(ihdr[4:8], height)
+		ihdr[8] = 8 // bit depth
+		ihdr[9] = 6 // truecolor with alpha
+		if err := encoder.writeChunk("IHDR", ihdr); nil != err {
+			return err
+		}
+		actl := make([]byte, 8)
+		binary.BigEndian.PutUint32(actl[0:4], uint32(count))
+		if err := encoder.writeChunk("acTL", actl); nil != err {
+			return err
+		}
+	}
+
+	fctl := make([]byte, 26)
+	binary.BigEndian.PutUint32(fctl[0:4], encoder.next())
+	binary.BigEndian.PutUint32(fctl[4:8], width)
+	binary.BigEndian.PutUint32(fctl[8:12], height)
+	binary.BigEndian.PutUint16(fctl[20:22], uint16(frameDelay(duration, 1000)))
+	binary.BigEndian.PutUint16(fctl[22:24], 1000)
+	if err := encoder.writeChunk("fcTL", fctl); nil != err {
+		return err
+	}
+
+	data, err := frameData(img)
+	if nil != err {
+		return err
+	}
+	if 0 == encoder.frames {
+		err = encoder.writeChunk("IDAT", data)
+	} else {
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, encoder.next())
+		err = encoder.writeChunk("fdAT", append(fdat, data...))
+	}
+	if nil != err {
+		return err
+	}
+	encoder.frames++
+	return nil
+}
+
+/*
+next returns the next animation chunk sequence number.
+*/
+func (encoder *apngEncoder) next() uint32 {
+	sequence := encoder.sequence
+	encoder.sequence++
+	return sequence
+}
+
+/*
+frameData returns the compressed image data of a frame. Scanlines are not
+filtered.
+*/
+func frameData(img *image.NRGBA) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zlib.NewWriter(buf)
+	rowSize := 4 * img.Bounds().Dx()
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		if _, err := zw.Write([]byte{0}); nil != err {
+			return nil, err
+		}
+		if _, err := zw.Write(img.Pix[y*img.Stride : y*img.Stride+rowSize]); nil != err {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); nil != err {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/*
+frameDelay converts a frame duration into a delay in 1/denominator seconds,
+with a minimum of one unit.
+*/
+func frameDelay(duration time.Duration, denominator int) int {
+	delay := int(duration * time.Duration(denominator) / time.Second)
+	if delay < 1 {
+		delay = 1
+	}
+	if delay > 65535 {
+		delay = 65535
+	}
+	return delay
+}
@@ -0,0 +1,286 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+ScreencastOptions configures a screencast recording. The zero value records
+every frame as a JPEG image at the browser's default quality and size.
+*/
+type ScreencastOptions struct {
+	// Optional. Frame image format, defaults to page.Format.Jpeg.
+	Format page.FormatEnum
+
+	// Optional. Compression quality in the range [0..100], jpeg only.
+	Quality int
+
+	// Optional. Maximum frame width and height in pixels.
+	MaxWidth  int
+	MaxHeight int
+
+	// Optional. Record every n-th frame.
+	EveryNthFrame int
+}
+
+/*
+ScreencastFrame is a single recorded frame.
+*/
+type ScreencastFrame struct {
+	// The encoded frame image.
+	Data []byte
+
+	// The frame image format, "jpeg" or "png".
+	Format string
+
+	// The time the frame was presented.
+	Timestamp time.Time
+
+	// How long the frame was displayed before the next frame replaced it.
+	Duration time.Duration
+
+	// The frame metadata reported by the browser.
+	Metadata *page.ScreencastFrameMetadata
+}
+
+/*
+Recorder records a tab's screencast. Every frame is acknowledged as it arrives
+so that the browser keeps sending frames; consecutive identical frames are
+merged into a single frame with a longer duration.
+*/
+type Recorder struct {
+	format  string
+	frames  []*ScreencastFrame
+	handler socket.EventHandler
+	mux     sync.Mutex
+	stopped time.Time
+	tab     *Tab
+}
+
+/*
+StartRecording starts a screencast and records its frames until Stop is called.
+
+	recorder, err := tab.StartRecording(ctx, nil)
+	...
+	recorder.Stop(ctx)
+	recorder.WriteGIF(file)
+*/
+func (tab *Tab) StartRecording(ctx context.Context, opts *ScreencastOptions) (*Recorder, error) {
+	if nil == opts {
+		opts = &ScreencastOptions{}
+	}
+	format := opts.Format
+	if "" == format.String() {
+		format = page.Format.Jpeg
+	}
+
+	recorder := &Recorder{
+		format: format.String(),
+		frames: []*ScreencastFrame{},
+		tab:    tab,
+	}
+	recorder.handler = socket.NewEventHandler("Page.screencastFrame", func(response *socket.Response) {
+		event := &page.ScreencastFrameEvent{}
+		if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+			log.Errorf("invalid screencast frame: %s", err)
+			return
+		}
+		recorder.addFrame(event, time.Now())
+	})
+	tab.AddEventHandler(recorder.handler)
+
+	result := <-tab.withContext(ctx).Page().StartScreencast(&page.StartScreencastParams{
+		Format:        format,
+		Quality:       opts.Quality,
+		MaxWidth:      opts.MaxWidth,
+		MaxHeight:     opts.MaxHeight,
+		EveryNthFrame: opts.EveryNthFrame,
+	})
+	if nil != result.Err {
+		tab.RemoveEventHandler(recorder.handler)
+		return nil, errs.Wrap(result.Err, codes.CaptureFailed, "could not start screencast")
+	}
+	return recorder, nil
+}
+
+/*
+Frames returns the recorded frames in presentation order, with identical
+consecutive frames merged.
+*/
+func (recorder *Recorder) Frames() []*ScreencastFrame {
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+
+	frames := append(recorder.frames[:0:0], recorder.frames...)
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Timestamp.Before(frames[j].Timestamp)
+	})
+
+	end := recorder.stopped
+	if end.IsZero() {
+		end = time.Now()
+	}
+	merged := []*ScreencastFrame{}
+	for k, frame := range frames {
+		next := end
+		if k+1 < len(frames) {
+			next = frames[k+1].Timestamp
+		}
+		if last := len(merged) - 1; last >= 0 && bytes.Equal(merged[last].Data, frame.Data) {
+			merged[last].Duration = next.Sub(merged[last].Timestamp)
+			continue
+		}
+		unique := *frame
+		unique.Duration = next.Sub(frame.Timestamp)
+		if unique.Duration < 0 {
+			unique.Duration = 0
+		}
+		merged = append(merged, &unique)
+	}
+	return merged
+}
+
+/*
+Stop stops the screencast. Frames received afterwards are discarded.
+*/
+func (recorder *Recorder) Stop(ctx context.Context) error {
+	recorder.mux.Lock()
+	if !recorder.stopped.IsZero() {
+		recorder.mux.Unlock()
+		return nil
+	}
+	recorder.stopped = time.Now()
+	recorder.mux.Unlock()
+
+	recorder.tab.RemoveEventHandler(recorder.handler)
+	if result := <-recorder.tab.withContext(ctx).Page().StopScreencast(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.CaptureFailed, "could not stop screencast")
+	}
+	return nil
+}
+
+/*
+WriteSequence writes every frame to dir as a numbered image file along with a
+manifest.json file describing the frame timing and metadata.
+*/
+func (recorder *Recorder) WriteSequence(dir string) error {
+	if err := os.MkdirAll(dir, 0755); nil != err {
+		return errs.Wrap(err, codes.CaptureEncodeFailed, fmt.Sprintf("could not create directory '%s'", dir))
+	}
+
+	type manifestFrame struct {
+		File      string                        `json:"file"`
+		Timestamp time.Time                     `json:"timestamp"`
+		Duration  int64                         `json:"durationMs"`
+		Metadata  *page.ScreencastFrameMetadata `json:"metadata,omitempty"`
+	}
+	manifest := struct {
+		Format string           `json:"format"`
+		Frames []*manifestFrame `json:"frames"`
+	}{
+		Format: recorder.format,
+		Frames: []*manifestFrame{},
+	}
+
+	for k, frame := range recorder.Frames() {
+		file := fmt.Sprintf("frame-%05d.%s", k+1, frame.Format)
+		if err := ioutil.WriteFile(filepath.Join(dir, file), frame.Data, 0644); nil != err {
+			return errs.Wrap(err, codes.CaptureEncodeFailed, fmt.Sprintf("could not write frame '%s'", file))
+		}
+		manifest.Frames = append(manifest.Frames, &manifestFrame{
+			File:      file,
+			Timestamp: frame.Timestamp,
+			Duration:  int64(frame.Duration / time.Millisecond),
+			Metadata:  frame.Metadata,
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if nil != err {
+		return errs.Wrap(err, codes.CaptureEncodeFailed, "could not encode manifest")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); nil != err {
+		return errs.Wrap(err, codes.CaptureEncodeFailed, "could not write manifest")
+	}
+	return nil
+}
+
+/*
+addFrame acknowledges a frame and adds it to the recording.
+*/
+func (recorder *Recorder) addFrame(event *page.ScreencastFrameEvent, received time.Time) {
+	recorder.mux.Lock()
+	stopped := !recorder.stopped.IsZero()
+	recorder.mux.Unlock()
+	if stopped {
+		return
+	}
+
+	// Acknowledge first, the browser doesn't send the next frame until the
+	// current one is acknowledged.
+	if result := <-recorder.tab.Page().ScreencastFrameAck(&page.ScreencastFrameAckParams{
+		SessionID: event.SessionID,
+	}); nil != result.Err {
+		log.Warnf("could not acknowledge screencast frame: %s", result.Err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(event.Data)
+	if nil != err {
+		log.Errorf("could not decode screencast frame: %s", err)
+		return
+	}
+	timestamp := received
+	if nil != event.Metadata && 0 != event.Metadata.Timestamp {
+		seconds := float64(event.Metadata.Timestamp)
+		timestamp = time.Unix(0, int64(seconds*float64(time.Second)))
+	}
+
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	recorder.frames = append(recorder.frames, &ScreencastFrame{
+		Data:      data,
+		Format:    recorder.format,
+		Timestamp: timestamp,
+		Metadata:  event.Metadata,
+	})
+}
+
+/*
+decodeFrames decodes frame images and draws them onto canvases the size of the
+first frame so that every animation frame has the same bounds.
+*/
+func decodeFrames(frames []*ScreencastFrame) ([]*image.RGBA, error) {
+	images := make([]*image.RGBA, 0, len(frames))
+	var bounds image.Rectangle
+	for k, frame := range frames {
+		img, _, err := image.Decode(bytes.NewReader(frame.Data))
+		if nil != err {
+			return nil, errs.Wrap(err, codes.CaptureDecodeFailed, fmt.Sprintf("could not decode frame %d", k))
+		}
+		if 0 == k {
+			bounds = image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+		canvas := image.NewRGBA(bounds)
+		draw.Draw(canvas, bounds, img, img.Bounds().Min, draw.Src)
+		images = append(images, canvas)
+	}
+	return images, nil
+}
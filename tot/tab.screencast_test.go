@@ -0,0 +1,260 @@
+package chrome
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/page"
+)
+
+/*
+pngChunk is a chunk of an encoded PNG.
+*/
+type pngChunk struct {
+	name string
+	data []byte
+}
+
+/*
+pngChunks splits an encoded PNG into its chunks.
+*/
+func pngChunks(data []byte) ([]*pngChunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("invalid PNG signature")
+	}
+	chunks := []*pngChunk{}
+	for offset := len(pngSignature); offset+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+		if offset+12+length > len(data) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		chunks = append(chunks, &pngChunk{
+			name: string(data[offset+4 : offset+8]),
+			data: data[offset+8 : offset+8+length],
+		})
+		offset += 12 + length
+	}
+	return chunks, nil
+}
+
+func newMockRecording(t *testing.T, url string) (*Recorder, *MockSocket) {
+	tab, mockSocket := newMockTab(t, url)
+	recorder, err := tab.StartRecording(context.Background(), &ScreencastOptions{Format: page.Format.Png})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	// 4x4 PNG images of each gray level.
+	images := map[uint8]string{}
+	for _, shade := range []uint8{0, 255} {
+		img := image.NewGray(image.Rect(0, 0, 4, 4))
+		for k := range img.Pix {
+			img.Pix[k] = shade
+		}
+		buf := &bytes.Buffer{}
+		png.Encode(buf, img)
+		images[shade] = base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	frames := []struct {
+		session   int
+		shade     uint8
+		timestamp float64
+	}{
+		{1, 0, 1000.0},
+		{3, 255, 1000.5},
+		{2, 0, 1000.25},
+		{4, 255, 1000.75},
+		{5, 0, 1001.0},
+	}
+	for k, frame := range frames {
+		// The last frame arrives after the recording is stopped.
+		if len(frames)-1 == k {
+			recorder.Stop(context.Background())
+		}
+		mockSocket.Fire("Page.screencastFrame", &page.ScreencastFrameEvent{
+			Data:      images[frame.shade],
+			Metadata:  &page.ScreencastFrameMetadata{DeviceWidth: 4, DeviceHeight: 4, Timestamp: page.TimeSinceEpoch(frame.timestamp)},
+			SessionID: frame.session,
+		})
+	}
+	recorder.stopped = time.Unix(1001, 0)
+	return recorder, mockSocket
+}
+
+func TestRecorderFrames(t *testing.T) {
+	recorder, mockSocket := newMockRecording(t, "https://TestRecorderFrames")
+
+	params := mockSocket.Commands("Page.startScreencast")[0].Params().(*page.StartScreencastParams)
+	if page.Format.Png != params.Format {
+		t.Errorf("Expected png, received %s", params.Format)
+	}
+	if 4 != len(mockSocket.Commands("Page.screencastFrameAck")) {
+		t.Errorf("Expected 4 acknowledged frames, received %d", len(mockSocket.Commands("Page.screencastFrameAck")))
+	}
+	if 1 != len(mockSocket.Commands("Page.stopScreencast")) {
+		t.Errorf("Expected the screencast to be stopped")
+	}
+
+	frames := recorder.Frames()
+	if 2 != len(frames) {
+		t.Fatalf("Expected 2 merged frames, received %d", len(frames))
+	}
+	expected := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	for k, frame := range frames {
+		if expected[k] != frame.Duration {
+			t.Errorf("Frame %d: expected %s, received %s", k, expected[k], frame.Duration)
+		}
+	}
+	if "png" != frames[0].Format || 4 != frames[0].Metadata.DeviceWidth {
+		t.Errorf("Expected png frame metadata, received %s %+v", frames[0].Format, frames[0].Metadata)
+	}
+}
+
+func TestRecorderWriteGIF(t *testing.T) {
+	recorder, _ := newMockRecording(t, "https://TestRecorderWriteGIF")
+
+	buf := &bytes.Buffer{}
+	if err := recorder.WriteGIF(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	animation, err := gif.DecodeAll(buf)
+	if nil != err {
+		t.Fatalf("Expected GIF data, received error: %v", err)
+	}
+	if 2 != len(animation.Image) {
+		t.Fatalf("Expected 2 frames, received %d", len(animation.Image))
+	}
+	if 50 != animation.Delay[0] || 50 != animation.Delay[1] {
+		t.Errorf("Expected 50/100s delays, received %v", animation.Delay)
+	}
+}
+
+func TestRecorderWriteAPNG(t *testing.T) {
+	recorder, _ := newMockRecording(t, "https://TestRecorderWriteAPNG")
+
+	buf := &bytes.Buffer{}
+	if err := recorder.WriteAPNG(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(buf.Bytes())); nil != err {
+		t.Errorf("Expected the default image to decode, received error: %v", err)
+	}
+	chunks, err := pngChunks(buf.Bytes())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	names := []string{}
+	for _, chunk := range chunks {
+		names = append(names, chunk.name)
+	}
+	expected := []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "IEND"}
+	if len(expected) != len(names) {
+		t.Fatalf("Expected chunks %v, received %v", expected, names)
+	}
+	for k := range expected {
+		if expected[k] != names[k] {
+			t.Errorf("Expected chunks %v, received %v", expected, names)
+			break
+		}
+	}
+}
+
+func TestRecorderWriteAPNGTransparency(t *testing.T) {
+	// An opaque and a translucent frame, which png.Encode writes with
+	// different color types.
+	opaque, translucent := &bytes.Buffer{}, &bytes.Buffer{}
+	png.Encode(opaque, image.NewGray(image.Rect(0, 0, 3, 2)))
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.NRGBA{R: 255, A: 128})
+	png.Encode(translucent, img)
+	recorder := &Recorder{
+		frames: []*ScreencastFrame{
+			{Data: opaque.Bytes(), Timestamp: time.Unix(1000, 0)},
+			{Data: translucent.Bytes(), Timestamp: time.Unix(1001, 0)},
+		},
+		stopped: time.Unix(1002, 0),
+	}
+
+	buf := &bytes.Buffer{}
+	if err := recorder.WriteAPNG(buf); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	chunks, err := pngChunks(buf.Bytes())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "IHDR" != chunks[0].name || 6 != chunks[0].data[9] {
+		t.Fatalf("Expected an RGBA header, received %v", chunks[0])
+	}
+	for _, chunk := range chunks {
+		data := chunk.data
+		if "fdAT" == chunk.name {
+			data = data[4:]
+		} else if "IDAT" != chunk.name {
+			continue
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if nil != err {
+			t.Fatalf("Expected nil, received error: %v", err)
+		}
+		pixels, _ := ioutil.ReadAll(zr)
+		if (1+4*3)*2 != len(pixels) {
+			t.Errorf("Expected %s to match the RGBA header, received %d bytes", chunk.name, len(pixels))
+		}
+	}
+}
+
+func TestRecorderWriteSequence(t *testing.T) {
+	recorder, _ := newMockRecording(t, "https://TestRecorderWriteSequence")
+	dir, err := ioutil.TempDir("", "TestRecorderWriteSequence")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := recorder.WriteSequence(dir); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "frame-00002.png")); nil != err {
+		t.Errorf("Expected frame-00002.png, received error: %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	if nil != err {
+		t.Fatalf("Expected manifest.json, received error: %v", err)
+	}
+	manifest := struct {
+		Format string `json:"format"`
+		Frames []struct {
+			File     string `json:"file"`
+			Duration int64  `json:"durationMs"`
+		} `json:"frames"`
+	}{}
+	if err := json.Unmarshal(data, &manifest); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "png" != manifest.Format || 2 != len(manifest.Frames) || 500 != manifest.Frames[1].Duration {
+		t.Errorf("Unexpected manifest %s", data)
+	}
+}
+
+func TestRecorderEmpty(t *testing.T) {
+	tab, _ := newMockTab(t, "https://TestRecorderEmpty")
+	recorder, _ := tab.StartRecording(context.Background(), nil)
+	recorder.Stop(context.Background())
+	if err := recorder.WriteGIF(&bytes.Buffer{}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
@@ -0,0 +1,311 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/page"
+)
+
+/*
+DefaultMaxTextureSize is the largest image dimension, in device pixels, that is
+captured in a single Page.captureScreenshot call. Chrome's GPU texture limit is
+typically 16384 pixels; taller captures are tiled and stitched together.
+*/
+const DefaultMaxTextureSize = 16384
+
+/*
+ScreenshotOptions configures a screenshot. The zero value captures the current
+viewport as a PNG image.
+*/
+type ScreenshotOptions struct {
+	// Optional. Image format, defaults to page.Format.Png.
+	Format page.FormatEnum
+
+	// Optional. Compression quality in the range [0..100], jpeg only.
+	Quality int
+
+	// Optional. Capture the full scrollable page instead of the viewport.
+	FullPage bool
+
+	// Optional. Capture the border box of a DOM node.
+	Element dom.NodeID
+
+	// Optional. Capture a region of the document in CSS pixels.
+	Clip *page.Rect
+
+	// Optional. The maximum image height in device pixels captured in a
+	// single call, defaults to DefaultMaxTextureSize.
+	MaxTextureSize int
+}
+
+/*
+Screenshot captures the page and returns the decoded image data. Element and
+clip captures render the region beyond the viewport without changing the
+layout. Full page captures temporarily resize the viewport to the content with
+a device metrics override and restore the override applied by Emulate
+afterwards. Regions taller than the texture limit are captured in lossless
+tiles and stitched and encoded as a single image.
+
+	data, err := tab.Screenshot(ctx, &chrome.ScreenshotOptions{FullPage: true})
+*/
+func (tab *Tab) Screenshot(ctx context.Context, opts *ScreenshotOptions) ([]byte, error) {
+	if nil == opts {
+		opts = &ScreenshotOptions{}
+	}
+	format := opts.Format
+	if "" == format.String() {
+		format = page.Format.Png
+	}
+	quality := 0
+	if page.Format.Jpeg == format {
+		quality = opts.Quality
+	}
+	maxTextureSize := opts.MaxTextureSize
+	if maxTextureSize <= 0 {
+		maxTextureSize = DefaultMaxTextureSize
+	}
+
+	if !opts.FullPage && 0 == opts.Element && nil == opts.Clip {
+		return tab.captureScreenshot(ctx, &page.CaptureScreenshotParams{
+			Format:  format,
+			Quality: quality,
+		})
+	}
+
+	scaleFactor := tab.devicePixelRatio(ctx)
+	tileHeight := math.Floor(float64(maxTextureSize) / scaleFactor)
+	if opts.FullPage {
+		restore, err := tab.resizeToContent(ctx, tileHeight)
+		if nil != err {
+			return nil, err
+		}
+		defer restore()
+	}
+
+	viewport, content, err := tab.layoutMetrics(ctx)
+	if nil != err {
+		return nil, err
+	}
+	region, err := tab.screenshotRegion(ctx, opts, viewport, content)
+	if nil != err {
+		return nil, err
+	}
+	if region.Width < 1 || region.Height < 1 {
+		return nil, errs.New(codes.CaptureOptionsInvalid, fmt.Sprintf("capture region %vx%v is empty", region.Width, region.Height))
+	}
+
+	// Tiles are captured losslessly so that the stitched image is only
+	// compressed once.
+	tileFormat, tileQuality := format, quality
+	if region.Height > tileHeight {
+		tileFormat, tileQuality = page.Format.Png, 0
+	}
+
+	tiles := [][]byte{}
+	for y := 0.0; y < region.Height; y += tileHeight {
+		data, err := tab.captureScreenshot(ctx, &page.CaptureScreenshotParams{
+			Format:  tileFormat,
+			Quality: tileQuality,
+			Clip: &page.Viewport{
+				X:      region.X,
+				Y:      region.Y + y,
+				Width:  region.Width,
+				Height: math.Min(tileHeight, region.Height-y),
+				Scale:  1,
+			},
+			CaptureBeyondViewport: true,
+		})
+		if nil != err {
+			return nil, err
+		}
+		tiles = append(tiles, data)
+	}
+	if 1 == len(tiles) {
+		return tiles[0], nil
+	}
+	return stitchTiles(tiles, format, quality)
+}
+
+/*
+captureScreenshot calls Page.captureScreenshot and decodes the image data.
+*/
+func (tab *Tab) captureScreenshot(ctx context.Context, params *page.CaptureScreenshotParams) ([]byte, error) {
+	result := <-tab.withContext(ctx).Page().CaptureScreenshot(params)
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.CaptureFailed, "could not capture screenshot")
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Data)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.CaptureDecodeFailed, "could not decode screenshot data")
+	}
+	return data, nil
+}
+
+/*
+layoutMetrics returns the layout viewport and the content size in CSS pixels.
+Newer versions of Chrome report device pixels in the layout fields and CSS
+pixels in the css* fields.
+*/
+func (tab *Tab) layoutMetrics(ctx context.Context) (*page.LayoutViewport, *page.Rect, error) {
+	metrics := <-tab.withContext(ctx).Page().GetLayoutMetrics()
+	if nil != metrics.Err {
+		return nil, nil, errs.Wrap(metrics.Err, codes.CaptureFailed, "could not get layout metrics")
+	}
+	viewport, content := metrics.CSSLayoutViewport, metrics.CSSContentSize
+	if nil == viewport || nil == content {
+		viewport, content = metrics.LayoutViewport, metrics.ContentSize
+	}
+	if nil == viewport || nil == content {
+		return nil, nil, errs.New(codes.CaptureFailed, "layout metrics are not available")
+	}
+	return viewport, content, nil
+}
+
+/*
+devicePixelRatio returns the number of device pixels per CSS pixel, or 1 if it
+can't be determined.
+*/
+func (tab *Tab) devicePixelRatio(ctx context.Context) float64 {
+	scaleFactor := 1.0
+	if err := tab.Eval(ctx, "window.devicePixelRatio", &scaleFactor); nil != err || scaleFactor <= 0 {
+		return 1.0
+	}
+	return scaleFactor
+}
+
+/*
+resizeToContent resizes the viewport to the width of the content and to its
+height, up to maxHeight CSS pixels, so that a full page capture renders the
+whole page. The returned function restores the device metrics override applied
+by Emulate, or removes the override if no device is emulated.
+*/
+func (tab *Tab) resizeToContent(ctx context.Context, maxHeight float64) (func(), error) {
+	_, content, err := tab.layoutMetrics(ctx)
+	if nil != err {
+		return nil, err
+	}
+	saved := tab.activeDeviceMetrics()
+	metrics := &emulation.SetDeviceMetricsOverrideParams{}
+	if nil != saved {
+		*metrics = *saved
+	}
+	metrics.Width = int(math.Ceil(content.Width))
+	metrics.Height = int(math.Min(math.Ceil(content.Height), maxHeight))
+	result := <-tab.withContext(ctx).Emulation().SetDeviceMetricsOverride(metrics)
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.CaptureFailed, "could not resize the viewport")
+	}
+	return func() {
+		tab.restoreDeviceMetrics(saved)
+	}, nil
+}
+
+/*
+activeDeviceMetrics returns the device metrics override applied by Emulate, or
+nil if no device is emulated.
+*/
+func (tab *Tab) activeDeviceMetrics() *emulation.SetDeviceMetricsOverrideParams {
+	tab.emulatedMux.Lock()
+	defer tab.emulatedMux.Unlock()
+	return tab.emulatedMetrics
+}
+
+/*
+restoreDeviceMetrics replaces the device metrics override applied by a full
+page capture with the saved override, or removes it if saved is nil.
+*/
+func (tab *Tab) restoreDeviceMetrics(saved *emulation.SetDeviceMetricsOverrideParams) {
+	if nil != saved {
+		<-tab.Emulation().SetDeviceMetricsOverride(saved)
+		return
+	}
+	<-tab.Emulation().ClearDeviceMetricsOverride()
+}
+
+/*
+screenshotRegion returns the region of the document to capture in CSS pixels.
+*/
+func (tab *Tab) screenshotRegion(
+	ctx context.Context,
+	opts *ScreenshotOptions,
+	viewport *page.LayoutViewport,
+	content *page.Rect,
+) (*page.Rect, error) {
+	if opts.FullPage {
+		return &page.Rect{Width: math.Ceil(content.Width), Height: math.Ceil(content.Height)}, nil
+	}
+	if nil != opts.Clip {
+		return opts.Clip, nil
+	}
+
+	result := <-tab.withContext(ctx).DOM().GetBoxModel(&dom.GetBoxModelParams{
+		NodeID: opts.Element,
+	})
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.CaptureOptionsInvalid, fmt.Sprintf("could not get box model for node %d", opts.Element))
+	}
+	if nil == result.Model || 0 == result.Model.Width || 0 == result.Model.Height {
+		return nil, errs.New(codes.CaptureOptionsInvalid, fmt.Sprintf("node %d is not visible", opts.Element))
+	}
+	return &page.Rect{
+		X:      result.Model.Border[0] + viewport.PageX,
+		Y:      result.Model.Border[1] + viewport.PageY,
+		Width:  float64(result.Model.Width),
+		Height: float64(result.Model.Height),
+	}, nil
+}
+
+/*
+stitchTiles decodes a vertical sequence of image tiles and encodes them as a
+single image.
+*/
+func stitchTiles(tiles [][]byte, format page.FormatEnum, quality int) ([]byte, error) {
+	images := make([]image.Image, 0, len(tiles))
+	width, height := 0, 0
+	for k, tile := range tiles {
+		img, _, err := image.Decode(bytes.NewReader(tile))
+		if nil != err {
+			return nil, errs.Wrap(err, codes.CaptureDecodeFailed, fmt.Sprintf("could not decode tile %d", k))
+		}
+		images = append(images, img)
+		if img.Bounds().Dx() > width {
+			width = img.Bounds().Dx()
+		}
+		height += img.Bounds().Dy()
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	y := 0
+	for _, img := range images {
+		bounds := img.Bounds()
+		draw.Draw(canvas, image.Rect(0, y, bounds.Dx(), y+bounds.Dy()), img, bounds.Min, draw.Src)
+		y += bounds.Dy()
+	}
+
+	buf := &bytes.Buffer{}
+	var err error
+	if page.Format.Jpeg == format {
+		if 0 == quality {
+			quality = jpeg.DefaultQuality
+		}
+		err = jpeg.Encode(buf, canvas, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(buf, canvas)
+	}
+	if nil != err {
+		return nil, errs.Wrap(err, codes.CaptureEncodeFailed, "could not encode stitched image")
+	}
+	return buf.Bytes(), nil
+}
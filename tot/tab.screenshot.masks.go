@@ -0,0 +1,112 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/page"
+)
+
+/*
+ScreenshotMasks returns the border boxes of the elements matching the CSS
+selectors in the image pixels of a screenshot taken with opts, for masking
+regions that change between runs in visual comparisons. Elements that are not
+rendered are skipped. For full page captures the elements are measured with
+the viewport resized as it is for the capture.
+
+	opts := &chrome.ScreenshotOptions{FullPage: true}
+	masks, err := tab.ScreenshotMasks(ctx, opts, ".timestamp", "#ad")
+	...
+	screenshot, err := tab.Screenshot(ctx, opts)
+	...
+	result, err := baselines.Check("home", screenshot, masks...)
+*/
+func (tab *Tab) ScreenshotMasks(ctx context.Context, opts *ScreenshotOptions, selectors ...string) ([]image.Rectangle, error) {
+	if nil == opts {
+		opts = &ScreenshotOptions{}
+	}
+
+	// Full page captures resize the viewport, which can change the layout, so
+	// the elements are measured with the same viewport.
+	scaleFactor := tab.devicePixelRatio(ctx)
+	if opts.FullPage {
+		maxTextureSize := opts.MaxTextureSize
+		if maxTextureSize <= 0 {
+			maxTextureSize = DefaultMaxTextureSize
+		}
+		restore, err := tab.resizeToContent(ctx, math.Floor(float64(maxTextureSize)/scaleFactor))
+		if nil != err {
+			return nil, err
+		}
+		defer restore()
+	}
+
+	viewport, content, err := tab.layoutMetrics(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	// Viewport captures are relative to the viewport, other captures are
+	// relative to the captured region of the document.
+	originX, originY := viewport.PageX, viewport.PageY
+	if opts.FullPage || 0 != opts.Element || nil != opts.Clip {
+		region, err := tab.screenshotRegion(ctx, opts, viewport, content)
+		if nil != err {
+			return nil, err
+		}
+		originX, originY = region.X, region.Y
+	}
+
+	protocol := tab.withContext(ctx)
+	document := <-protocol.DOM().GetDocument(&dom.GetDocumentParams{})
+	if nil != document.Err {
+		return nil, errs.Wrap(document.Err, codes.CaptureFailed, "could not get the document")
+	}
+	if nil == document.Root {
+		return nil, errs.New(codes.CaptureFailed, "the document has no root node")
+	}
+
+	masks := []image.Rectangle{}
+	for _, selector := range selectors {
+		nodes := <-protocol.DOM().QuerySelectorAll(&dom.QuerySelectorAllParams{
+			NodeID:   document.Root.NodeID,
+			Selector: selector,
+		})
+		if nil != nodes.Err {
+			return nil, errs.Wrap(nodes.Err, codes.CaptureOptionsInvalid, fmt.Sprintf("could not query selector '%s'", selector))
+		}
+		for _, nodeID := range nodes.NodeIDs {
+			box := <-protocol.DOM().GetBoxModel(&dom.GetBoxModelParams{NodeID: nodeID})
+			if nil != box.Err {
+				continue
+			}
+			if nil == box.Model || 0 == box.Model.Width || 0 == box.Model.Height {
+				continue
+			}
+			masks = append(masks, maskRect(&page.Rect{
+				X:      box.Model.Border[0] + viewport.PageX - originX,
+				Y:      box.Model.Border[1] + viewport.PageY - originY,
+				Width:  float64(box.Model.Width),
+				Height: float64(box.Model.Height),
+			}, scaleFactor))
+		}
+	}
+	return masks, nil
+}
+
+/*
+maskRect converts a rectangle in CSS pixels to the device pixels that cover it.
+*/
+func maskRect(rect *page.Rect, scaleFactor float64) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(rect.X*scaleFactor)),
+		int(math.Floor(rect.Y*scaleFactor)),
+		int(math.Ceil((rect.X+rect.Width)*scaleFactor)),
+		int(math.Ceil((rect.Y+rect.Height)*scaleFactor)),
+	)
+}
@@ -0,0 +1,236 @@
+package chrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/dom"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+mockScreenshot responds to Page.captureScreenshot with a PNG image of the clip
+size, filled with a gray level derived from the clip offset.
+*/
+func mockScreenshot(params interface{}) (interface{}, *socket.Error) {
+	width, height := 10, 10
+	shade := uint8(0)
+	if clip := params.(*page.CaptureScreenshotParams).Clip; nil != clip {
+		width, height = int(clip.Width), int(clip.Height)
+		shade = uint8(clip.Y)
+	}
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for k := range img.Pix {
+		img.Pix[k] = shade
+	}
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	return map[string]string{"data": base64.StdEncoding.EncodeToString(buf.Bytes())}, nil
+}
+
+func mockLayoutMetrics(params interface{}) (interface{}, *socket.Error) {
+	return json.RawMessage(`{
+		"layoutViewport": {"pageX": 0, "pageY": 0, "clientWidth": 1600, "clientHeight": 1200},
+		"contentSize": {"x": 0, "y": 0, "width": 1600, "height": 5000},
+		"cssLayoutViewport": {"pageX": 0, "pageY": 50, "clientWidth": 800, "clientHeight": 600},
+		"cssContentSize": {"x": 0, "y": 0, "width": 800, "height": 2500.5}
+	}`), nil
+}
+
+func TestTabScreenshotViewport(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabScreenshotViewport")
+	mockSocket.Respond("Page.captureScreenshot", mockScreenshot)
+
+	data, err := tab.Screenshot(context.Background(), nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); nil != err {
+		t.Errorf("Expected PNG data, received error: %v", err)
+	}
+	if 0 != len(mockSocket.Commands("Emulation.setDeviceMetricsOverride")) {
+		t.Errorf("Expected the viewport not to be resized")
+	}
+}
+
+func TestTabScreenshotFullPage(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabScreenshotFullPage")
+	mockSocket.Respond("Page.getLayoutMetrics", mockLayoutMetrics)
+	mockSocket.Respond("Page.captureScreenshot", mockScreenshot)
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"number","value":2}}`), nil
+	})
+
+	data, err := tab.Screenshot(context.Background(), &ScreenshotOptions{
+		FullPage:       true,
+		MaxTextureSize: 2000,
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	captures := mockSocket.Commands("Page.captureScreenshot")
+	if 3 != len(captures) {
+		t.Fatalf("Expected 3 tiles, received %d", len(captures))
+	}
+	expected := [][2]float64{{0, 1000}, {1000, 1000}, {2000, 501}}
+	for k, capture := range captures {
+		clip := capture.Params().(*page.CaptureScreenshotParams).Clip
+		if expected[k][0] != clip.Y || expected[k][1] != clip.Height || 800 != clip.Width {
+			t.Errorf("Tile %d: expected %v, received %+v", k, expected[k], clip)
+		}
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if nil != err {
+		t.Fatalf("Expected PNG data, received error: %v", err)
+	}
+	if 800 != img.Bounds().Dx() || 2501 != img.Bounds().Dy() {
+		t.Errorf("Expected 800x2501, received %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if gray := color.GrayModel.Convert(img.At(0, 1500)).(color.Gray); 232 != gray.Y {
+		t.Errorf("Expected the second tile at 1500, received shade %d", gray.Y)
+	}
+
+	overrides := mockSocket.Commands("Emulation.setDeviceMetricsOverride")
+	if 1 != len(overrides) {
+		t.Fatalf("Expected 1 device metrics override, received %d", len(overrides))
+	}
+	params := overrides[0].Params().(*emulation.SetDeviceMetricsOverrideParams)
+	if 800 != params.Width || 1000 != params.Height {
+		t.Errorf("Expected 800x1000, received %dx%d", params.Width, params.Height)
+	}
+	if 1 != len(mockSocket.Commands("Emulation.clearDeviceMetricsOverride")) {
+		t.Errorf("Expected the device metrics to be restored")
+	}
+
+	// Tiled JPEG captures are stitched from PNG tiles and encoded once.
+	data, err = tab.Screenshot(context.Background(), &ScreenshotOptions{
+		FullPage:       true,
+		Format:         page.Format.Jpeg,
+		Quality:        80,
+		MaxTextureSize: 2000,
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for _, capture := range mockSocket.Commands("Page.captureScreenshot")[3:] {
+		if params := capture.Params().(*page.CaptureScreenshotParams); page.Format.Png != params.Format || 0 != params.Quality {
+			t.Errorf("Expected PNG tiles, received %s at %d", params.Format, params.Quality)
+		}
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); nil != err {
+		t.Errorf("Expected JPEG data, received error: %v", err)
+	}
+}
+
+func TestTabScreenshotElement(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabScreenshotElement")
+	mockSocket.Respond("Page.getLayoutMetrics", mockLayoutMetrics)
+	mockSocket.Respond("Page.captureScreenshot", mockScreenshot)
+	mockSocket.Respond("DOM.getBoxModel", func(params interface{}) (interface{}, *socket.Error) {
+		return &dom.GetBoxModelResult{Model: &dom.BoxModel{
+			Border: dom.Quad{20, 30},
+			Width:  100,
+			Height: 40,
+		}}, nil
+	})
+
+	if _, err := tab.Screenshot(context.Background(), &ScreenshotOptions{
+		Element: 5,
+		Format:  page.Format.Jpeg,
+		Quality: 80,
+	}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params := mockSocket.Commands("Page.captureScreenshot")[0].Params().(*page.CaptureScreenshotParams)
+	if page.Format.Jpeg != params.Format || 80 != params.Quality {
+		t.Errorf("Expected jpeg at 80, received %s at %d", params.Format, params.Quality)
+	}
+	if 20 != params.Clip.X || 80 != params.Clip.Y || 100 != params.Clip.Width || 40 != params.Clip.Height {
+		t.Errorf("Expected the element border box offset by the scroll position, received %+v", params.Clip)
+	}
+	if !params.CaptureBeyondViewport {
+		t.Errorf("Expected the element to be captured beyond the viewport")
+	}
+	if 0 != len(mockSocket.Commands("Emulation.setDeviceMetricsOverride")) || 0 != len(mockSocket.Commands("Emulation.clearDeviceMetricsOverride")) {
+		t.Errorf("Expected the device metrics not to be changed")
+	}
+}
+
+func TestTabScreenshotEmptyClip(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabScreenshotEmptyClip")
+	mockSocket.Respond("Page.getLayoutMetrics", mockLayoutMetrics)
+
+	if _, err := tab.Screenshot(context.Background(), &ScreenshotOptions{
+		Clip: &page.Rect{X: 10, Y: 10},
+	}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabScreenshotMasks(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabScreenshotMasks")
+	mockSocket.Respond("Page.getLayoutMetrics", mockLayoutMetrics)
+	mockSocket.Respond("Runtime.evaluate", func(params interface{}) (interface{}, *socket.Error) {
+		return json.RawMessage(`{"result":{"type":"number","value":2}}`), nil
+	})
+	mockSocket.Respond("DOM.getDocument", func(params interface{}) (interface{}, *socket.Error) {
+		return &dom.GetDocumentResult{Root: &dom.Node{NodeID: 1}}, nil
+	})
+	mockSocket.Respond("DOM.querySelectorAll", func(params interface{}) (interface{}, *socket.Error) {
+		if ".ad" == params.(*dom.QuerySelectorAllParams).Selector {
+			return &dom.QuerySelectorAllResult{NodeIDs: []dom.NodeID{2, 3}}, nil
+		}
+		return &dom.QuerySelectorAllResult{}, nil
+	})
+	mockSocket.Respond("DOM.getBoxModel", func(params interface{}) (interface{}, *socket.Error) {
+		if 3 == params.(*dom.GetBoxModelParams).NodeID {
+			return nil, &socket.Error{Code: 1, Message: "Could not compute box model."}
+		}
+		return &dom.GetBoxModelResult{Model: &dom.BoxModel{
+			Border: dom.Quad{10.5, 20},
+			Width:  30,
+			Height: 10,
+		}}, nil
+	})
+
+	masks, err := tab.ScreenshotMasks(context.Background(), nil, ".ad", "#none")
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 1 != len(masks) || image.Rect(21, 40, 81, 60) != masks[0] {
+		t.Errorf("Expected the viewport mask (21,40)-(81,60), received %v", masks)
+	}
+
+	masks, err = tab.ScreenshotMasks(context.Background(), &ScreenshotOptions{FullPage: true}, ".ad")
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 1 != len(masks) || image.Rect(21, 140, 81, 160) != masks[0] {
+		t.Errorf("Expected the full page mask (21,140)-(81,160), received %v", masks)
+	}
+	overrides := mockSocket.Commands("Emulation.setDeviceMetricsOverride")
+	if 1 != len(overrides) || 1 != len(mockSocket.Commands("Emulation.clearDeviceMetricsOverride")) {
+		t.Fatalf("Expected the viewport to be resized and restored")
+	}
+	if boxes := mockSocket.Commands("DOM.getBoxModel"); 4 != len(boxes) || overrides[0].ID() > boxes[2].ID() {
+		t.Errorf("Expected the elements to be measured after the viewport is resized")
+	}
+
+	mockSocket.Respond("DOM.querySelectorAll", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "DOM Error while querying"}
+	})
+	if _, err := tab.ScreenshotMasks(context.Background(), nil, "[invalid"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
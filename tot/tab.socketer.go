@@ -1,6 +1,8 @@
 package chrome
 
 import (
+	"context"
+
 	"github.com/mkenney/go-chrome/tot/socket"
 )
 
@@ -24,3 +26,35 @@ SendCommand implements Socketer
 func (tab *Tab) SendCommand(command socket.Commander) chan *socket.Response {
 	return tab.Socket().SendCommand(command)
 }
+
+/*
+withContext returns the protocol interfaces of the tab bound to ctx. Commands
+sent through them fail with the context error if ctx is done before the socket
+responds.
+*/
+func (tab *Tab) withContext(ctx context.Context) socket.Protocoller {
+	return socket.WithContext(ctx, tab.Socket())
+}
+
+/*
+protocolCommand is a command in a sequence of protocol commands. The method
+name identifies the command in errors.
+*/
+type protocolCommand struct {
+	method string
+	send   func(protocol socket.Protocoller) error
+}
+
+/*
+sendCommand sends a command to conn and waits for the response, or until ctx is
+done. Unlike the protocol methods it returns the socket response, which counts
+the events read before it.
+*/
+func sendCommand(ctx context.Context, conn socket.Socketer, method string, params interface{}) (*socket.Response, error) {
+	contextSocket := socket.WithContext(ctx, conn)
+	response := <-contextSocket.SendCommand(socket.NewCommand(contextSocket, method, params))
+	if nil != response.Error && 0 != response.Error.Code {
+		return nil, response.Error
+	}
+	return response, nil
+}
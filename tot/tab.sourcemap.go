@@ -0,0 +1,345 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/sourcemap"
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/network"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+SourceMaps tracks parsed scripts and maps their positions back to the original
+sources of their source maps. Source maps are loaded the first time a script
+position is mapped, from the page's resources with Page.getResourceContent or
+through the browser's network stack with Network.loadNetworkResource.
+
+	maps, err := tab.StartSourceMaps(ctx)
+	...
+	err = tab.Eval(ctx, "app.run()", nil)
+	if jsErr, ok := err.(*JSError); ok {
+		err = maps.MapError(ctx, jsErr)
+	}
+*/
+type SourceMaps struct {
+	handler socket.EventHandler
+	loaded  map[runtime.ScriptID]*loadedSourceMap
+	mux     sync.Mutex
+	scripts []*Script
+	tab     *Tab
+}
+
+/*
+loadedSourceMap is a loaded source map or the error loading it.
+*/
+type loadedSourceMap struct {
+	err error
+	sm  *sourcemap.Map
+}
+
+/*
+newSourceMaps returns source maps without an event handler, scripts are added
+by the owner.
+*/
+func newSourceMaps(tab *Tab) *SourceMaps {
+	return &SourceMaps{
+		loaded:  map[runtime.ScriptID]*loadedSourceMap{},
+		scripts: []*Script{},
+		tab:     tab,
+	}
+}
+
+/*
+StartSourceMaps enables the debugger and starts tracking parsed scripts.
+Scripts parsed before the debugger is enabled are reported when it is.
+*/
+func (tab *Tab) StartSourceMaps(ctx context.Context) (*SourceMaps, error) {
+	maps := newSourceMaps(tab)
+	maps.handler = socket.NewEventHandler("Debugger.scriptParsed", func(response *socket.Response) {
+		event := &debugger.ScriptParsedEvent{}
+		if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+			log.Errorf("invalid scriptParsed event: %s", err)
+			return
+		}
+		maps.scriptParsed(event)
+	})
+	tab.AddEventHandler(maps.handler)
+
+	if result := <-tab.withContext(ctx).Debugger().Enable(); nil != result.Err {
+		tab.RemoveEventHandler(maps.handler)
+		return nil, errs.Wrap(result.Err, codes.DebuggerFailed, "Debugger.enable failed")
+	}
+	return maps, nil
+}
+
+/*
+Stop stops tracking scripts and disables the debugger.
+*/
+func (maps *SourceMaps) Stop(ctx context.Context) error {
+	if nil == maps.handler {
+		return nil
+	}
+	maps.tab.RemoveEventHandler(maps.handler)
+	maps.handler = nil
+	if result := <-maps.tab.withContext(ctx).Debugger().Disable(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.DebuggerFailed, "Debugger.disable failed")
+	}
+	return nil
+}
+
+/*
+scriptParsed records a parsed script.
+*/
+func (maps *SourceMaps) scriptParsed(event *debugger.ScriptParsedEvent) *Script {
+	script := &Script{
+		ID:                 event.ScriptID,
+		URL:                event.URL,
+		SourceMapURL:       event.SourceMapURL,
+		Hash:               event.Hash,
+		StartLine:          event.StartLine,
+		StartColumn:        event.StartColumn,
+		ExecutionContextID: event.ExecutionContextID,
+	}
+	if frameID, ok := event.ExecutionContextAuxData["frameId"].(string); ok {
+		script.FrameID = page.FrameID(frameID)
+	}
+	maps.mux.Lock()
+	maps.scripts = append(maps.scripts, script)
+	maps.mux.Unlock()
+	return script
+}
+
+/*
+Scripts returns the parsed scripts in the order they were parsed.
+*/
+func (maps *SourceMaps) Scripts() []*Script {
+	maps.mux.Lock()
+	defer maps.mux.Unlock()
+	return append(maps.scripts[:0:0], maps.scripts...)
+}
+
+/*
+Script returns a parsed script by ID, or nil.
+*/
+func (maps *SourceMaps) Script(id runtime.ScriptID) *Script {
+	maps.mux.Lock()
+	defer maps.mux.Unlock()
+	for _, script := range maps.scripts {
+		if id == script.ID {
+			return script
+		}
+	}
+	return nil
+}
+
+/*
+ScriptsByURL returns the parsed scripts whose URL is file or ends with the path
+file, e.g. "js/app.js".
+*/
+func (maps *SourceMaps) ScriptsByURL(file string) []*Script {
+	maps.mux.Lock()
+	defer maps.mux.Unlock()
+	scripts := []*Script{}
+	for _, script := range maps.scripts {
+		if sourcemap.MatchURL(script.URL, file) {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
+
+/*
+Map returns the source map of a parsed script, loading it the first time.
+*/
+func (maps *SourceMaps) Map(ctx context.Context, id runtime.ScriptID) (*sourcemap.Map, error) {
+	script := maps.Script(id)
+	if nil == script {
+		return nil, errs.New(codes.SourceMapLoadFailed, fmt.Sprintf("unknown script %s", id))
+	}
+	if "" == script.SourceMapURL {
+		return nil, errs.New(codes.SourceMapLoadFailed, fmt.Sprintf("%s has no source map", script.URL))
+	}
+	maps.mux.Lock()
+	loaded, ok := maps.loaded[id]
+	maps.mux.Unlock()
+	if ok {
+		return loaded.sm, loaded.err
+	}
+
+	sm, err := sourcemap.Load(script.URL, script.SourceMapURL, func(url string) ([]byte, error) {
+		return maps.tab.fetchSourceMap(ctx, script.FrameID, url)
+	})
+	// Retry maps that failed because the context is done.
+	if nil != err && nil != ctx.Err() {
+		return nil, err
+	}
+	maps.mux.Lock()
+	maps.loaded[id] = &loadedSourceMap{err: err, sm: sm}
+	maps.mux.Unlock()
+	return sm, err
+}
+
+/*
+Original returns the original position of a position (0-based) in a parsed
+script. Positions in inline scripts are relative to their document, as
+reported by the protocol.
+*/
+func (maps *SourceMaps) Original(ctx context.Context, id runtime.ScriptID, line, column int) (*sourcemap.Position, bool) {
+	script := maps.Script(id)
+	if nil == script || "" == script.SourceMapURL {
+		return nil, false
+	}
+	sm, err := maps.Map(ctx, id)
+	if nil != err {
+		log.Warnf("%s", err)
+		return nil, false
+	}
+	if line == script.StartLine {
+		column -= script.StartColumn
+	}
+	return sm.Original(line-script.StartLine, column)
+}
+
+/*
+MapLocation returns the original position of a debugger location.
+*/
+func (maps *SourceMaps) MapLocation(ctx context.Context, location *debugger.Location) (*sourcemap.Position, bool) {
+	if nil == location {
+		return nil, false
+	}
+	return maps.Original(ctx, location.ScriptID, int(location.LineNumber), int(location.ColumnNumber))
+}
+
+/*
+MapStackTrace returns a copy of a stack trace, including its parents, with the
+call frames mapped to their original sources. Frames of scripts without a
+source map are unchanged.
+*/
+func (maps *SourceMaps) MapStackTrace(ctx context.Context, trace *runtime.StackTrace) *runtime.StackTrace {
+	if nil == trace {
+		return nil
+	}
+	mapped := *trace
+	mapped.CallFrames = make([]*runtime.CallFrame, 0, len(trace.CallFrames))
+	for _, frame := range trace.CallFrames {
+		mappedFrame := *frame
+		if pos, ok := maps.Original(ctx, frame.ScriptID, frame.LineNumber, frame.ColumnNumber); ok {
+			mappedFrame.URL = pos.Source
+			mappedFrame.LineNumber = pos.Line
+			mappedFrame.ColumnNumber = pos.Column
+		}
+		mapped.CallFrames = append(mapped.CallFrames, &mappedFrame)
+	}
+	mapped.Parent = maps.MapStackTrace(ctx, trace.Parent)
+	return &mapped
+}
+
+/*
+MapExceptionDetails returns a copy of exception details with the location and
+the stack trace mapped to their original sources.
+*/
+func (maps *SourceMaps) MapExceptionDetails(ctx context.Context, details *runtime.ExceptionDetails) *runtime.ExceptionDetails {
+	if nil == details {
+		return nil
+	}
+	mapped := *details
+	if pos, ok := maps.Original(ctx, details.ScriptID, details.LineNumber, details.ColumnNumber); ok {
+		mapped.URL = pos.Source
+		mapped.LineNumber = pos.Line
+		mapped.ColumnNumber = pos.Column
+	}
+	mapped.StackTrace = maps.MapStackTrace(ctx, details.StackTrace)
+	return &mapped
+}
+
+/*
+MapError returns a copy of a JSError with the location and the stack trace
+mapped to their original sources. The JavaScript stack in the description of
+Error objects is replaced by the mapped stack trace.
+*/
+func (maps *SourceMaps) MapError(ctx context.Context, err *JSError) *JSError {
+	if nil == err {
+		return nil
+	}
+	mapped := *err
+	id := err.scriptID
+	if "" == id {
+		// Exceptions of evaluations report a URL but no script.
+		for _, script := range maps.Scripts() {
+			if err.URL == script.URL {
+				id = script.ID
+			}
+		}
+	}
+	if pos, ok := maps.Original(ctx, id, err.LineNumber-1, err.ColumnNumber-1); ok {
+		mapped.URL = pos.Source
+		mapped.LineNumber = pos.Line + 1
+		mapped.ColumnNumber = pos.Column + 1
+	}
+	if nil != err.StackTrace {
+		mapped.StackTrace = maps.MapStackTrace(ctx, err.StackTrace)
+		if stack := strings.Index(mapped.Description, "\n    at "); stack >= 0 {
+			mapped.Description = mapped.Description[:stack]
+		}
+	}
+	return &mapped
+}
+
+/*
+fetchSourceMap loads a source map from the resources of a frame, or of the
+main frame if frameID is empty. Source maps that aren't page resources are
+loaded through the browser's network stack so that the page's cookies and
+cache apply.
+*/
+func (tab *Tab) fetchSourceMap(ctx context.Context, frameID page.FrameID, url string) ([]byte, error) {
+	if "" == frameID {
+		tree := <-tab.withContext(ctx).Page().GetFrameTree()
+		if nil != tree.Err {
+			return nil, errs.Wrap(tree.Err, codes.SourceMapLoadFailed, "could not get the main frame")
+		}
+		if nil != tree.FrameTree && nil != tree.FrameTree.Frame {
+			frameID = page.FrameID(tree.FrameTree.Frame.ID)
+		}
+	}
+
+	content := <-tab.withContext(ctx).Page().GetResourceContent(&page.GetResourceContentParams{
+		FrameID: frameID,
+		URL:     url,
+	})
+	if nil == content.Err {
+		if content.Base64Encoded {
+			return base64.StdEncoding.DecodeString(content.Content)
+		}
+		return []byte(content.Content), nil
+	}
+
+	result := <-tab.withContext(ctx).Network().LoadNetworkResource(&network.LoadNetworkResourceParams{
+		FrameID: frameID,
+		URL:     url,
+		Options: &network.LoadNetworkResourceOptions{IncludeCredentials: true},
+	})
+	if nil != result.Err {
+		return nil, errs.Wrap(result.Err, codes.SourceMapLoadFailed, fmt.Sprintf("could not load %s", url))
+	}
+	if nil == result.Resource {
+		return nil, errs.New(codes.SourceMapLoadFailed, fmt.Sprintf("could not load %s", url))
+	}
+	if !result.Resource.Success {
+		return nil, errs.New(codes.SourceMapLoadFailed, fmt.Sprintf("could not load %s: %s (HTTP %d)", url, result.Resource.NetErrorName, result.Resource.HTTPStatusCode))
+	}
+	stream := tab.OpenStream(ctx, result.Resource.Stream)
+	defer stream.Close()
+	return ioutil.ReadAll(stream)
+}
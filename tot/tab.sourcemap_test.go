@@ -0,0 +1,180 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/debugger"
+	"github.com/mkenney/go-chrome/tot/network"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/runtime"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+testSourceMapInline is an inline source map that maps the inline script to
+inline.ts.
+*/
+var testSourceMapInline = "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(`{"version":3,"sources":["inline.ts"],"mappings":"AAAA"}`))
+
+func TestTabSourceMaps(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabSourceMaps")
+	maps, err := tab.StartSourceMaps(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	mockSocket.Respond("Page.getResourceContent", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "No resource with given URL found"}
+	})
+	mockSocket.Respond("Network.loadNetworkResource", func(params interface{}) (interface{}, *socket.Error) {
+		return &network.LoadNetworkResourceResult{Resource: &network.LoadNetworkResourcePageResult{Success: true, HTTPStatusCode: 200, Stream: "stream-1"}}, nil
+	})
+	mockSocket.RespondStream(`{"version":3,"sources":["src/app.ts"],`, `"mappings":"AAAA;EACA"}`)
+
+	// A script with a source map that isn't a page resource, an inline script
+	// with an inline source map and a script without a source map.
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "1", URL: "https://example.com/js/app.js", SourceMapURL: "app.js.map", ExecutionContextAuxData: map[string]interface{}{"isDefault": true, "frameId": "F1"}})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "2", URL: "https://example.com/", StartLine: 4, StartColumn: 8, SourceMapURL: testSourceMapInline})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "3", URL: "https://example.com/lib.js"})
+	if 3 != len(maps.Scripts()) || "F1" != maps.Script("1").FrameID {
+		t.Fatalf("Unexpected scripts %+v", maps.Scripts())
+	}
+
+	trace := maps.MapStackTrace(context.Background(), &runtime.StackTrace{
+		CallFrames: []*runtime.CallFrame{
+			{FunctionName: "f", ScriptID: "1", URL: "https://example.com/js/app.js", LineNumber: 1, ColumnNumber: 4},
+			{FunctionName: "g", ScriptID: "3", URL: "https://example.com/lib.js", LineNumber: 5, ColumnNumber: 5},
+		},
+		Parent: &runtime.StackTrace{
+			Description: "setTimeout",
+			CallFrames:  []*runtime.CallFrame{{ScriptID: "2", URL: "https://example.com/", LineNumber: 4, ColumnNumber: 8}},
+		},
+	})
+	if frame := trace.CallFrames[0]; "https://example.com/js/src/app.ts" != frame.URL || 1 != frame.LineNumber || 0 != frame.ColumnNumber || "f" != frame.FunctionName {
+		t.Errorf("Unexpected frame %+v", frame)
+	}
+	if frame := trace.CallFrames[1]; "https://example.com/lib.js" != frame.URL || 5 != frame.LineNumber {
+		t.Errorf("Expected frames without a source map to be unchanged, received %+v", frame)
+	}
+	if frame := trace.Parent.CallFrames[0]; "https://example.com/inline.ts" != frame.URL || 0 != frame.LineNumber || 0 != frame.ColumnNumber {
+		t.Errorf("Expected inline script positions to be relative to the script, received %+v", frame)
+	}
+
+	params := mockSocket.Commands("Network.loadNetworkResource")[0].Params().(*network.LoadNetworkResourceParams)
+	if "F1" != params.FrameID || "https://example.com/js/app.js.map" != params.URL || !params.Options.IncludeCredentials {
+		t.Errorf("Unexpected parameters %+v", params)
+	}
+	if pos, ok := maps.MapLocation(context.Background(), &debugger.Location{ScriptID: "1", LineNumber: 0, ColumnNumber: 3}); !ok || 0 != pos.Line {
+		t.Errorf("Unexpected position %+v", pos)
+	}
+	if 1 != len(mockSocket.Commands("Network.loadNetworkResource")) || 1 != len(mockSocket.Commands("IO.close")) {
+		t.Errorf("Expected the source map to be loaded once")
+	}
+
+	details := maps.MapExceptionDetails(context.Background(), &runtime.ExceptionDetails{
+		Text:         "Uncaught",
+		ScriptID:     "1",
+		URL:          "https://example.com/js/app.js",
+		LineNumber:   1,
+		ColumnNumber: 2,
+	})
+	if "https://example.com/js/src/app.ts" != details.URL || 1 != details.LineNumber || nil != details.StackTrace {
+		t.Errorf("Unexpected exception details %+v", details)
+	}
+
+	if err := maps.Stop(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if 1 != len(mockSocket.Commands("Debugger.disable")) {
+		t.Errorf("Expected the debugger to be disabled")
+	}
+}
+
+func TestTabSourceMapsError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabSourceMapsError")
+	maps, err := tab.StartSourceMaps(context.Background())
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	mockSocket.Respond("Page.getResourceContent", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "No resource with given URL found"}
+	})
+	mockSocket.Respond("Network.loadNetworkResource", func(params interface{}) (interface{}, *socket.Error) {
+		return &network.LoadNetworkResourceResult{Resource: &network.LoadNetworkResourcePageResult{Success: true, HTTPStatusCode: 200, Stream: "stream-1"}}, nil
+	})
+	mockSocket.RespondStream(`{"version":3,"sources":["src/app.ts"],`, `"mappings":"AAAA;EACA"}`)
+
+	// A script with a source map that isn't a page resource, an inline script
+	// with an inline source map and a script without a source map.
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "1", URL: "https://example.com/js/app.js", SourceMapURL: "app.js.map", ExecutionContextAuxData: map[string]interface{}{"isDefault": true, "frameId": "F1"}})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "2", URL: "https://example.com/", StartLine: 4, StartColumn: 8, SourceMapURL: testSourceMapInline})
+	mockSocket.Fire("Debugger.scriptParsed", &debugger.ScriptParsedEvent{ScriptID: "3", URL: "https://example.com/lib.js"})
+
+	jsErr := newJSError(&runtime.ExceptionDetails{
+		Text:         "Uncaught",
+		LineNumber:   1,
+		ColumnNumber: 2,
+		URL:          "https://example.com/js/app.js",
+		StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+			{FunctionName: "f", ScriptID: "1", URL: "https://example.com/js/app.js", LineNumber: 1, ColumnNumber: 2},
+		}},
+		Exception: &runtime.RemoteObject{Type: runtime.ObjectType.Object, Subtype: runtime.ObjectSubtype.Error, Description: "Error: boom\n    at f (https://example.com/js/app.js:2:3)"},
+	})
+	mapped := maps.MapError(context.Background(), jsErr)
+	if "https://example.com/js/src/app.ts" != mapped.URL || 2 != mapped.LineNumber || 1 != mapped.ColumnNumber {
+		t.Errorf("Expected the error to be mapped by URL, received %+v", mapped)
+	}
+	if "Uncaught: Error: boom\n    at f (https://example.com/js/src/app.ts:2:1)" != mapped.Error() {
+		t.Errorf("Unexpected message %s", mapped.Error())
+	}
+	if !strings.Contains(jsErr.Error(), "app.js:2:3") {
+		t.Errorf("Expected the original error to be unchanged, received %s", jsErr.Error())
+	}
+
+	// Maps that fail to load are reported once.
+	if _, err := maps.Map(context.Background(), "3"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if _, err := maps.Map(context.Background(), "4"); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+
+	mockSocket.Respond("Debugger.enable", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Debugger agent is not enabled"}
+	})
+	if _, err := tab.StartSourceMaps(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTabFetchSourceMap(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTabFetchSourceMap")
+	mockSocket.Respond("Page.getFrameTree", func(params interface{}) (interface{}, *socket.Error) {
+		return &page.GetFrameTreeResult{FrameTree: &page.FrameTree{Frame: &page.Frame{ID: "main"}}}, nil
+	})
+	mockSocket.Respond("Page.getResourceContent", func(params interface{}) (interface{}, *socket.Error) {
+		return &page.GetResourceContentResult{Content: base64.StdEncoding.EncodeToString([]byte("{}")), Base64Encoded: true}, nil
+	})
+	data, err := tab.fetchSourceMap(context.Background(), "", "https://example.com/app.js.map")
+	if nil != err || "{}" != string(data) {
+		t.Errorf("Expected {}, received %s (%v)", data, err)
+	}
+	if "main" != mockSocket.Commands("Page.getResourceContent")[0].Params().(*page.GetResourceContentParams).FrameID {
+		t.Errorf("Expected the resources of the main frame")
+	}
+
+	mockSocket.Respond("Page.getResourceContent", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "No resource with given URL found"}
+	})
+	mockSocket.Respond("Network.loadNetworkResource", func(params interface{}) (interface{}, *socket.Error) {
+		return &network.LoadNetworkResourceResult{Resource: &network.LoadNetworkResourcePageResult{NetErrorName: "net::ERR_HTTP_RESPONSE_CODE_FAILURE", HTTPStatusCode: 404}}, nil
+	})
+	if _, err := tab.fetchSourceMap(context.Background(), "F1", "https://example.com/missing.js.map"); nil == err || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, received %v", err)
+	}
+	if 1 != len(mockSocket.Commands("Page.getFrameTree")) {
+		t.Errorf("Expected the main frame to be looked up once")
+	}
+}
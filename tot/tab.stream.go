@@ -0,0 +1,142 @@
+package chrome
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	cdtpio "github.com/mkenney/go-chrome/tot/io"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+streamReadSize is the maximum number of bytes requested by each IO.read call.
+*/
+const streamReadSize = 1 << 18
+
+/*
+StreamReader reads a protocol stream such as a trace, a PDF document or a
+resolved Blob. It implements io.ReadCloser; base64 encoded chunks are decoded
+transparently and Close releases the stream in the browser.
+*/
+type StreamReader struct {
+	buf    []byte
+	closed bool
+	ctx    context.Context
+	eof    bool
+	handle cdtpio.StreamHandle
+	mux    sync.Mutex
+	socket socket.Socketer
+}
+
+/*
+OpenStream returns a reader for a protocol stream. Reads fail with the context
+error once ctx is done. The stream must be closed when it is no longer needed.
+
+	result := &page.PrintToPDFResult{}
+	...
+	stream := tab.OpenStream(ctx, result.Stream)
+	defer stream.Close()
+	io.Copy(file, stream)
+*/
+func (tab *Tab) OpenStream(ctx context.Context, handle cdtpio.StreamHandle) *StreamReader {
+	return openStream(ctx, tab.Socket(), handle)
+}
+
+/*
+openStream returns a reader for a protocol stream of the target connected to
+conn.
+*/
+func openStream(ctx context.Context, conn socket.Socketer, handle cdtpio.StreamHandle) *StreamReader {
+	return &StreamReader{
+		ctx:    ctx,
+		handle: handle,
+		socket: conn,
+	}
+}
+
+/*
+Close closes the stream and discards the browser's backing storage. Closing a
+closed stream has no effect. Close stops waiting for the browser and returns the
+context error once the stream's context is done.
+*/
+func (stream *StreamReader) Close() error {
+	stream.mux.Lock()
+	if stream.closed {
+		stream.mux.Unlock()
+		return nil
+	}
+	stream.closed = true
+	stream.mux.Unlock()
+
+	result := <-socket.WithContext(stream.ctx, stream.socket).IO().Close(&cdtpio.CloseParams{Handle: stream.handle})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.StreamReadFailed, fmt.Sprintf("could not close stream '%s'", stream.handle))
+	}
+	return nil
+}
+
+/*
+Handle returns the stream handle.
+*/
+func (stream *StreamReader) Handle() cdtpio.StreamHandle {
+	return stream.handle
+}
+
+/*
+Read implements io.Reader.
+*/
+func (stream *StreamReader) Read(p []byte) (int, error) {
+	for 0 == len(stream.buf) {
+		if stream.eof {
+			return 0, io.EOF
+		}
+		if err := stream.fill(); nil != err {
+			return 0, err
+		}
+	}
+	n := copy(p, stream.buf)
+	stream.buf = stream.buf[n:]
+	return n, nil
+}
+
+/*
+fill reads the next chunk of the stream into the buffer.
+*/
+func (stream *StreamReader) fill() error {
+	stream.mux.Lock()
+	closed := stream.closed
+	stream.mux.Unlock()
+	if closed {
+		return errs.New(codes.StreamClosed, fmt.Sprintf("stream '%s' is closed", stream.handle))
+	}
+	if err := stream.ctx.Err(); nil != err {
+		return err
+	}
+
+	chunk := <-socket.WithContext(stream.ctx, stream.socket).IO().Read(&cdtpio.ReadParams{
+		Handle: stream.handle,
+		Size:   streamReadSize,
+	})
+	if nil != chunk.Err {
+		if err := stream.ctx.Err(); nil != err {
+			return err
+		}
+		return errs.Wrap(chunk.Err, codes.StreamReadFailed, fmt.Sprintf("could not read stream '%s'", stream.handle))
+	}
+
+	stream.eof = chunk.EOF
+	stream.buf = []byte(chunk.Data)
+	if chunk.Base64Encoded {
+		data, err := base64.StdEncoding.DecodeString(chunk.Data)
+		if nil != err {
+			return errs.Wrap(err, codes.StreamDecodeFailed, fmt.Sprintf("could not decode stream '%s'", stream.handle))
+		}
+		stream.buf = data
+	}
+	return nil
+}
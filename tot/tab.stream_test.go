@@ -0,0 +1,86 @@
+package chrome
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	cdtpio "github.com/mkenney/go-chrome/tot/io"
+)
+
+func TestStreamReader(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestStreamReader")
+	mockSocket.RespondStream("first ", "", "second ", "third")
+
+	stream := tab.OpenStream(context.Background(), "stream-1")
+	buf := make([]byte, 4)
+	n, err := stream.Read(buf)
+	if nil != err || "firs" != string(buf[:n]) {
+		t.Errorf("Expected 'firs', received '%s' %v", buf[:n], err)
+	}
+	data, err := ioutil.ReadAll(stream)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "t second third" != string(data) {
+		t.Errorf("Expected 't second third', received '%s'", data)
+	}
+	if n, err := stream.Read(buf); 0 != n || io.EOF != err {
+		t.Errorf("Expected EOF, received %d %v", n, err)
+	}
+	if 4 != len(mockSocket.Commands("IO.read")) {
+		t.Errorf("Expected 4 reads, received %d", len(mockSocket.Commands("IO.read")))
+	}
+
+	if err := stream.Close(); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	stream.Close()
+	closes := mockSocket.Commands("IO.close")
+	if 1 != len(closes) || "stream-1" != closes[0].Params().(*cdtpio.CloseParams).Handle {
+		t.Errorf("Expected the stream to be closed once")
+	}
+}
+
+func TestStreamReaderClosed(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestStreamReaderClosed")
+	mockSocket.RespondStream("data")
+
+	stream := tab.OpenStream(context.Background(), "stream-1")
+	stream.Close()
+	if _, err := stream.Read(make([]byte, 4)); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 0 != len(mockSocket.Commands("IO.read")) {
+		t.Errorf("Expected a closed stream not to be read")
+	}
+}
+
+func TestStreamReaderContext(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestStreamReaderContext")
+	mockSocket.RespondStream("data")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := tab.OpenStream(ctx, "stream-1")
+	if _, err := stream.Read(make([]byte, 4)); context.Canceled != err {
+		t.Errorf("Expected context.Canceled, received %v", err)
+	}
+	if err := stream.Close(); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 0 != len(mockSocket.Commands("IO.close")) {
+		t.Errorf("Expected the stream not to be closed after the context is done")
+	}
+}
+
+func TestStreamReaderError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestStreamReaderError")
+	mockSocket.RespondStream()
+
+	stream := tab.OpenStream(context.Background(), "stream-1")
+	if _, err := stream.Read(make([]byte, 4)); nil == err || io.EOF == err {
+		t.Errorf("Expected error, received %v", err)
+	}
+}
@@ -3,11 +3,14 @@ package chrome
 import (
 	"fmt"
 	"net/url"
+	"sync"
 
 	errs "github.com/bdlm/errors"
 	"github.com/bdlm/log"
 	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/emulation"
 	"github.com/mkenney/go-chrome/tot/socket"
+	"github.com/mkenney/go-chrome/tot/target"
 )
 
 /*
@@ -30,11 +33,15 @@ func (chrome *Chrome) NewTab(uri string) (*Tab, error) {
 		url:    targetURL,
 	}
 
-	_, err = tab.Chromium().Query(
-		fmt.Sprintf("/json/new?%s", url.QueryEscape(uri)),
-		url.Values{},
-		tab.data,
-	)
+	if chrome.Deterministic() {
+		err = tab.createTarget(uri)
+	} else {
+		_, err = tab.Chromium().Query(
+			fmt.Sprintf("/json/new?%s", url.QueryEscape(uri)),
+			url.Values{},
+			tab.data,
+		)
+	}
 	if nil != err {
 		return nil, errs.Wrap(err, codes.TabQueryFailed, fmt.Sprintf("/new?%s query failed", url.QueryEscape(uri)))
 	}
@@ -52,15 +59,95 @@ func (chrome *Chrome) NewTab(uri string) (*Tab, error) {
 	return tab, nil
 }
 
+/*
+createTarget opens the tab with Target.createTarget on the browser target.
+BeginFrame control can only be enabled for a tab when it is created and
+/json/new doesn't accept it, so tabs of a browser in deterministic mode are
+created this way.
+*/
+func (tab *Tab) createTarget(uri string) error {
+	browser, err := browserSocket(tab.Chromium())
+	if nil != err {
+		return err
+	}
+	defer browser.Stop()
+
+	targetID, err := createTarget(browser, uri)
+	if nil != err {
+		return err
+	}
+	targets := []*TabData{}
+	if _, err := tab.Chromium().Query("/json/list", url.Values{}, &targets); nil != err {
+		return err
+	}
+	for _, data := range targets {
+		if string(targetID) == data.ID {
+			tab.data = data
+			return nil
+		}
+	}
+	return errs.New(codes.TabQueryFailed, fmt.Sprintf("target '%s' not found", targetID))
+}
+
+/*
+browserSocket opens a socket connection to the browser target.
+*/
+func browserSocket(chromium Chromium) (*socket.Socket, error) {
+	version, err := chromium.Version()
+	if nil != err {
+		return nil, err
+	}
+	browserURL, err := url.Parse(version.WebSocketDebuggerURL)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.TabWebsocketURLInvalid, fmt.Sprintf("invalid browser websocket URL '%s'", version.WebSocketDebuggerURL))
+	}
+	return socket.New(browserURL), nil
+}
+
+/*
+createTarget creates a page target with BeginFrame control enabled.
+*/
+func createTarget(browser socket.Protocoller, uri string) (target.ID, error) {
+	result := <-browser.Target().CreateTarget(&target.CreateTargetParams{
+		URL:                     uri,
+		EnableBeginFrameControl: true,
+	})
+	if nil != result.Err {
+		return "", errs.Wrap(result.Err, codes.TabQueryFailed, "Target.createTarget failed")
+	}
+	return result.ID, nil
+}
+
 /*
 Tab is a struct representing an individual Chrome tab
 */
 type Tab struct {
-	chrome   Chromium
-	data     *TabData
-	protocol socket.Protocoller
-	socket   socket.Socketer
-	url      *url.URL
+	beginFrames     *BeginFrames
+	beginFramesMux  sync.Mutex
+	bindings        *bindings
+	bindingsMux     sync.Mutex
+	chrome          Chromium
+	data            *TabData
+	emulated        *Device
+	emulatedMetrics *emulation.SetDeviceMetricsOverrideParams
+	emulatedMux     sync.Mutex
+	environment     *Environment
+	environmentMux  sync.Mutex
+	keyboard        *Keyboard
+	keyboardMux     sync.Mutex
+	mouse           *Mouse
+	mouseMux        sync.Mutex
+	objects         *ObjectRegistry
+	objectsMux      sync.Mutex
+	protocol        socket.Protocoller
+	socket          socket.Socketer
+	throttling      *Throttling
+	throttlingMux   sync.Mutex
+	touchscreen     *Touchscreen
+	touchscreenMux  sync.Mutex
+	url             *url.URL
+	virtualTime     *VirtualTime
+	virtualTimeMux  sync.Mutex
 }
 
 /*
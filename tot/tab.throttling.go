@@ -0,0 +1,399 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/network"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+NetworkProfile describes emulated network conditions. Throughput values are in
+bytes per second; -1 disables throttling in that direction.
+*/
+type NetworkProfile struct {
+	// The profile name.
+	Name string
+
+	// Optional. Emulate a disconnected network.
+	Offline bool
+
+	// Optional. Minimum latency from request sent to response headers
+	// received.
+	Latency time.Duration
+
+	// Maximum aggregated download and upload throughput in bytes per second.
+	DownloadThroughput float64
+	UploadThroughput   float64
+
+	// Optional. The connection type reported to the page.
+	ConnectionType network.ConnectionTypeEnum
+}
+
+/*
+Named network profiles. The 3G profiles match the DevTools presets.
+*/
+var (
+	NetworkNoThrottling = &NetworkProfile{
+		Name:               "No throttling",
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+	}
+	NetworkOffline = &NetworkProfile{
+		Name:               "Offline",
+		Offline:            true,
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+		ConnectionType:     network.ConnectionType.None,
+	}
+	NetworkSlow3G = &NetworkProfile{
+		Name:               "Slow 3G",
+		Latency:            2000 * time.Millisecond,
+		DownloadThroughput: 500 * 1024 * 0.8 / 8,
+		UploadThroughput:   500 * 1024 * 0.8 / 8,
+		ConnectionType:     network.ConnectionType.Cellular3g,
+	}
+	NetworkFast3G = &NetworkProfile{
+		Name:               "Fast 3G",
+		Latency:            562500 * time.Microsecond,
+		DownloadThroughput: 1.6 * 1024 * 1024 * 0.9 / 8,
+		UploadThroughput:   750 * 1024 * 0.9 / 8,
+		ConnectionType:     network.ConnectionType.Cellular3g,
+	}
+	Network4G = &NetworkProfile{
+		Name:               "4G",
+		Latency:            20 * time.Millisecond,
+		DownloadThroughput: 4 * 1024 * 1024 / 8,
+		UploadThroughput:   3 * 1024 * 1024 / 8,
+		ConnectionType:     network.ConnectionType.Cellular4g,
+	}
+)
+
+/*
+NetworkProfiles is the catalog of named network profiles.
+*/
+var NetworkProfiles = map[string]*NetworkProfile{
+	NetworkNoThrottling.Name: NetworkNoThrottling,
+	NetworkOffline.Name:      NetworkOffline,
+	NetworkSlow3G.Name:       NetworkSlow3G,
+	NetworkFast3G.Name:       NetworkFast3G,
+	Network4G.Name:           Network4G,
+}
+
+/*
+String implements Stringer.
+*/
+func (profile *NetworkProfile) String() string {
+	if nil == profile {
+		return NetworkNoThrottling.Name
+	}
+	if profile.Offline {
+		return profile.Name
+	}
+	return fmt.Sprintf(
+		"%s (latency %s, down %s, up %s)",
+		profile.Name,
+		profile.Latency,
+		formatThroughput(profile.DownloadThroughput),
+		formatThroughput(profile.UploadThroughput),
+	)
+}
+
+/*
+Conditions are the network and CPU throttling conditions of a tab.
+*/
+type Conditions struct {
+	// Optional. The network profile, nil disables network throttling.
+	Network *NetworkProfile
+
+	// Optional. The CPU slowdown factor, e.g. 4 for a 4x slowdown. Values
+	// below 1 disable CPU throttling.
+	CPURate float64
+}
+
+/*
+String implements Stringer.
+*/
+func (conditions Conditions) String() string {
+	cpu := "no CPU throttling"
+	if conditions.CPURate > 1 {
+		cpu = fmt.Sprintf("CPU %gx slowdown", conditions.CPURate)
+	}
+	return fmt.Sprintf("%s, %s", conditions.Network, cpu)
+}
+
+/*
+ThrottlingStep is a change of conditions in a throttling schedule.
+*/
+type ThrottlingStep struct {
+	// The time after the start of the schedule the conditions are applied.
+	After time.Duration
+
+	// The conditions to apply.
+	Conditions Conditions
+}
+
+/*
+ThrottlingChange records a change of conditions.
+*/
+type ThrottlingChange struct {
+	Time       time.Time
+	Conditions Conditions
+}
+
+/*
+Throttling applies network and CPU throttling to a tab and keeps a history of
+the conditions in effect so that they can be reported when a test fails.
+*/
+type Throttling struct {
+	cancel     context.CancelFunc
+	conditions Conditions
+	enabled    bool
+	history    []*ThrottlingChange
+	mux        sync.Mutex
+	tab        *Tab
+}
+
+/*
+Throttling returns the network and CPU throttling controller for this tab.
+
+	err := tab.Throttling().Apply(ctx, chrome.Conditions{
+		Network: chrome.NetworkSlow3G,
+		CPURate: 4,
+	})
+	...
+	if failed {
+		t.Logf("throttling: %s", tab.Throttling().Report())
+	}
+*/
+func (tab *Tab) Throttling() *Throttling {
+	tab.throttlingMux.Lock()
+	defer tab.throttlingMux.Unlock()
+	if nil == tab.throttling {
+		tab.throttling = &Throttling{
+			history: []*ThrottlingChange{},
+			tab:     tab,
+		}
+	}
+	return tab.throttling
+}
+
+/*
+Apply applies network and CPU throttling conditions and cancels any running
+schedule.
+*/
+func (throttling *Throttling) Apply(ctx context.Context, conditions Conditions) error {
+	throttling.mux.Lock()
+	if nil != throttling.cancel {
+		throttling.cancel()
+		throttling.cancel = nil
+	}
+	throttling.mux.Unlock()
+	return throttling.apply(ctx, conditions)
+}
+
+/*
+Conditions returns the conditions currently in effect.
+*/
+func (throttling *Throttling) Conditions() Conditions {
+	throttling.mux.Lock()
+	defer throttling.mux.Unlock()
+	return throttling.conditions
+}
+
+/*
+History returns every change of conditions in the order they were applied.
+*/
+func (throttling *Throttling) History() []*ThrottlingChange {
+	throttling.mux.Lock()
+	defer throttling.mux.Unlock()
+	return append(throttling.history[:0:0], throttling.history...)
+}
+
+/*
+Report describes the conditions in effect and the changes that led to them.
+*/
+func (throttling *Throttling) Report() string {
+	history := throttling.History()
+	report := []string{fmt.Sprintf("current conditions: %s", throttling.Conditions())}
+	if len(history) > 0 {
+		start := history[0].Time
+		for _, change := range history {
+			report = append(report, fmt.Sprintf("  +%s: %s", change.Time.Sub(start), change.Conditions))
+		}
+	}
+	return strings.Join(report, "\n")
+}
+
+/*
+Reset removes all throttling and cancels any running schedule.
+*/
+func (throttling *Throttling) Reset(ctx context.Context) error {
+	return throttling.Apply(ctx, Conditions{})
+}
+
+/*
+Schedule applies a sequence of conditions at offsets from now, replacing any
+running schedule. The returned channel receives an error if a step could not be
+applied, which stops the schedule, and is closed when the schedule completes,
+ctx is done or the schedule is replaced.
+
+	errc := tab.Throttling().Schedule(ctx,
+		chrome.ThrottlingStep{After: 0, Conditions: chrome.Conditions{Network: chrome.Network4G}},
+		chrome.ThrottlingStep{After: 5 * time.Second, Conditions: chrome.Conditions{Network: chrome.NetworkOffline}},
+	)
+*/
+func (throttling *Throttling) Schedule(ctx context.Context, steps ...ThrottlingStep) <-chan error {
+	errc := make(chan error, 1)
+	for _, step := range steps {
+		if err := step.Conditions.validate(); nil != err {
+			errc <- err
+			close(errc)
+			return errc
+		}
+	}
+
+	throttling.mux.Lock()
+	if nil != throttling.cancel {
+		throttling.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	throttling.cancel = cancel
+	throttling.mux.Unlock()
+
+	start := time.Now()
+	go func() {
+		defer close(errc)
+		defer cancel()
+		for _, step := range steps {
+			timer := time.NewTimer(time.Until(start.Add(step.After)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if err := throttling.apply(ctx, step.Conditions); nil != err {
+				if nil == ctx.Err() {
+					errc <- err
+				}
+				return
+			}
+		}
+	}()
+	return errc
+}
+
+/*
+apply sends the throttling commands and records the change.
+*/
+func (throttling *Throttling) apply(ctx context.Context, conditions Conditions) error {
+	if err := conditions.validate(); nil != err {
+		return err
+	}
+
+	throttling.mux.Lock()
+	defer throttling.mux.Unlock()
+
+	// A replaced schedule may be waiting for the lock.
+	if err := ctx.Err(); nil != err {
+		return err
+	}
+	protocol := throttling.tab.withContext(ctx)
+	if !throttling.enabled {
+		if result := <-protocol.Network().Enable(&network.EnableParams{}); nil != result.Err {
+			return errs.Wrap(result.Err, codes.EmulationFailed, "could not enable the network domain")
+		}
+		throttling.enabled = true
+	}
+
+	if err := emulateNetwork(protocol, conditions.Network); nil != err {
+		return err
+	}
+
+	rate := conditions.CPURate
+	if rate < 1 {
+		rate = 1
+	}
+	if result := <-protocol.Emulation().SetCPUThrottlingRate(&emulation.SetCPUThrottlingRateParams{
+		Rate: rate,
+	}); nil != result.Err {
+		// Restore the previous network conditions, or record the network
+		// conditions that remain applied.
+		if err := emulateNetwork(protocol, throttling.conditions.Network); nil != err {
+			throttling.record(Conditions{
+				Network: conditions.Network,
+				CPURate: throttling.conditions.CPURate,
+			})
+		}
+		return errs.Wrap(result.Err, codes.EmulationFailed, fmt.Sprintf("could not set CPU throttling rate %g", rate))
+	}
+
+	throttling.record(conditions)
+	return nil
+}
+
+/*
+record records applied conditions. The caller must hold the mutex.
+*/
+func (throttling *Throttling) record(conditions Conditions) {
+	throttling.conditions = conditions
+	throttling.history = append(throttling.history, &ThrottlingChange{
+		Time:       time.Now(),
+		Conditions: conditions,
+	})
+}
+
+/*
+emulateNetwork applies a network profile, nil disables network throttling.
+*/
+func emulateNetwork(protocol socket.Protocoller, profile *NetworkProfile) error {
+	if nil == profile {
+		profile = NetworkNoThrottling
+	}
+	if result := <-protocol.Network().EmulateConditions(&network.EmulateConditionsParams{
+		Offline:            profile.Offline,
+		Latency:            float64(profile.Latency) / float64(time.Millisecond),
+		DownloadThroughput: profile.DownloadThroughput,
+		UploadThroughput:   profile.UploadThroughput,
+		ConnectionType:     profile.ConnectionType,
+	}); nil != result.Err {
+		return errs.Wrap(result.Err, codes.EmulationFailed, fmt.Sprintf("could not emulate network conditions '%s'", profile.Name))
+	}
+	return nil
+}
+
+/*
+validate checks that the conditions can be applied.
+*/
+func (conditions Conditions) validate() error {
+	if conditions.CPURate < 0 {
+		return errs.New(codes.EmulationConditionsInvalid, fmt.Sprintf("invalid CPU throttling rate %g", conditions.CPURate))
+	}
+	if nil != conditions.Network && conditions.Network.Latency < 0 {
+		return errs.New(codes.EmulationConditionsInvalid, fmt.Sprintf("network profile '%s' has a negative latency", conditions.Network.Name))
+	}
+	return nil
+}
+
+/*
+formatThroughput formats a throughput in bytes per second.
+*/
+func formatThroughput(throughput float64) string {
+	switch {
+	case throughput < 0:
+		return "unlimited"
+	case throughput >= 1024*1024:
+		return fmt.Sprintf("%.1f MB/s", throughput/(1024*1024))
+	case throughput >= 1024:
+		return fmt.Sprintf("%.1f kB/s", throughput/1024)
+	}
+	return fmt.Sprintf("%.0f B/s", throughput)
+}
@@ -0,0 +1,152 @@
+package chrome
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/network"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestThrottlingApply(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestThrottlingApply")
+
+	if err := tab.Throttling().Apply(context.Background(), Conditions{
+		Network: NetworkSlow3G,
+		CPURate: 4,
+	}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params := mockSocket.Commands("Network.emulateNetworkConditions")[0].Params().(*network.EmulateConditionsParams)
+	if 2000 != params.Latency || 51200 != params.DownloadThroughput || network.ConnectionType.Cellular3g != params.ConnectionType {
+		t.Errorf("Unexpected network conditions %+v", params)
+	}
+	if 4 != mockSocket.Commands("Emulation.setCPUThrottlingRate")[0].Params().(*emulation.SetCPUThrottlingRateParams).Rate {
+		t.Errorf("Expected a 4x CPU slowdown")
+	}
+
+	if err := tab.Throttling().Reset(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params = mockSocket.Commands("Network.emulateNetworkConditions")[1].Params().(*network.EmulateConditionsParams)
+	if 0 != params.Latency || -1 != params.DownloadThroughput || -1 != params.UploadThroughput {
+		t.Errorf("Expected network throttling to be disabled, received %+v", params)
+	}
+	if 1 != mockSocket.Commands("Emulation.setCPUThrottlingRate")[1].Params().(*emulation.SetCPUThrottlingRateParams).Rate {
+		t.Errorf("Expected CPU throttling to be disabled")
+	}
+	if 1 != len(mockSocket.Commands("Network.enable")) {
+		t.Errorf("Expected the network domain to be enabled once")
+	}
+
+	if err := tab.Throttling().Apply(context.Background(), Conditions{CPURate: -1}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestThrottlingSchedule(t *testing.T) {
+	tab, _ := newMockTab(t, "https://TestThrottlingSchedule")
+
+	errc := tab.Throttling().Schedule(context.Background(),
+		ThrottlingStep{After: 0, Conditions: Conditions{Network: NetworkFast3G}},
+		ThrottlingStep{After: 20 * time.Millisecond, Conditions: Conditions{Network: NetworkOffline, CPURate: 2}},
+	)
+	for err := range errc {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+
+	conditions := tab.Throttling().Conditions()
+	if NetworkOffline != conditions.Network || 2 != conditions.CPURate {
+		t.Errorf("Expected offline with a 2x slowdown, received %s", conditions)
+	}
+	history := tab.Throttling().History()
+	if 2 != len(history) || NetworkFast3G != history[0].Conditions.Network {
+		t.Fatalf("Unexpected history %v", history)
+	}
+	if history[1].Time.Sub(history[0].Time) < 20*time.Millisecond {
+		t.Errorf("Expected the second step to be applied after 20ms")
+	}
+	report := tab.Throttling().Report()
+	if !strings.Contains(report, "current conditions: Offline, CPU 2x slowdown") || !strings.Contains(report, "Fast 3G (latency 562.5ms") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+}
+
+func TestThrottlingScheduleCancel(t *testing.T) {
+	tab, _ := newMockTab(t, "https://TestThrottlingScheduleCancel")
+
+	errc := tab.Throttling().Schedule(context.Background(),
+		ThrottlingStep{After: time.Hour, Conditions: Conditions{Network: NetworkOffline}},
+	)
+	if err := tab.Throttling().Apply(context.Background(), Conditions{Network: Network4G}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	for err := range errc {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if Network4G != tab.Throttling().Conditions().Network {
+		t.Errorf("Expected 4G, received %s", tab.Throttling().Conditions())
+	}
+}
+
+func TestThrottlingScheduleError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestThrottlingScheduleError")
+	mockSocket.Respond("Network.emulateNetworkConditions", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "error message"}
+	})
+
+	errc := tab.Throttling().Schedule(context.Background(),
+		ThrottlingStep{Conditions: Conditions{Network: NetworkSlow3G}},
+		ThrottlingStep{Conditions: Conditions{Network: NetworkFast3G}},
+	)
+	if err := <-errc; nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if _, ok := <-errc; ok {
+		t.Errorf("Expected the schedule to stop")
+	}
+	if 1 != len(mockSocket.Commands("Network.emulateNetworkConditions")) {
+		t.Errorf("Expected the schedule to stop after the first step")
+	}
+	if nil != tab.Throttling().Conditions().Network {
+		t.Errorf("Expected no conditions to be applied")
+	}
+}
+
+func TestThrottlingApplyCPUError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestThrottlingApplyCPUError")
+	if err := tab.Throttling().Apply(context.Background(), Conditions{Network: NetworkFast3G}); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	mockSocket.Respond("Emulation.setCPUThrottlingRate", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "error message"}
+	})
+
+	if err := tab.Throttling().Apply(context.Background(), Conditions{Network: NetworkSlow3G, CPURate: 4}); nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	emulated := mockSocket.Commands("Network.emulateNetworkConditions")
+	if 3 != len(emulated) || NetworkFast3G.DownloadThroughput != emulated[2].Params().(*network.EmulateConditionsParams).DownloadThroughput {
+		t.Errorf("Expected the previous network conditions to be restored")
+	}
+	if NetworkFast3G != tab.Throttling().Conditions().Network {
+		t.Errorf("Expected the previous conditions, received %s", tab.Throttling().Conditions())
+	}
+
+	// The network conditions that can't be restored are recorded.
+	mockSocket.Respond("Network.emulateNetworkConditions", func(params interface{}) (interface{}, *socket.Error) {
+		if NetworkFast3G.DownloadThroughput == params.(*network.EmulateConditionsParams).DownloadThroughput {
+			return nil, &socket.Error{Code: 1, Message: "error message"}
+		}
+		return nil, nil
+	})
+	if err := tab.Throttling().Apply(context.Background(), Conditions{Network: NetworkSlow3G, CPURate: 4}); nil == err {
+		t.Fatalf("Expected error, received nil")
+	}
+	if conditions := tab.Throttling().Conditions(); NetworkSlow3G != conditions.Network || 0 != conditions.CPURate {
+		t.Errorf("Expected the applied network conditions, received %s", conditions)
+	}
+}
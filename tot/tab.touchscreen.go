@@ -0,0 +1,101 @@
+package chrome
+
+import (
+	"fmt"
+	"math"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/input"
+)
+
+/*
+Touchscreen provides a high-level interface for sending touch input and
+gestures to a tab.
+*/
+type Touchscreen struct {
+	tab *Tab
+}
+
+/*
+Touchscreen returns the Touchscreen instance for this tab.
+*/
+func (tab *Tab) Touchscreen() *Touchscreen {
+	tab.touchscreenMux.Lock()
+	defer tab.touchscreenMux.Unlock()
+	if nil == tab.touchscreen {
+		tab.touchscreen = &Touchscreen{tab: tab}
+	}
+	return tab.touchscreen
+}
+
+/*
+Pinch synthesizes a pinch gesture centered on the specified coordinates. A
+scaleFactor greater than 1.0 zooms in, less than 1.0 zooms out. speed is the
+relative pointer speed in pixels per second; 0 uses the browser default.
+*/
+func (touchscreen *Touchscreen) Pinch(x, y int, scaleFactor float64, speed int) error {
+	result := <-touchscreen.tab.Input().SynthesizePinchGesture(&input.SynthesizePinchGestureParams{
+		X:                 float64(x),
+		Y:                 float64(y),
+		ScaleFactor:       scaleFactor,
+		RelativeSpeed:     speed,
+		GestureSourceType: input.GestureSourceType("touch"),
+	})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.InputDispatchFailed, fmt.Sprintf("pinch gesture at %d,%d failed", x, y))
+	}
+	return nil
+}
+
+/*
+Swipe touches the start coordinates, moves the touch point to the end
+coordinates in the specified number of steps and lifts it.
+*/
+func (touchscreen *Touchscreen) Swipe(fromX, fromY, toX, toY, steps int) error {
+	if steps < 1 {
+		steps = 1
+	}
+	if err := touchscreen.dispatch(input.TouchEvent.TouchStart, fromX, fromY); nil != err {
+		return err
+	}
+	for step := 1; step <= steps; step++ {
+		x := fromX + int(math.Floor(float64(toX-fromX)*float64(step)/float64(steps)+0.5))
+		y := fromY + int(math.Floor(float64(toY-fromY)*float64(step)/float64(steps)+0.5))
+		if err := touchscreen.dispatch(input.TouchEvent.TouchMove, x, y); nil != err {
+			touchscreen.dispatch(input.TouchEvent.TouchCancel, x, y)
+			return err
+		}
+	}
+	return touchscreen.dispatch(input.TouchEvent.TouchEnd, toX, toY)
+}
+
+/*
+Tap touches and lifts a single touch point at the specified coordinates.
+*/
+func (touchscreen *Touchscreen) Tap(x, y int) error {
+	if err := touchscreen.dispatch(input.TouchEvent.TouchStart, x, y); nil != err {
+		return err
+	}
+	return touchscreen.dispatch(input.TouchEvent.TouchEnd, x, y)
+}
+
+/*
+dispatch sends a single touch event to the tab. TouchEnd and TouchCancel events
+are sent without touch points as required by the protocol.
+*/
+func (touchscreen *Touchscreen) dispatch(eventType input.TouchEventEnum, x, y int) error {
+	params := &input.DispatchTouchEventParams{
+		Type:        eventType,
+		TouchPoints: []*input.TouchPoint{},
+		Modifiers:   touchscreen.tab.Keyboard().Modifiers(),
+	}
+	if input.TouchEvent.TouchStart == eventType || input.TouchEvent.TouchMove == eventType {
+		params.TouchPoints = append(params.TouchPoints, &input.TouchPoint{X: x, Y: y})
+	}
+	result := <-touchscreen.tab.Input().DispatchTouchEvent(params)
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.InputDispatchFailed, fmt.Sprintf("%s event at %d,%d failed", eventType, x, y))
+	}
+	return nil
+}
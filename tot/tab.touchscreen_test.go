@@ -0,0 +1,66 @@
+package chrome
+
+import (
+	"testing"
+
+	"github.com/mkenney/go-chrome/tot/input"
+)
+
+func touchEvents(mockSocket *MockSocket) []*input.DispatchTouchEventParams {
+	events := []*input.DispatchTouchEventParams{}
+	for _, command := range mockSocket.Commands("Input.dispatchTouchEvent") {
+		events = append(events, command.Params().(*input.DispatchTouchEventParams))
+	}
+	return events
+}
+
+func TestTouchscreenTap(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTouchscreenTap")
+
+	if err := tab.Touchscreen().Tap(10, 20); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := touchEvents(mockSocket)
+	if 2 != len(events) {
+		t.Fatalf("Expected 2 touch events, received %d", len(events))
+	}
+	if input.TouchEvent.TouchStart != events[0].Type || 1 != len(events[0].TouchPoints) {
+		t.Errorf("Expected touchStart with one point, received %+v", events[0])
+	}
+	if input.TouchEvent.TouchEnd != events[1].Type || 0 != len(events[1].TouchPoints) {
+		t.Errorf("Expected touchEnd without points, received %+v", events[1])
+	}
+}
+
+func TestTouchscreenSwipe(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTouchscreenSwipe")
+
+	if err := tab.Touchscreen().Swipe(0, 300, 0, 100, 2); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	events := touchEvents(mockSocket)
+	if 4 != len(events) {
+		t.Fatalf("Expected 4 touch events, received %d", len(events))
+	}
+	if input.TouchEvent.TouchMove != events[1].Type || 200 != events[1].TouchPoints[0].Y {
+		t.Errorf("Expected touchMove to 0,200, received %+v", events[1])
+	}
+	if input.TouchEvent.TouchEnd != events[3].Type {
+		t.Errorf("Expected touchEnd, received %s", events[3].Type)
+	}
+}
+
+func TestTouchscreenPinch(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTouchscreenPinch")
+
+	if err := tab.Touchscreen().Pinch(50, 50, 0.5, 0); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	commands := mockSocket.Commands("Input.synthesizePinchGesture")
+	if 1 != len(commands) {
+		t.Fatalf("Expected 1 pinch gesture, received %d", len(commands))
+	}
+	if 0.5 != commands[0].Params().(*input.SynthesizePinchGestureParams).ScaleFactor {
+		t.Errorf("Expected scale factor 0.5, received %v", commands[0].Params().(*input.SynthesizePinchGestureParams).ScaleFactor)
+	}
+}
@@ -0,0 +1,523 @@
+package chrome
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/bdlm/log"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/socket"
+	"github.com/mkenney/go-chrome/tot/tracing"
+)
+
+/*
+TracePreset is a named set of trace categories.
+*/
+type TracePreset struct {
+	// The preset name.
+	Name string
+
+	// The trace categories to record.
+	Categories []string
+
+	// Optional. Record a detailed memory dump at this interval, requires the
+	// disabled-by-default-memory-infra category.
+	MemoryDumpInterval time.Duration
+}
+
+/*
+Trace category presets. TracePageLoad records the categories used by the
+DevTools performance panel.
+*/
+var (
+	TracePageLoad = &TracePreset{
+		Name: "page load",
+		Categories: []string{
+			"blink.console",
+			"blink.user_timing",
+			"devtools.timeline",
+			"disabled-by-default-devtools.timeline",
+			"disabled-by-default-devtools.timeline.frame",
+			"latencyInfo",
+			"loading",
+			"navigation",
+			"netlog",
+			"toplevel",
+			"v8.execute",
+		},
+	}
+	TraceRendering = &TracePreset{
+		Name: "rendering",
+		Categories: []string{
+			"benchmark",
+			"blink",
+			"cc",
+			"devtools.timeline",
+			"disabled-by-default-devtools.timeline",
+			"disabled-by-default-devtools.timeline.frame",
+			"disabled-by-default-devtools.timeline.layers",
+			"disabled-by-default-devtools.timeline.paint",
+			"gpu",
+			"latencyInfo",
+			"toplevel",
+			"viz",
+		},
+	}
+	TraceJavaScript = &TracePreset{
+		Name: "javascript",
+		Categories: []string{
+			"blink.user_timing",
+			"devtools.timeline",
+			"disabled-by-default-devtools.timeline",
+			"disabled-by-default-devtools.timeline.stack",
+			"disabled-by-default-v8.cpu_profiler",
+			"toplevel",
+			"v8",
+			"v8.execute",
+		},
+	}
+	TraceMemory = &TracePreset{
+		Name: "memory-infra",
+		Categories: []string{
+			"blink.console",
+			"disabled-by-default-devtools.timeline",
+			"disabled-by-default-memory-infra",
+			"toplevel",
+			"v8",
+		},
+		MemoryDumpInterval: time.Second,
+	}
+)
+
+/*
+TracePresets is the catalog of trace category presets.
+*/
+var TracePresets = map[string]*TracePreset{
+	TracePageLoad.Name:   TracePageLoad,
+	TraceRendering.Name:  TraceRendering,
+	TraceJavaScript.Name: TraceJavaScript,
+	TraceMemory.Name:     TraceMemory,
+}
+
+/*
+traceDataEvent is the event that delivers the trace events of a trace recorded
+with events.
+*/
+const traceDataEvent = "Tracing.dataCollected"
+
+/*
+TraceOptions configures a trace. The zero value records the TracePageLoad
+categories to a stream.
+*/
+type TraceOptions struct {
+	// Optional. The category presets to record, defaults to TracePageLoad.
+	Presets []*TracePreset
+
+	// Optional. Additional categories to record. Categories prefixed with "-"
+	// are excluded.
+	Categories []string
+
+	// Optional. Receive the trace in Tracing.dataCollected events instead of
+	// reading it from a stream after tracing ends. Events are written in the
+	// order they were received as they arrive.
+	Events bool
+
+	// Optional. Compress the trace with gzip.
+	Gzip bool
+}
+
+/*
+Trace is a running trace. The socket numbers the Tracing.dataCollected events,
+the events of a trace follow the events read before tracing started and the
+tracingComplete event counts the events read before it.
+*/
+type Trace struct {
+	browser   bool
+	closer    io.Closer
+	collected chan struct{}
+	complete  chan *socket.Response
+	completed bool
+	events    int
+	finished  bool
+	handlers  []socket.EventHandler
+	last      int
+	mux       sync.Mutex
+	next      int
+	opened    bool
+	opts      *TraceOptions
+	pending   map[int][]map[string]interface{}
+	socket    socket.Socketer
+	stopped   bool
+	w         io.Writer
+	writeErr  error
+	writeMux  sync.Mutex
+}
+
+/*
+StartTrace starts recording a trace that is written to w in the Trace Event
+Format when the trace is stopped. The file can be loaded in the DevTools
+performance panel.
+
+	file, err := os.Create("trace.json.gz")
+	...
+	trace, err := tab.StartTrace(ctx, file, &chrome.TraceOptions{
+		Presets: []*chrome.TracePreset{chrome.TracePageLoad, chrome.TraceJavaScript},
+		Gzip:    true,
+	})
+	...
+	err = trace.Stop(ctx)
+*/
+func (tab *Tab) StartTrace(ctx context.Context, w io.Writer, opts *TraceOptions) (*Trace, error) {
+	return startTrace(ctx, tab.Socket(), w, opts)
+}
+
+/*
+StartTrace starts recording a trace on the browser target. Unlike a tab trace
+it doesn't depend on a tab, tabs can be opened and closed while it records.
+The trace keeps its own connection to the browser target, which is closed when
+the trace stops.
+*/
+func (chrome *Chrome) StartTrace(ctx context.Context, w io.Writer, opts *TraceOptions) (*Trace, error) {
+	browser, err := browserSocket(chrome)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.TracingFailed, "could not connect to the browser target")
+	}
+	trace, err := startTrace(ctx, browser, w, opts)
+	if nil != err {
+		browser.Stop()
+		return nil, err
+	}
+	trace.browser = true
+	return trace, nil
+}
+
+/*
+startTrace starts recording a trace on the target connected to conn.
+*/
+func startTrace(ctx context.Context, conn socket.Socketer, w io.Writer, opts *TraceOptions) (*Trace, error) {
+	if nil == opts {
+		opts = &TraceOptions{}
+	}
+	trace := &Trace{
+		collected: make(chan struct{}),
+		complete:  make(chan *socket.Response, 1),
+		opts:      opts,
+		pending:   map[int][]map[string]interface{}{},
+		socket:    conn,
+		w:         w,
+	}
+	if opts.Gzip {
+		gz := gzip.NewWriter(w)
+		trace.w, trace.closer = gz, gz
+	}
+
+	transferMode := tracing.TransferMode.ReturnAsStream
+	if opts.Events {
+		transferMode = tracing.TransferMode.ReportEvents
+	}
+	started, err := sendCommand(ctx, conn, "Tracing.start", &tracing.StartParams{
+		TransferMode: transferMode,
+		TraceConfig:  opts.config(),
+	})
+	if nil != err {
+		return nil, errs.Wrap(err, codes.TracingFailed, "could not start tracing")
+	}
+	trace.next = started.EventCounts[traceDataEvent] + 1
+
+	// The browser sends the trace after tracing ends, the handlers are added
+	// once tracing has started.
+	trace.handlers = []socket.EventHandler{
+		socket.NewEventHandler("Tracing.tracingComplete", func(response *socket.Response) {
+			select {
+			case trace.complete <- response:
+			default:
+			}
+		}),
+	}
+	if opts.Events {
+		trace.handlers = append(trace.handlers, socket.NewEventHandler(traceDataEvent, func(response *socket.Response) {
+			event := &tracing.DataCollectedEvent{}
+			if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+				log.Errorf("invalid dataCollected event: %s", err)
+			}
+			trace.collect(response.EventCounts[traceDataEvent], event.Value)
+		}))
+	}
+	for _, handler := range trace.handlers {
+		conn.AddEventHandler(handler)
+	}
+	return trace, nil
+}
+
+/*
+Events returns the number of trace events written so far. Traces read from a
+stream report 0.
+*/
+func (trace *Trace) Events() int {
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	return trace.events
+}
+
+/*
+MemoryDump requests a global memory dump, recorded in traces that include the
+disabled-by-default-memory-infra category.
+*/
+func (trace *Trace) MemoryDump(ctx context.Context) error {
+	result := <-socket.WithContext(ctx, trace.socket).Tracing().RequestMemoryDump()
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.TracingFailed, "could not request a memory dump")
+	}
+	if !result.Success {
+		return errs.New(codes.TracingFailed, fmt.Sprintf("memory dump %s failed", result.DumpGUID))
+	}
+	return nil
+}
+
+/*
+Stop stops tracing, waits for the browser to flush the trace and finishes
+writing it. The writer is not closed.
+*/
+func (trace *Trace) Stop(ctx context.Context) error {
+	trace.mux.Lock()
+	defer trace.mux.Unlock()
+	if trace.stopped {
+		return errs.New(codes.TracingFailed, "the trace is stopped")
+	}
+	trace.stopped = true
+	defer trace.removeHandlers()
+	if trace.browser {
+		defer trace.socket.Stop()
+	}
+
+	if result := <-socket.WithContext(ctx, trace.socket).Tracing().End(); nil != result.Err {
+		return errs.Wrap(result.Err, codes.TracingFailed, "could not end tracing")
+	}
+	var response *socket.Response
+	select {
+	case response = <-trace.complete:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	event := &tracing.CompleteEvent{}
+	if err := json.Unmarshal([]byte(response.Params), event); nil != err {
+		return errs.Wrap(err, codes.TracingFailed, "invalid tracingComplete event")
+	}
+
+	if trace.opts.Events {
+		if err := trace.wait(ctx, response.EventCounts[traceDataEvent]); nil != err {
+			return err
+		}
+	} else {
+		if "" == event.Stream {
+			return errs.New(codes.TracingFailed, "tracing completed without a stream")
+		}
+		stream := openStream(ctx, trace.socket, event.Stream)
+		_, err := io.Copy(writerFunc(trace.write), stream)
+		stream.Close()
+		if nil != err {
+			return errs.Wrap(err, codes.TracingWriteFailed, "could not read the trace stream")
+		}
+	}
+
+	if nil != trace.closer {
+		if err := trace.closer.Close(); nil != err {
+			trace.fail(err)
+		}
+	}
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	if nil != trace.writeErr {
+		return errs.Wrap(trace.writeErr, codes.TracingWriteFailed, "could not write the trace")
+	}
+	return nil
+}
+
+/*
+collect writes the trace events of the Tracing.dataCollected event with the
+given number and of the events after it that have arrived, or keeps them until
+the events before it arrive.
+*/
+func (trace *Trace) collect(number int, events []map[string]interface{}) {
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	if trace.finished || number < trace.next {
+		return
+	}
+	trace.pending[number] = events
+	for {
+		events, ok := trace.pending[trace.next]
+		if !ok {
+			break
+		}
+		delete(trace.pending, trace.next)
+		trace.next++
+		trace.writeEvents(events)
+	}
+	if trace.completed && trace.next > trace.last {
+		trace.completed = false
+		close(trace.collected)
+	}
+}
+
+/*
+config returns the trace config for the options.
+*/
+func (opts *TraceOptions) config() *tracing.TraceConfig {
+	presets := opts.Presets
+	if 0 == len(presets) {
+		presets = []*TracePreset{TracePageLoad}
+	}
+	config := &tracing.TraceConfig{RecordMode: tracing.RecordMode.RecordAsMuchAsPossible}
+	seen := map[string]bool{}
+	add := func(category string) {
+		if seen[category] {
+			return
+		}
+		seen[category] = true
+		if strings.HasPrefix(category, "-") {
+			config.ExcludedCategories = append(config.ExcludedCategories, category[1:])
+			return
+		}
+		config.IncludedCategories = append(config.IncludedCategories, category)
+	}
+
+	var interval time.Duration
+	for _, preset := range presets {
+		for _, category := range preset.Categories {
+			add(category)
+		}
+		if preset.MemoryDumpInterval > 0 && (0 == interval || preset.MemoryDumpInterval < interval) {
+			interval = preset.MemoryDumpInterval
+		}
+	}
+	for _, category := range opts.Categories {
+		add(category)
+	}
+	if interval > 0 {
+		config.MemoryDumpConfig = tracing.MemoryDumpConfig{
+			"triggers": []map[string]interface{}{{
+				"mode":                 "detailed",
+				"periodic_interval_ms": int64(interval / time.Millisecond),
+			}},
+		}
+	}
+	return config
+}
+
+/*
+fail records the first write error.
+*/
+func (trace *Trace) fail(err error) {
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	if nil == trace.writeErr {
+		trace.writeErr = err
+	}
+}
+
+/*
+removeHandlers removes the trace event handlers.
+*/
+func (trace *Trace) removeHandlers() {
+	for _, handler := range trace.handlers {
+		trace.socket.RemoveEventHandler(handler)
+	}
+}
+
+/*
+wait waits until the trace events of the Tracing.dataCollected events up to the
+given number are written and finishes the list of trace events.
+*/
+func (trace *Trace) wait(ctx context.Context, last int) error {
+	trace.writeMux.Lock()
+	if trace.next > last {
+		close(trace.collected)
+	} else {
+		trace.completed, trace.last = true, last
+	}
+	trace.writeMux.Unlock()
+
+	var err error
+	select {
+	case <-trace.collected:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	trace.finished = true
+	if nil == err {
+		trace.writeEvents(nil)
+		trace.writeData([]byte("]}\n"))
+	}
+	return err
+}
+
+/*
+write writes data to the trace output. Write errors are recorded and reported
+by Stop.
+*/
+func (trace *Trace) write(data []byte) (int, error) {
+	trace.writeMux.Lock()
+	defer trace.writeMux.Unlock()
+	if !trace.writeData(data) {
+		return 0, trace.writeErr
+	}
+	return len(data), nil
+}
+
+/*
+writeData writes data to the trace output unless a write failed and returns
+whether it was written. The caller must hold the write lock.
+*/
+func (trace *Trace) writeData(data []byte) bool {
+	if nil == trace.writeErr {
+		_, trace.writeErr = trace.w.Write(data)
+	}
+	return nil == trace.writeErr
+}
+
+/*
+writeEvents writes trace events to the list of trace events, which is opened
+by the first write. The caller must hold the write lock.
+*/
+func (trace *Trace) writeEvents(events []map[string]interface{}) {
+	if !trace.opened {
+		trace.opened = true
+		trace.writeData([]byte(`{"traceEvents":[`))
+	}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if nil != err {
+			log.Errorf("invalid trace event: %s", err)
+			continue
+		}
+		if trace.events > 0 && !trace.writeData([]byte(",\n")) {
+			return
+		}
+		if !trace.writeData(data) {
+			return
+		}
+		trace.events++
+	}
+}
+
+/*
+writerFunc adapts a function to io.Writer.
+*/
+type writerFunc func([]byte) (int, error)
+
+/*
+Write implements io.Writer.
+*/
+func (fn writerFunc) Write(data []byte) (int, error) {
+	return fn(data)
+}
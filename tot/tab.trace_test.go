@@ -0,0 +1,177 @@
+package chrome
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/socket"
+	"github.com/mkenney/go-chrome/tot/tracing"
+)
+
+func TestTraceStream(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTraceStream")
+	mockSocket.Respond("Tracing.end", func(params interface{}) (interface{}, *socket.Error) {
+		mockSocket.Fire("Tracing.tracingComplete", &tracing.CompleteEvent{Stream: "trace-1"})
+		return nil, nil
+	})
+	mockSocket.RespondStream(`{"traceEvents":[`, `{"name":"a","ts":1}`, `]}`)
+
+	buf := &bytes.Buffer{}
+	trace, err := tab.StartTrace(context.Background(), buf, &TraceOptions{
+		Presets:    []*TracePreset{TracePageLoad, TraceMemory},
+		Categories: []string{"custom", "-excluded"},
+		Gzip:       true,
+	})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	params := mockSocket.Commands("Tracing.start")[0].Params().(*tracing.StartParams)
+	if tracing.TransferMode.ReturnAsStream != params.TransferMode {
+		t.Errorf("Expected the trace to be returned as a stream, received %s", params.TransferMode)
+	}
+	config := params.TraceConfig
+	included := map[string]bool{}
+	for _, category := range config.IncludedCategories {
+		if included[category] {
+			t.Errorf("Expected category '%s' to be included once", category)
+		}
+		included[category] = true
+	}
+	if !included["devtools.timeline"] || !included["disabled-by-default-memory-infra"] || !included["custom"] {
+		t.Errorf("Unexpected categories %v", config.IncludedCategories)
+	}
+	if 1 != len(config.ExcludedCategories) || "excluded" != config.ExcludedCategories[0] {
+		t.Errorf("Expected the 'excluded' category to be excluded, received %v", config.ExcludedCategories)
+	}
+	if triggers, ok := config.MemoryDumpConfig["triggers"].([]map[string]interface{}); !ok || int64(1000) != triggers[0]["periodic_interval_ms"] {
+		t.Errorf("Expected memory dumps every second")
+	}
+
+	if err := trace.Stop(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	reader, err := gzip.NewReader(buf)
+	if nil != err {
+		t.Fatalf("Expected gzip data, received error: %v", err)
+	}
+	data, _ := ioutil.ReadAll(reader)
+	if `{"traceEvents":[{"name":"a","ts":1}]}` != string(data) {
+		t.Errorf("Unexpected trace %s", data)
+	}
+	if 1 != len(mockSocket.Commands("IO.close")) {
+		t.Errorf("Expected the trace stream to be closed")
+	}
+	if err := trace.Stop(context.Background()); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestTraceEvents(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTraceEvents")
+	mockSocket.Concurrent()
+	collected := func(value string) *socket.Response {
+		return mockEvent("Tracing.dataCollected", json.RawMessage(`{"value":`+value+`}`))
+	}
+	mockSocket.RespondEvents("Tracing.end",
+		collected(`[{"name":"a","ts":1},{"name":"b","args":{"data":{"frame":"F1"}}}]`),
+		collected(`[{"name":"c","ts":3}]`),
+		mockEvent("Tracing.tracingComplete", &tracing.CompleteEvent{}),
+	)
+
+	buf := &bytes.Buffer{}
+	trace, err := tab.StartTrace(context.Background(), buf, &TraceOptions{Events: true})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if tracing.TransferMode.ReportEvents != mockSocket.Commands("Tracing.start")[0].Params().(*tracing.StartParams).TransferMode {
+		t.Errorf("Expected the trace to be reported in events")
+	}
+
+	// Handlers run concurrently, events are written in the order they were
+	// read from the socket and Stop waits for the events read before tracing
+	// completed.
+	if err := trace.Stop(context.Background()); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	result := struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &result); nil != err {
+		t.Fatalf("Expected a Trace Event Format file, received error: %v\n%s", err, buf.Bytes())
+	}
+	if 3 != len(result.TraceEvents) || "a" != result.TraceEvents[0]["name"] || "c" != result.TraceEvents[2]["name"] {
+		t.Errorf("Expected 3 trace events in order, received %s", buf.Bytes())
+	}
+
+	mockSocket.Fire("Tracing.dataCollected", json.RawMessage(`{"value":[{"name":"late"}]}`))
+	mockSocket.Wait()
+	if 3 != trace.Events() {
+		t.Errorf("Expected no events after the trace was written, received %d", trace.Events())
+	}
+}
+
+func TestTraceEventsMissing(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTraceEventsMissing")
+	mockSocket.Respond("Tracing.end", func(params interface{}) (interface{}, *socket.Error) {
+		mockSocket.Event("Tracing.dataCollected", json.RawMessage(`{"value":[{"name":"a"}]}`))
+		mockSocket.Fire("Tracing.dataCollected", json.RawMessage(`{"value":[{"name":"b"}]}`))
+		mockSocket.Fire("Tracing.tracingComplete", &tracing.CompleteEvent{})
+		return nil, nil
+	})
+
+	buf := &bytes.Buffer{}
+	trace, err := tab.StartTrace(context.Background(), buf, &TraceOptions{Events: true})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+
+	// Stop waits for the first events, which never arrive.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := trace.Stop(ctx); context.DeadlineExceeded != err {
+		t.Errorf("Expected the context error, received %v", err)
+	}
+	if 0 != buf.Len() {
+		t.Errorf("Expected events not to be written before the missing events, received %s", buf.Bytes())
+	}
+}
+
+func TestTraceStartError(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestTraceStartError")
+	mockSocket.Respond("Tracing.start", func(params interface{}) (interface{}, *socket.Error) {
+		return nil, &socket.Error{Code: 1, Message: "Tracing is already started"}
+	})
+
+	buf := &bytes.Buffer{}
+	if _, err := tab.StartTrace(context.Background(), buf, &TraceOptions{Events: true, Gzip: true}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+	if 0 != buf.Len() {
+		t.Errorf("Expected nothing to be written, received %q", buf.Bytes())
+	}
+}
+
+func TestChromeStartTraceError(t *testing.T) {
+	chrome := New(
+		&Flags{
+			"addr": "devnul",
+			"port": 9222,
+		},
+		"", //"path/to/chrome",
+		"", //"path/to/stderr",
+		"", //"path/to/stdout",
+		"", //"path/to/workdir",
+	)
+	if _, err := chrome.StartTrace(context.Background(), &bytes.Buffer{}, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	} else if codes.TracingFailed != err.(*errs.Err).Code() {
+		t.Errorf("Expected code %d, received %d", codes.TracingFailed, err.(*errs.Err).Code())
+	}
+}
@@ -0,0 +1,221 @@
+package chrome
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+/*
+VirtualTime controls a tab's virtual time. While virtual time is paused timers,
+animations and requestAnimationFrame callbacks don't run; time only moves
+forward by the budgets passed to Advance, and never while resource fetches are
+pending. Pages rendered this way are deterministic regardless of how long the
+browser takes to load and paint them.
+*/
+type VirtualTime struct {
+	elapsed time.Duration
+	expired chan struct{}
+	handler socket.EventHandler
+	mux     sync.Mutex
+	paused  bool
+	tab     *Tab
+}
+
+/*
+VirtualTime returns the virtual time controller for this tab.
+
+	vt := tab.VirtualTime()
+	err := vt.Navigate(ctx, "https://example.com/", 500*time.Millisecond)
+	...
+	frame, err := vt.Snapshot(ctx, 2*time.Second, nil)
+*/
+func (tab *Tab) VirtualTime() *VirtualTime {
+	tab.virtualTimeMux.Lock()
+	defer tab.virtualTimeMux.Unlock()
+	if nil == tab.virtualTime {
+		tab.virtualTime = &VirtualTime{
+			expired: make(chan struct{}, 1),
+			tab:     tab,
+		}
+	}
+	return tab.virtualTime
+}
+
+/*
+Advance lets virtual time run for budget and waits until the budget expires.
+Virtual time doesn't advance while resource fetches are pending, so the call
+also waits for the page to finish loading the resources it requests.
+*/
+func (vt *VirtualTime) Advance(ctx context.Context, budget time.Duration) error {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+	return vt.advance(ctx, budget)
+}
+
+/*
+AdvanceTo advances virtual time to a point in time relative to when it was
+first paused.
+*/
+func (vt *VirtualTime) AdvanceTo(ctx context.Context, at time.Duration) error {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+	return vt.advanceTo(ctx, at)
+}
+
+/*
+Elapsed returns the amount of virtual time that has elapsed since virtual time
+was first paused.
+*/
+func (vt *VirtualTime) Elapsed() time.Duration {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+	return vt.elapsed
+}
+
+/*
+Navigate pauses virtual time, navigates to url and advances virtual time by
+budget, which waits for the page and its resources to load.
+*/
+func (vt *VirtualTime) Navigate(ctx context.Context, url string, budget time.Duration) error {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+
+	if err := vt.pause(ctx); nil != err {
+		return err
+	}
+	result := <-vt.tab.withContext(ctx).Page().Navigate(&page.NavigateParams{URL: url})
+	if nil != result.Err {
+		return errs.Wrap(result.Err, codes.EmulationFailed, fmt.Sprintf("could not navigate to '%s'", url))
+	}
+	if "" != result.ErrorText {
+		return errs.New(codes.EmulationFailed, fmt.Sprintf("could not navigate to '%s': %s", url, result.ErrorText))
+	}
+	return vt.advance(ctx, budget)
+}
+
+/*
+Pause pauses virtual time. Pause before navigating so that the page's timers
+and animations start from a known state.
+*/
+func (vt *VirtualTime) Pause(ctx context.Context) error {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+	return vt.pause(ctx)
+}
+
+/*
+Resume lets virtual time run freely. Virtual time can't be disabled once
+enabled; it advances as fast as the page allows after Resume.
+*/
+func (vt *VirtualTime) Resume(ctx context.Context) error {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+
+	if err := vt.setPolicy(ctx, &emulation.SetVirtualTimePolicyParams{
+		Policy: emulation.VirtualTimePolicy.Advance,
+	}); nil != err {
+		return err
+	}
+	vt.paused = false
+	return nil
+}
+
+/*
+Snapshot advances virtual time to a point in time relative to when it was
+first paused and captures a screenshot.
+*/
+func (vt *VirtualTime) Snapshot(ctx context.Context, at time.Duration, opts *ScreenshotOptions) ([]byte, error) {
+	vt.mux.Lock()
+	defer vt.mux.Unlock()
+
+	if err := vt.advanceTo(ctx, at); nil != err {
+		return nil, err
+	}
+	return vt.tab.Screenshot(ctx, opts)
+}
+
+/*
+advance sets a budget and waits for the virtualTimeBudgetExpired event.
+*/
+func (vt *VirtualTime) advance(ctx context.Context, budget time.Duration) error {
+	if budget <= 0 {
+		return nil
+	}
+	if err := vt.pause(ctx); nil != err {
+		return err
+	}
+
+	// Discard an expiry left over from a cancelled advance.
+	select {
+	case <-vt.expired:
+	default:
+	}
+	if err := vt.setPolicy(ctx, &emulation.SetVirtualTimePolicyParams{
+		Policy: emulation.VirtualTimePolicy.PauseIfNetworkFetchesPending,
+		Budget: float64(budget) / float64(time.Millisecond),
+	}); nil != err {
+		return err
+	}
+	select {
+	case <-vt.expired:
+	case <-ctx.Done():
+		// The budget is still running, pause again before the next advance.
+		vt.paused = false
+		return ctx.Err()
+	}
+	vt.elapsed += budget
+	return nil
+}
+
+/*
+advanceTo advances virtual time to a point in time.
+*/
+func (vt *VirtualTime) advanceTo(ctx context.Context, at time.Duration) error {
+	if at < vt.elapsed {
+		return errs.New(codes.EmulationVirtualTimeInvalid, fmt.Sprintf("virtual time %s has passed, %s has elapsed", at, vt.elapsed))
+	}
+	return vt.advance(ctx, at-vt.elapsed)
+}
+
+/*
+pause registers the budget expiry handler and pauses virtual time.
+*/
+func (vt *VirtualTime) pause(ctx context.Context) error {
+	if vt.paused {
+		return nil
+	}
+	if nil == vt.handler {
+		vt.handler = socket.NewEventHandler("Emulation.virtualTimeBudgetExpired", func(response *socket.Response) {
+			select {
+			case vt.expired <- struct{}{}:
+			default:
+			}
+		})
+		vt.tab.AddEventHandler(vt.handler)
+	}
+	if err := vt.setPolicy(ctx, &emulation.SetVirtualTimePolicyParams{
+		Policy: emulation.VirtualTimePolicy.Pause,
+	}); nil != err {
+		return err
+	}
+	vt.paused = true
+	return nil
+}
+
+/*
+setPolicy sends Emulation.setVirtualTimePolicy.
+*/
+func (vt *VirtualTime) setPolicy(ctx context.Context, params *emulation.SetVirtualTimePolicyParams) error {
+	if result := <-vt.tab.withContext(ctx).Emulation().SetVirtualTimePolicy(params); nil != result.Err {
+		return errs.Wrap(result.Err, codes.EmulationFailed, fmt.Sprintf("could not set virtual time policy '%s'", params.Policy))
+	}
+	return nil
+}
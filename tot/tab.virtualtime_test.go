@@ -0,0 +1,95 @@
+package chrome
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/emulation"
+	"github.com/mkenney/go-chrome/tot/page"
+	"github.com/mkenney/go-chrome/tot/socket"
+)
+
+func TestVirtualTimeNavigate(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestVirtualTimeNavigate")
+	mockSocket.Respond("Emulation.setVirtualTimePolicy", func(params interface{}) (interface{}, *socket.Error) {
+		if params.(*emulation.SetVirtualTimePolicyParams).Budget > 0 {
+			mockSocket.Fire("Emulation.virtualTimeBudgetExpired", &emulation.VirtualTimeBudgetExpiredEvent{})
+		}
+		return nil, nil
+	})
+
+	vt := tab.VirtualTime()
+	if err := vt.Navigate(context.Background(), "https://example.com/", 500*time.Millisecond); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if "https://example.com/" != mockSocket.Commands("Page.navigate")[0].Params().(*page.NavigateParams).URL {
+		t.Errorf("Expected navigation to https://example.com/")
+	}
+	policies := mockSocket.Commands("Emulation.setVirtualTimePolicy")
+	if 2 != len(policies) {
+		t.Fatalf("Expected 2 policy changes, received %d", len(policies))
+	}
+	if emulation.VirtualTimePolicy.Pause != policies[0].Params().(*emulation.SetVirtualTimePolicyParams).Policy {
+		t.Errorf("Expected virtual time to be paused before navigating")
+	}
+	params := policies[1].Params().(*emulation.SetVirtualTimePolicyParams)
+	if emulation.VirtualTimePolicy.PauseIfNetworkFetchesPending != params.Policy || 500 != params.Budget {
+		t.Errorf("Expected a 500ms budget waiting for fetches, received %+v", params)
+	}
+	if 500*time.Millisecond != vt.Elapsed() {
+		t.Errorf("Expected 500ms elapsed, received %s", vt.Elapsed())
+	}
+}
+
+func TestVirtualTimeSnapshot(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestVirtualTimeSnapshot")
+	mockSocket.Respond("Emulation.setVirtualTimePolicy", func(params interface{}) (interface{}, *socket.Error) {
+		if params.(*emulation.SetVirtualTimePolicyParams).Budget > 0 {
+			mockSocket.Fire("Emulation.virtualTimeBudgetExpired", &emulation.VirtualTimeBudgetExpiredEvent{})
+		}
+		return nil, nil
+	})
+	mockSocket.Respond("Page.captureScreenshot", mockScreenshot)
+
+	vt := tab.VirtualTime()
+	vt.Advance(context.Background(), 250*time.Millisecond)
+	if _, err := vt.Snapshot(context.Background(), time.Second, nil); nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	policies := mockSocket.Commands("Emulation.setVirtualTimePolicy")
+	if 750 != policies[len(policies)-1].Params().(*emulation.SetVirtualTimePolicyParams).Budget {
+		t.Errorf("Expected a 750ms budget")
+	}
+	if 1 != len(mockSocket.Commands("Page.captureScreenshot")) {
+		t.Errorf("Expected a screenshot")
+	}
+	if time.Second != vt.Elapsed() {
+		t.Errorf("Expected 1s elapsed, received %s", vt.Elapsed())
+	}
+	if _, err := vt.Snapshot(context.Background(), 500*time.Millisecond, nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestVirtualTimeCancel(t *testing.T) {
+	tab, mockSocket := newMockTab(t, "https://TestVirtualTimeCancel")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	vt := tab.VirtualTime()
+	if err := vt.Advance(ctx, time.Second); context.DeadlineExceeded != err {
+		t.Errorf("Expected context.DeadlineExceeded, received %v", err)
+	}
+	if 0 != vt.Elapsed() {
+		t.Errorf("Expected no elapsed time, received %s", vt.Elapsed())
+	}
+
+	if err := vt.Resume(context.Background()); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	policies := mockSocket.Commands("Emulation.setVirtualTimePolicy")
+	if emulation.VirtualTimePolicy.Advance != policies[len(policies)-1].Params().(*emulation.SetVirtualTimePolicyParams).Policy {
+		t.Errorf("Expected virtual time to advance freely")
+	}
+}
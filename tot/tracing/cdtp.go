@@ -7,11 +7,11 @@ package tracing
 
 /*
 MemoryDumpConfig is the configuration for memory dump. Used only when "memory-infra" category is
-enabled.
+enabled. Values are nested objects such as the list of dump triggers.
 
 https://chromedevtools.github.io/devtools-protocol/tot/Tracing/#type-MemoryDumpConfig
 */
-type MemoryDumpConfig map[string]string
+type MemoryDumpConfig map[string]interface{}
 
 /*
 TraceConfig is the trace configuration
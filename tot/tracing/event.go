@@ -31,7 +31,8 @@ DataCollectedEvent represents Overlay.dataCollected event data.
 https://chromedevtools.github.io/devtools-protocol/tot/Tracing/#event-dataCollected
 */
 type DataCollectedEvent struct {
-	Value []map[string]string `json:"value"`
+	// Trace events in the Trace Event Format.
+	Value []map[string]interface{} `json:"value"`
 
 	// Error information related to this event
 	Err error `json:"-"`
@@ -0,0 +1,515 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+LongTaskThreshold is the duration above which a main thread task is a long task
+that blocks input.
+*/
+var LongTaskThreshold = 50 * time.Millisecond
+
+/*
+Layout shift session windows. Shifts less than LayoutShiftGap apart are grouped
+into a window of at most LayoutShiftWindow, the cumulative layout shift is the
+score of the worst window.
+*/
+var (
+	LayoutShiftGap    = time.Second
+	LayoutShiftWindow = 5 * time.Second
+)
+
+/*
+Category is a main thread activity category.
+*/
+type Category string
+
+/*
+Main thread activity categories.
+*/
+const (
+	ScriptEvaluation  Category = "scriptEvaluation"
+	ScriptParsing     Category = "scriptParseCompile"
+	StyleLayout       Category = "styleLayout"
+	Rendering         Category = "paintCompositeRender"
+	ParseHTML         Category = "parseHTML"
+	GarbageCollection Category = "garbageCollection"
+	Other             Category = "other"
+)
+
+/*
+categories maps trace event names to main thread activity categories. Events
+that are not listed are attributed to the category of the enclosing event.
+*/
+var categories = map[string]Category{
+	"EvaluateScript":        ScriptEvaluation,
+	"EventDispatch":         ScriptEvaluation,
+	"FireAnimationFrame":    ScriptEvaluation,
+	"FireIdleCallback":      ScriptEvaluation,
+	"FunctionCall":          ScriptEvaluation,
+	"RunMicrotasks":         ScriptEvaluation,
+	"TimerFire":             ScriptEvaluation,
+	"V8.Execute":            ScriptEvaluation,
+	"v8.evaluateModule":     ScriptEvaluation,
+	"v8.run":                ScriptEvaluation,
+	"XHRReadyStateChange":   ScriptEvaluation,
+	"XHRLoad":               ScriptEvaluation,
+	"v8.compile":            ScriptParsing,
+	"v8.compileModule":      ScriptParsing,
+	"v8.parseOnBackground":  ScriptParsing,
+	"V8.CompileCode":        ScriptParsing,
+	"V8.CompileLazy":        ScriptParsing,
+	"InvalidateLayout":      StyleLayout,
+	"Layout":                StyleLayout,
+	"RecalculateStyles":     StyleLayout,
+	"ScheduleStyleRecalc":   StyleLayout,
+	"UpdateLayoutTree":      StyleLayout,
+	"CompositeLayers":       Rendering,
+	"Decode Image":          Rendering,
+	"Layerize":              Rendering,
+	"Paint":                 Rendering,
+	"PrePaint":              Rendering,
+	"UpdateLayer":           Rendering,
+	"UpdateLayerTree":       Rendering,
+	"ParseAuthorStyleSheet": ParseHTML,
+	"ParseHTML":             ParseHTML,
+	"BlinkGC.AtomicPhase":   GarbageCollection,
+	"GCEvent":               GarbageCollection,
+	"MajorGC":               GarbageCollection,
+	"MinorGC":               GarbageCollection,
+	"V8.GCFinalizeMC":       GarbageCollection,
+	"V8.GCScavenger":        GarbageCollection,
+}
+
+/*
+taskNames are the names of the top level main thread tasks.
+*/
+var taskNames = map[string]bool{
+	"RunTask":                                    true,
+	"ThreadControllerImpl::DoWork":               true,
+	"ThreadControllerImpl::RunTask":              true,
+	"TaskQueueManager::ProcessTaskFromWorkQueue": true,
+}
+
+/*
+Task is a main thread task.
+*/
+type Task struct {
+	// The task start relative to the navigation start.
+	Start time.Duration
+
+	// The task duration.
+	Duration time.Duration
+}
+
+/*
+LayoutShift is a layout shift.
+*/
+type LayoutShift struct {
+	// The shift time relative to the navigation start.
+	Time time.Duration
+
+	// The layout shift score.
+	Score float64
+
+	// The shift followed user input and is excluded from the cumulative
+	// layout shift.
+	HadRecentInput bool
+}
+
+/*
+Frame is a frame drawn by the compositor.
+*/
+type Frame struct {
+	// The frame start relative to the navigation start.
+	Start time.Duration
+
+	// The time until the next frame was drawn.
+	Duration time.Duration
+}
+
+/*
+Report is the analysis of a page load trace. Times are relative to the
+navigation start, milestones that were not recorded are zero.
+*/
+type Report struct {
+	// The URL of the navigation.
+	URL string
+
+	// The navigation start on the trace clock, or the first event if the
+	// trace does not include a navigation.
+	NavigationStart time.Duration
+
+	// Navigation milestones.
+	FirstPaint             time.Duration
+	FirstContentfulPaint   time.Duration
+	LargestContentfulPaint time.Duration
+	DOMContentLoaded       time.Duration
+	Load                   time.Duration
+
+	// Main thread tasks longer than LongTaskThreshold.
+	LongTasks []*Task
+
+	// The sum of the long task time above LongTaskThreshold after the first
+	// contentful paint.
+	TotalBlockingTime time.Duration
+
+	// Layout shifts and the cumulative layout shift score.
+	LayoutShifts          []*LayoutShift
+	CumulativeLayoutShift float64
+
+	// Main thread self time by activity category.
+	MainThread map[Category]time.Duration
+
+	// Frames drawn after the navigation start, the number of dropped frames
+	// and the average and longest frame durations.
+	Frames           []*Frame
+	DroppedFrames    int
+	AverageFrameTime time.Duration
+	MaxFrameTime     time.Duration
+}
+
+/*
+FPS returns the average frame rate.
+*/
+func (report *Report) FPS() float64 {
+	if 0 == report.AverageFrameTime {
+		return 0
+	}
+	return float64(time.Second) / float64(report.AverageFrameTime)
+}
+
+/*
+String implements Stringer.
+*/
+func (report *Report) String() string {
+	return fmt.Sprintf(
+		"FCP %s, LCP %s, DCL %s, load %s, TBT %s, CLS %.3f, %d long tasks, %d frames (%.1f fps)",
+		report.FirstContentfulPaint,
+		report.LargestContentfulPaint,
+		report.DOMContentLoaded,
+		report.Load,
+		report.TotalBlockingTime,
+		report.CumulativeLayoutShift,
+		len(report.LongTasks),
+		len(report.Frames),
+		report.FPS(),
+	)
+}
+
+/*
+Analyze computes the page load metrics of the navigation recorded in the trace.
+If the trace records several navigations the first main frame navigation is
+analyzed.
+*/
+func (trace *Trace) Analyze() *Report {
+	analysis := newAnalysis(trace)
+	report := &Report{
+		NavigationStart: analysis.origin,
+		MainThread:      map[Category]time.Duration{},
+	}
+	if nil != analysis.navigation {
+		report.URL = analysis.navigation.argString("data", "documentLoaderURL")
+	}
+	analysis.milestones(report)
+	analysis.tasks(report)
+	analysis.layoutShifts(report)
+	analysis.breakdown(report)
+	analysis.frames(report)
+	return report
+}
+
+/*
+analysis holds the main thread and navigation of a trace.
+*/
+type analysis struct {
+	frame      string
+	main       thread
+	navigation *Event
+	origin     time.Duration
+	trace      *Trace
+}
+
+/*
+newAnalysis finds the navigation and the renderer main thread of a trace.
+*/
+func newAnalysis(trace *Trace) *analysis {
+	analysis := &analysis{trace: trace}
+	for _, event := range trace.Events {
+		if "navigationStart" != event.Name {
+			continue
+		}
+		if nil == analysis.navigation {
+			analysis.navigation = event
+		}
+		if event.argBool("data", "isLoadingMainFrame") && "" != event.argString("data", "documentLoaderURL") {
+			analysis.navigation = event
+			break
+		}
+	}
+
+	if nil != analysis.navigation {
+		analysis.main = thread{analysis.navigation.PID, analysis.navigation.TID}
+		analysis.origin = analysis.navigation.Time()
+		analysis.frame = eventFrame(analysis.navigation)
+		return analysis
+	}
+
+	// Without a navigation the busiest renderer main thread is analyzed.
+	renderers := map[thread]bool{}
+	for _, event := range trace.Events {
+		if PhaseMetadata == event.Phase && "thread_name" == event.Name && "CrRendererMain" == event.argString("name") {
+			renderers[thread{event.PID, event.TID}] = true
+		}
+	}
+	counts := map[thread]int{}
+	first := true
+	for _, event := range trace.Events {
+		if PhaseMetadata == event.Phase {
+			continue
+		}
+		if first && event.Timestamp > 0 {
+			analysis.origin = event.Time()
+			first = false
+		}
+		key := thread{event.PID, event.TID}
+		if PhaseComplete == event.Phase && (0 == len(renderers) || renderers[key]) {
+			counts[key]++
+			if counts[key] > counts[analysis.main] {
+				analysis.main = key
+			}
+		}
+	}
+	return analysis
+}
+
+/*
+eventFrame returns the frame an event belongs to, or "".
+*/
+func eventFrame(event *Event) string {
+	if frame := event.argString("frame"); "" != frame {
+		return frame
+	}
+	return event.argString("data", "frame")
+}
+
+/*
+navigationEvent reports whether an event belongs to the analyzed navigation.
+*/
+func (analysis *analysis) navigationEvent(event *Event) bool {
+	if event.PID != analysis.main.pid || event.Time() < analysis.origin {
+		return false
+	}
+	frame := eventFrame(event)
+	return "" == analysis.frame || "" == frame || frame == analysis.frame
+}
+
+/*
+milestones sets the navigation milestones. The largest contentful paint is the
+last candidate, unless the candidate was invalidated.
+*/
+func (analysis *analysis) milestones(report *Report) {
+	first := map[string]time.Duration{}
+	var lcp time.Duration
+	for _, event := range analysis.trace.Events {
+		if !analysis.navigationEvent(event) {
+			continue
+		}
+		switch event.Name {
+		case "firstPaint", "firstContentfulPaint", "domContentLoadedEventEnd", "loadEventEnd", "MarkDOMContent", "MarkLoad":
+			if _, ok := first[event.Name]; !ok {
+				first[event.Name] = event.Time() - analysis.origin
+			}
+		case "largestContentfulPaint::Candidate":
+			lcp = event.Time() - analysis.origin
+		case "largestContentfulPaint::Invalidate":
+			lcp = 0
+		}
+	}
+
+	report.FirstPaint = first["firstPaint"]
+	report.FirstContentfulPaint = first["firstContentfulPaint"]
+	report.LargestContentfulPaint = lcp
+	report.DOMContentLoaded = first["domContentLoadedEventEnd"]
+	if 0 == report.DOMContentLoaded {
+		report.DOMContentLoaded = first["MarkDOMContent"]
+	}
+	report.Load = first["loadEventEnd"]
+	if 0 == report.Load {
+		report.Load = first["MarkLoad"]
+	}
+}
+
+/*
+mainThreadEvents returns the complete events of the main thread.
+*/
+func (analysis *analysis) mainThreadEvents() []*Event {
+	events := []*Event{}
+	for _, event := range analysis.trace.Events {
+		if PhaseComplete == event.Phase && analysis.main == (thread{event.PID, event.TID}) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+/*
+tasks sets the long tasks and the total blocking time. Only the part of a task
+after the first contentful paint blocks.
+*/
+func (analysis *analysis) tasks(report *Report) {
+	blockingStart := analysis.origin + report.FirstContentfulPaint
+	var end time.Duration
+	for _, event := range analysis.mainThreadEvents() {
+		if !taskNames[event.Name] || event.Time() < end {
+			continue
+		}
+		end = event.End()
+		if event.Dur() <= LongTaskThreshold {
+			continue
+		}
+		report.LongTasks = append(report.LongTasks, &Task{
+			Start:    event.Time() - analysis.origin,
+			Duration: event.Dur(),
+		})
+
+		start := event.Time()
+		if start < blockingStart {
+			start = blockingStart
+		}
+		if blocking := event.End() - start - LongTaskThreshold; blocking > 0 {
+			report.TotalBlockingTime += blocking
+		}
+	}
+}
+
+/*
+layoutShifts sets the layout shifts and the cumulative layout shift.
+*/
+func (analysis *analysis) layoutShifts(report *Report) {
+	var windowStart, last time.Duration
+	var window float64
+	open := false
+	for _, event := range analysis.trace.Events {
+		if "LayoutShift" != event.Name || !analysis.navigationEvent(event) {
+			continue
+		}
+		score := event.argFloat("data", "weighted_score_delta")
+		if 0 == score {
+			score = event.argFloat("data", "score")
+		}
+		shift := &LayoutShift{
+			Time:           event.Time() - analysis.origin,
+			Score:          score,
+			HadRecentInput: event.argBool("data", "had_recent_input"),
+		}
+		report.LayoutShifts = append(report.LayoutShifts, shift)
+		if shift.HadRecentInput {
+			continue
+		}
+
+		if !open || shift.Time-last > LayoutShiftGap || shift.Time-windowStart > LayoutShiftWindow {
+			windowStart, window, open = shift.Time, 0, true
+		}
+		window += score
+		last = shift.Time
+		if window > report.CumulativeLayoutShift {
+			report.CumulativeLayoutShift = window
+		}
+	}
+}
+
+/*
+breakdown sets the main thread self time by category. Events without a
+category of their own are attributed to the category of the enclosing event.
+*/
+func (analysis *analysis) breakdown(report *Report) {
+	type node struct {
+		category Category
+		end      float64
+		self     float64
+	}
+	stack := []*node{}
+	pop := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		report.MainThread[top.category] += microseconds(top.self)
+	}
+
+	for _, event := range analysis.mainThreadEvents() {
+		if event.Duration <= 0 {
+			continue
+		}
+		for len(stack) > 0 && event.Timestamp >= stack[len(stack)-1].end {
+			pop()
+		}
+		current := &node{
+			category: Other,
+			end:      event.Timestamp + event.Duration,
+			self:     event.Duration,
+		}
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1]
+			current.category = parent.category
+			if current.end > parent.end {
+				current.end = parent.end
+				current.self = parent.end - event.Timestamp
+			}
+			parent.self -= current.self
+		}
+		if category, ok := categories[event.Name]; ok {
+			current.category = category
+		} else if strings.HasPrefix(event.Name, "V8.GC") {
+			current.category = GarbageCollection
+		}
+		stack = append(stack, current)
+	}
+	for len(stack) > 0 {
+		pop()
+	}
+}
+
+/*
+frames sets the frame timings from the DrawFrame events of the renderer, or of
+all processes if the renderer did not draw frames.
+*/
+func (analysis *analysis) frames(report *Report) {
+	draws := []*Event{}
+	renderer := []*Event{}
+	for _, event := range analysis.trace.Events {
+		if event.Time() < analysis.origin {
+			continue
+		}
+		switch event.Name {
+		case "DrawFrame":
+			draws = append(draws, event)
+			if event.PID == analysis.main.pid {
+				renderer = append(renderer, event)
+			}
+		case "DroppedFrame":
+			report.DroppedFrames++
+		}
+	}
+	if len(renderer) > 0 {
+		draws = renderer
+	}
+
+	var total time.Duration
+	for i := 1; i < len(draws); i++ {
+		frame := &Frame{
+			Start:    draws[i-1].Time() - analysis.origin,
+			Duration: draws[i].Time() - draws[i-1].Time(),
+		}
+		report.Frames = append(report.Frames, frame)
+		total += frame.Duration
+		if frame.Duration > report.MaxFrameTime {
+			report.MaxFrameTime = frame.Duration
+		}
+	}
+	if len(report.Frames) > 0 {
+		report.AverageFrameTime = total / time.Duration(len(report.Frames))
+	}
+}
@@ -0,0 +1,124 @@
+package trace
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+pageLoad is a page load trace. The renderer main thread is 1:10, the
+navigation starts at 1s.
+*/
+var pageLoad = `{"traceEvents":[
+{"name":"thread_name","ph":"M","pid":1,"tid":10,"args":{"name":"CrRendererMain"}},
+{"name":"thread_name","ph":"M","pid":1,"tid":11,"args":{"name":"Compositor"}},
+{"name":"navigationStart","cat":"blink.user_timing","ph":"R","ts":900000,"pid":1,"tid":10,"args":{"frame":"F","data":{"isLoadingMainFrame":true,"documentLoaderURL":""}}},
+{"name":"navigationStart","cat":"blink.user_timing","ph":"R","ts":1000000,"pid":1,"tid":10,"args":{"frame":"F","data":{"isLoadingMainFrame":true,"documentLoaderURL":"https://example.com/"}}},
+{"name":"firstPaint","cat":"loading","ph":"R","ts":1100000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"firstContentfulPaint","cat":"loading","ph":"R","ts":1120000,"pid":1,"tid":10,"args":{"frame":"G"}},
+{"name":"firstContentfulPaint","cat":"loading","ph":"R","ts":1150000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"largestContentfulPaint::Candidate","cat":"loading","ph":"R","ts":1200000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"largestContentfulPaint::Candidate","cat":"loading","ph":"R","ts":1400000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"domContentLoadedEventEnd","cat":"blink.user_timing","ph":"R","ts":1300000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"loadEventEnd","cat":"blink.user_timing","ph":"R","ts":1600000,"pid":1,"tid":10,"args":{"frame":"F"}},
+{"name":"RunTask","cat":"toplevel","ph":"X","ts":1100000,"dur":100000,"pid":1,"tid":10},
+{"name":"EvaluateScript","cat":"devtools.timeline","ph":"B","ts":1100000,"pid":1,"tid":10},
+{"name":"v8.compile","cat":"v8","ph":"X","ts":1100000,"dur":10000,"pid":1,"tid":10},
+{"name":"EvaluateScript","cat":"devtools.timeline","ph":"E","ts":1160000,"pid":1,"tid":10},
+{"name":"RunTask","cat":"toplevel","ph":"X","ts":1170000,"dur":10000,"pid":1,"tid":10},
+{"name":"RunTask","cat":"toplevel","ph":"X","ts":1250000,"dur":80000,"pid":1,"tid":10},
+{"name":"Layout","cat":"devtools.timeline","ph":"X","ts":1260000,"dur":20000,"pid":1,"tid":10},
+{"name":"RunTask","cat":"toplevel","ph":"X","ts":1400000,"dur":20000,"pid":1,"tid":10},
+{"name":"V8.GCIncrementalMarking","cat":"v8","ph":"X","ts":1400000,"dur":5000,"pid":1,"tid":10},
+{"name":"RunTask","cat":"toplevel","ph":"X","ts":1400000,"dur":90000,"pid":1,"tid":11},
+{"name":"DrawFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":990000,"pid":1,"tid":11},
+{"name":"DrawFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":1016000,"pid":1,"tid":11},
+{"name":"DrawFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":1032000,"pid":1,"tid":11},
+{"name":"DroppedFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":1048000,"pid":1,"tid":11},
+{"name":"DrawFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":1064000,"pid":1,"tid":11},
+{"name":"DrawFrame","cat":"disabled-by-default-devtools.timeline.frame","ph":"I","ts":1070000,"pid":2,"tid":20},
+{"name":"LayoutShift","cat":"loading","ph":"I","ts":1500000,"pid":1,"tid":10,"args":{"frame":"F","data":{"score":0.1,"had_recent_input":false}}},
+{"name":"LayoutShift","cat":"loading","ph":"I","ts":1600000,"pid":1,"tid":10,"args":{"frame":"F","data":{"score":0.05,"had_recent_input":false}}},
+{"name":"LayoutShift","cat":"loading","ph":"I","ts":1700000,"pid":1,"tid":10,"args":{"frame":"F","data":{"score":0.3,"had_recent_input":true}}},
+{"name":"LayoutShift","cat":"loading","ph":"I","ts":3000000,"pid":1,"tid":10,"args":{"frame":"F","data":{"score":0.12,"had_recent_input":false}}}
+]}`
+
+func TestAnalyze(t *testing.T) {
+	trace, err := Parse(strings.NewReader(pageLoad))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	report := trace.Analyze()
+
+	if "https://example.com/" != report.URL || time.Second != report.NavigationStart {
+		t.Errorf("Expected the example.com navigation, received %s at %s", report.URL, report.NavigationStart)
+	}
+	for name, milestone := range map[string][]time.Duration{
+		"first paint":              {report.FirstPaint, 100 * time.Millisecond},
+		"first contentful paint":   {report.FirstContentfulPaint, 150 * time.Millisecond},
+		"largest contentful paint": {report.LargestContentfulPaint, 400 * time.Millisecond},
+		"DOMContentLoaded":         {report.DOMContentLoaded, 300 * time.Millisecond},
+		"load":                     {report.Load, 600 * time.Millisecond},
+	} {
+		if milestone[1] != milestone[0] {
+			t.Errorf("Expected %s at %s, received %s", name, milestone[1], milestone[0])
+		}
+	}
+
+	if 2 != len(report.LongTasks) || 100*time.Millisecond != report.LongTasks[0].Duration || 250*time.Millisecond != report.LongTasks[1].Start {
+		t.Errorf("Unexpected long tasks %v", report.LongTasks)
+	}
+	if 30*time.Millisecond != report.TotalBlockingTime {
+		t.Errorf("Expected 30ms total blocking time, received %s", report.TotalBlockingTime)
+	}
+
+	if 4 != len(report.LayoutShifts) || !report.LayoutShifts[2].HadRecentInput {
+		t.Errorf("Unexpected layout shifts %v", report.LayoutShifts)
+	}
+	if math.Abs(0.15-report.CumulativeLayoutShift) > 1e-9 {
+		t.Errorf("Expected a cumulative layout shift of 0.15, received %g", report.CumulativeLayoutShift)
+	}
+
+	for category, expected := range map[Category]time.Duration{
+		Other:             115 * time.Millisecond,
+		ScriptEvaluation:  50 * time.Millisecond,
+		ScriptParsing:     10 * time.Millisecond,
+		StyleLayout:       20 * time.Millisecond,
+		GarbageCollection: 5 * time.Millisecond,
+		Rendering:         0,
+	} {
+		if expected != report.MainThread[category] {
+			t.Errorf("Expected %s main thread time of %s, received %s", category, expected, report.MainThread[category])
+		}
+	}
+
+	if 2 != len(report.Frames) || 1 != report.DroppedFrames {
+		t.Errorf("Expected 2 frames and a dropped frame, received %v, %d", report.Frames, report.DroppedFrames)
+	}
+	if 24*time.Millisecond != report.AverageFrameTime || 32*time.Millisecond != report.MaxFrameTime {
+		t.Errorf("Unexpected frame times %s, %s", report.AverageFrameTime, report.MaxFrameTime)
+	}
+	if math.Abs(1000.0/24-report.FPS()) > 1e-9 {
+		t.Errorf("Unexpected frame rate %g", report.FPS())
+	}
+	if "" == report.String() {
+		t.Errorf("Expected a summary")
+	}
+}
+
+func TestAnalyzeWithoutNavigation(t *testing.T) {
+	trace := New([]*Event{
+		{Name: "RunTask", Phase: PhaseComplete, Timestamp: 500, Duration: 10, PID: 1, TID: 1},
+		{Name: "RunTask", Phase: PhaseComplete, Timestamp: 1000, Duration: 60000, PID: 2, TID: 2},
+		{Name: "RunTask", Phase: PhaseComplete, Timestamp: 70000, Duration: 70000, PID: 2, TID: 2},
+	})
+	report := trace.Analyze()
+	if 500*time.Microsecond != report.NavigationStart || 0 != report.FirstContentfulPaint {
+		t.Errorf("Expected the trace to start at the first event, received %s", report.NavigationStart)
+	}
+	if 2 != len(report.LongTasks) || 30*time.Millisecond != report.TotalBlockingTime {
+		t.Errorf("Expected the busiest thread to be analyzed, received %v, %s", report.LongTasks, report.TotalBlockingTime)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Package trace analyzes traces in the Trace Event Format recorded by
+Tab.StartTrace or received in Tracing.dataCollected events. It computes the
+navigation milestones, long tasks, total blocking time, layout shifts, the main
+thread breakdown and frame timings of a page load.
+
+	t, err := trace.Parse(file)
+	...
+	report := t.Analyze()
+	if report.LargestContentfulPaint > 2500*time.Millisecond {
+		...
+	}
+*/
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+	"github.com/mkenney/go-chrome/tot/tracing"
+)
+
+/*
+Event phases used by the analysis.
+*/
+const (
+	PhaseBegin    = "B"
+	PhaseEnd      = "E"
+	PhaseComplete = "X"
+	PhaseMetadata = "M"
+)
+
+/*
+Event is a trace event.
+
+https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+*/
+type Event struct {
+	// The event name.
+	Name string `json:"name"`
+
+	// A comma separated list of the event categories.
+	Category string `json:"cat"`
+
+	// The event phase.
+	Phase string `json:"ph"`
+
+	// The event timestamp in microseconds.
+	Timestamp float64 `json:"ts"`
+
+	// Optional. The duration of complete events in microseconds.
+	Duration float64 `json:"dur"`
+
+	// The process ID.
+	PID int `json:"pid"`
+
+	// The thread ID.
+	TID int `json:"tid"`
+
+	// Optional. The event arguments.
+	Args map[string]interface{} `json:"args"`
+}
+
+/*
+Time returns the event timestamp.
+*/
+func (event *Event) Time() time.Duration {
+	return microseconds(event.Timestamp)
+}
+
+/*
+End returns the end of a complete event, or its timestamp for other events.
+*/
+func (event *Event) End() time.Duration {
+	return microseconds(event.Timestamp + event.Duration)
+}
+
+/*
+Dur returns the duration of a complete event.
+*/
+func (event *Event) Dur() time.Duration {
+	return microseconds(event.Duration)
+}
+
+/*
+Arg returns a nested event argument, e.g. event.Arg("data", "score"), or nil.
+*/
+func (event *Event) Arg(path ...string) interface{} {
+	var value interface{} = event.Args
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+/*
+argString returns a string argument, or "".
+*/
+func (event *Event) argString(path ...string) string {
+	value, _ := event.Arg(path...).(string)
+	return value
+}
+
+/*
+argFloat returns a number argument, or 0.
+*/
+func (event *Event) argFloat(path ...string) float64 {
+	value, _ := event.Arg(path...).(float64)
+	return value
+}
+
+/*
+argBool returns a boolean argument, or false.
+*/
+func (event *Event) argBool(path ...string) bool {
+	value, _ := event.Arg(path...).(bool)
+	return value
+}
+
+/*
+Trace is a parsed trace. Events are sorted by timestamp and matching begin and
+end events are merged into complete events.
+*/
+type Trace struct {
+	Events []*Event
+}
+
+/*
+traceFile is the JSON object format of a trace file.
+*/
+type traceFile struct {
+	TraceEvents []*Event `json:"traceEvents"`
+}
+
+/*
+Parse reads a trace in the JSON object format written by Tab.StartTrace, or in
+the JSON array format.
+*/
+func Parse(r io.Reader) (*Trace, error) {
+	data, err := ioutil.ReadAll(r)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.TracingDataInvalid, "could not read the trace")
+	}
+	data = bytes.TrimSpace(data)
+
+	events := []*Event{}
+	if len(data) > 0 && '[' == data[0] {
+		// Traces in the array format may be truncated after the last event.
+		if ']' != data[len(data)-1] {
+			data = append(bytes.TrimRight(data, ","), ']')
+		}
+		err = json.Unmarshal(data, &events)
+	} else {
+		file := &traceFile{}
+		err = json.Unmarshal(data, file)
+		events = file.TraceEvents
+	}
+	if nil != err {
+		return nil, errs.Wrap(err, codes.TracingDataInvalid, "could not decode the trace")
+	}
+	return New(events), nil
+}
+
+/*
+FromDataCollected returns the trace received in Tracing.dataCollected events.
+*/
+func FromDataCollected(collected ...*tracing.DataCollectedEvent) (*Trace, error) {
+	events := []*Event{}
+	for _, event := range collected {
+		data, err := json.Marshal(event.Value)
+		if nil != err {
+			return nil, errs.Wrap(err, codes.TracingDataInvalid, "could not encode trace events")
+		}
+		value := []*Event{}
+		if err := json.Unmarshal(data, &value); nil != err {
+			return nil, errs.Wrap(err, codes.TracingDataInvalid, "could not decode trace events")
+		}
+		events = append(events, value...)
+	}
+	return New(events), nil
+}
+
+/*
+New returns a trace of events.
+*/
+func New(events []*Event) *Trace {
+	sorted := make([]*Event, 0, len(events))
+	for _, event := range events {
+		if nil != event {
+			sorted = append(sorted, event)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+	return &Trace{Events: mergeBeginEnd(sorted)}
+}
+
+/*
+thread identifies a thread in a trace.
+*/
+type thread struct {
+	pid int
+	tid int
+}
+
+/*
+mergeBeginEnd replaces matching begin and end events with complete events.
+Unmatched events are kept.
+*/
+func mergeBeginEnd(events []*Event) []*Event {
+	merged := make([]*Event, 0, len(events))
+	stacks := map[thread][]*Event{}
+	for _, event := range events {
+		key := thread{event.PID, event.TID}
+		switch event.Phase {
+		case PhaseBegin:
+			complete := *event
+			complete.Phase = PhaseComplete
+			// The args of the end event are merged into a copy, the caller's
+			// events are not modified.
+			if nil != event.Args {
+				complete.Args = make(map[string]interface{}, len(event.Args))
+				for k, v := range event.Args {
+					complete.Args[k] = v
+				}
+			}
+			stacks[key] = append(stacks[key], &complete)
+			merged = append(merged, &complete)
+			continue
+		case PhaseEnd:
+			stack := stacks[key]
+			if len(stack) > 0 {
+				begin := stack[len(stack)-1]
+				stacks[key] = stack[:len(stack)-1]
+				begin.Duration = event.Timestamp - begin.Timestamp
+				for k, v := range event.Args {
+					if nil == begin.Args {
+						begin.Args = map[string]interface{}{}
+					}
+					begin.Args[k] = v
+				}
+				continue
+			}
+		}
+		merged = append(merged, event)
+	}
+
+	// Unmatched begin events are restored.
+	for _, stack := range stacks {
+		for _, event := range stack {
+			event.Phase = PhaseBegin
+		}
+	}
+	return merged
+}
+
+/*
+microseconds converts a trace timestamp to a duration.
+*/
+func microseconds(us float64) time.Duration {
+	return time.Duration(us * float64(time.Microsecond))
+}
@@ -0,0 +1,78 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkenney/go-chrome/tot/tracing"
+)
+
+func TestParse(t *testing.T) {
+	trace, err := Parse(strings.NewReader(`[
+		{"name":"b","ph":"X","ts":20,"dur":5,"pid":1,"tid":1},
+		{"name":"a","ph":"B","ts":10,"pid":1,"tid":1,"args":{"x":1}},
+		{"name":"a","ph":"E","ts":40,"pid":1,"tid":1,"args":{"data":{"y":"z"}}},
+		{"name":"c","ph":"B","ts":50,"pid":1,"tid":1},
+	`))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 3 != len(trace.Events) {
+		t.Fatalf("Expected 3 events, received %d", len(trace.Events))
+	}
+	a := trace.Events[0]
+	if "a" != a.Name || PhaseComplete != a.Phase || 30*time.Microsecond != a.Dur() || 40*time.Microsecond != a.End() {
+		t.Errorf("Expected the begin and end events to be merged, received %+v", a)
+	}
+	if 1.0 != a.Arg("x") || "z" != a.Arg("data", "y") || nil != a.Arg("data", "y", "z") {
+		t.Errorf("Unexpected arguments %v", a.Args)
+	}
+	if PhaseBegin != trace.Events[2].Phase {
+		t.Errorf("Expected an unmatched begin event to be kept")
+	}
+
+	trace, err = Parse(strings.NewReader(`{"traceEvents":[{"name":"a","ph":"I","ts":1}],"metadata":{}}`))
+	if nil != err || 1 != len(trace.Events) || time.Microsecond != trace.Events[0].Time() {
+		t.Errorf("Expected the object format to be parsed, received %v", err)
+	}
+
+	if _, err := Parse(strings.NewReader(`{"traceEvents":`)); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestFromDataCollected(t *testing.T) {
+	trace, err := FromDataCollected(
+		&tracing.DataCollectedEvent{Value: []map[string]interface{}{
+			{"name": "b", "ph": "X", "ts": 20, "dur": 5, "pid": 1, "tid": 1},
+		}},
+		&tracing.DataCollectedEvent{Value: []map[string]interface{}{
+			{"name": "a", "ph": "X", "ts": 10, "dur": 5, "pid": 1, "tid": 1},
+		}},
+	)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 2 != len(trace.Events) || "a" != trace.Events[0].Name {
+		t.Errorf("Expected 2 sorted events, received %v", trace.Events)
+	}
+
+	if _, err := FromDataCollected(&tracing.DataCollectedEvent{Value: []map[string]interface{}{
+		{"name": 1},
+	}}); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}
+
+func TestNew(t *testing.T) {
+	begin := &Event{Name: "a", Phase: PhaseBegin, Timestamp: 10, PID: 1, TID: 1, Args: map[string]interface{}{"x": 1}}
+	end := &Event{Name: "a", Phase: PhaseEnd, Timestamp: 20, PID: 1, TID: 1, Args: map[string]interface{}{"y": 2}}
+	trace := New([]*Event{begin, end})
+	if 1 != len(trace.Events) || 2 != len(trace.Events[0].Args) {
+		t.Fatalf("Expected the begin and end events to be merged, received %v", trace.Events)
+	}
+	if PhaseBegin != begin.Phase || 1 != len(begin.Args) {
+		t.Errorf("Expected the input events to be unchanged, received %+v", begin)
+	}
+}
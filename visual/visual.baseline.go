@@ -0,0 +1,137 @@
+package visual
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+Baselines stores baseline images as PNG files in a directory and checks
+screenshots against them. When a check fails the screenshot and the diff image
+are written next to the baseline as <name>.actual.png and <name>.diff.png.
+
+	baselines := &visual.Baselines{
+		Dir:     "testdata/screenshots",
+		Update:  "" != os.Getenv("UPDATE_BASELINES"),
+		Options: &visual.Options{Metric: visual.YIQ, Tolerance: 0.1},
+	}
+	result, err := baselines.Check("home", screenshot, masks...)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !result.Match {
+		t.Errorf("home: %s", result)
+	}
+*/
+type Baselines struct {
+	// The directory baselines are stored in.
+	Dir string
+
+	// Optional. Write screenshots as the new baselines instead of comparing
+	// them. Checks always match in update mode.
+	Update bool
+
+	// Optional. The comparison options, an exact match is required by
+	// default. Masks passed to Check are added to the option masks.
+	Options *Options
+}
+
+/*
+Check compares a screenshot with the named baseline. Baselines are created in
+update mode; otherwise a missing baseline is an error.
+*/
+func (baselines *Baselines) Check(name string, screenshot []byte, masks ...image.Rectangle) (*Result, error) {
+	actual, err := Decode(screenshot)
+	if nil != err {
+		return nil, err
+	}
+
+	opts := Options{}
+	if nil != baselines.Options {
+		opts = *baselines.Options
+	}
+	opts.Masks = append(append([]image.Rectangle{}, opts.Masks...), masks...)
+
+	if baselines.Update {
+		if err := baselines.write(baselines.Path(name), actual); nil != err {
+			return nil, err
+		}
+		baselines.clean(name)
+		return Compare(actual, actual, &opts)
+	}
+
+	data, err := ioutil.ReadFile(baselines.Path(name))
+	if os.IsNotExist(err) {
+		return nil, errs.Wrap(err, codes.VisualBaselineMissing, fmt.Sprintf("baseline '%s' does not exist, run in update mode to create it", name))
+	} else if nil != err {
+		return nil, errs.Wrap(err, codes.VisualBaselineFailed, fmt.Sprintf("could not read baseline '%s'", name))
+	}
+	baseline, err := Decode(data)
+	if nil != err {
+		return nil, err
+	}
+
+	result, err := Compare(baseline, actual, &opts)
+	if nil != err {
+		return nil, err
+	}
+	if result.Match {
+		baselines.clean(name)
+		return result, nil
+	}
+	if err := baselines.write(baselines.path(name, ".actual.png"), actual); nil != err {
+		return nil, err
+	}
+	if err := baselines.write(baselines.path(name, ".diff.png"), result.Diff); nil != err {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+Path returns the path of the named baseline. Names may contain slashes to
+organize baselines in subdirectories.
+*/
+func (baselines *Baselines) Path(name string) string {
+	return baselines.path(name, ".png")
+}
+
+/*
+clean removes the actual and diff images left by a failed check.
+*/
+func (baselines *Baselines) clean(name string) {
+	os.Remove(baselines.path(name, ".actual.png"))
+	os.Remove(baselines.path(name, ".diff.png"))
+}
+
+/*
+path returns the path of a file stored for the named baseline.
+*/
+func (baselines *Baselines) path(name, suffix string) string {
+	return filepath.Join(baselines.Dir, filepath.FromSlash(name)+suffix)
+}
+
+/*
+write encodes an image as a PNG file.
+*/
+func (baselines *Baselines) write(path string, img image.Image) error {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); nil != err {
+		return errs.Wrap(err, codes.VisualBaselineFailed, fmt.Sprintf("could not encode '%s'", path))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); nil != err {
+		return errs.Wrap(err, codes.VisualBaselineFailed, fmt.Sprintf("could not create '%s'", filepath.Dir(path)))
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); nil != err {
+		return errs.Wrap(err, codes.VisualBaselineFailed, fmt.Sprintf("could not write '%s'", path))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package visual
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/*
+encodePNG encodes an image as a PNG.
+*/
+func encodePNG(img image.Image) []byte {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+func TestBaselinesCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "visual")
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	baselines := &Baselines{Dir: dir}
+	screenshot := encodePNG(solidImage(10, 10, color.White))
+	if _, err := baselines.Check("pages/home", screenshot); nil == err {
+		t.Errorf("Expected a missing baseline error, received nil")
+	}
+
+	baselines.Update = true
+	result, err := baselines.Check("pages/home", screenshot)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !result.Match {
+		t.Errorf("Expected a match in update mode, received %s", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pages", "home.png")); nil != err {
+		t.Errorf("Expected the baseline to be written: %v", err)
+	}
+
+	baselines.Update = false
+	changed := solidImage(10, 10, color.White)
+	changed.Set(9, 9, color.Black)
+	result, err = baselines.Check("pages/home", encodePNG(changed))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if result.Match || 1 != result.DiffPixels {
+		t.Errorf("Expected 1 differing pixel, received %s", result)
+	}
+	for _, suffix := range []string{".actual.png", ".diff.png"} {
+		if _, err := os.Stat(filepath.Join(dir, "pages", "home"+suffix)); nil != err {
+			t.Errorf("Expected %s to be written: %v", suffix, err)
+		}
+	}
+
+	result, err = baselines.Check("pages/home", encodePNG(changed), image.Rect(9, 9, 10, 10))
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !result.Match {
+		t.Errorf("Expected the masked change to match, received %s", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pages", "home.diff.png")); !os.IsNotExist(err) {
+		t.Errorf("Expected the diff image to be removed after a match")
+	}
+}
@@ -0,0 +1,327 @@
+/*
+Package visual compares screenshots with baseline images for visual regression
+testing. Images are compared pixel by pixel, either with a per-channel
+tolerance or with a perceptual color difference in the YIQ color space, which
+ignores changes the eye can't distinguish. Regions that change between runs,
+such as timestamps or ads, can be masked.
+
+	result, err := visual.CompareBytes(baseline, screenshot, &visual.Options{
+		Metric:    visual.YIQ,
+		Tolerance: 0.1,
+		Masks:     masks,
+	})
+	...
+	if !result.Match {
+		result.WriteDiff(file)
+	}
+*/
+package visual
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // Register the GIF decoder.
+	_ "image/jpeg" // Register the JPEG decoder.
+	"image/png"
+	"io"
+
+	errs "github.com/bdlm/errors"
+	"github.com/mkenney/go-chrome/codes"
+)
+
+/*
+Metric is the method used to decide if two pixels differ.
+*/
+type Metric int
+
+const (
+	// Pixel compares each color channel. Pixels differ if any channel differs
+	// by more than Tolerance * 255.
+	Pixel Metric = iota
+
+	// YIQ compares the perceptual color difference of pixels blended with a
+	// white background. Pixels differ if the difference is more than
+	// Tolerance * Tolerance of the largest possible difference. A tolerance
+	// of 0.1 is a good default.
+	YIQ
+)
+
+/*
+String implements Stringer.
+*/
+func (metric Metric) String() string {
+	switch metric {
+	case Pixel:
+		return "pixel"
+	case YIQ:
+		return "yiq"
+	}
+	return fmt.Sprintf("Metric(%d)", int(metric))
+}
+
+/*
+yiqMaxDelta is the largest possible YIQ difference between two colors.
+*/
+const yiqMaxDelta = 35215.0
+
+/*
+Colors used to draw diff images.
+*/
+var (
+	// DiffChanged marks pixels that differ.
+	DiffChanged = color.RGBA{R: 255, A: 255}
+
+	// DiffMasked marks pixels that were not compared.
+	DiffMasked = color.RGBA{R: 200, G: 200, B: 255, A: 255}
+)
+
+/*
+Options are the comparison options. The zero value requires an exact match.
+*/
+type Options struct {
+	// Optional. The method used to compare pixels, defaults to Pixel.
+	Metric Metric
+
+	// Optional. The per-pixel tolerance in the range [0..1].
+	Tolerance float64
+
+	// Optional. The number of differing pixels allowed for the images to
+	// match.
+	MaxDiffPixels int
+
+	// Optional. The ratio of differing to compared pixels allowed for the
+	// images to match, in the range [0..1].
+	MaxDiffRatio float64
+
+	// Optional. Regions that are not compared, in image pixels.
+	Masks []image.Rectangle
+}
+
+/*
+Result is the result of a comparison.
+*/
+type Result struct {
+	// The compared region, the union of both image bounds.
+	Bounds image.Rectangle
+
+	// The number of pixels compared, excluding masked pixels.
+	Pixels int
+
+	// The number of pixels that differ. Pixels outside either image always
+	// differ.
+	DiffPixels int
+
+	// DiffPixels / Pixels.
+	DiffRatio float64
+
+	// The smallest rectangle containing every differing pixel.
+	DiffBounds image.Rectangle
+
+	// The diff image: unchanged pixels are a faded copy of the baseline,
+	// changed pixels are DiffChanged and masked pixels are DiffMasked.
+	Diff *image.RGBA
+
+	// Whether the difference is within the allowed limits.
+	Match bool
+}
+
+/*
+String implements Stringer.
+*/
+func (result *Result) String() string {
+	if result.Match {
+		return fmt.Sprintf("images match, %d of %d pixels differ", result.DiffPixels, result.Pixels)
+	}
+	return fmt.Sprintf(
+		"images differ, %d of %d pixels (%.2f%%) differ in %v",
+		result.DiffPixels,
+		result.Pixels,
+		result.DiffRatio*100,
+		result.DiffBounds,
+	)
+}
+
+/*
+WriteDiff writes the diff image to w as a PNG.
+*/
+func (result *Result) WriteDiff(w io.Writer) error {
+	if err := png.Encode(w, result.Diff); nil != err {
+		return errs.Wrap(err, codes.VisualBaselineFailed, "could not encode the diff image")
+	}
+	return nil
+}
+
+/*
+Decode decodes a PNG, JPEG or GIF image.
+*/
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if nil != err {
+		return nil, errs.Wrap(err, codes.VisualImageInvalid, "could not decode image")
+	}
+	return img, nil
+}
+
+/*
+CompareBytes decodes and compares two encoded images.
+*/
+func CompareBytes(baseline, actual []byte, opts *Options) (*Result, error) {
+	baselineImage, err := Decode(baseline)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.VisualImageInvalid, "invalid baseline image")
+	}
+	actualImage, err := Decode(actual)
+	if nil != err {
+		return nil, errs.Wrap(err, codes.VisualImageInvalid, "invalid image")
+	}
+	return Compare(baselineImage, actualImage, opts)
+}
+
+/*
+Compare compares an image with a baseline. Images of different sizes are
+compared over the union of their bounds so that the diff image shows the
+missing or added area.
+*/
+func Compare(baseline, actual image.Image, opts *Options) (*Result, error) {
+	if nil == opts {
+		opts = &Options{}
+	}
+	if err := opts.validate(); nil != err {
+		return nil, err
+	}
+
+	bounds := baseline.Bounds().Union(actual.Bounds())
+	result := &Result{
+		Bounds: bounds,
+		Diff:   image.NewRGBA(bounds),
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			point := image.Pt(x, y)
+			if opts.masked(point) {
+				result.Diff.Set(x, y, DiffMasked)
+				continue
+			}
+			result.Pixels++
+
+			inBaseline := point.In(baseline.Bounds())
+			if inBaseline && point.In(actual.Bounds()) && !opts.differ(baseline.At(x, y), actual.At(x, y)) {
+				result.Diff.Set(x, y, faded(baseline.At(x, y)))
+				continue
+			}
+			result.DiffPixels++
+			result.DiffBounds = result.DiffBounds.Union(image.Rect(x, y, x+1, y+1))
+			result.Diff.Set(x, y, DiffChanged)
+		}
+	}
+
+	if result.Pixels > 0 {
+		result.DiffRatio = float64(result.DiffPixels) / float64(result.Pixels)
+	}
+	result.Match = result.DiffPixels <= opts.MaxDiffPixels || result.DiffRatio <= opts.MaxDiffRatio
+	return result, nil
+}
+
+/*
+differ returns true if two colors differ by more than the tolerance.
+*/
+func (opts *Options) differ(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	if ar == br && ag == bg && ab == bb && aa == ba {
+		return false
+	}
+	if YIQ == opts.Metric {
+		return yiqDelta(a, b) > yiqMaxDelta*opts.Tolerance*opts.Tolerance
+	}
+	limit := opts.Tolerance * 0xffff
+	for _, delta := range []float64{
+		channelDelta(ar, br),
+		channelDelta(ag, bg),
+		channelDelta(ab, bb),
+		channelDelta(aa, ba),
+	} {
+		if delta > limit {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+masked returns true if a point is inside a mask.
+*/
+func (opts *Options) masked(point image.Point) bool {
+	for _, mask := range opts.Masks {
+		if point.In(mask) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+validate checks that the options are in range.
+*/
+func (opts *Options) validate() error {
+	if opts.Metric != Pixel && opts.Metric != YIQ {
+		return errs.New(codes.VisualOptionsInvalid, fmt.Sprintf("unknown metric %s", opts.Metric))
+	}
+	if opts.Tolerance < 0 || opts.Tolerance > 1 {
+		return errs.New(codes.VisualOptionsInvalid, fmt.Sprintf("tolerance %g is not in the range [0..1]", opts.Tolerance))
+	}
+	if opts.MaxDiffPixels < 0 || opts.MaxDiffRatio < 0 || opts.MaxDiffRatio > 1 {
+		return errs.New(codes.VisualOptionsInvalid, fmt.Sprintf("invalid difference limits %d pixels, ratio %g", opts.MaxDiffPixels, opts.MaxDiffRatio))
+	}
+	return nil
+}
+
+/*
+channelDelta returns the absolute difference of two 16 bit color channels.
+*/
+func channelDelta(a, b uint32) float64 {
+	if a > b {
+		return float64(a - b)
+	}
+	return float64(b - a)
+}
+
+/*
+yiqDelta returns the squared perceptual difference of two colors blended with
+a white background, as described in "Measuring perceived color difference
+using YIQ NTSC transmission color space in mobile applications" by Y. Kotsarenko
+and F. Ramos.
+*/
+func yiqDelta(a, b color.Color) float64 {
+	ay, ai, aq := yiq(a)
+	by, bi, bq := yiq(b)
+	dy, di, dq := ay-by, ai-bi, aq-bq
+	return 0.5053*dy*dy + 0.299*di*di + 0.1957*dq*dq
+}
+
+/*
+yiq converts a color blended with a white background to YIQ with 8 bit
+channels.
+*/
+func yiq(c color.Color) (float64, float64, float64) {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	alpha := float64(nrgba.A) / 255
+	r := 255 + (float64(nrgba.R)-255)*alpha
+	g := 255 + (float64(nrgba.G)-255)*alpha
+	b := 255 + (float64(nrgba.B)-255)*alpha
+	return r*0.29889531 + g*0.58662247 + b*0.11448223,
+		r*0.59597799 - g*0.27417610 - b*0.32180189,
+		r*0.21147017 - g*0.52261711 + b*0.31114694
+}
+
+/*
+faded returns the gray value of a color blended towards white, used for
+unchanged pixels in diff images.
+*/
+func faded(c color.Color) color.Color {
+	y, _, _ := yiq(c)
+	gray := uint8(255 + (y-255)*0.1)
+	return color.RGBA{R: gray, G: gray, B: gray, A: 255}
+}
@@ -0,0 +1,141 @@
+package visual
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+/*
+solidImage returns an image filled with a single color.
+*/
+func solidImage(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestComparePixel(t *testing.T) {
+	baseline := solidImage(10, 10, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+	actual := solidImage(10, 10, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+	actual.Set(2, 3, color.RGBA{R: 110, G: 100, B: 100, A: 255})
+	actual.Set(7, 8, color.RGBA{R: 200, G: 100, B: 100, A: 255})
+
+	result, err := Compare(baseline, actual, nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if result.Match || 2 != result.DiffPixels || 100 != result.Pixels {
+		t.Errorf("Expected 2 differing pixels, received %s", result)
+	}
+	if image.Rect(2, 3, 8, 9) != result.DiffBounds {
+		t.Errorf("Unexpected diff bounds %v", result.DiffBounds)
+	}
+	if DiffChanged != result.Diff.RGBAAt(2, 3) || DiffChanged == result.Diff.RGBAAt(0, 0) {
+		t.Errorf("Expected changed pixels to be highlighted in the diff image")
+	}
+
+	result, _ = Compare(baseline, actual, &Options{Tolerance: 0.1})
+	if 1 != result.DiffPixels {
+		t.Errorf("Expected the tolerance to ignore a small change, received %s", result)
+	}
+	result, _ = Compare(baseline, actual, &Options{MaxDiffPixels: 2})
+	if !result.Match {
+		t.Errorf("Expected 2 differing pixels to be allowed, received %s", result)
+	}
+	result, _ = Compare(baseline, actual, &Options{MaxDiffRatio: 0.01})
+	if result.Match {
+		t.Errorf("Expected a 2%% difference not to match a 1%% limit, received %s", result)
+	}
+}
+
+func TestCompareYIQ(t *testing.T) {
+	baseline := solidImage(4, 4, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	actual := solidImage(4, 4, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	actual.Set(0, 0, color.RGBA{R: 250, G: 250, B: 250, A: 255})
+	actual.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	result, err := Compare(baseline, actual, &Options{Metric: YIQ, Tolerance: 0.1})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if 1 != result.DiffPixels || image.Rect(1, 1, 2, 2) != result.DiffBounds {
+		t.Errorf("Expected only the red pixel to differ, received %s", result)
+	}
+
+	// Transparent pixels are blended with white.
+	transparent := solidImage(4, 4, color.RGBA{})
+	result, _ = Compare(baseline, transparent, &Options{Metric: YIQ, Tolerance: 0.1})
+	if 0 != result.DiffPixels {
+		t.Errorf("Expected transparent pixels to match white, received %s", result)
+	}
+}
+
+func TestCompareMasks(t *testing.T) {
+	baseline := solidImage(10, 10, color.White)
+	actual := solidImage(10, 10, color.White)
+	for x := 0; x < 5; x++ {
+		actual.Set(x, 0, color.Black)
+	}
+
+	result, err := Compare(baseline, actual, &Options{Masks: []image.Rectangle{image.Rect(0, 0, 5, 1)}})
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !result.Match || 95 != result.Pixels {
+		t.Errorf("Expected the masked region to be ignored, received %s", result)
+	}
+	if DiffMasked != result.Diff.RGBAAt(0, 0) {
+		t.Errorf("Expected masked pixels to be marked in the diff image")
+	}
+}
+
+func TestCompareSize(t *testing.T) {
+	result, err := Compare(solidImage(10, 10, color.White), solidImage(10, 12, color.White), nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if result.Match || 20 != result.DiffPixels || image.Rect(0, 10, 10, 12) != result.DiffBounds {
+		t.Errorf("Expected the added rows to differ, received %s", result)
+	}
+
+	for _, opts := range []*Options{
+		{Metric: Metric(5)},
+		{Tolerance: 2},
+		{MaxDiffRatio: -1},
+	} {
+		if _, err := Compare(solidImage(1, 1, color.White), solidImage(1, 1, color.White), opts); nil == err {
+			t.Errorf("%+v: expected error, received nil", opts)
+		}
+	}
+}
+
+func TestCompareBytes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, solidImage(3, 3, color.White))
+
+	result, err := CompareBytes(buf.Bytes(), buf.Bytes(), nil)
+	if nil != err {
+		t.Fatalf("Expected nil, received error: %v", err)
+	}
+	if !result.Match {
+		t.Errorf("Expected identical images to match, received %s", result)
+	}
+	diff := &bytes.Buffer{}
+	if err := result.WriteDiff(diff); nil != err {
+		t.Errorf("Expected nil, received error: %v", err)
+	}
+	if _, err := png.Decode(diff); nil != err {
+		t.Errorf("Expected a PNG diff image, received error: %v", err)
+	}
+
+	if _, err := CompareBytes(buf.Bytes(), []byte("not an image"), nil); nil == err {
+		t.Errorf("Expected error, received nil")
+	}
+}